@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var accessCmd = &cobra.Command{
+	Use:   "access",
+	Short: "Manage who can access an agent",
+	Long:  "List and revoke access granted with 'oken share'.",
+}
+
+var accessListCmd = &cobra.Command{
+	Use:   "list <slug>",
+	Short: "List users with access to an agent",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAccessList,
+}
+
+var accessRevokeCmd = &cobra.Command{
+	Use:   "revoke <slug> <email>",
+	Short: "Revoke a user's access to an agent",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runAccessRevoke,
+}
+
+func init() {
+	accessCmd.AddCommand(accessListCmd)
+	accessCmd.AddCommand(accessRevokeCmd)
+	rootCmd.AddCommand(accessCmd)
+}
+
+func runAccessList(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.ListAccess(slug)
+	if err != nil {
+		ui.Error("Failed to list access: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Grants)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(resp.Grants))
+		for _, g := range resp.Grants {
+			rows = append(rows, []string{g.Email, g.Role, g.CreatedAt})
+		}
+		return ui.Table([]string{"email", "role", "granted"}, rows)
+	}
+
+	if len(resp.Grants) == 0 {
+		ui.Info("Agent '%s' is not shared with anyone. Use 'oken share' to add teammates.", slug)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "EMAIL\tROLE\tGRANTED")
+	for _, g := range resp.Grants {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", g.Email, g.Role, dateOnly(g.CreatedAt))
+	}
+	_ = w.Flush()
+
+	return nil
+}
+
+func runAccessRevoke(cmd *cobra.Command, args []string) error {
+	slug, email := args[0], args[1]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	if _, err := client.RevokeAccess(slug, email); err != nil {
+		ui.Error("Failed to revoke access: %v", err)
+		return err
+	}
+
+	ui.Success("Access revoked: %s (agent: %s)", email, slug)
+
+	return nil
+}
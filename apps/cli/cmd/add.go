@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/deps"
+	"github.com/neult/oken/apps/cli/internal/scaffold"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var addToolFramework string
+
+var addCmd = &cobra.Command{
+	Use:         "add",
+	Short:       "Add code to an agent project",
+	Annotations: textOutput,
+	Long:        "Generate building blocks in the agent project in the current directory, after 'oken init'.",
+}
+
+var addToolCmd = &cobra.Command{
+	Use:   "tool <name>",
+	Short: "Generate a tool for the agent",
+	Long: `Generate a tool module in tools/ for a Python agent, and register it.
+
+The tool is written for the agent framework found in the project's
+dependencies: LangChain or LangGraph, the OpenAI Agents SDK, or CrewAI.
+--framework picks one instead. For these frameworks the tool is imported in
+the entrypoint and added to the first tools list there, such as
+tools=[search], and the package providing the tool decorator is added to
+requirements.txt if it is missing. A plain function is generated otherwise,
+to wire up by hand.
+
+Examples:
+  oken add tool fetch-weather
+  oken add tool search_docs --framework openai-agents`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAddTool,
+}
+
+func init() {
+	addToolCmd.Flags().StringVar(&addToolFramework, "framework", "", "Framework to write the tool for: langchain, openai-agents, crewai or none (default: detected)")
+	addCmd.AddCommand(addToolCmd)
+	rootCmd.AddCommand(addCmd)
+}
+
+func runAddTool(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat("oken.toml"); err != nil {
+		ui.Error("No oken.toml in this directory. Run 'oken init' first.")
+		return fmt.Errorf("not an agent project")
+	}
+	okenCfg, err := loadOkenConfig(".")
+	if err != nil {
+		ui.Error("Failed to parse oken.toml: %v", err)
+		return err
+	}
+	if okenCfg.Runtime != "" && okenCfg.Runtime != "python" {
+		ui.Error("Tools can only be generated for Python agents, not %s.", okenCfg.Runtime)
+		return fmt.Errorf("unsupported runtime")
+	}
+
+	set, err := deps.Load(".")
+	if err != nil {
+		ui.Error("Failed to read dependencies: %v", err)
+		return err
+	}
+	framework := scaffold.DetectFramework(set)
+	if addToolFramework != "" {
+		if framework, err = scaffold.ParseFramework(addToolFramework); err != nil {
+			ui.Error("%v", err)
+			return fmt.Errorf("invalid flags")
+		}
+	}
+
+	tool, err := scaffold.NewTool(args[0], framework)
+	if err != nil {
+		ui.Error("%v", err)
+		return fmt.Errorf("invalid arguments")
+	}
+
+	path := filepath.FromSlash(tool.Path())
+	if _, err := os.Stat(path); err == nil {
+		ui.Error("%s already exists", tool.Path())
+		return fmt.Errorf("tool exists")
+	}
+	if err := os.MkdirAll(scaffold.ToolsDir, 0755); err != nil {
+		ui.Error("Failed to create %s: %v", scaffold.ToolsDir, err)
+		return err
+	}
+	// Makes tools a regular package, importable from the entrypoint
+	initPath := filepath.Join(scaffold.ToolsDir, "__init__.py")
+	if _, err := os.Stat(initPath); os.IsNotExist(err) {
+		if err := os.WriteFile(initPath, nil, 0644); err != nil {
+			ui.Error("Failed to create %s: %v", initPath, err)
+			return err
+		}
+	}
+	if err := os.WriteFile(path, []byte(tool.Source()), 0644); err != nil {
+		ui.Error("Failed to create %s: %v", tool.Path(), err)
+		return err
+	}
+	if framework == scaffold.FrameworkNone {
+		ui.Success("Created %s", tool.Path())
+	} else {
+		ui.Success("Created %s (%s tool)", tool.Path(), framework)
+	}
+
+	if framework == scaffold.FrameworkNone {
+		ui.Info("Import it where your agent defines its tools: %s", tool.Import())
+	} else if err := registerTool(tool, okenCfg.Entrypoint, set); err != nil {
+		return err
+	}
+
+	ui.Info("Implement %s in %s, then run 'oken deploy'", tool.Function, tool.Path())
+
+	return nil
+}
+
+// registerTool wires a framework tool into the entrypoint and makes sure the
+// framework's tool package is a dependency
+func registerTool(tool scaffold.Tool, entrypoint string, set *deps.Set) error {
+	if entrypoint == "" {
+		entrypoint = "main.py"
+	}
+	source, err := os.ReadFile(entrypoint)
+	switch {
+	case os.IsNotExist(err):
+		ui.Info("Import it in your entrypoint: %s", tool.Import())
+	case err != nil:
+		ui.Error("Failed to read %s: %v", entrypoint, err)
+		return err
+	default:
+		registered, listed := tool.Register(string(source))
+		if registered != string(source) {
+			if err := os.WriteFile(entrypoint, []byte(registered), 0644); err != nil {
+				ui.Error("Failed to update %s: %v", entrypoint, err)
+				return err
+			}
+		}
+		if listed {
+			ui.Success("Registered %s in %s", tool.Function, entrypoint)
+		} else {
+			ui.Success("Imported %s in %s", tool.Function, entrypoint)
+			ui.Info("No tools list found there; pass %s to your agent's tools", tool.Function)
+		}
+	}
+
+	pkg := tool.Framework.Requirement()
+	if set != nil {
+		for _, d := range set.Dependencies {
+			if d.Name == pkg {
+				return nil
+			}
+		}
+	}
+	// Lockfiles and pyproject.toml are left to the package manager
+	if set != nil && set.File != deps.RequirementsFile {
+		ui.Info("Add %s to your dependencies: uv add %s", pkg, pkg)
+		return nil
+	}
+	requirements, err := os.ReadFile(deps.RequirementsFile)
+	if err != nil && !os.IsNotExist(err) {
+		ui.Error("Failed to read %s: %v", deps.RequirementsFile, err)
+		return err
+	}
+	if err := os.WriteFile(deps.RequirementsFile, []byte(scaffold.AddRequirement(string(requirements), pkg)), 0644); err != nil {
+		ui.Error("Failed to update %s: %v", deps.RequirementsFile, err)
+		return err
+	}
+	ui.Success("Added %s to %s", pkg, deps.RequirementsFile)
+	return nil
+}
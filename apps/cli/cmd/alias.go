@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/alias"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/plugin"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage command aliases",
+	Long: `Manage shortcuts for the commands and flags you use most.
+
+'oken <alias> [args...]' runs the alias's command line with args added at the
+end. Aliases are saved in your config file.`,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> <command>",
+	Short: "Create or change an alias",
+	Long: `Create or change an alias. Quote the command so its flags aren't read
+as flags of 'oken alias set'. Quotes inside it group words, as in a shell.
+
+An alias can't replace a built-in command, and the command it runs must be a
+built-in command or a plugin.
+
+Examples:
+  oken alias set dp "deploy --wait"
+  oken alias set ask "invoke my-agent -i '{\"question\": \"status?\"}'"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAliasSet,
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List aliases",
+	Args:  cobra.NoArgs,
+	RunE:  runAliasList,
+}
+
+var aliasDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete an alias",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAliasDelete,
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasDeleteCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
+
+// expandAlias replaces an alias in args with its command line. The alias is
+// the first argument after any global flags, such as "oken -o json dp".
+// Built-in commands always win over aliases.
+func expandAlias(args []string) ([]string, error) {
+	i := commandIndex(args)
+	if i < 0 || !alias.ValidName(args[i]) || isBuiltin(args[i]) {
+		return args, nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		// The command reports the config error itself
+		return args, nil
+	}
+	expanded, _, err := alias.Expand(cfg.Aliases, args[i:])
+	if err != nil {
+		return nil, err
+	}
+	return append(args[:i:i], expanded...), nil
+}
+
+// commandIndex returns the index of the command name in args, skipping the
+// global flags before it and their values
+func commandIndex(args []string) int {
+	flags := rootCmd.PersistentFlags()
+	return alias.CommandIndex(args, func(name string) bool {
+		f := flags.Lookup(name)
+		if f == nil && len(name) == 1 {
+			f = flags.ShorthandLookup(name)
+		}
+		return f != nil && f.NoOptDefVal == ""
+	})
+}
+
+func runAliasSet(cmd *cobra.Command, args []string) error {
+	name, expansion := args[0], args[1]
+
+	if !alias.ValidName(name) {
+		ui.Error("Invalid alias name %q. Use a single word that doesn't start with '-'.", name)
+		return fmt.Errorf("invalid alias")
+	}
+	if isBuiltin(name) {
+		ui.Error("'oken %s' is a built-in command and can't be an alias", name)
+		return fmt.Errorf("invalid alias")
+	}
+	words, err := alias.Split(expansion)
+	if err != nil {
+		ui.Error("Invalid command: %v", err)
+		return fmt.Errorf("invalid alias")
+	}
+	if len(words) == 0 {
+		ui.Error("The command for %s is empty", name)
+		return fmt.Errorf("invalid alias")
+	}
+	if !isBuiltin(words[0]) {
+		if _, ok := plugin.Find(words[0]); !ok {
+			ui.Error("Unknown command %q. An alias must run a built-in command or a plugin.", words[0])
+			return fmt.Errorf("invalid alias")
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Aliases == nil {
+		cfg.Aliases = map[string]string{}
+	}
+	previous, existed := cfg.Aliases[name]
+	cfg.Aliases[name] = expansion
+	if err := config.Save(cfg); err != nil {
+		ui.Error("Failed to save config: %v", err)
+		return err
+	}
+
+	if existed && previous != expansion {
+		ui.Success("Changed alias %s: oken %s (was: oken %s)", ui.Bold(name), expansion, previous)
+		return nil
+	}
+	ui.Success("Added alias %s: oken %s", ui.Bold(name), expansion)
+	return nil
+}
+
+// aliasEntry is an alias as listed by 'oken alias list'
+type aliasEntry struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
+
+func runAliasList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	entries := make([]aliasEntry, 0, len(cfg.Aliases))
+	for name, expansion := range cfg.Aliases {
+		entries = append(entries, aliasEntry{Name: name, Command: expansion})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	if ui.IsStructured() {
+		return ui.Result(entries)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(entries))
+		for _, e := range entries {
+			rows = append(rows, []string{e.Name, e.Command})
+		}
+		return ui.Table([]string{"name", "command"}, rows)
+	}
+
+	if len(entries) == 0 {
+		ui.Info("No aliases. Create one with 'oken alias set <name> <command>'.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tCOMMAND")
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(w, "%s\token %s\n", e.Name, e.Command)
+	}
+	_ = w.Flush()
+
+	return nil
+}
+
+func runAliasDelete(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if _, ok := cfg.Aliases[name]; !ok {
+		ui.Error("Alias '%s' not found. Run 'oken alias list' to see your aliases.", name)
+		return fmt.Errorf("alias not found")
+	}
+	delete(cfg.Aliases, name)
+	if err := config.Save(cfg); err != nil {
+		ui.Error("Failed to save config: %v", err)
+		return err
+	}
+
+	ui.Success("Deleted alias %s", ui.Bold(name))
+	return nil
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/manifest"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	applyFile   string
+	applyPrune  bool
+	applyDryRun bool
+	applyYes    bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Converge agents to a declarative manifest",
+	Long: `Compare a manifest describing agents, their secrets, scale and schedules
+with the platform, show the changes, and apply them.
+
+Agents that don't exist are deployed from their path. Secret values are
+read from the environment variables the manifest references. Agents,
+secrets and schedules missing from the manifest are only deleted with
+--prune.
+
+Examples:
+  oken apply
+  oken apply -f agents.yaml --dry-run
+  oken apply --prune --yes`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+func init() {
+	applyCmd.Flags().StringVarP(&applyFile, "file", "f", manifest.DefaultFile, "Manifest to apply")
+	applyCmd.Flags().BoolVar(&applyPrune, "prune", false, "Delete agents, and secrets and schedules of managed agents, that aren't in the manifest")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Show the changes without applying them")
+	applyCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "Apply without asking for confirmation")
+	rootCmd.AddCommand(applyCmd)
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	client, changes, err := planManifest(applyFile, applyPrune)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		if ui.IsStructured() {
+			return ui.Result(plannedChanges(nil))
+		}
+		ui.Success("No changes. Agents match %s.", applyFile)
+		return nil
+	}
+
+	// Stdout is kept for the result in JSON and YAML output
+	out := io.Writer(os.Stdout)
+	if ui.IsStructured() {
+		out = os.Stderr
+	}
+
+	ui.Info("Changes to converge %s:", applyFile)
+	printPlan(out, changes)
+	if applyDryRun {
+		if ui.IsStructured() {
+			return ui.Result(plannedChanges(changes))
+		}
+		return nil
+	}
+
+	// Fail before changing anything if a secret's value is missing
+	for _, c := range changes {
+		if c.Kind == manifest.KindSecret && c.Action == manifest.ActionCreate && os.Getenv(c.SecretRef.FromEnv) == "" {
+			ui.Error("Secret %s of %s reads $%s, which is not set.", c.Secret, c.Agent, c.SecretRef.FromEnv)
+			return fmt.Errorf("missing secret value")
+		}
+	}
+
+	if !applyYes {
+		_, _ = fmt.Fprintln(out)
+		confirmed, err := ui.Confirm("Apply these changes?")
+		if errors.Is(err, ui.ErrNoInput) {
+			ui.Error("Cannot ask for confirmation. Pass --yes to apply without prompting.")
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Info("Aborted")
+			return nil
+		}
+	}
+
+	_, _ = fmt.Fprintln(out)
+	for i, c := range changes {
+		if err := applyChange(client, c); err != nil {
+			ui.Error("Failed to %s %s: %v", c.Action, c, err)
+			if i > 0 {
+				ui.Info("%d of %d changes were applied. Run 'oken apply' again to retry the rest.", i, len(changes))
+			}
+			return err
+		}
+		ui.Success("%s %s", actionPastTense(c.Action), c)
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(plannedChanges(changes))
+	}
+
+	fmt.Println()
+	ui.Success("Applied %d changes", len(changes))
+	return nil
+}
+
+// planManifest loads a manifest and returns the changes that converge the
+// platform to it
+func planManifest(file string, prune bool) (*api.Client, []manifest.Change, error) {
+	m, err := manifest.Load(file)
+	if err != nil {
+		ui.Error("Failed to read %s: %v", file, err)
+		return nil, nil, err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return nil, nil, err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return nil, nil, fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	state, err := fetchState(client, m, prune)
+	if err != nil {
+		ui.Error("Failed to read platform state: %v", err)
+		return nil, nil, err
+	}
+
+	return client, manifest.Diff(m, state, prune), nil
+}
+
+// fetchState reads the platform state of the agents in a manifest. With
+// prune, it also lists the other agents, which would be deleted.
+func fetchState(client *api.Client, m *manifest.Manifest, prune bool) (*manifest.State, error) {
+	state := &manifest.State{
+		Agents:    make(map[string]*api.Agent),
+		Secrets:   make(map[string][]string),
+		Schedules: make(map[string][]api.Schedule),
+	}
+
+	if prune {
+		agents, err := client.ListAgents()
+		if err != nil {
+			return nil, fmt.Errorf("agents: %w", err)
+		}
+		for i := range agents.Agents {
+			state.Agents[agents.Agents[i].Slug] = &agents.Agents[i]
+		}
+	}
+
+	for _, a := range m.Agents {
+		agent, err := client.GetAgent(a.Slug)
+		if api.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("agent %s: %w", a.Slug, err)
+		}
+		state.Agents[a.Slug] = agent
+
+		secrets, err := client.ListSecrets(a.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("secrets of %s: %w", a.Slug, err)
+		}
+		for _, s := range secrets.Secrets {
+			// Global secrets aren't managed per agent
+			if s.AgentSlug != nil && *s.AgentSlug == a.Slug {
+				state.Secrets[a.Slug] = append(state.Secrets[a.Slug], s.Name)
+			}
+		}
+
+		schedules, err := client.ListSchedules(a.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("schedules of %s: %w", a.Slug, err)
+		}
+		state.Schedules[a.Slug] = schedules.Schedules
+	}
+	return state, nil
+}
+
+// printPlan lists changes to w with +, ~ and - markers and a summary count.
+// Secrets whose environment variable isn't set are flagged.
+func printPlan(w io.Writer, changes []manifest.Change) {
+	counts := make(map[manifest.Action]int)
+	for _, c := range changes {
+		counts[c.Action]++
+		switch c.Action {
+		case manifest.ActionCreate:
+			if c.Kind == manifest.KindSecret && os.Getenv(c.SecretRef.FromEnv) == "" {
+				_, _ = fmt.Fprintf(w, "  %s %s %s\n", ui.Green("+"), c, ui.Yellow("not set"))
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "  %s %s\n", ui.Green("+"), c)
+		case manifest.ActionUpdate:
+			_, _ = fmt.Fprintf(w, "  %s %s\n", ui.Yellow("~"), c)
+		case manifest.ActionDelete:
+			_, _ = fmt.Fprintf(w, "  %s %s\n", ui.Red("-"), c)
+		}
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintf(w, "%d to create, %d to update, %d to delete\n",
+		counts[manifest.ActionCreate], counts[manifest.ActionUpdate], counts[manifest.ActionDelete])
+}
+
+// applyChange makes one change on the platform
+func applyChange(client *api.Client, c manifest.Change) error {
+	switch c.Kind {
+	case manifest.KindAgent:
+		switch c.Action {
+		case manifest.ActionUpdate:
+			_, err := client.UpdateAgent(c.Agent, *c.Update)
+			return err
+		case manifest.ActionDelete:
+			_, err := client.DeleteAgent(c.Agent)
+			return err
+		}
+		_, err := deployDir(client, c.Path, c.Name, c.Agent)
+		return err
+	case manifest.KindScale:
+		_, err := client.ScaleAgent(c.Agent, *c.Scale)
+		return err
+	case manifest.KindSecret:
+		if c.Action == manifest.ActionDelete {
+			_, err := client.DeleteSecret(c.Secret, c.Agent)
+			return err
+		}
+		slug := c.Agent
+		_, err := client.SetSecret(c.Secret, os.Getenv(c.SecretRef.FromEnv), &slug)
+		return err
+	case manifest.KindSchedule:
+		if c.Action == manifest.ActionDelete {
+			_, err := client.DeleteSchedule(c.ScheduleID)
+			return err
+		}
+		_, err := client.CreateSchedule(c.Agent, c.Schedule.Cron, c.Schedule.Input)
+		return err
+	}
+	return fmt.Errorf("unsupported change %s %s", c.Action, c.Kind)
+}
+
+func actionPastTense(a manifest.Action) string {
+	switch a {
+	case manifest.ActionCreate:
+		return "Created"
+	case manifest.ActionUpdate:
+		return "Updated"
+	case manifest.ActionDelete:
+		return "Deleted"
+	}
+	return string(a)
+}
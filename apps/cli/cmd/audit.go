@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	auditSince string
+	auditActor string
+	auditAgent string
+	auditLimit int
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show account activity",
+	Long: `Show the audit log of your account, or of the selected organization:
+who deployed, stopped, deleted or changed which agent, and when. Events are
+listed newest first.
+
+--since takes a duration back from now (15m, 24h, 7d), a date or an RFC 3339
+timestamp.
+
+Examples:
+  oken audit
+  oken audit --since 30d --agent my-agent
+  oken audit --actor ana@example.com -o csv > audit.csv`,
+	Args: cobra.NoArgs,
+	RunE: runAudit,
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditSince, "since", "7d", "Show events after this time (e.g. 24h, 30d, 2026-01-31)")
+	auditCmd.Flags().StringVar(&auditActor, "actor", "", "Only show events by this user or token")
+	auditCmd.Flags().StringVar(&auditAgent, "agent", "", "Only show events for this agent")
+	auditCmd.Flags().IntVarP(&auditLimit, "limit", "n", 100, "Maximum number of events to show")
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	since, err := api.ParseSince(auditSince, time.Now())
+	if err != nil {
+		ui.Error("Invalid --since: %v", err)
+		return err
+	}
+	if auditLimit < 1 {
+		ui.Error("Invalid --limit %d. Use 1 or more.", auditLimit)
+		return fmt.Errorf("invalid limit")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.ListAuditEvents(api.AuditFilter{
+		Since: since,
+		Actor: auditActor,
+		Agent: auditAgent,
+		Limit: auditLimit,
+	})
+	if err != nil {
+		ui.Error("Failed to get audit log: %v", err)
+		return err
+	}
+
+	if ui.IsTemplate() {
+		for _, e := range resp.Events {
+			if err := ui.Template(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if ui.IsStructured() {
+		return ui.Result(resp.Events)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(resp.Events))
+		for _, e := range resp.Events {
+			rows = append(rows, []string{e.Time, e.Actor, e.Action, stringValue(e.AgentSlug)})
+		}
+		return ui.Table([]string{"time", "actor", "action", "agent"}, rows)
+	}
+
+	if len(resp.Events) == 0 {
+		ui.Info("No events since %s", since.Local().Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tACTOR\tACTION\tAGENT")
+	for _, e := range resp.Events {
+		agent := "-"
+		if e.AgentSlug != nil && *e.AgentSlug != "" {
+			agent = *e.AgentSlug
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Time, e.Actor, e.Action, agent)
+	}
+	_ = w.Flush()
+
+	if len(resp.Events) == auditLimit {
+		fmt.Println()
+		ui.Info("Showing the latest %d events. Use a larger --limit to see older events.", auditLimit)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+// bulkResult is the outcome for one agent of a command run on several
+type bulkResult struct {
+	Slug   string `json:"slug"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// runBulk calls fn for each slug, up to concurrency at a time. fn returns
+// what became of the agent, such as its new status. Results are in the order
+// of slugs.
+func runBulk(slugs []string, concurrency int, fn func(slug string) (string, error)) []bulkResult {
+	results := make([]bulkResult, len(slugs))
+	jobs := make(chan int)
+	// mu serializes progress events and the counter
+	var mu sync.Mutex
+	done := 0
+	var wg sync.WaitGroup
+	for range min(concurrency, len(slugs)) {
+		wg.Go(func() {
+			for i := range jobs {
+				result, err := fn(slugs[i])
+				results[i] = bulkResult{Slug: slugs[i], Result: result}
+				if err != nil {
+					results[i].Error = err.Error()
+				}
+
+				mu.Lock()
+				done++
+				if ui.IsProgressJSON() {
+					ui.Progress("bulk", done*100/len(slugs), "Done %d of %d", done, len(slugs))
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	for i := range slugs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// printBulkResults prints one row per agent, or the results in the selected
+// output format, and returns an error if any agent failed. action names what
+// was done, as in "Stopped 3 agents".
+func printBulkResults(results []bulkResult, action string) error {
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+
+	switch {
+	case ui.IsStructured():
+		if err := ui.Result(results); err != nil {
+			return err
+		}
+	case ui.IsTabular():
+		rows := make([][]string, 0, len(results))
+		for _, r := range results {
+			rows = append(rows, []string{r.Slug, r.Result, r.Error})
+		}
+		if err := ui.Table([]string{"slug", "result", "error"}, rows); err != nil {
+			return err
+		}
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "SLUG\tRESULT")
+		for _, r := range results {
+			if r.Error != "" {
+				_, _ = fmt.Fprintf(w, "%s\t%s %s\n", r.Slug, ui.Red("failed:"), r.Error)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", r.Slug, ui.Green(r.Result))
+		}
+		_ = w.Flush()
+		fmt.Println()
+	}
+
+	if failed > 0 {
+		ui.Error("%d of %d agents failed", failed, len(results))
+		return fmt.Errorf("%d of %d agents failed", failed, len(results))
+	}
+	ui.Success("%s %d agents", action, len(results))
+	return nil
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var chatCmd = &cobra.Command{
+	Use:         "chat <slug>",
+	Short:       "Chat with an agent",
+	Annotations: textOutput,
+	Long: `Start a conversation with an agent. Each message you type is sent to the
+agent in the same session, and its reply is printed as it streams in.
+
+End a line with \ to continue the message on the next line, or put """ on
+its own line before and after a multi-line message.
+
+Commands:
+  /reset         Start a new session and clear the transcript
+  /save <file>   Save the transcript as JSON
+  /help          Show the commands
+  /exit          Leave the chat (or press Ctrl-D)
+
+Press Ctrl-C to stop a reply.
+
+Examples:
+  oken chat my-agent
+  echo "Summarize today's tickets" | oken chat my-agent`,
+	Args: cobra.ExactArgs(1),
+	RunE: runChat,
+}
+
+func init() {
+	rootCmd.AddCommand(chatCmd)
+}
+
+// chatTranscript is written by /save
+type chatTranscript struct {
+	Agent     string            `json:"agent"`
+	SessionID string            `json:"sessionId"`
+	Messages  []api.ChatMessage `json:"messages"`
+}
+
+// chatHelp lists the REPL commands
+const chatHelp = `/reset         Start a new session and clear the transcript
+/save <file>   Save the transcript as JSON
+/help          Show the commands
+/exit          Leave the chat (or press Ctrl-D)`
+
+// multilineDelimiter starts and ends a multi-line message
+const multilineDelimiter = `"""`
+
+func runChat(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	// Without a terminal, messages are read from stdin without prompts and
+	// the first failure ends the chat
+	interactive := ui.CanPrompt()
+	transcript := &chatTranscript{Agent: slug, SessionID: api.NewSessionID()}
+	if interactive {
+		ui.Info("Chatting with %s. Type /help for commands, Ctrl-D to exit.", slug)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		message, err := readChatMessage(reader, interactive)
+		if errors.Is(err, io.EOF) {
+			if interactive {
+				fmt.Println()
+			}
+			return nil
+		}
+		if err != nil {
+			ui.Error("Failed to read input: %v", err)
+			return err
+		}
+
+		message = strings.TrimSpace(message)
+		if message == "" {
+			continue
+		}
+
+		if command, arg, ok := parseChatCommand(message); ok {
+			switch command {
+			case "/exit", "/quit":
+				return nil
+			case "/help":
+				fmt.Println(chatHelp)
+			case "/reset":
+				transcript.SessionID = api.NewSessionID()
+				transcript.Messages = nil
+				ui.Success("Started a new session")
+			case "/save":
+				if arg == "" {
+					ui.Error("Usage: /save <file>")
+					continue
+				}
+				if err := saveTranscript(arg, transcript); err != nil {
+					ui.Error("Failed to save transcript: %v", err)
+					continue
+				}
+				ui.Success("Saved %d messages to %s", len(transcript.Messages), arg)
+			default:
+				ui.Error("Unknown command %s. Type /help for commands.", command)
+			}
+			continue
+		}
+
+		err = sendChatMessage(client, transcript, message, interactive)
+		if err != nil && (!interactive || api.IsNotFound(err)) {
+			return err
+		}
+	}
+}
+
+// readChatMessage reads one message, joining lines that end with \ and lines
+// between """ delimiters. It returns io.EOF once input ends.
+func readChatMessage(r *bufio.Reader, prompt bool) (string, error) {
+	var lines []string
+	block := false
+	for {
+		if prompt {
+			if len(lines) == 0 && !block {
+				fmt.Print(ui.Bold("you> "))
+			} else {
+				fmt.Print("...> ")
+			}
+		}
+
+		line, err := r.ReadString('\n')
+		if err != nil && line == "" {
+			if errors.Is(err, io.EOF) && len(lines) > 0 {
+				return strings.Join(lines, "\n"), nil
+			}
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.TrimSpace(line) == multilineDelimiter:
+			if block {
+				return strings.Join(lines, "\n"), nil
+			}
+			block = true
+		case block:
+			lines = append(lines, line)
+		case strings.HasSuffix(line, `\`):
+			lines = append(lines, strings.TrimSuffix(line, `\`))
+		default:
+			return strings.Join(append(lines, line), "\n"), nil
+		}
+	}
+}
+
+// parseChatCommand splits a /command and its argument
+func parseChatCommand(message string) (command, arg string, ok bool) {
+	if !strings.HasPrefix(message, "/") || strings.Contains(message, "\n") {
+		return "", "", false
+	}
+	command, arg, _ = strings.Cut(message, " ")
+	return strings.ToLower(command), strings.TrimSpace(arg), true
+}
+
+// sendChatMessage sends a message in the transcript's session and streams the
+// reply to stdout. Ctrl-C stops the reply and keeps what arrived.
+func sendChatMessage(client *api.Client, transcript *chatTranscript, message string, interactive bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// The reply ends with a newline even if the agent's text doesn't
+	atLineStart := true
+	if interactive {
+		fmt.Print(ui.Cyan(transcript.Agent + "> "))
+		atLineStart = false
+	}
+	reply, err := client.StreamChat(ctx, transcript.Agent, api.ChatRequest{
+		SessionID: transcript.SessionID,
+		Message:   message,
+	}, func(text string) {
+		if text != "" {
+			fmt.Print(text)
+			atLineStart = strings.HasSuffix(text, "\n")
+		}
+	})
+	if !atLineStart {
+		fmt.Println()
+	}
+
+	var agentErr *api.AgentError
+	switch {
+	case errors.Is(err, context.Canceled):
+		ui.Warning("Stopped the reply")
+	case errors.As(err, &agentErr):
+		ui.Error("Agent error: %s", agentErr.Message)
+		transcript.Messages = append(transcript.Messages, api.ChatMessage{Role: api.RoleUser, Content: message})
+		return fmt.Errorf("agent error: %s", agentErr.Message)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		ui.Error("The connection closed before the reply finished")
+		return err
+	case api.IsNotFound(err):
+		ui.Error("Agent %s not found", transcript.Agent)
+		return err
+	case err != nil:
+		ui.Error("Failed to send message: %v", err)
+		return err
+	}
+
+	transcript.Messages = append(transcript.Messages,
+		api.ChatMessage{Role: api.RoleUser, Content: message},
+		api.ChatMessage{Role: api.RoleAssistant, Content: reply.Content},
+	)
+	return nil
+}
+
+// saveTranscript writes the conversation so far as indented JSON
+func saveTranscript(path string, transcript *chatTranscript) error {
+	if transcript.Messages == nil {
+		transcript.Messages = []api.ChatMessage{}
+	}
+	data, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
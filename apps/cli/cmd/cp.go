@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/pack"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var cpCmd = &cobra.Command{
+	Use:         "cp <src> <dest>",
+	Short:       "Copy files to or from a running agent",
+	Annotations: textOutput,
+	Long: `Copy files or directories between your machine and a running agent.
+Refer to a path inside the agent as <slug>:<path>.
+
+When copying to an agent, a destination ending in / is treated as a
+directory and the source keeps its name.
+
+Examples:
+  oken cp my-agent:/app/output.json ./
+  oken cp my-agent:/app/results ./results
+  oken cp ./fixtures my-agent:/app/`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCp,
+}
+
+func init() {
+	rootCmd.AddCommand(cpCmd)
+}
+
+// remotePathPattern matches <slug>:<path>. Slugs are at least two characters
+// so Windows drive letters like C:\ are treated as local paths.
+var remotePathPattern = regexp.MustCompile(`^([a-z0-9][a-z0-9-]*[a-z0-9]):(.+)$`)
+
+// parseCopyPath splits a <slug>:<path> argument, reporting whether it is remote
+func parseCopyPath(arg string) (slug, p string, remote bool) {
+	if m := remotePathPattern.FindStringSubmatch(arg); m != nil {
+		return m[1], m[2], true
+	}
+	return "", arg, false
+}
+
+func runCp(cmd *cobra.Command, args []string) error {
+	srcSlug, src, srcRemote := parseCopyPath(args[0])
+	destSlug, dest, destRemote := parseCopyPath(args[1])
+
+	if srcRemote == destRemote {
+		ui.Error("Exactly one of source and destination must be <slug>:<path>.")
+		return fmt.Errorf("invalid copy paths")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if srcRemote {
+		return copyFromAgent(ctx, client, srcSlug, src, dest)
+	}
+	return copyToAgent(ctx, client, src, destSlug, dest)
+}
+
+// copyFromAgent streams a tar archive of the remote path and extracts it locally
+func copyFromAgent(ctx context.Context, client *api.Client, slug, src, dest string) error {
+	src = path.Clean(src)
+	name := path.Base(src)
+
+	var archive, stderr bytes.Buffer
+	command := []string{"tar", "-czf", "-", "-C", path.Dir(src), name}
+	code, err := client.Exec(ctx, slug, command, &archive, &stderr)
+	if err != nil {
+		ui.Error("Failed to copy from agent: %v", err)
+		return err
+	}
+	if code != 0 {
+		ui.Error("Failed to read %s:%s: %s", slug, src, strings.TrimSpace(stderr.String()))
+		return fmt.Errorf("tar exited with status %d", code)
+	}
+
+	// Copying into an existing directory keeps the source name; otherwise
+	// the copy is renamed to dest
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		if err := pack.ExtractTarball(&archive, dest); err != nil {
+			ui.Error("Failed to extract files: %v", err)
+			return err
+		}
+		ui.Success("Copied %s:%s to %s", slug, src, filepath.Join(dest, name))
+		return nil
+	}
+
+	tmp, err := os.MkdirTemp(filepath.Dir(dest), ".oken-cp-")
+	if err != nil {
+		ui.Error("Failed to create temporary directory: %v", err)
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if err := pack.ExtractTarball(&archive, tmp); err != nil {
+		ui.Error("Failed to extract files: %v", err)
+		return err
+	}
+	if err := os.Rename(filepath.Join(tmp, name), dest); err != nil {
+		ui.Error("Failed to write %s: %v", dest, err)
+		return err
+	}
+
+	ui.Success("Copied %s:%s to %s", slug, src, dest)
+	return nil
+}
+
+// copyToAgent archives the local path and extracts it in the agent with tar
+func copyToAgent(ctx context.Context, client *api.Client, src, slug, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		ui.Error("Cannot read %s: %v", src, err)
+		return err
+	}
+	if !info.Mode().IsRegular() && !info.IsDir() {
+		ui.Error("%s is not a regular file or directory", src)
+		return fmt.Errorf("unsupported file type")
+	}
+
+	dir, name := path.Clean(dest), filepath.Base(filepath.Clean(src))
+	if !strings.HasSuffix(dest, "/") {
+		dir, name = path.Dir(dir), path.Base(dir)
+	}
+
+	archive, err := pack.ArchivePath(src, name)
+	if err != nil {
+		ui.Error("Failed to package %s: %v", src, err)
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+	command := []string{"tar", "-xzf", "-", "-C", dir}
+	code, err := client.ExecWithInput(ctx, slug, command, archive.Bytes(), &stdout, &stderr)
+	if err != nil {
+		ui.Error("Failed to copy to agent: %v", err)
+		return err
+	}
+	if code != 0 {
+		ui.Error("Failed to write %s:%s: %s", slug, dest, strings.TrimSpace(stderr.String()))
+		return fmt.Errorf("tar exited with status %d", code)
+	}
+
+	ui.Success("Copied %s to %s:%s", src, slug, path.Join(dir, name))
+	return nil
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	curlMethod  string
+	curlHeaders []string
+	curlData    string
+	curlInclude bool
+)
+
+var curlCmd = &cobra.Command{
+	Use:         "curl <slug> [path]",
+	Short:       "Send an HTTP request to an agent's endpoint",
+	Annotations: textOutput,
+	Long: `Send an authenticated HTTP request to an agent's public endpoint and print
+the response body, for testing the deployed HTTP surface without copying
+tokens around.
+
+The request carries your Oken token and organization, unless -H sets the
+Authorization header. The method defaults to GET, or POST with --data.
+
+--data sends a request body: a string, @file to read a file, or @- to read
+stdin. Bodies that are valid JSON are sent as application/json unless -H
+sets the Content-Type.
+
+The command fails if the endpoint responds with a 4xx or 5xx status, after
+printing the body.
+
+Examples:
+  oken curl my-agent /health
+  oken curl my-agent /v1/chat -d '{"message": "hi"}'
+  oken curl my-agent /v1/items/42 -X DELETE -H 'X-Debug: 1' -i
+  oken curl my-agent /upload -d @payload.json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runCurl,
+}
+
+func init() {
+	curlCmd.Flags().StringVarP(&curlMethod, "request", "X", "", "HTTP method (default GET, or POST with --data)")
+	curlCmd.Flags().StringArrayVarP(&curlHeaders, "header", "H", nil, "Extra header, as 'Name: value' (repeatable)")
+	curlCmd.Flags().StringVarP(&curlData, "data", "d", "", "Request body, @file to read a file, or @- for stdin")
+	curlCmd.Flags().BoolVarP(&curlInclude, "include", "i", false, "Print the response status and headers")
+	rootCmd.AddCommand(curlCmd)
+}
+
+func runCurl(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+	path := ""
+	if len(args) == 2 {
+		path = args[1]
+	}
+
+	header, err := api.ParseHeaders(curlHeaders)
+	if err != nil {
+		ui.Error("Invalid --header: %v", err)
+		return fmt.Errorf("invalid flags")
+	}
+
+	body, err := readCurlData(curlData)
+	if err != nil {
+		ui.Error("Failed to read --data: %v", err)
+		return err
+	}
+	if body != nil && header.Get("Content-Type") == "" && json.Valid(body) {
+		header.Set("Content-Type", "application/json")
+	}
+
+	method := strings.ToUpper(curlMethod)
+	if method == "" {
+		method = http.MethodGet
+		if body != nil {
+			method = http.MethodPost
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	agent, err := client.GetAgent(slug)
+	if err != nil {
+		err = client.WithSuggestions(slug, err)
+		ui.Error("Failed to get agent: %v", err)
+		return err
+	}
+	endpoint := stringValue(agent.Endpoint)
+	if endpoint == "" {
+		ui.Error("Agent %s has no endpoint (status: %s). Is it running?", slug, ui.AgentStatus(agent.Status))
+		return fmt.Errorf("no endpoint")
+	}
+
+	// The response body is printed to stdout, so keep it free of status messages
+	ui.ReserveStdout()
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = strings.NewReader(string(body))
+	}
+	resp, err := client.EndpointRequest(endpoint, method, path, header, bodyReader)
+	if err != nil {
+		ui.Error("Request failed: %v", err)
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if curlInclude {
+		printResponseHeader(resp)
+	}
+	if _, err := io.Copy(os.Stdout, resp.Body); err != nil {
+		ui.Error("Failed to read response: %v", err)
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		ui.Error("%s %s responded with %s", method, resp.Request.URL, resp.Status)
+		return fmt.Errorf("request failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// readCurlData returns the body given with --data, reading it from a file or
+// stdin for @file and @-. It returns nil if no body was given.
+func readCurlData(data string) ([]byte, error) {
+	if data == "" {
+		return nil, nil
+	}
+	source, ok := strings.CutPrefix(data, "@")
+	if !ok {
+		return []byte(data), nil
+	}
+	if source == "-" {
+		return io.ReadAll(io.LimitReader(os.Stdin, maxInputSize))
+	}
+	return os.ReadFile(source)
+}
+
+// printResponseHeader prints the status line and headers of a response, as
+// curl -i does
+func printResponseHeader(resp *http.Response) {
+	fmt.Printf("%s %s\n", resp.Proto, resp.Status)
+	names := make([]string, 0, len(resp.Header))
+	for name := range resp.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range resp.Header[name] {
+			fmt.Printf("%s: %s\n", name, value)
+		}
+	}
+	fmt.Println()
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	debugPort      int
+	debugLocalPort int
+	debugWait      bool
+	debugTimeout   time.Duration
+)
+
+var debugCmd = &cobra.Command{
+	Use:         "debug <slug>",
+	Short:       "Attach a debugger to a deployed agent",
+	Annotations: textOutput,
+	Long: `Restart a Python agent with debugpy listening, and forward the debugger
+port to this machine so VS Code, PyCharm or any other debugpy client can
+attach to the deployed agent, set breakpoints and step through requests.
+
+The attach configuration for VS Code is printed once the agent is up; paste
+it into .vscode/launch.json, open the agent's directory and start debugging.
+--wait holds the agent's startup until a debugger attaches, to debug code
+that runs on import.
+
+Press Ctrl+C to stop; the agent is restarted without the debugger. A paused
+agent doesn't answer requests, so avoid debugging production agents.
+
+Examples:
+  oken debug my-agent
+  oken debug my-agent --wait
+  oken debug my-agent --local-port 5679`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDebug,
+}
+
+func init() {
+	debugCmd.Flags().IntVar(&debugPort, "port", api.DefaultDebugPort, "Port debugpy listens on in the agent")
+	debugCmd.Flags().IntVar(&debugLocalPort, "local-port", api.DefaultDebugPort, "Local port to forward, 0 to pick a free one")
+	debugCmd.Flags().BoolVar(&debugWait, "wait", false, "Hold the agent's startup until a debugger attaches")
+	debugCmd.Flags().DurationVar(&debugTimeout, "timeout", 2*time.Minute, "How long to wait for the agent to restart")
+	rootCmd.AddCommand(debugCmd)
+}
+
+func runDebug(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	if debugLocalPort < 0 || debugLocalPort > 65535 {
+		ui.Error("Invalid --local-port %d. Use a port from 1 to 65535, or 0 for a free one.", debugLocalPort)
+		return fmt.Errorf("invalid flags")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	agent, err := client.GetAgent(slug)
+	if err != nil {
+		err = client.WithSuggestions(slug, err)
+		ui.Error("Failed to get agent: %v", err)
+		return err
+	}
+	if runtime := stringValue(agent.Runtime); runtime != "" && runtime != "python" {
+		ui.Error("Agent %s runs on %s. Only Python agents can be debugged.", slug, runtime)
+		return fmt.Errorf("unsupported runtime")
+	}
+
+	// Listen before restarting, so a taken port doesn't restart the agent
+	// for nothing
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(debugLocalPort))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		ui.Error("Failed to listen on %s: %v", address, err)
+		return err
+	}
+	defer func() { _ = listener.Close() }()
+
+	ui.Info("Restarting %s with debugpy on port %d...", slug, debugPort)
+
+	resp, err := client.StartDebug(slug, api.DebugRequest{Port: debugPort, WaitForClient: debugWait})
+	if err != nil {
+		ui.Error("Failed to start debugger: %v", err)
+		return err
+	}
+	// The agent keeps the debugger until it is restarted without it, so do
+	// that however this command ends
+	defer stopDebug(client, slug)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := waitForAgentRunning(ctx, client, slug, debugTimeout); err != nil {
+		if ctx.Err() != nil {
+			fmt.Println()
+			return nil
+		}
+		ui.Error("%v", err)
+		return err
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	ui.Success("Debugger forwarded: %s -> %s:%d", listener.Addr(), slug, resp.Port)
+	fmt.Println()
+	printDebugAttach(slug, port, resp.RemoteRoot)
+	fmt.Println()
+	if debugWait {
+		ui.Info("The agent starts once a debugger attaches. Press Ctrl+C to stop")
+	} else {
+		ui.Info("Press Ctrl+C to stop")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		acceptForwarded(ctx, client, slug, listener, resp.Port)
+	}()
+
+	<-ctx.Done()
+	_ = listener.Close()
+	<-done
+	fmt.Println()
+	return nil
+}
+
+// waitForAgentRunning polls the agent until it is running again after a
+// restart, ctx is done, or timeout passes
+func waitForAgentRunning(ctx context.Context, client *api.Client, slug string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		agent, err := client.GetAgent(slug)
+		if err != nil {
+			return fmt.Errorf("failed to get agent: %w", err)
+		}
+		switch {
+		case agent.Status.IsHealthy():
+			return nil
+		case agent.Status.IsTerminal():
+			return fmt.Errorf("agent %s is %s after restarting with the debugger; see 'oken logs %s'", slug, ui.AgentStatus(agent.Status), slug)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("agent %s not running after %s", slug, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// stopDebug restarts the agent without the debugger
+func stopDebug(client *api.Client, slug string) {
+	ui.Info("Restarting %s without the debugger...", slug)
+	if _, err := client.StopDebug(slug); err != nil {
+		ui.Error("Failed to stop debugger: %v. The agent keeps it until 'oken debug %s' is run and stopped again.", err, slug)
+		return
+	}
+	ui.Success("Debugger stopped: %s", slug)
+}
+
+// debugLaunchConfig is a VS Code launch configuration that attaches to
+// debugpy
+type debugLaunchConfig struct {
+	Name         string             `json:"name"`
+	Type         string             `json:"type"`
+	Request      string             `json:"request"`
+	Connect      debugConnect       `json:"connect"`
+	PathMappings []debugPathMapping `json:"pathMappings"`
+	JustMyCode   bool               `json:"justMyCode"`
+}
+
+type debugConnect struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type debugPathMapping struct {
+	LocalRoot  string `json:"localRoot"`
+	RemoteRoot string `json:"remoteRoot"`
+}
+
+// printDebugAttach prints how to attach VS Code and other debugpy clients to
+// the forwarded port
+func printDebugAttach(slug string, port int, remoteRoot string) {
+	if remoteRoot == "" {
+		remoteRoot = "."
+	}
+	launch := debugLaunchConfig{
+		Name:         "Attach to " + slug + " (oken)",
+		Type:         "debugpy",
+		Request:      "attach",
+		Connect:      debugConnect{Host: "127.0.0.1", Port: port},
+		PathMappings: []debugPathMapping{{LocalRoot: "${workspaceFolder}", RemoteRoot: remoteRoot}},
+	}
+	data, _ := json.MarshalIndent(launch, "", "  ")
+
+	fmt.Println("VS Code (.vscode/launch.json, under \"configurations\"):")
+	fmt.Println(string(data))
+	fmt.Println()
+	fmt.Println("PyCharm and other debugpy clients:")
+	fmt.Println("  Host:         127.0.0.1")
+	fmt.Printf("  Port:         %d\n", port)
+	fmt.Printf("  Path mapping: <agent directory> = %s\n", remoteRoot)
+}
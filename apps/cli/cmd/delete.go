@@ -1,9 +1,10 @@
 package cmd
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -13,23 +14,43 @@ import (
 	"github.com/neult/oken/apps/cli/internal/ui"
 )
 
-var deleteForce bool
+var (
+	deleteForce       bool
+	deleteConcurrency int
+)
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete <slug>",
+	Use:   "delete <slug|pattern>",
 	Short: "Delete an agent",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runDelete,
+	Long: `Delete an agent.
+
+Given a glob pattern such as 'test-*', every agent whose slug matches is
+deleted, up to --concurrency at a time. The matched agents are listed first,
+and the pattern must be typed again to confirm. Quote the pattern so the
+shell doesn't expand it. --force skips the confirmation.
+
+Examples:
+  oken delete my-agent
+  oken delete 'test-*'
+  oken delete 'exp-202?-*' --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDelete,
 }
 
 func init() {
 	deleteCmd.Flags().BoolVarP(&deleteForce, "force", "f", false, "Skip confirmation prompt")
+	deleteCmd.Flags().IntVarP(&deleteConcurrency, "concurrency", "j", 4, "How many agents to delete at once with a pattern")
 	rootCmd.AddCommand(deleteCmd)
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
 	slug := args[0]
 
+	if deleteConcurrency < 1 {
+		ui.Error("Invalid --concurrency %d. Use 1 or more.", deleteConcurrency)
+		return fmt.Errorf("invalid concurrency")
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		ui.Error("Failed to load config: %v", err)
@@ -41,31 +62,104 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
+	if isSlugPattern(slug) {
+		return deleteMatching(newClient(cfg), slug)
+	}
+
 	if !deleteForce {
-		fmt.Printf("Are you sure you want to delete agent '%s'? [y/N] ", slug)
-		reader := bufio.NewReader(os.Stdin)
-		response, err := reader.ReadString('\n')
+		confirmed, err := ui.Confirm(fmt.Sprintf("Are you sure you want to delete agent '%s'?", slug))
+		if errors.Is(err, ui.ErrNoInput) {
+			ui.Error("Cannot ask for confirmation. Pass --force to delete without prompting.")
+			return err
+		}
 		if err != nil {
 			return err
 		}
-		response = strings.TrimSpace(strings.ToLower(response))
-		if response != "y" && response != "yes" {
+		if !confirmed {
 			ui.Info("Aborted")
 			return nil
 		}
 	}
 
-	client := api.NewClient(cfg.Endpoint, cfg.Token)
+	client := newClient(cfg)
 
 	ui.Info("Deleting agent %s...", slug)
 
 	_, err = client.DeleteAgent(slug)
 	if err != nil {
+		err = client.WithSuggestions(slug, err)
 		ui.Error("Failed to delete agent: %v", err)
 		return err
 	}
 
+	if ui.IsStructured() {
+		return ui.Result(bulkResult{Slug: slug, Result: "deleted"})
+	}
+
 	ui.Success("Agent deleted: %s", slug)
 
 	return nil
 }
+
+// isSlugPattern reports whether s is a glob pattern rather than a slug
+func isSlugPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// deleteMatching deletes every agent whose slug matches pattern, after
+// listing them and having the user type the pattern to confirm
+func deleteMatching(client *api.Client, pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		ui.Error("Invalid pattern %q: %v", pattern, err)
+		return err
+	}
+
+	resp, err := client.ListAgents()
+	if err != nil {
+		ui.Error("Failed to list agents: %v", err)
+		return err
+	}
+
+	var matched []api.Agent
+	for _, agent := range resp.Agents {
+		if ok, _ := path.Match(pattern, agent.Slug); ok {
+			matched = append(matched, agent)
+		}
+	}
+	if len(matched) == 0 {
+		ui.Info("No agents match %s", pattern)
+		return nil
+	}
+
+	// The preview goes to stderr, like the prompt, so results stay parseable
+	ui.Info("%d agents match %s:", len(matched), pattern)
+	slugs := make([]string, 0, len(matched))
+	for _, agent := range matched {
+		_, _ = fmt.Fprintf(os.Stderr, "  %s (%s)\n", agent.Slug, ui.AgentStatus(agent.Status))
+		slugs = append(slugs, agent.Slug)
+	}
+
+	if !deleteForce {
+		confirmed, err := ui.ConfirmTyped(fmt.Sprintf("Delete these %d agents?", len(matched)), pattern)
+		if errors.Is(err, ui.ErrNoInput) {
+			ui.Error("Cannot ask for confirmation. Pass --force to delete without prompting.")
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Info("Aborted")
+			return nil
+		}
+	}
+
+	ui.Info("Deleting %d agents, %d at a time...", len(slugs), min(deleteConcurrency, len(slugs)))
+	results := runBulk(slugs, deleteConcurrency, func(slug string) (string, error) {
+		if _, err := client.DeleteAgent(slug); err != nil {
+			return "", err
+		}
+		return "deleted", nil
+	})
+	return printBulkResults(results, "Deleted")
+}
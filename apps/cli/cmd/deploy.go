@@ -1,71 +1,402 @@
 package cmd
 
 import (
+	"cmp"
+	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
 
 	"github.com/neult/oken/apps/cli/internal/api"
 	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/deps"
+	"github.com/neult/oken/apps/cli/internal/git"
 	"github.com/neult/oken/apps/cli/internal/pack"
+	"github.com/neult/oken/apps/cli/internal/sign"
 	"github.com/neult/oken/apps/cli/internal/ui"
 )
 
 type okenConfig struct {
-	Name          string `toml:"name"`
-	Slug          string `toml:"slug"`
-	PythonVersion string `toml:"python_version"`
-	Entrypoint    string `toml:"entrypoint"`
+	Name          string          `toml:"name"`
+	Slug          string          `toml:"slug"`
+	Description   string          `toml:"description"`
+	Repository    string          `toml:"repository"`
+	Owner         string          `toml:"owner"`
+	Runtime       string          `toml:"runtime"`
+	PythonVersion string          `toml:"python_version"`
+	NodeVersion   string          `toml:"node_version"`
+	Entrypoint    string          `toml:"entrypoint"`
+	Resources     resourcesConfig `toml:"resources"`
+	Lifecycle     lifecycleConfig `toml:"lifecycle"`
+	Package       packageConfig   `toml:"package"`
+}
+
+type packageConfig struct {
+	Gitignore     *bool    `toml:"gitignore"`
+	IncludeHidden bool     `toml:"include_hidden"`
+	Include       []string `toml:"include"`
+	Exclude       []string `toml:"exclude"`
+	Compression   string   `toml:"compression"`
+	Level         int      `toml:"compression_level"`
+}
+
+// loadOkenConfig reads oken.toml from dir, returning an empty config if it
+// doesn't exist
+func loadOkenConfig(dir string) (okenConfig, error) {
+	var okenCfg okenConfig
+	path := filepath.Join(dir, "oken.toml")
+	if _, err := os.Stat(path); err == nil {
+		if _, err := toml.DecodeFile(path, &okenCfg); err != nil {
+			return okenCfg, err
+		}
+	}
+	return okenCfg, nil
+}
+
+// packOptions returns the packaging options set by the [package] table
+func (c okenConfig) packOptions() pack.Options {
+	return pack.Options{
+		NoGitignore:   c.Package.Gitignore != nil && !*c.Package.Gitignore,
+		IncludeHidden: c.Package.IncludeHidden,
+		Include:       c.Package.Include,
+		Exclude:       c.Package.Exclude,
+		Compression:   pack.Compression(c.Package.Compression),
+		Level:         c.Package.Level,
+	}
+}
+
+type resourcesConfig struct {
+	Memory  string `toml:"memory"`
+	CPU     string `toml:"cpu"`
+	Timeout string `toml:"timeout"`
+}
+
+// toAPI validates the [resources] table and converts it for the deploy request
+func (r resourcesConfig) toAPI() (*api.Resources, error) {
+	if r.Memory == "" && r.CPU == "" && r.Timeout == "" {
+		return nil, nil
+	}
+	if r.CPU != "" {
+		if cpu, err := strconv.ParseFloat(r.CPU, 64); err != nil || cpu <= 0 {
+			return nil, fmt.Errorf("invalid cpu %q: must be a positive number of cores", r.CPU)
+		}
+	}
+	if r.Timeout != "" {
+		if d, err := time.ParseDuration(r.Timeout); err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q: must be a duration like 60s", r.Timeout)
+		}
+	}
+	return &api.Resources{Memory: r.Memory, CPU: r.CPU, Timeout: r.Timeout}, nil
+}
+
+type lifecycleConfig struct {
+	MinInstances  *int   `toml:"min_instances"`
+	Autosleep     string `toml:"autosleep"`
+	RestartPolicy string `toml:"restart_policy"`
+}
+
+// toAPI validates the [lifecycle] table and converts it for the deploy request
+func (l lifecycleConfig) toAPI() (*api.Lifecycle, error) {
+	lifecycle := api.Lifecycle{MinInstances: l.MinInstances, Autosleep: l.Autosleep, RestartPolicy: l.RestartPolicy}
+	if lifecycle.IsZero() {
+		return nil, nil
+	}
+	if err := lifecycle.Validate(); err != nil {
+		return nil, err
+	}
+	return &lifecycle, nil
+}
+
+// runtime returns the configured runtime, defaulting to python
+func (c okenConfig) runtime() string {
+	if c.Runtime == "" {
+		return "python"
+	}
+	return c.Runtime
+}
+
+// runtimeVersion returns the version pin for the configured runtime
+func (c okenConfig) runtimeVersion() string {
+	if c.runtime() == "node" {
+		return c.NodeVersion
+	}
+	return c.PythonVersion
 }
 
 var (
-	deployName string
-	deploySlug string
+	deployName          string
+	deploySlug          string
+	deployEnv           string
+	deployBuild         string
+	deployNoGitignore   bool
+	deployAllowSecrets  bool
+	deployWait          bool
+	deployNoLogs        bool
+	deployTimeout       time.Duration
+	deployUploadTimeout time.Duration
+	deployConcurrency   int
+	deployGit           string
+	deployTarball       string
+	deploySign          bool
+	deploySigningKey    string
+	deployStrict        bool
+	deployMessage       string
+	deployLabels        []string
+	deployDescription   string
+	deployRepository    string
+	deployOwner         string
+
+	// deployLabelSet is deployLabels parsed, sent with every deploy
+	deployLabelSet map[string]string
+
+	// signingKey signs packages when --sign is set, and signingPublicKey is
+	// its PEM public key, sent to identify the signer
+	signingKey       ed25519.PrivateKey
+	signingPublicKey string
 )
 
 var deployCmd = &cobra.Command{
-	Use:   "deploy",
+	Use:   "deploy [dir...]",
 	Short: "Deploy agent to platform",
-	Args:  cobra.NoArgs,
-	RunE:  runDeploy,
+	Long: `Package and deploy the agent in the current directory.
+
+Pass directories to deploy several agents at once, each from its own
+oken.toml. Up to --concurrency agents are packaged and uploaded in parallel,
+and failures are summarized at the end.
+
+With --git, the agent is deployed from a repository instead: the ref is
+cloned, the given subdirectory packaged, and the commit SHA recorded on the
+deployment.
+
+With --tarball, a gzipped tar archive built elsewhere is uploaded as is,
+read from a file or from stdin with '-'. Name and slug come from the flags
+or oken.toml in the current directory.
+
+With --sign, the package digest is signed with a local ed25519 key and the
+signature stored with the deployment, to be checked with 'oken verify'.
+
+Examples:
+  oken deploy
+  oken deploy --wait
+  oken deploy -m "fix retry loop"
+  oken deploy --label team=ml --label tier=prod
+  oken deploy --description "Summarizes support tickets" --owner ml-team@acme.com
+  oken deploy --git https://github.com/acme/agents#main:bots/support
+  oken deploy --tarball dist/agent.tar.gz
+  oken deploy --sign
+  oken deploy agents/* --concurrency 4`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runDeploy,
 }
 
 func init() {
 	deployCmd.Flags().StringVarP(&deployName, "name", "n", "", "Agent name (overrides oken.toml)")
 	deployCmd.Flags().StringVarP(&deploySlug, "slug", "s", "", "Agent slug (overrides oken.toml)")
+	deployCmd.Flags().StringVarP(&deployEnv, "env", "e", "", "Target environment (e.g. staging, prod)")
+	deployCmd.Flags().StringVarP(&deployMessage, "message", "m", "", "Describe the release, shown in 'oken deployments list'")
+	deployCmd.Flags().StringSliceVar(&deployLabels, "label", nil, "Add a key=value label to the agent (repeatable)")
+	deployCmd.Flags().StringVar(&deployDescription, "description", "", "What the agent is for (overrides oken.toml)")
+	deployCmd.Flags().StringVar(&deployRepository, "repository", "", "URL of the agent's source code (overrides oken.toml)")
+	deployCmd.Flags().StringVar(&deployOwner, "owner", "", "Who to contact about the agent, e.g. a team email (overrides oken.toml)")
+	deployCmd.Flags().StringVar(&deployBuild, "build", "", "Build mode: docker builds from the project's Dockerfile")
+	deployCmd.Flags().BoolVar(&deployNoGitignore, "no-gitignore", false, "Package files excluded by .gitignore")
+	deployCmd.Flags().BoolVar(&deployAllowSecrets, "allow-secrets", false, "Deploy even if packaged files appear to contain credentials")
+	deployCmd.Flags().BoolVarP(&deployWait, "wait", "w", false, "Wait for the build to finish, streaming its logs")
+	deployCmd.Flags().BoolVar(&deployNoLogs, "no-logs", false, "Don't stream build logs with --wait")
+	deployCmd.Flags().DurationVar(&deployTimeout, "timeout", 15*time.Minute, "How long to wait with --wait")
+	deployCmd.Flags().StringVar(&deployGit, "git", "", "Deploy from a git repository, as <url>#<ref>:<dir>")
+	deployCmd.Flags().StringVar(&deployTarball, "tarball", "", "Deploy a pre-built .tar.gz or .tar.zst instead of packaging, or - to read it from stdin")
+	deployCmd.Flags().BoolVar(&deployStrict, "strict", false, "Fail instead of warning when Python dependencies aren't pinned or locked")
+	deployCmd.Flags().BoolVar(&deploySign, "sign", false, "Sign the package digest for 'oken verify'")
+	deployCmd.Flags().StringVar(&deploySigningKey, "signing-key", "", "Private key for --sign (default: signing.key next to the config file, created if missing)")
+	deployCmd.Flags().IntVarP(&deployConcurrency, "concurrency", "j", 4, "How many agents to deploy at once when deploying several directories")
+	deployCmd.Flags().DurationVar(&deployUploadTimeout, "upload-timeout", api.DefaultUploadTimeout, "Upload timeout for slow links, 0 to disable (env: "+config.UploadTimeoutEnv+")")
 	rootCmd.AddCommand(deployCmd)
 }
 
-func runDeploy(cmd *cobra.Command, args []string) error {
-	// Try to load oken.toml
-	var okenCfg okenConfig
-	if _, err := os.Stat("oken.toml"); err == nil {
-		if _, err := toml.DecodeFile("oken.toml", &okenCfg); err != nil {
-			ui.Error("Failed to parse oken.toml: %v", err)
-			return err
+// deployTarget is an agent directory resolved from its oken.toml and the
+// deploy flags
+type deployTarget struct {
+	dir       string
+	name      string
+	slug      string
+	runtime   string
+	okenCfg   okenConfig
+	resources *api.Resources
+	lifecycle *api.Lifecycle
+	metadata  api.AgentMetadata
+	// git is the commit the target was fetched from with --git
+	git *api.GitMetadata
+	// deps are the Python dependencies read while packaging, if any
+	deps *deps.Set
+	// sbom lists the packaged dependencies, if any were found
+	sbom []byte
+}
+
+// resolveDeployTarget reads oken.toml in dir, applies name and slug if set,
+// and validates the result
+func resolveDeployTarget(dir, name, slug string) (*deployTarget, error) {
+	okenCfg, err := loadOkenConfig(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse oken.toml: %w", err)
+	}
+
+	t := &deployTarget{dir: dir, name: name, slug: slug, runtime: okenCfg.runtime(), okenCfg: okenCfg}
+	if t.name == "" {
+		t.name = okenCfg.Name
+	}
+	if t.slug == "" {
+		t.slug = okenCfg.Slug
+	}
+
+	if t.name == "" {
+		return nil, fmt.Errorf("agent name is required: use --name or create oken.toml with 'oken init'")
+	}
+	if t.slug == "" {
+		return nil, fmt.Errorf("agent slug is required: use --slug or create oken.toml with 'oken init'")
+	}
+
+	if deployBuild != "" && deployBuild != api.BuildDocker {
+		return nil, fmt.Errorf("invalid build mode '%s': supported: %s", deployBuild, api.BuildDocker)
+	}
+	// A pre-built tarball carries its own Dockerfile
+	if deployBuild == api.BuildDocker && deployTarball == "" {
+		if _, err := os.Stat(filepath.Join(dir, "Dockerfile")); err != nil {
+			return nil, fmt.Errorf("no Dockerfile found in %s", dir)
 		}
 	}
 
-	// Flags override oken.toml
-	name := deployName
-	if name == "" {
-		name = okenCfg.Name
+	if !slices.Contains(api.Runtimes, t.runtime) {
+		return nil, fmt.Errorf("invalid runtime '%s' in oken.toml: supported runtimes: %s", t.runtime, strings.Join(api.Runtimes, ", "))
+	}
+
+	if compression := okenCfg.Package.Compression; compression != "" {
+		if _, err := pack.ParseCompression(compression); err != nil {
+			return nil, fmt.Errorf("%w in oken.toml", err)
+		}
+	}
+	if level := okenCfg.Package.Level; level < 0 || level > 9 {
+		return nil, fmt.Errorf("invalid compression_level %d in oken.toml: use 1 (fastest) to 9 (smallest)", level)
+	}
+
+	if t.resources, err = okenCfg.Resources.toAPI(); err != nil {
+		return nil, fmt.Errorf("invalid [resources] in oken.toml: %w", err)
+	}
+	if t.lifecycle, err = okenCfg.Lifecycle.toAPI(); err != nil {
+		return nil, fmt.Errorf("invalid [lifecycle] in oken.toml: %w", err)
+	}
+
+	t.metadata = api.AgentMetadata{
+		Description: cmp.Or(deployDescription, okenCfg.Description),
+		Repository:  cmp.Or(deployRepository, okenCfg.Repository),
+		Owner:       cmp.Or(deployOwner, okenCfg.Owner),
 	}
-	slug := deploySlug
-	if slug == "" {
-		slug = okenCfg.Slug
+	if err := t.metadata.Validate(); err != nil {
+		return nil, err
 	}
+	return t, nil
+}
+
+// packOptions returns the packaging options for the target, honoring --build and --no-gitignore
+func (t *deployTarget) packOptions() pack.Options {
+	opts := t.okenCfg.packOptions()
+	opts.Docker = deployBuild == api.BuildDocker
+	if deployNoGitignore {
+		opts.NoGitignore = true
+	}
+	return opts
+}
+
+// deployOptions returns the deploy request options for the packaged target
+func (t *deployTarget) deployOptions(tarball *pack.Tarball) api.DeployOptions {
+	return api.DeployOptions{
+		Resources:      t.resources,
+		Lifecycle:      t.lifecycle,
+		Environment:    deployEnv,
+		Runtime:        t.runtime,
+		RuntimeVersion: t.okenCfg.runtimeVersion(),
+		Build:          deployBuild,
+		Compression:    string(tarball.Compression),
+		Checksum:       tarball.SHA256,
+		Message:        deployMessage,
+		Git:            t.git,
+		Signature:      signPackage(tarball),
+		SBOM:           t.sbom,
+		Labels:         deployLabelSet,
+		Metadata:       t.metadata,
+	}
+}
+
+// loadDependencies reads the target's Python dependencies and turns them into
+// a CycloneDX SBOM, leaving both empty when there are none
+func (t *deployTarget) loadDependencies() error {
+	set, err := deps.Load(t.dir)
+	if err != nil || set == nil {
+		return err
+	}
+	t.deps = set
+	t.sbom, err = set.SBOM(deps.SBOMMetadata{Agent: t.slug, ToolVersion: Version, Time: time.Now()})
+	return err
+}
+
+// dependencyProblems lists why the platform might install other versions
+// than were tested locally. A requirements.txt pinning every package, as
+// written by pip-compile, counts as a lockfile.
+func (t *deployTarget) dependencyProblems() []string {
+	if t.deps == nil || t.deps.Locked || t.runtime != "python" || deployBuild == api.BuildDocker {
+		return nil
+	}
+
+	var problems []string
+	unpinned := t.deps.Unpinned()
+	for _, d := range unpinned {
+		if d.Specifier == "" {
+			problems = append(problems, fmt.Sprintf("%s doesn't pin %s", t.deps.File, d.Name))
+		} else {
+			problems = append(problems, fmt.Sprintf("%s doesn't pin %s (%s)", t.deps.File, d.Name, d.Specifier))
+		}
+	}
+	if len(unpinned) > 0 || t.deps.File == deps.ProjectFile {
+		problems = append(problems, fmt.Sprintf("%s has no lockfile, so versions are resolved at build time", t.deps.File))
+	}
+	return problems
+}
 
-	if name == "" {
-		ui.Error("Agent name is required. Use --name flag or create oken.toml with 'oken init'.")
-		return fmt.Errorf("name required")
+func runDeploy(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 && (deployName != "" || deploySlug != "") {
+		ui.Error("--name and --slug can't be used when deploying several directories.")
+		return fmt.Errorf("invalid flags")
+	}
+	if deployGit != "" && len(args) > 0 {
+		ui.Error("--git can't be used with directories.")
+		return fmt.Errorf("invalid flags")
 	}
-	if slug == "" {
-		ui.Error("Agent slug is required. Use --slug flag or create oken.toml with 'oken init'.")
-		return fmt.Errorf("slug required")
+	if deployTarball != "" && (deployGit != "" || len(args) > 0) {
+		ui.Error("--tarball can't be used with --git or directories.")
+		return fmt.Errorf("invalid flags")
 	}
+	if deployConcurrency < 1 {
+		ui.Error("Invalid --concurrency %d. Use 1 or more.", deployConcurrency)
+		return fmt.Errorf("invalid concurrency")
+	}
+	labels, err := api.ParseLabels(deployLabels)
+	if err != nil {
+		ui.Error("Invalid --label: %v", err)
+		return fmt.Errorf("invalid flags")
+	}
+	deployLabelSet = labels
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -78,39 +409,532 @@ func runDeploy(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
-	// Get current directory
-	dir, err := os.Getwd()
-	if err != nil {
-		ui.Error("Failed to get current directory: %v", err)
-		return err
+	client := newClient(cfg)
+
+	// --upload-timeout wins over OKEN_UPLOAD_TIMEOUT and the config file
+	uploadTimeout := deployUploadTimeout
+	if uploadTimeout < 0 {
+		ui.Error("Invalid --upload-timeout %s. Use a positive duration, or 0 to disable.", uploadTimeout)
+		return fmt.Errorf("invalid upload timeout")
+	}
+	if !cmd.Flags().Changed("upload-timeout") {
+		uploadTimeout, err = cfg.ResolveUploadTimeout(api.DefaultUploadTimeout)
+		if err != nil {
+			ui.Error("%v", err)
+			return err
+		}
 	}
+	client.UploadClient.Timeout = uploadTimeout
 
-	ui.Info("Packaging agent from %s...", dir)
+	if deploySign {
+		if signingKey, err = loadSigningKey(deploySigningKey); err != nil {
+			ui.Error("Failed to load signing key: %v", err)
+			return err
+		}
+		if signingPublicKey, err = sign.EncodePublicKey(signingKey.Public().(ed25519.PublicKey)); err != nil {
+			ui.Error("Failed to encode public key: %v", err)
+			return err
+		}
+	}
+
+	if len(args) > 0 {
+		return deployDirs(client, args)
+	}
+
+	var dir string
+	var gitMeta *api.GitMetadata
+	if deployGit != "" {
+		src, err := git.ParseSource(deployGit)
+		if err != nil {
+			ui.Error("Invalid --git source: %v", err)
+			return err
+		}
+
+		tmp, err := os.MkdirTemp("", "oken-git-")
+		if err != nil {
+			ui.Error("Failed to create temporary directory: %v", err)
+			return err
+		}
+		defer os.RemoveAll(tmp)
+
+		ui.Progress("clone", -1, "Fetching %s...", src)
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		commit, err := git.Fetch(ctx, src, tmp)
+		stop()
+		if err != nil {
+			ui.Error("Failed to fetch %s: %v", src.Repository(), err)
+			return err
+		}
 
-	tarball, err := pack.CreateTarball(dir)
+		dir = filepath.Join(tmp, src.Dir)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			ui.Error("Directory %s not found at %s", src.Dir, commit)
+			return fmt.Errorf("directory not found")
+		}
+		gitMeta = &api.GitMetadata{Repository: src.Repository(), Ref: src.Ref, Commit: commit}
+	} else {
+		dir, err = os.Getwd()
+		if err != nil {
+			ui.Error("Failed to get current directory: %v", err)
+			return err
+		}
+		// A pre-built tarball may not come from the checkout it's deployed from
+		if deployTarball == "" {
+			gitMeta = describeGit(dir)
+		}
+	}
+
+	// Flags override oken.toml
+	target, err := resolveDeployTarget(dir, deployName, deploySlug)
 	if err != nil {
-		ui.Error("Failed to create package: %v", err)
+		ui.Error("Cannot deploy: %v", err)
 		return err
 	}
+	target.git = gitMeta
+	name, slug := target.name, target.slug
 
-	client := api.NewClient(cfg.Endpoint, cfg.Token)
+	var tarball *pack.Tarball
+	if deployTarball != "" {
+		tarball, err = loadTarball(deployTarball)
+		if err != nil {
+			source := deployTarball
+			if source == "-" {
+				source = "stdin"
+			}
+			ui.Error("Failed to read package from %s: %v", source, err)
+			return err
+		}
+	} else {
+		tarball, err = packageTarget(target)
+		if err != nil {
+			return err
+		}
+	}
 
-	ui.Info("Deploying %s...", name)
+	if ui.IsProgressJSON() {
+		client.OnUploadProgress = func(sent, total int64) {
+			ui.Progress("upload", int(sent*100/total), "Uploaded %s of %s", ui.Bytes(sent), ui.Bytes(total))
+		}
+	}
 
-	resp, err := client.DeployAgent(name, slug, tarball)
+	if deployEnv != "" {
+		ui.Progress("upload", -1, "Deploying %s to %s...", name, deployEnv)
+	} else {
+		ui.Progress("upload", -1, "Deploying %s...", name)
+	}
+
+	resp, err := client.DeployAgent(name, slug, tarball, target.deployOptions(tarball))
 	if err != nil {
 		ui.Error("Failed to deploy agent: %v", err)
 		return err
 	}
 
+	if deployWait {
+		deployment, err := waitForDeployment(client, resp.Deployment.ID)
+		if err != nil {
+			if !errors.Is(err, errDeployInterrupted) {
+				ui.Error("Failed to wait for deployment: %v", err)
+			}
+			return err
+		}
+		if deployment.Failed() {
+			if deployment.Status == "cancelled" {
+				ui.Warning("Deployment %s cancelled", deployment.ID)
+			} else if deployment.Error != "" {
+				ui.Error("Deployment failed: %s", deployment.Error)
+			} else {
+				ui.Error("Deployment failed")
+			}
+			return fmt.Errorf("deployment %s failed", deployment.ID)
+		}
+		if agent, err := client.GetAgent(slug); err == nil {
+			resp.Agent = *agent
+		}
+	}
+
+	if ui.IsStructured() {
+		result := deployOutput{Agent: resp.Agent, Deployment: resp.Deployment.ID, SHA256: tarball.SHA256, Git: gitMeta}
+		if signingKey != nil {
+			result.Signer = sign.Fingerprint(signingKey.Public().(ed25519.PublicKey))
+		}
+		return ui.Result(result)
+	}
+
 	fmt.Println()
 	ui.Success("Agent deployed successfully!")
 	fmt.Printf("  Name:     %s\n", resp.Agent.Name)
 	fmt.Printf("  Slug:     %s\n", resp.Agent.Slug)
-	fmt.Printf("  Status:   %s\n", resp.Agent.Status)
+	if resp.Agent.Environment != nil && *resp.Agent.Environment != "" {
+		fmt.Printf("  Env:      %s\n", *resp.Agent.Environment)
+	}
+	fmt.Printf("  Status:   %s\n", ui.AgentStatus(resp.Agent.Status))
 	if resp.Agent.Endpoint != nil && *resp.Agent.Endpoint != "" {
 		fmt.Printf("  Endpoint: %s\n", *resp.Agent.Endpoint)
 	}
+	fmt.Printf("  Digest:   sha256:%s\n", tarball.SHA256)
+	if gitMeta != nil {
+		fmt.Printf("  Commit:   %s\n", formatCommit(gitMeta))
+	}
+	if signingKey != nil {
+		fmt.Printf("  Signed:   %s\n", sign.Fingerprint(signingKey.Public().(ed25519.PublicKey)))
+	}
 
 	return nil
 }
+
+// deployOutput is the result of a deploy in JSON and YAML output
+type deployOutput struct {
+	Agent      api.Agent        `json:"agent"`
+	Deployment string           `json:"deployment"`
+	SHA256     string           `json:"sha256"`
+	Git        *api.GitMetadata `json:"git,omitempty"`
+	// Signer is the fingerprint of the key the package was signed with
+	Signer string `json:"signer,omitempty"`
+}
+
+// packageTarget scans the target for credentials and packages it, printing
+// findings and progress
+func packageTarget(target *deployTarget) (*pack.Tarball, error) {
+	ui.Progress("package", -1, "Packaging agent from %s...", target.dir)
+
+	packOpts := target.packOptions()
+
+	findings, err := pack.ScanSecrets(target.dir, packOpts)
+	if err != nil {
+		ui.Error("Failed to scan for secrets: %v", err)
+		return nil, err
+	}
+	if len(findings) > 0 {
+		for _, f := range findings {
+			if deployAllowSecrets {
+				ui.Warning("Possible %s in %s:%d", f.Rule, f.Path, f.Line)
+			} else {
+				ui.Error("Possible %s in %s:%d", f.Rule, f.Path, f.Line)
+			}
+		}
+		if !deployAllowSecrets {
+			ui.Info("Move credentials to 'oken secrets set', exclude the files, or use --allow-secrets.")
+			return nil, fmt.Errorf("possible secrets in package")
+		}
+	}
+
+	tarball, err := pack.CreateTarball(target.dir, packOpts)
+	if err != nil {
+		ui.Error("Failed to create package: %v", err)
+		return nil, err
+	}
+
+	if err := target.loadDependencies(); err != nil {
+		ui.Warning("Deploying without an SBOM: %v", err)
+	}
+	if err := auditDependencies(target); err != nil {
+		return nil, err
+	}
+	return tarball, nil
+}
+
+// auditDependencies summarizes the target's direct dependencies and warns
+// about unpinned versions, failing instead with --strict
+func auditDependencies(target *deployTarget) error {
+	set := target.deps
+	if set == nil {
+		return nil
+	}
+	if set.Locked {
+		ui.Info("%d dependencies locked in %s", len(set.Dependencies), set.File)
+	} else {
+		direct := make([]string, 0, len(set.Dependencies))
+		for _, d := range set.Dependencies {
+			direct = append(direct, d.Name+d.Specifier)
+		}
+		ui.Info("Dependencies from %s: %s", set.File, strings.Join(direct, ", "))
+	}
+
+	problems := target.dependencyProblems()
+	for _, p := range problems {
+		if deployStrict {
+			ui.Error("%s", p)
+		} else {
+			ui.Warning("%s", p)
+		}
+	}
+	if deployStrict && len(problems) > 0 {
+		ui.Info("Pin every version with == (pip-compile writes such a requirements.txt), or lock pyproject.toml with 'uv lock'.")
+		return fmt.Errorf("unpinned dependencies")
+	}
+	return nil
+}
+
+// loadTarball reads a pre-built package from path, or from stdin if path is -
+func loadTarball(path string) (*pack.Tarball, error) {
+	if path == "-" {
+		ui.Progress("package", -1, "Reading package from stdin...")
+		return pack.LoadTarball(os.Stdin)
+	}
+
+	ui.Progress("package", -1, "Reading package from %s...", path)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	return pack.LoadTarball(file)
+}
+
+// describeGit returns the commit dir is checked out at, or nil when it isn't
+// in a git work tree. Git metadata is best effort and never fails a deploy.
+func describeGit(dir string) *api.GitMetadata {
+	info, err := git.Describe(context.Background(), dir)
+	if err != nil || info == nil {
+		return nil
+	}
+	return &api.GitMetadata{Repository: info.Repository, Commit: info.Commit, Branch: info.Branch, Dirty: info.Dirty}
+}
+
+// loadSigningKey reads the private key for --sign. The default key is
+// created on first use; a key given with --signing-key must exist.
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	if path != "" {
+		return sign.LoadPrivateKey(path)
+	}
+
+	path, err := config.SigningKeyPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		key, err := sign.GenerateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		ui.Info("Created signing key %s (%s)", path, sign.Fingerprint(key.Public().(ed25519.PublicKey)))
+		return key, nil
+	}
+	return sign.LoadPrivateKey(path)
+}
+
+// signPackage signs the tarball's digest with the --sign key, returning nil
+// when not signing
+func signPackage(tarball *pack.Tarball) *api.Signature {
+	if signingKey == nil {
+		return nil
+	}
+	return &api.Signature{
+		Algorithm: sign.Algorithm,
+		PublicKey: signingPublicKey,
+		Digest:    tarball.SHA256,
+		Value:     sign.Sign(signingKey, tarball.SHA256),
+	}
+}
+
+// errDeployInterrupted is returned when the user stops waiting for a
+// deployment without cancelling it
+var errDeployInterrupted = errors.New("interrupted")
+
+// waitForDeployment streams build logs, unless disabled, and waits until the
+// deployment is done. Ctrl+C offers to cancel the deployment.
+func waitForDeployment(client *api.Client, id string) (*api.Deployment, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// --timeout covers streaming the logs and polling for the result together
+	deadline := time.Now().Add(deployTimeout)
+
+	if !deployNoLogs {
+		streamCtx, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
+
+		// Keep stdout for the result in JSON and YAML output
+		logs := os.Stdout
+		if ui.IsStructured() {
+			logs = os.Stderr
+		}
+		ui.Progress("build", -1, "Streaming build logs for deployment %s...", id)
+		if err := client.GetDeploymentLogsStream(streamCtx, id, logs); err != nil {
+			if ctx.Err() != nil {
+				stop()
+				return offerCancel(client, id)
+			}
+			ui.Warning("Build log stream ended early: %v", err)
+		}
+	} else {
+		ui.Progress("build", -1, "Waiting for deployment %s to finish...", id)
+	}
+
+	type result struct {
+		deployment *api.Deployment
+		err        error
+	}
+	done := make(chan result, 1)
+	go func() {
+		deployment, err := client.WaitForDeployment(id, 2*time.Second, time.Until(deadline))
+		done <- result{deployment, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.deployment, r.err
+	case <-ctx.Done():
+		// Restore default handling so a second Ctrl+C exits immediately
+		stop()
+		return offerCancel(client, id)
+	}
+}
+
+// offerCancel asks whether an interrupted deployment should be cancelled
+func offerCancel(client *api.Client, id string) (*api.Deployment, error) {
+	fmt.Println()
+	confirmed, err := ui.Confirm(fmt.Sprintf("Cancel deployment %s?", id))
+	if err != nil || !confirmed {
+		ui.Info("Deployment %s continues in the background. Cancel it with 'oken deployments cancel %s'.", id, id)
+		return nil, errDeployInterrupted
+	}
+
+	resp, err := client.CancelDeployment(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel deployment: %w", err)
+	}
+	return &resp.Deployment, nil
+}
+
+// deployResult is the outcome of deploying one directory with deployDirs
+type deployResult struct {
+	dir   string
+	agent *api.Agent
+	err   error
+}
+
+// deployDirOutput is the result of deploying one directory in JSON and YAML
+// output
+type deployDirOutput struct {
+	Dir   string     `json:"dir"`
+	Agent *api.Agent `json:"agent,omitempty"`
+	Error string     `json:"error,omitempty"`
+}
+
+// deployDirs deploys the agents in dirs, up to --concurrency at a time,
+// printing a status line as each finishes and a summary of failures
+func deployDirs(client *api.Client, dirs []string) error {
+	workers := min(deployConcurrency, len(dirs))
+	ui.Info("Deploying %d agents, %d at a time...", len(dirs), workers)
+
+	results := make([]deployResult, len(dirs))
+	jobs := make(chan int)
+	// mu keeps status lines from interleaving
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for i := range jobs {
+				agent, err := deployDir(client, dirs[i], deployName, deploySlug)
+				results[i] = deployResult{dir: dirs[i], agent: agent, err: err}
+
+				mu.Lock()
+				if err != nil {
+					ui.Error("%s: %v", dirs[i], err)
+				} else {
+					ui.Success("%s: deployed %s (%s)", dirs[i], agent.Slug, ui.AgentStatus(agent.Status))
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	for i := range dirs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var failed []deployResult
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, r)
+		}
+	}
+
+	if ui.IsStructured() {
+		out := make([]deployDirOutput, 0, len(results))
+		for _, r := range results {
+			o := deployDirOutput{Dir: r.dir, Agent: r.agent}
+			if r.err != nil {
+				o.Error = r.err.Error()
+			}
+			out = append(out, o)
+		}
+		if err := ui.Result(out); err != nil {
+			return err
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%d of %d deploys failed", len(failed), len(dirs))
+		}
+		return nil
+	}
+
+	fmt.Println()
+	if len(failed) == 0 {
+		ui.Success("Deployed %d agents", len(dirs))
+		return nil
+	}
+	ui.Error("%d of %d deploys failed:", len(failed), len(dirs))
+	for _, r := range failed {
+		fmt.Printf("  %s: %v\n", r.dir, r.err)
+	}
+	return fmt.Errorf("%d of %d deploys failed", len(failed), len(dirs))
+}
+
+// deployDir packages and deploys the agent in dir without printing progress,
+// overriding oken.toml with name and slug if set. With --wait it waits for
+// the build, without streaming logs.
+func deployDir(client *api.Client, dir, name, slug string) (*api.Agent, error) {
+	target, err := resolveDeployTarget(dir, name, slug)
+	if err != nil {
+		return nil, err
+	}
+	target.git = describeGit(dir)
+
+	packOpts := target.packOptions()
+	findings, err := pack.ScanSecrets(dir, packOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for secrets: %w", err)
+	}
+	if len(findings) > 0 && !deployAllowSecrets {
+		f := findings[0]
+		if len(findings) > 1 {
+			return nil, fmt.Errorf("possible %s in %s:%d and %d more; move credentials to 'oken secrets set' or use --allow-secrets", f.Rule, f.Path, f.Line, len(findings)-1)
+		}
+		return nil, fmt.Errorf("possible %s in %s:%d; move credentials to 'oken secrets set' or use --allow-secrets", f.Rule, f.Path, f.Line)
+	}
+
+	tarball, err := pack.CreateTarball(dir, packOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create package: %w", err)
+	}
+	// The SBOM is optional; unreadable dependency files are left to the build
+	_ = target.loadDependencies()
+	if problems := target.dependencyProblems(); deployStrict && len(problems) > 0 {
+		return nil, fmt.Errorf("%s; pin versions or deploy without --strict", problems[0])
+	}
+
+	resp, err := client.DeployAgent(target.name, target.slug, tarball, target.deployOptions(tarball))
+	if err != nil {
+		return nil, fmt.Errorf("failed to deploy agent: %w", err)
+	}
+	if !deployWait {
+		return &resp.Agent, nil
+	}
+
+	deployment, err := client.WaitForDeployment(resp.Deployment.ID, 2*time.Second, deployTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for deployment: %w", err)
+	}
+	if deployment.Failed() {
+		if deployment.Error != "" {
+			return nil, fmt.Errorf("deployment %s %s: %s", deployment.ID, deployment.Status, deployment.Error)
+		}
+		return nil, fmt.Errorf("deployment %s %s", deployment.ID, deployment.Status)
+	}
+	if agent, err := client.GetAgent(target.slug); err == nil {
+		return agent, nil
+	}
+	return &resp.Agent, nil
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var deploymentsCmd = &cobra.Command{
+	Use:   "deployments",
+	Short: "Manage deployments",
+	Long:  "Manage individual deployments of your agents.",
+}
+
+var deploymentsListCmd = &cobra.Command{
+	Use:   "list <slug>",
+	Short: "List deployments of an agent",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDeploymentsList,
+}
+
+var deploymentsCancelCmd = &cobra.Command{
+	Use:   "cancel <deployment-id>",
+	Short: "Cancel an in-flight deployment",
+	Long: `Cancel a deployment that is still building. The previously running
+deployment, if any, keeps serving.
+
+The deployment ID is printed by 'oken deploy --wait'.
+
+Examples:
+  oken deployments cancel dep_abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDeploymentsCancel,
+}
+
+func init() {
+	deploymentsCmd.AddCommand(deploymentsListCmd)
+	deploymentsCmd.AddCommand(deploymentsCancelCmd)
+	rootCmd.AddCommand(deploymentsCmd)
+}
+
+func runDeploymentsList(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.ListDeployments(slug)
+	if err != nil {
+		ui.Error("Failed to list deployments: %v", err)
+		return err
+	}
+
+	if ui.IsTemplate() {
+		for _, d := range resp.Deployments {
+			if err := ui.Template(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if ui.IsStructured() {
+		return ui.Result(resp.Deployments)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(resp.Deployments))
+		for _, d := range resp.Deployments {
+			var commit, branch, dirty string
+			if d.Git != nil {
+				commit, branch, dirty = d.Git.Commit, d.Git.Branch, strconv.FormatBool(d.Git.Dirty)
+			}
+			rows = append(rows, []string{d.ID, d.Status, d.CreatedAt, stringValue(d.FinishedAt), commit, branch, dirty, d.Message})
+		}
+		return ui.Table([]string{"id", "status", "created", "finished", "commit", "branch", "dirty", "message"}, rows)
+	}
+
+	if len(resp.Deployments) == 0 {
+		ui.Info("No deployments found for agent '%s'", slug)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tSTATUS\tCREATED\tFINISHED\tCOMMIT\tMESSAGE")
+	for _, d := range resp.Deployments {
+		finished := "-"
+		if d.FinishedAt != nil && *d.FinishedAt != "" {
+			finished = *d.FinishedAt
+		}
+		message := "-"
+		if d.Message != "" {
+			message = summarize(d.Message, 50)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", d.ID, d.Status, d.CreatedAt, finished, formatCommit(d.Git), message)
+	}
+	_ = w.Flush()
+
+	return nil
+}
+
+// formatCommit shows the commit a deployment was built from, such as
+// "a1b2c3d (main, dirty)", or "-" if unknown
+func formatCommit(g *api.GitMetadata) string {
+	if g == nil || g.Commit == "" {
+		return "-"
+	}
+	var details []string
+	if g.Branch != "" {
+		details = append(details, g.Branch)
+	} else if g.Ref != "" {
+		details = append(details, g.Ref)
+	}
+	if g.Dirty {
+		details = append(details, "dirty")
+	}
+	if len(details) == 0 {
+		return g.ShortCommit()
+	}
+	return fmt.Sprintf("%s (%s)", g.ShortCommit(), strings.Join(details, ", "))
+}
+
+// summarize returns the first line of a message, cut to max characters
+func summarize(message string, max int) string {
+	line, rest, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	line = strings.TrimSpace(line)
+	if runes := []rune(line); len(runes) > max {
+		return string(runes[:max-1]) + "…"
+	}
+	if rest != "" {
+		return line + " …"
+	}
+	return line
+}
+
+func runDeploymentsCancel(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.CancelDeployment(id)
+	if err != nil {
+		ui.Error("Failed to cancel deployment: %v", err)
+		return err
+	}
+
+	ui.Success("Deployment cancelled: %s", resp.Deployment.ID)
+
+	return nil
+}
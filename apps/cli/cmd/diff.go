@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/diff"
+	"github.com/neult/oken/apps/cli/internal/pack"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var diffContent bool
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <slug>",
+	Short: "Compare local project with deployed source",
+	Long: `Compare the files in the current directory with the currently deployed source.
+
+Files are selected with the same exclusion rules as 'oken deploy'. Added files exist
+only locally, removed files exist only in the deployment.
+
+Examples:
+  oken diff my-agent
+  oken diff my-agent --content`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().BoolVarP(&diffContent, "content", "c", false, "Show unified diffs of changed files")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		ui.Error("Failed to get current directory: %v", err)
+		return err
+	}
+
+	okenCfg, err := loadOkenConfig(".")
+	if err != nil {
+		ui.Error("Failed to parse oken.toml: %v", err)
+		return err
+	}
+
+	local := make(map[string][]byte)
+	err = pack.Walk(cwd, okenCfg.packOptions(), func(path, relPath string, info os.FileInfo) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		local[filepath.ToSlash(relPath)] = data
+		return nil
+	})
+	if err != nil {
+		ui.Error("Failed to read project: %v", err)
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	body, err := client.DownloadSource(slug, "")
+	if err != nil {
+		ui.Error("Failed to download source: %v", err)
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	deployed, err := pack.ReadTarball(body)
+	if err != nil {
+		ui.Error("Failed to read deployed source: %v", err)
+		return err
+	}
+
+	var added, removed, changed []string
+	for path, data := range local {
+		remote, ok := deployed[path]
+		switch {
+		case !ok:
+			added = append(added, path)
+		case !bytes.Equal(data, remote):
+			changed = append(changed, path)
+		}
+	}
+	for path := range deployed {
+		if _, ok := local[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+
+	if ui.IsStructured() {
+		return ui.Result(map[string][]string{
+			"added":   nonNil(added),
+			"removed": nonNil(removed),
+			"changed": nonNil(changed),
+		})
+	}
+
+	if len(added)+len(removed)+len(changed) == 0 {
+		ui.Success("No differences from deployed source")
+		return nil
+	}
+
+	for _, path := range added {
+		fmt.Printf("%s %s\n", ui.Green("A"), path)
+	}
+	for _, path := range removed {
+		fmt.Printf("%s %s\n", ui.Red("D"), path)
+	}
+	for _, path := range changed {
+		fmt.Printf("%s %s\n", ui.Yellow("M"), path)
+	}
+
+	if diffContent {
+		for _, path := range changed {
+			fmt.Println()
+			printFileDiff(path, deployed[path], local[path])
+		}
+	}
+
+	fmt.Println()
+	ui.Info("%d added, %d removed, %d changed", len(added), len(removed), len(changed))
+
+	return nil
+}
+
+// printFileDiff prints a unified diff of one file, deployed version first
+func printFileDiff(path string, deployed, local []byte) {
+	if bytes.IndexByte(deployed, 0) >= 0 || bytes.IndexByte(local, 0) >= 0 {
+		fmt.Printf("Binary files a/%s and b/%s differ\n", path, path)
+		return
+	}
+
+	out, err := diff.Unified("a/"+path, "b/"+path, string(deployed), string(local))
+	if errors.Is(err, diff.ErrTooLarge) {
+		fmt.Printf("Files a/%s and b/%s differ (too large to diff)\n", path, path)
+		return
+	}
+	fmt.Print(out)
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	domainsWait    bool
+	domainsTimeout time.Duration
+)
+
+var domainsCmd = &cobra.Command{
+	Use:   "domains",
+	Short: "Manage custom domains",
+	Long:  "Attach, verify and remove custom domains for agent endpoints.",
+}
+
+var domainsAddCmd = &cobra.Command{
+	Use:   "add <slug> <domain>",
+	Short: "Attach a custom domain",
+	Long: `Attach a custom domain to an agent and print the DNS records to create.
+
+Examples:
+  oken domains add my-agent api.mycompany.com`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDomainsAdd,
+}
+
+var domainsVerifyCmd = &cobra.Command{
+	Use:   "verify <slug> <domain>",
+	Short: "Check DNS verification of a domain",
+	Long: `Check whether the DNS records for a custom domain are in place.
+Use --wait to keep polling until the domain is verified.
+
+Examples:
+  oken domains verify my-agent api.mycompany.com
+  oken domains verify my-agent api.mycompany.com --wait`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDomainsVerify,
+}
+
+var domainsListCmd = &cobra.Command{
+	Use:   "list <slug>",
+	Short: "List custom domains for an agent",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDomainsList,
+}
+
+var domainsRemoveCmd = &cobra.Command{
+	Use:   "remove <slug> <domain>",
+	Short: "Remove a custom domain",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runDomainsRemove,
+}
+
+func init() {
+	domainsVerifyCmd.Flags().BoolVarP(&domainsWait, "wait", "w", false, "Poll until the domain is verified")
+	domainsVerifyCmd.Flags().DurationVar(&domainsTimeout, "timeout", 10*time.Minute, "How long to wait with --wait")
+
+	domainsCmd.AddCommand(domainsAddCmd)
+	domainsCmd.AddCommand(domainsVerifyCmd)
+	domainsCmd.AddCommand(domainsListCmd)
+	domainsCmd.AddCommand(domainsRemoveCmd)
+
+	rootCmd.AddCommand(domainsCmd)
+}
+
+func runDomainsAdd(cmd *cobra.Command, args []string) error {
+	slug, domain := args[0], args[1]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.AddDomain(slug, domain)
+	if err != nil {
+		ui.Error("Failed to add domain: %v", err)
+		return err
+	}
+
+	ui.Success("Domain added: %s (agent: %s)", resp.Domain.Name, slug)
+
+	if resp.Domain.Status != "verified" {
+		fmt.Println()
+		printDNSRecords(resp.Domain.Records)
+		fmt.Println()
+		ui.Info("Then run 'oken domains verify %s %s --wait'", slug, resp.Domain.Name)
+	}
+
+	return nil
+}
+
+func runDomainsVerify(cmd *cobra.Command, args []string) error {
+	slug, domain := args[0], args[1]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	var resp *api.DomainResponse
+	if domainsWait {
+		ui.Info("Waiting for DNS verification of %s...", domain)
+		resp, err = client.WaitForDomainVerification(slug, domain, 10*time.Second, domainsTimeout)
+	} else {
+		resp, err = client.VerifyDomain(slug, domain)
+	}
+	if err != nil && resp == nil {
+		ui.Error("Failed to verify domain: %v", err)
+		return err
+	}
+
+	if resp.Domain.Status == "verified" {
+		ui.Success("Domain verified: %s", resp.Domain.Name)
+		return nil
+	}
+
+	if resp.Domain.Status == "failed" {
+		ui.Error("Domain verification failed: %s", resp.Domain.Name)
+		if resp.Message != "" {
+			ui.Info("%s", resp.Message)
+		}
+	} else {
+		ui.Warning("Domain not verified yet: %s (status: %s)", resp.Domain.Name, resp.Domain.Status)
+	}
+	fmt.Println()
+	printDNSRecords(resp.Domain.Records)
+
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("domain not verified")
+}
+
+func runDomainsList(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.ListDomains(slug)
+	if err != nil {
+		ui.Error("Failed to list domains: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Domains)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(resp.Domains))
+		for _, d := range resp.Domains {
+			rows = append(rows, []string{d.Name, d.Status, d.CreatedAt})
+		}
+		return ui.Table([]string{"domain", "status", "created"}, rows)
+	}
+
+	if len(resp.Domains) == 0 {
+		ui.Info("No custom domains for agent '%s'", slug)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DOMAIN\tSTATUS\tCREATED")
+	for _, d := range resp.Domains {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", d.Name, d.Status, dateOnly(d.CreatedAt))
+	}
+	_ = w.Flush()
+
+	return nil
+}
+
+func runDomainsRemove(cmd *cobra.Command, args []string) error {
+	slug, domain := args[0], args[1]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	if _, err := client.RemoveDomain(slug, domain); err != nil {
+		ui.Error("Failed to remove domain: %v", err)
+		return err
+	}
+
+	ui.Success("Domain removed: %s (agent: %s)", domain, slug)
+
+	return nil
+}
+
+// printDNSRecords prints the records the user needs to create at their DNS provider
+func printDNSRecords(records []api.DNSRecord) {
+	if len(records) == 0 {
+		return
+	}
+	fmt.Println("Create these DNS records with your DNS provider:")
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  TYPE\tNAME\tVALUE")
+	for _, r := range records {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", r.Type, r.Name, r.Value)
+	}
+	_ = w.Flush()
+}
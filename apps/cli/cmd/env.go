@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var envAgentSlug string
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage environment variables",
+	Long: `Manage plain environment variables for your agents, such as LOG_LEVEL=debug.
+
+Unlike secrets, values are not sensitive and can be read back with 'oken env list'.
+Use 'oken secrets' for API keys, passwords and other credentials.`,
+}
+
+var envSetCmd = &cobra.Command{
+	Use:   "set <KEY=value>...",
+	Short: "Set environment variables",
+	Long: `Set one or more environment variables. Values may be empty.
+
+Names may contain letters, digits and underscores and must not start with a digit.
+
+Examples:
+  oken env set LOG_LEVEL=debug
+  oken env set LOG_LEVEL=info REGION=eu-west-1 --agent my-agent`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runEnvSet,
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List environment variables",
+	Long: `List environment variables and their values. Use --agent to filter by agent.
+
+Examples:
+  oken env list
+  oken env list --agent my-agent
+  oken env list -o json`,
+	Args: cobra.NoArgs,
+	RunE: runEnvList,
+}
+
+var envUnsetCmd = &cobra.Command{
+	Use:   "unset <KEY>",
+	Short: "Remove an environment variable",
+	Long: `Remove an environment variable by name.
+
+Examples:
+  oken env unset LOG_LEVEL
+  oken env unset LOG_LEVEL --agent my-agent`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvUnset,
+}
+
+func init() {
+	envCmd.PersistentFlags().StringVarP(&envAgentSlug, "agent", "a", "", "Agent slug (for agent-specific variables)")
+
+	envCmd.AddCommand(envSetCmd)
+	envCmd.AddCommand(envListCmd)
+	envCmd.AddCommand(envUnsetCmd)
+
+	rootCmd.AddCommand(envCmd)
+}
+
+// envScope describes where a variable applies, as shown in messages and lists
+func envScope(agentSlug *string) string {
+	if agentSlug != nil && *agentSlug != "" {
+		return fmt.Sprintf("agent:%s", *agentSlug)
+	}
+	return "user-level"
+}
+
+func runEnvSet(cmd *cobra.Command, args []string) error {
+	// Parse every KEY=value before setting any
+	names := make([]string, 0, len(args))
+	values := make([]string, 0, len(args))
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			ui.Error("Invalid format %q. Use KEY=value", arg)
+			return fmt.Errorf("invalid format")
+		}
+		names = append(names, name)
+		values = append(values, value)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	var agentSlugPtr *string
+	if envAgentSlug != "" {
+		agentSlugPtr = &envAgentSlug
+	}
+
+	for i, name := range names {
+		resp, err := client.SetEnv(name, values[i], agentSlugPtr)
+		if err != nil {
+			ui.Error("Failed to set %s: %v", name, err)
+			return err
+		}
+		ui.Success("%s: %s (%s)", resp.Message, name, envScope(agentSlugPtr))
+	}
+
+	return nil
+}
+
+func runEnvList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.ListEnv(envAgentSlug)
+	if err != nil {
+		ui.Error("Failed to list environment variables: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Vars)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(resp.Vars))
+		for _, v := range resp.Vars {
+			rows = append(rows, []string{v.Name, v.Value, stringValue(v.AgentSlug), v.UpdatedAt})
+		}
+		return ui.Table([]string{"name", "value", "agent", "updated"}, rows)
+	}
+
+	if len(resp.Vars) == 0 {
+		if envAgentSlug != "" {
+			ui.Info("No environment variables found for agent '%s'", envAgentSlug)
+		} else {
+			ui.Info("No environment variables found")
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tVALUE\tSCOPE\tUPDATED")
+	for _, v := range resp.Vars {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Name, v.Value, envScope(v.AgentSlug), dateOnly(v.UpdatedAt))
+	}
+	_ = w.Flush()
+
+	return nil
+}
+
+func runEnvUnset(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	if _, err := client.UnsetEnv(name, envAgentSlug); err != nil {
+		ui.Error("Failed to remove %s: %v", name, err)
+		return err
+	}
+
+	var agentSlugPtr *string
+	if envAgentSlug != "" {
+		agentSlugPtr = &envAgentSlug
+	}
+	ui.Success("Variable removed: %s (%s)", name, envScope(agentSlugPtr))
+
+	return nil
+}
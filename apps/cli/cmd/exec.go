@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var execCmd = &cobra.Command{
+	Use:         "exec <slug> -- <command> [args...]",
+	Short:       "Run a command in a running agent",
+	Annotations: textOutput,
+	Long: `Run a one-off command inside the agent's running environment and stream its
+output. The exit status of the command becomes the exit status of oken.
+
+Examples:
+  oken exec my-agent -- pip list
+  oken exec my-agent -- cat requirements.txt
+  oken exec my-agent -- sh -c 'ls -la /app'`,
+	Args:          cobra.MinimumNArgs(2),
+	RunE:          runExec,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	if cmd.ArgsLenAtDash() != 1 {
+		ui.Error("Separate the command with --, e.g. 'oken exec my-agent -- pip list'")
+		return fmt.Errorf("missing --")
+	}
+	slug, command := args[0], args[1:]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	// Ctrl+C stops the remote command
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	code, err := client.Exec(ctx, slug, command, os.Stdout, os.Stderr)
+	if err != nil {
+		if ctx.Err() != nil {
+			return &ExitError{Code: 130}
+		}
+		ui.Error("Failed to run command: %v", err)
+		return err
+	}
+
+	if code != 0 {
+		return &ExitError{Code: code}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/manifest"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	exportFile  string
+	exportForce bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:         "export [slug...]",
+	Short:       "Write current agents to a manifest",
+	Annotations: textOutput,
+	Long: `Describe agents as they are on the platform, with their scale, secret
+names and schedules, in a manifest for 'oken apply'. All agents are
+exported unless slugs are given.
+
+Secrets reference environment variables of the same name, since their
+values can't be read back. Add a path to agents you want 'oken apply' to
+be able to recreate.
+
+Examples:
+  oken export > oken.yaml
+  oken export my-agent other-agent -f oken.yaml`,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "Write the manifest to a file instead of stdout")
+	exportCmd.Flags().BoolVar(&exportForce, "force", false, "Overwrite an existing file")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportFile != "" && !exportForce {
+		if _, err := os.Stat(exportFile); err == nil {
+			ui.Error("%s already exists. Use --force to overwrite it.", exportFile)
+			return fmt.Errorf("file exists")
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	agents, err := exportAgents(client, args)
+	if err != nil {
+		ui.Error("Failed to get agents: %v", err)
+		return err
+	}
+
+	state := &manifest.State{
+		Secrets:   make(map[string][]string),
+		Schedules: make(map[string][]api.Schedule),
+	}
+
+	secrets, err := client.ListSecrets("")
+	if err != nil {
+		ui.Error("Failed to list secrets: %v", err)
+		return err
+	}
+	for _, s := range secrets.Secrets {
+		// Global secrets don't belong to any agent in the manifest
+		if s.AgentSlug != nil {
+			state.Secrets[*s.AgentSlug] = append(state.Secrets[*s.AgentSlug], s.Name)
+		}
+	}
+
+	schedules, err := client.ListSchedules("")
+	if err != nil {
+		ui.Error("Failed to list schedules: %v", err)
+		return err
+	}
+	for _, s := range schedules.Schedules {
+		state.Schedules[s.AgentSlug] = append(state.Schedules[s.AgentSlug], s)
+	}
+
+	var buf bytes.Buffer
+	if err := manifest.Write(&buf, manifest.FromState(agents, state)); err != nil {
+		ui.Error("Failed to encode manifest: %v", err)
+		return err
+	}
+
+	if exportFile == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(exportFile, buf.Bytes(), 0644); err != nil {
+		ui.Error("Failed to write %s: %v", exportFile, err)
+		return err
+	}
+	ui.Success("Exported %d agents to %s", len(agents), exportFile)
+	return nil
+}
+
+// exportAgents returns the agents with the given slugs, or all agents
+func exportAgents(client *api.Client, slugs []string) ([]api.Agent, error) {
+	if len(slugs) == 0 {
+		resp, err := client.ListAgents()
+		if err != nil {
+			return nil, err
+		}
+		return resp.Agents, nil
+	}
+
+	agents := make([]api.Agent, 0, len(slugs))
+	for i, slug := range slugs {
+		if slices.Contains(slugs[:i], slug) {
+			continue
+		}
+		agent, err := client.GetAgent(slug)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", slug, err)
+		}
+		agents = append(agents, *agent)
+	}
+	return agents, nil
+}
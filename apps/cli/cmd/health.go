@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	healthWait    bool
+	healthTimeout time.Duration
+	healthDirect  bool
+)
+
+var healthCmd = &cobra.Command{
+	Use:   "health <slug>",
+	Short: "Check agent health",
+	Long: `Probe an agent's health endpoint and report latency and cold starts.
+
+By default the platform probes the agent. Use --direct to call the agent's public
+endpoint from this machine instead; cold starts are only reported through the
+platform, so they aren't shown with --direct. Use --wait to block until the agent is healthy,
+for example right after a deploy.
+
+Exits with a non-zero status if the agent is unhealthy.
+
+Examples:
+  oken health my-agent
+  oken health my-agent --direct
+  oken deploy && oken health my-agent --wait --timeout 5m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHealth,
+}
+
+func init() {
+	healthCmd.Flags().BoolVarP(&healthWait, "wait", "w", false, "Poll until the agent is healthy")
+	healthCmd.Flags().DurationVar(&healthTimeout, "timeout", 2*time.Minute, "How long to wait with --wait")
+	healthCmd.Flags().BoolVar(&healthDirect, "direct", false, "Probe the agent endpoint directly instead of through the platform (cold starts aren't reported)")
+	healthCmd.MarkFlagsMutuallyExclusive("wait", "direct")
+	rootCmd.AddCommand(healthCmd)
+}
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	var health *api.HealthResponse
+	switch {
+	case healthDirect:
+		agent, err := client.GetAgent(slug)
+		if err != nil {
+			ui.Error("Failed to get agent: %v", err)
+			return err
+		}
+		if agent.Endpoint == nil || *agent.Endpoint == "" {
+			ui.Error("Agent %s has no endpoint. Is it running?", slug)
+			return fmt.Errorf("no endpoint")
+		}
+		health, err = client.ProbeEndpoint(*agent.Endpoint)
+		if err != nil {
+			ui.Error("Failed to probe endpoint: %v", err)
+			return err
+		}
+	case healthWait:
+		ui.Info("Waiting for %s to become healthy...", slug)
+		health, err = client.WaitForHealthy(slug, 2*time.Second, healthTimeout)
+		if err != nil && health == nil {
+			ui.Error("Failed to check health: %v", err)
+			return err
+		}
+	default:
+		health, err = client.CheckHealth(slug)
+		if err != nil {
+			ui.Error("Failed to check health: %v", err)
+			return err
+		}
+	}
+
+	if ui.IsStructured() {
+		if err := ui.Result(health); err != nil {
+			return err
+		}
+	} else if health.Healthy {
+		detail := fmt.Sprintf("%dms", health.LatencyMs)
+		if health.ColdStart {
+			detail += ", cold start"
+		}
+		ui.Success("%s is healthy (%s)", slug, detail)
+	} else if health.Error != "" {
+		ui.Error("%s is unhealthy: %s", slug, health.Error)
+	} else {
+		ui.Error("%s is unhealthy", slug)
+	}
+
+	if !health.Healthy {
+		if healthWait {
+			return fmt.Errorf("agent not healthy after %s", healthTimeout)
+		}
+		return fmt.Errorf("agent unhealthy")
+	}
+
+	return nil
+}
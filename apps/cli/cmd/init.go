@@ -13,9 +13,10 @@ import (
 )
 
 var initCmd = &cobra.Command{
-	Use:   "init",
-	Short: "Create oken.toml in current directory",
-	RunE:  runInit,
+	Use:         "init",
+	Short:       "Create oken.toml in current directory",
+	Annotations: textOutput,
+	RunE:        runInit,
 }
 
 func init() {
@@ -41,15 +42,46 @@ func runInit(cmd *cobra.Command, args []string) error {
 	slug := toSlug(dirName)
 	name := dirName
 
+	// Node projects are detected by their package.json
+	runtime := `# runtime = "python"
+# python_version = "3.12"
+# entrypoint = "main.py"`
+	if _, err := os.Stat("package.json"); err == nil {
+		runtime = `runtime = "node"
+# node_version = "20"
+# entrypoint = "index.js"`
+	}
+
 	content := fmt.Sprintf(`# Oken agent configuration
 
 name = "%s"
 slug = "%s"
 
+# What the agent is for, shown in 'oken status':
+# description = "Summarizes support tickets"
+# repository = "https://github.com/acme/agents"
+# owner = "ml-team@acme.com"
+
 # Optional settings:
-# python_version = "3.12"
-# entrypoint = "main.py"
-`, name, slug)
+%s
+
+# Runtime sizing:
+# [resources]
+# memory = "512Mi"
+# cpu = "0.5"
+# timeout = "60s"
+
+# Keeping instances running:
+# [lifecycle]
+# min_instances = 1  # instances kept running while idle
+# autosleep = "15m"  # stop idle instances after this, or "off"
+# restart_policy = "on-failure"  # always, on-failure or never
+
+# Packaging:
+# [package]
+# gitignore = false  # also package files excluded by .gitignore
+# include = [".streamlit/**", "**/.keep"]  # hidden files to package
+`, name, slug, runtime)
 
 	if err := os.WriteFile("oken.toml", []byte(content), 0644); err != nil {
 		ui.Error("Failed to create oken.toml: %v", err)
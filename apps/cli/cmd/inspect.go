@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <slug>",
+	Short: "Print the full agent object",
+	Long: `Print an agent exactly as the platform returns it, as indented JSON.
+
+Unlike 'oken status', which shows a curated summary, this includes every
+field, such as deployment details, scale, labels and the package digest,
+for debugging platform-side state. Use -o yaml for YAML, or --format to pick
+fields with a Go template.
+
+Examples:
+  oken inspect my-agent
+  oken inspect my-agent --format '{{.digest}}'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInspect,
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	raw, err := client.InspectAgent(slug)
+	if err != nil {
+		ui.Error("Failed to get agent: %v", err)
+		return err
+	}
+
+	if ui.IsTemplate() {
+		// Templates use the platform's field names, e.g. {{.digest}}
+		var agent map[string]any
+		if err := json.Unmarshal(raw, &agent); err != nil {
+			return err
+		}
+		return ui.Template(agent)
+	}
+
+	// Both keep the platform's field order
+	if ui.IsStructured() {
+		return ui.Result(raw)
+	}
+	return ui.JSON(raw)
+}
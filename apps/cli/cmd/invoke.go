@@ -2,34 +2,180 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"net/http"
 	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/batch"
 	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/outputs"
 	"github.com/neult/oken/apps/cli/internal/ui"
 )
 
-var invokeInput string
+// maxInputSize caps a single invocation input read from stdin or a batch file
+const maxInputSize = 10 * 1024 * 1024 // 10MB
+
+var (
+	invokeInput       string
+	invokeBatch       string
+	invokeResults     string
+	invokeConcurrency int
+	invokeBench       bool
+	invokeRequests    int
+	invokeRetry       int
+	invokeRetryOn     string
+	invokeSession     string
+	invokeNewSession  bool
+	invokeAttach      []string
+	invokeDownloadDir string
+	invokeTrace       bool
+	invokeHeaders     []string
+
+	// invokeAttachments are the --attach files, read and, when large, uploaded
+	// once for all invocations
+	invokeAttachments []api.Attachment
+	// invokeHeader is --header parsed, sent with every invocation
+	invokeHeader http.Header
+)
 
 var invokeCmd = &cobra.Command{
 	Use:   "invoke <slug>",
 	Short: "Invoke an agent",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runInvoke,
+	Long: `Call an agent with a JSON input, given with --input or on stdin, and print
+its output.
+
+With --batch, the agent is called once per line of a JSON Lines file, up to
+--concurrency calls at a time. Each result is written as a JSON line to
+--results, or to stdout, followed by a pass/fail summary.
+
+With --bench, the agent is called --requests times with the same input, up
+to --concurrency calls at a time, and latency percentiles, error rate and
+throughput are reported.
+
+--session passes a conversation ID so stateful agents can tie several
+invocations together; --new-session generates one. The session ID is
+printed after the output.
+
+--trace asks the platform for an execution trace of the invocation, with its
+steps, tool and model calls and how long each took, and prints it as a tree
+after the output. With -o json or -o yaml, the output and the trace are
+printed together as {"output": ..., "trace": ...}. --batch results include
+the trace of each line.
+
+The request ID of each invocation is printed too, also when it fails, so its
+logs can be found with 'oken logs <slug> --request <id>'.
+
+--header adds a header that the platform forwards to the agent, for trace
+propagation or feature flags. Repeat it to send several headers.
+
+--attach sends a file alongside the input, for document or vision agents.
+Repeat it to send several files.
+
+--download-dir saves files the agent returns, such as images, audio or CSV,
+instead of printing them as base64. A file is an output value like
+{"type": "file", "name": "chart.png", "data": "<base64>"} or a base64 data
+URL; each is replaced in the printed output by the path it was saved to.
+
+--retry retries failed invocations with exponential backoff. --retry-on picks
+which failures are retried: 5xx, 429, timeout and network are transport
+errors, and agent is an error returned by the agent itself.
+
+Examples:
+  oken invoke my-agent --input '{"question": "hi"}'
+  oken invoke my-agent@staging --input '{"question": "hi"}'
+  oken invoke my-agent --batch inputs.jsonl --concurrency 8 --results results.jsonl
+  oken invoke my-agent --bench --requests 100 --concurrency 10
+  oken invoke my-agent --input '{"question": "hi"}' --retry 3 --retry-on 5xx,timeout
+  oken invoke my-agent --input '{"question": "and tomorrow?"}' --session ses_abc123
+  oken invoke my-agent --input '{"question": "summarize"}' --attach report.pdf --attach chart.png
+  oken invoke my-agent --input '{"prompt": "a red fox"}' --download-dir ./out
+  oken invoke my-agent --input '{"question": "hi"}' --trace
+  oken invoke my-agent --input '{"question": "hi"}' -H 'X-Trace-Id: abc' -H 'X-Feature: beta'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInvoke,
 }
 
 func init() {
 	invokeCmd.Flags().StringVarP(&invokeInput, "input", "i", "", "JSON input (or use stdin)")
+	invokeCmd.Flags().StringVar(&invokeBatch, "batch", "", "Invoke once per line of a JSON Lines file, or - for stdin")
+	invokeCmd.Flags().StringVar(&invokeResults, "results", "", "Write --batch results to a JSON Lines file instead of stdout")
+	invokeCmd.Flags().IntVarP(&invokeConcurrency, "concurrency", "j", 4, "How many --batch or --bench invocations to run at once")
+	invokeCmd.Flags().BoolVar(&invokeBench, "bench", false, "Invoke repeatedly with the same input and report latency")
+	invokeCmd.Flags().IntVar(&invokeRequests, "requests", 100, "How many invocations to send with --bench")
+	invokeCmd.Flags().IntVar(&invokeRetry, "retry", 0, "Retry failed invocations up to this many times")
+	invokeCmd.Flags().StringVar(&invokeRetryOn, "retry-on", "5xx,timeout", "Failures to retry: 5xx, 429, timeout, network, agent")
+	invokeCmd.Flags().StringVar(&invokeSession, "session", "", "Session ID to continue a conversation with a stateful agent")
+	invokeCmd.Flags().BoolVar(&invokeNewSession, "new-session", false, "Start a new session with a generated ID")
+	invokeCmd.MarkFlagsMutuallyExclusive("session", "new-session")
+	invokeCmd.Flags().StringArrayVar(&invokeAttach, "attach", nil, "Send a file with the input (repeatable)")
+	invokeCmd.Flags().StringVar(&invokeDownloadDir, "download-dir", "", "Save files returned by the agent to this directory")
+	invokeCmd.Flags().BoolVar(&invokeTrace, "trace", false, "Print an execution trace of the invocation")
+	invokeCmd.Flags().StringArrayVarP(&invokeHeaders, "header", "H", nil, "Header to forward to the agent, as 'Name: value' (repeatable)")
 	rootCmd.AddCommand(invokeCmd)
 }
 
 func runInvoke(cmd *cobra.Command, args []string) error {
 	slug := args[0]
 
+	if invokeBatch == "" && invokeResults != "" {
+		ui.Error("--results can only be used with --batch.")
+		return fmt.Errorf("invalid flags")
+	}
+	if invokeBatch != "" && invokeInput != "" {
+		ui.Error("--input can't be used with --batch.")
+		return fmt.Errorf("invalid flags")
+	}
+	if invokeBench && invokeBatch != "" {
+		ui.Error("--bench can't be used with --batch.")
+		return fmt.Errorf("invalid flags")
+	}
+	if cmd.Flags().Changed("requests") && !invokeBench {
+		ui.Error("--requests can only be used with --bench.")
+		return fmt.Errorf("invalid flags")
+	}
+	if invokeRequests < 1 {
+		ui.Error("Invalid --requests %d. Use 1 or more.", invokeRequests)
+		return fmt.Errorf("invalid requests")
+	}
+	if invokeRetry < 0 {
+		ui.Error("Invalid --retry %d. Use 0 or more.", invokeRetry)
+		return fmt.Errorf("invalid retry")
+	}
+	if invokeBench && invokeDownloadDir != "" {
+		ui.Error("--download-dir can't be used with --bench.")
+		return fmt.Errorf("invalid flags")
+	}
+	if invokeBench && invokeTrace {
+		ui.Error("--trace can't be used with --bench.")
+		return fmt.Errorf("invalid flags")
+	}
+	if invokeBench && invokeRetry > 0 {
+		ui.Error("--retry can't be used with --bench, since retries would hide failures and skew latency.")
+		return fmt.Errorf("invalid flags")
+	}
+	retryOn, err := api.ParseRetryOn(invokeRetryOn)
+	if err != nil {
+		ui.Error("Invalid --retry-on: %v", err)
+		return err
+	}
+	if invokeConcurrency < 1 {
+		ui.Error("Invalid --concurrency %d. Use 1 or more.", invokeConcurrency)
+		return fmt.Errorf("invalid concurrency")
+	}
+	if invokeHeader, err = api.ParseHeaders(invokeHeaders); err != nil {
+		ui.Error("Invalid --header: %v", err)
+		return fmt.Errorf("invalid flags")
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		ui.Error("Failed to load config: %v", err)
@@ -41,6 +187,180 @@ func runInvoke(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
+	client := newClient(cfg)
+
+	invokeAttachments, err = loadAttachments(invokeAttach)
+	if err != nil {
+		ui.Error("Failed to read attachment: %v", err)
+		return err
+	}
+	// Large attachments are uploaded once, and every invocation and retry
+	// references the same upload
+	invokeAttachments, err = client.UploadAttachments(invokeAttachments)
+	if err != nil {
+		ui.Error("Failed to upload attachment: %v", err)
+		return err
+	}
+
+	policy := api.RetryPolicy{Retries: invokeRetry, On: retryOn}
+	if invokeBatch != "" {
+		return runBatchInvoke(client, slug, policy)
+	}
+
+	input, err := readInvokeInput()
+	if err != nil {
+		return err
+	}
+
+	if invokeBench {
+		return runBenchInvoke(client, slug, input)
+	}
+
+	// The output is printed to stdout, so keep it free of status messages
+	ui.ReserveStdout()
+
+	policy.OnRetry = func(attempt int, err error, wait time.Duration) {
+		ui.Warning("Attempt %d failed: %v. Retrying in %s...", attempt, err, wait)
+	}
+	opts := invokeOptions()
+	resp, err := client.InvokeAgentWithRetry(slug, input, opts, policy)
+	var retryErr *api.RetryError
+	var agentErr *api.AgentError
+	switch {
+	case errors.As(err, &retryErr):
+		ui.Error("Failed to invoke agent: %v", err)
+		printRequestLogsHint(slug, api.RequestIDOf(err))
+		return err
+	case errors.As(err, &agentErr):
+		ui.Error("Agent error: %s", agentErr.Message)
+		printInvokeTrace(agentErr.Trace)
+		printRequestLogsHint(slug, agentErr.RequestID)
+		return fmt.Errorf("agent error: %s", agentErr.Message)
+	case err != nil:
+		err = client.WithSuggestions(slug, err)
+		ui.Error("Failed to invoke agent: %v", err)
+		printRequestLogsHint(slug, api.RequestIDOf(err))
+		return err
+	}
+
+	unsaved := 0
+	if invokeDownloadDir != "" {
+		files, err := outputs.Save(resp.Output, invokeDownloadDir)
+		for _, f := range files {
+			ui.Success("Saved %s (%s)", f.Path, ui.Bytes(int64(f.Size)))
+		}
+		if err != nil {
+			ui.Error("Failed to save output files: %v", err)
+			return err
+		}
+	} else {
+		unsaved = outputs.Count(resp.Output)
+	}
+
+	if invokeTrace && ui.IsStructured() {
+		if err := ui.Result(tracedOutput{Output: resp.Output, Trace: resp.Trace}); err != nil {
+			ui.Error("Failed to format output: %v", err)
+			return err
+		}
+	} else {
+		// Output response as JSON
+		output, err := json.MarshalIndent(resp.Output, "", "  ")
+		if err != nil {
+			ui.Error("Failed to format output: %v", err)
+			return err
+		}
+
+		fmt.Println(string(output))
+		printInvokeTrace(resp.Trace)
+	}
+
+	if unsaved > 0 {
+		ui.Info("The output has %d files. Use --download-dir to save them.", unsaved)
+	}
+	if session := sessionOf(resp, opts); session != "" {
+		ui.Info("Session: %s", session)
+	}
+	if resp.RequestID != "" {
+		ui.Info("Request ID: %s", resp.RequestID)
+	}
+
+	return nil
+}
+
+// tracedOutput is printed by 'oken invoke --trace' with -o json or -o yaml
+type tracedOutput struct {
+	Output map[string]any `json:"output"`
+	Trace  *api.Trace     `json:"trace"`
+}
+
+// printInvokeTrace prints the trace of an invocation run with --trace
+func printInvokeTrace(trace *api.Trace) {
+	if !invokeTrace {
+		return
+	}
+	if trace == nil {
+		ui.Warning("No trace was returned for this invocation.")
+		return
+	}
+	if err := ui.Trace(trace); err != nil {
+		ui.Warning("Failed to print trace: %v", err)
+	}
+}
+
+// printRequestLogsHint prints the request ID of a failed invocation and how
+// to see its logs
+func printRequestLogsHint(slug, requestID string) {
+	if requestID == "" {
+		return
+	}
+	ui.Info("Request ID: %s", requestID)
+	ui.Info("See its logs with: oken logs %s --request %s", slug, requestID)
+}
+
+// invokeOptions returns the options of one invocation. With --new-session,
+// every call starts its own session.
+func invokeOptions() api.InvokeOptions {
+	opts := api.InvokeOptions{SessionID: invokeSession, Attachments: invokeAttachments, Trace: invokeTrace, Header: invokeHeader}
+	if invokeNewSession {
+		opts.SessionID = api.NewSessionID()
+	}
+	return opts
+}
+
+// loadAttachments reads --attach files, taking each content type from the
+// file extension or, failing that, the contents
+func loadAttachments(paths []string) ([]api.Attachment, error) {
+	attachments := make([]api.Attachment, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		contentType := mime.TypeByExtension(filepath.Ext(path))
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		attachments = append(attachments, api.Attachment{
+			Name:        filepath.Base(path),
+			ContentType: contentType,
+			Data:        data,
+		})
+	}
+	return attachments, nil
+}
+
+// sessionOf returns the session an invocation belonged to, preferring the
+// one the platform echoed
+func sessionOf(resp *api.InvokeResponse, opts api.InvokeOptions) string {
+	if resp != nil && resp.SessionID != "" {
+		return resp.SessionID
+	}
+	return opts.SessionID
+}
+
+// readInvokeInput parses the JSON input from --input or stdin, defaulting to
+// an empty object
+func readInvokeInput() (map[string]any, error) {
 	// Get input from flag or stdin
 	var inputJSON string
 	if invokeInput != "" {
@@ -49,11 +369,10 @@ func runInvoke(cmd *cobra.Command, args []string) error {
 		// Check if stdin has data
 		stat, err := os.Stdin.Stat()
 		if err == nil && (stat.Mode()&os.ModeCharDevice) == 0 {
-			const maxInputSize = 10 * 1024 * 1024 // 10MB
 			data, err := io.ReadAll(io.LimitReader(os.Stdin, maxInputSize))
 			if err != nil {
 				ui.Error("Failed to read stdin: %v", err)
-				return err
+				return nil, err
 			}
 			inputJSON = string(data)
 		}
@@ -68,30 +387,221 @@ func runInvoke(cmd *cobra.Command, args []string) error {
 	var input map[string]any
 	if err := json.Unmarshal([]byte(inputJSON), &input); err != nil {
 		ui.Error("Invalid JSON input: %v", err)
-		return err
+		return nil, err
 	}
+	return input, nil
+}
+
+// batchResult is one line of 'oken invoke --batch' results
+type batchResult struct {
+	Line       int            `json:"line"`
+	Input      map[string]any `json:"input"`
+	Output     map[string]any `json:"output,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	SessionID  string         `json:"sessionId,omitempty"`
+	RequestID  string         `json:"requestId,omitempty"`
+	Trace      *api.Trace     `json:"trace,omitempty"`
+	DurationMs int64          `json:"durationMs"`
+}
 
-	client := api.NewClient(cfg.Endpoint, cfg.Token)
+// runBatchInvoke invokes the agent once per --batch input, up to
+// --concurrency at a time, writing results as they finish
+func runBatchInvoke(client *api.Client, slug string, policy api.RetryPolicy) error {
+	source := invokeBatch
+	var in io.Reader = os.Stdin
+	if invokeBatch == "-" {
+		source = "stdin"
+	} else {
+		file, err := os.Open(invokeBatch)
+		if err != nil {
+			ui.Error("Failed to open batch file: %v", err)
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		in = file
+	}
 
-	resp, err := client.InvokeAgent(slug, input)
+	inputs, err := batch.Read(in, maxInputSize)
 	if err != nil {
-		ui.Error("Failed to invoke agent: %v", err)
+		ui.Error("Failed to read %s: %v", source, err)
 		return err
 	}
+	if len(inputs) == 0 {
+		ui.Warning("No inputs in %s", source)
+		return nil
+	}
 
-	if resp.Error != "" {
-		ui.Error("Agent error: %s", resp.Error)
-		return fmt.Errorf("agent error: %s", resp.Error)
+	out := io.Writer(os.Stdout)
+	var results *os.File
+	if invokeResults != "" {
+		results, err = os.Create(invokeResults)
+		if err != nil {
+			ui.Error("Failed to create results file: %v", err)
+			return err
+		}
+		defer func() { _ = results.Close() }()
+		out = results
+	} else {
+		// Results go to stdout, so keep it free of status messages
+		ui.ReserveStdout()
 	}
 
-	// Output response as JSON
-	output, err := json.MarshalIndent(resp.Output, "", "  ")
+	workers := min(invokeConcurrency, len(inputs))
+	ui.Info("Invoking %s with %d inputs, %d at a time...", slug, len(inputs), workers)
+
+	start := time.Now()
+	jobs := make(chan batch.Input)
+	encoder := json.NewEncoder(out)
+	// mu serializes result lines and the counters
+	var mu sync.Mutex
+	var done, failed int
+	var writeErr error
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for in := range jobs {
+				result := invokeBatchInput(client, slug, in, policy)
+
+				mu.Lock()
+				done++
+				if result.Error != "" {
+					failed++
+					ui.Error("Line %d: %s", result.Line, result.Error)
+				}
+				if err := encoder.Encode(result); err != nil && writeErr == nil {
+					writeErr = err
+				}
+				if ui.IsProgressJSON() {
+					ui.Progress("invoke", done*100/len(inputs), "Invoked %d of %d", done, len(inputs))
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	for _, in := range inputs {
+		jobs <- in
+	}
+	close(jobs)
+	wg.Wait()
+
+	if writeErr == nil && results != nil {
+		writeErr = results.Close()
+	}
+	if writeErr != nil {
+		ui.Error("Failed to write results: %v", writeErr)
+		return writeErr
+	}
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if failed == 0 {
+		ui.Success("%d of %d invocations passed in %s", len(inputs), len(inputs), elapsed)
+		return nil
+	}
+	ui.Error("%d of %d invocations failed (%d passed) in %s", failed, len(inputs), len(inputs)-failed, elapsed)
+	return fmt.Errorf("%d of %d invocations failed", failed, len(inputs))
+}
+
+// invokeBatchInput invokes the agent with one input, recording agent and
+// request errors in the result
+func invokeBatchInput(client *api.Client, slug string, in batch.Input, policy api.RetryPolicy) batchResult {
+	result := batchResult{Line: in.Line, Input: in.Input}
+	policy.OnRetry = func(attempt int, err error, wait time.Duration) {
+		ui.Warning("Line %d: attempt %d failed: %v. Retrying in %s...", in.Line, attempt, err, wait)
+	}
+	opts := invokeOptions()
+	start := time.Now()
+	resp, err := client.InvokeAgentWithRetry(slug, in.Input, opts, policy)
+	result.DurationMs = time.Since(start).Milliseconds()
+	result.SessionID = sessionOf(resp, opts)
+
 	if err != nil {
-		ui.Error("Failed to format output: %v", err)
-		return err
+		result.Error = err.Error()
+		result.RequestID = api.RequestIDOf(err)
+		var agentErr *api.AgentError
+		if errors.As(err, &agentErr) {
+			result.Trace = agentErr.Trace
+		}
+		return result
+	}
+	result.Output = resp.Output
+	result.RequestID = resp.RequestID
+	result.Trace = resp.Trace
+
+	// Each line's files go in their own directory so names don't collide
+	if invokeDownloadDir != "" {
+		dir := filepath.Join(invokeDownloadDir, fmt.Sprintf("line-%d", in.Line))
+		if _, err := outputs.Save(result.Output, dir); err != nil {
+			result.Error = fmt.Sprintf("save output files: %v", err)
+		}
+	}
+	return result
+}
+
+// runBenchInvoke invokes the agent --requests times with the same input, up
+// to --concurrency at a time, and reports latency and errors
+func runBenchInvoke(client *api.Client, slug string, input map[string]any) error {
+	workers := min(invokeConcurrency, invokeRequests)
+	ui.Info("Sending %d requests to %s, %d at a time...", invokeRequests, slug, workers)
+
+	start := time.Now()
+	jobs := make(chan int)
+	// mu guards latencies and failures
+	var mu sync.Mutex
+	latencies := make([]time.Duration, 0, invokeRequests)
+	failures := make(map[string]int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for range jobs {
+				began := time.Now()
+				resp, err := client.InvokeAgent(slug, input, invokeOptions())
+				elapsed := time.Since(began)
+
+				mu.Lock()
+				latencies = append(latencies, elapsed)
+				switch {
+				case err != nil:
+					failures[err.Error()]++
+				case resp.Error != "":
+					failures[resp.Error]++
+				}
+				if ui.IsProgressJSON() {
+					done := len(latencies)
+					ui.Progress("bench", done*100/invokeRequests, "Sent %d of %d requests", done, invokeRequests)
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	for i := range invokeRequests {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	result := batch.SummarizeBench(slug, workers, time.Since(start), latencies, failures)
+
+	if ui.IsStructured() {
+		return ui.Result(result)
 	}
 
-	fmt.Println(string(output))
+	fmt.Printf("Agent:        %s\n", slug)
+	fmt.Printf("Requests:     %d (%d at a time)\n", result.Requests, result.Concurrency)
+	fmt.Printf("Errors:       %d (%.1f%%)\n", result.Errors, result.ErrorRate*100)
+	fmt.Printf("Duration:     %s\n", (time.Duration(result.DurationMs) * time.Millisecond).String())
+	fmt.Printf("Throughput:   %.1f req/s\n", result.Throughput)
+	fmt.Printf("Latency min:  %.0fms\n", result.LatencyMinMs)
+	fmt.Printf("Latency p50:  %.0fms\n", result.LatencyP50Ms)
+	fmt.Printf("Latency p90:  %.0fms\n", result.LatencyP90Ms)
+	fmt.Printf("Latency p95:  %.0fms\n", result.LatencyP95Ms)
+	fmt.Printf("Latency p99:  %.0fms\n", result.LatencyP99Ms)
+	fmt.Printf("Latency max:  %.0fms\n", result.LatencyMaxMs)
 
+	if len(result.TopErrors) > 0 {
+		fmt.Println()
+		for _, e := range result.TopErrors {
+			ui.Warning("%d× %s", e.Count, e.Message)
+		}
+	}
 	return nil
 }
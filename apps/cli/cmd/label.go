@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var labelCmd = &cobra.Command{
+	Use:   "label <slug> <key=value|key->...",
+	Short: "Add, change or remove agent labels",
+	Long: `Add, change or remove labels on an agent. key=value sets a label and key-
+removes one; other labels are kept.
+
+Labels are key=value tags for finding and grouping agents, as with
+'oken list --label team=ml'. They can also be set with 'oken deploy --label'.
+
+Examples:
+  oken label my-agent team=ml tier=prod
+  oken label my-agent tier-`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runLabel,
+}
+
+func init() {
+	rootCmd.AddCommand(labelCmd)
+}
+
+func runLabel(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	var req api.LabelRequest
+	var set []string
+	for _, arg := range args[1:] {
+		if key, ok := strings.CutSuffix(arg, "-"); ok && !strings.Contains(arg, "=") {
+			req.Remove = append(req.Remove, key)
+			continue
+		}
+		set = append(set, arg)
+	}
+	if len(set) > 0 {
+		labels, err := api.ParseLabels(set)
+		if err != nil {
+			ui.Error("%v", err)
+			return fmt.Errorf("invalid labels")
+		}
+		req.Set = labels
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.SetLabels(slug, req)
+	if err != nil {
+		ui.Error("Failed to update labels: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Agent.Labels)
+	}
+
+	ui.Success("Labels updated: %s", resp.Agent.Slug)
+	printLabels(&resp.Agent, "  ")
+
+	return nil
+}
+
+// printLabels prints the labels of an agent, if it has any
+func printLabels(agent *api.Agent, indent string) {
+	if len(agent.Labels) > 0 {
+		fmt.Printf("%sLabels:     %s\n", indent, api.FormatLabels(agent.Labels))
+	}
+}
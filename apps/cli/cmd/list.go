@@ -12,18 +12,34 @@ import (
 	"github.com/neult/oken/apps/cli/internal/ui"
 )
 
+var listLabels []string
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all agents",
-	Args:  cobra.NoArgs,
-	RunE:  runList,
+	Long: `List all agents.
+
+With --label, only agents that have every given label are listed.
+
+Examples:
+  oken list
+  oken list --label team=ml --label tier=prod`,
+	Args: cobra.NoArgs,
+	RunE: runList,
 }
 
 func init() {
+	listCmd.Flags().StringSliceVarP(&listLabels, "label", "l", nil, "Only list agents with this key=value label (repeatable)")
 	rootCmd.AddCommand(listCmd)
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	selector, err := api.ParseLabels(listLabels)
+	if err != nil {
+		ui.Error("Invalid --label: %v", err)
+		return fmt.Errorf("invalid flags")
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		ui.Error("Failed to load config: %v", err)
@@ -35,7 +51,7 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
-	client := api.NewClient(cfg.Endpoint, cfg.Token)
+	client := newClient(cfg)
 
 	resp, err := client.ListAgents()
 	if err != nil {
@@ -43,21 +59,73 @@ func runList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if len(resp.Agents) == 0 {
-		ui.Info("No agents found. Deploy one with 'oken deploy'.")
+	agents := make([]api.Agent, 0, len(resp.Agents))
+	for _, agent := range resp.Agents {
+		if api.MatchLabels(agent, selector) {
+			agents = append(agents, agent)
+		}
+	}
+
+	empty := "No agents found. Deploy one with 'oken deploy'."
+	if len(selector) > 0 {
+		empty = fmt.Sprintf("No agents with labels %s", api.FormatLabels(selector))
+	}
+	return printAgents(agents, empty)
+}
+
+// printAgents prints agents as a table, or with the selected output format.
+// empty is shown instead of an empty table.
+func printAgents(agents []api.Agent, empty string) error {
+	if ui.IsTemplate() {
+		for _, agent := range agents {
+			if err := ui.Template(agent); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if ui.IsStructured() {
+		return ui.Result(agents)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(agents))
+		for _, agent := range agents {
+			rows = append(rows, []string{agent.Name, agent.Slug, stringValue(agent.Environment), string(agent.Status), stringValue(agent.Endpoint), api.FormatLabels(agent.Labels)})
+		}
+		return ui.Table([]string{"name", "slug", "env", "status", "endpoint", "labels"}, rows)
+	}
+
+	if len(agents) == 0 {
+		ui.Info("%s", empty)
 		return nil
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "NAME\tSLUG\tSTATUS\tENDPOINT")
-	for _, agent := range resp.Agents {
+	_, _ = fmt.Fprintln(w, "NAME\tSLUG\tENV\tSTATUS\tENDPOINT\tLABELS")
+	for _, agent := range agents {
 		endpoint := "-"
 		if agent.Endpoint != nil && *agent.Endpoint != "" {
 			endpoint = *agent.Endpoint
 		}
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", agent.Name, agent.Slug, agent.Status, endpoint)
+		env := "-"
+		if agent.Environment != nil && *agent.Environment != "" {
+			env = *agent.Environment
+		}
+		labels := "-"
+		if len(agent.Labels) > 0 {
+			labels = api.FormatLabels(agent.Labels)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", agent.Name, agent.Slug, env, ui.AgentStatus(agent.Status), endpoint, labels)
 	}
 	_ = w.Flush()
 
 	return nil
 }
+
+// stringValue returns the value of an optional API field, or "" if unset
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
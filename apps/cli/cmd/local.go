@@ -1,26 +1,105 @@
 package cmd
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	localContext      string
+	localBuild        bool
+	localWithMetrics  bool
+	localFollow       bool
+	localSeedEmail    string
+	localSeedPassword string
+	localSeedNoAgent  bool
+	localUpgradeVer   string
+	localUpgradeYes   bool
 )
 
-var localBuild bool
+// localVersionEnv pins the platform and runner images in the compose file
+const localVersionEnv = "OKEN_VERSION"
+
+// localUpgradeServices are the services whose images are released with Oken
+var localUpgradeServices = []string{"platform", "runner"}
+
+// imageTagPattern matches a Docker image tag
+var imageTagPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// localSeedAgent is the sample agent deployed by 'oken local seed'
+var localSeedAgent = map[string]string{
+	"oken.toml": `name = "Hello"
+slug = "hello"
+`,
+	"main.py": `def handler(input):
+    name = input.get("name", "world")
+    return {"message": f"Hello, {name}!"}
+`,
+}
+
+// localHealthTimeout bounds waiting for the platform and runner to answer
+// with --follow; the platform compiles on its first start
+const localHealthTimeout = 2 * time.Minute
+
+// localMetricsProfile is the compose profile of the Prometheus and cAdvisor
+// services
+const localMetricsProfile = "metrics"
 
 var localCmd = &cobra.Command{
-	Use:   "local",
-	Short: "Manage local development environment",
+	Use:         "local",
+	Short:       "Manage local development environment",
+	Annotations: textOutput,
+	Long: `Run the Oken stack with Docker.
+
+The stack runs on the Docker host docker itself uses: this machine, or a
+remote one set with --context or DOCKER_HOST, so a team can share one
+self-hosted dev instance. The URLs printed point at that host.
+
+Examples:
+  oken local start
+  oken local start --context shared-dev
+  DOCKER_HOST=ssh://dev@oken-dev.internal oken local start`,
 }
 
 var localStartCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start local development environment",
-	Long:  "Start all Oken services locally using Docker (postgres, platform, runner)",
-	RunE:  runLocalStart,
+	Long: `Start all Oken services using Docker (postgres, platform, runner), on this
+machine or the Docker host given with --context or DOCKER_HOST.
+
+--with-metrics also starts Prometheus on port 9090, preconfigured to scrape
+CPU, memory and network metrics of the platform, runner and agent containers
+through cAdvisor.
+
+--follow waits for the platform and runner to answer, then streams the logs
+of all services until Ctrl+C, so a crash shows up right away. The services
+keep running after you stop following.
+
+Examples:
+  oken local start
+  oken local start --build --with-metrics
+  oken local start --follow
+  oken local start --context shared-dev`,
+	RunE: runLocalStart,
 }
 
 var localStopCmd = &cobra.Command{
@@ -30,16 +109,65 @@ var localStopCmd = &cobra.Command{
 	RunE:  runLocalStop,
 }
 
+var localSeedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Create a demo user and sample agent",
+	Long: `Create a demo user on the local platform, log the CLI in as that user and
+deploy a sample agent, so the stack can be tried without opening the
+dashboard.
+
+The demo user is created on the first run and signed in on later ones. The
+sample agent, hello, greets whoever is named in its input; --no-agent only
+creates the user and logs in.
+
+Seeding targets the platform on the Docker host, http://localhost:3000 unless
+--context or DOCKER_HOST point elsewhere, or --endpoint. The login is stored
+for that endpoint without changing the default one.
+
+Examples:
+  oken local start && oken local seed
+  oken invoke hello -i '{"name": "Oken"}'
+  oken local seed --email me@example.com --password hunter2hunter2`,
+	RunE: runLocalSeed,
+}
+
+var localUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade the local stack to a newer release",
+	Long: `Pull the latest platform and runner images, or the release given with
+--version, then migrate the database and restart the services.
+
+The images are pulled first and what changed is listed before asking to
+continue; nothing is restarted if the stack is already up to date. The
+version is saved in infra/.env, so later 'oken local start' runs keep it.
+
+Examples:
+  oken local upgrade
+  oken local upgrade --version v0.4.2
+  oken local upgrade --yes`,
+	RunE: runLocalUpgrade,
+}
+
 func init() {
+	localCmd.PersistentFlags().StringVar(&localContext, "context", "", "Docker context to run the stack on (default: the current one, or DOCKER_HOST)")
 	localStartCmd.Flags().BoolVar(&localBuild, "build", false, "Rebuild Docker images")
+	localStartCmd.Flags().BoolVarP(&localFollow, "follow", "f", false, "Follow the logs of all services once they are up")
+	localStartCmd.Flags().BoolVar(&localWithMetrics, "with-metrics", false, "Also start Prometheus and cAdvisor for observability")
+	localSeedCmd.Flags().StringVar(&localSeedEmail, "email", "demo@oken.local", "Email of the demo user")
+	localSeedCmd.Flags().StringVar(&localSeedPassword, "password", "oken-demo-password", "Password of the demo user")
+	localSeedCmd.Flags().BoolVar(&localSeedNoAgent, "no-agent", false, "Don't deploy the sample agent")
 	localCmd.AddCommand(localStartCmd)
 	localCmd.AddCommand(localStopCmd)
+	localUpgradeCmd.Flags().StringVar(&localUpgradeVer, "version", "latest", "Release to upgrade to")
+	localUpgradeCmd.Flags().BoolVarP(&localUpgradeYes, "yes", "y", false, "Skip confirmation prompt")
+	localCmd.AddCommand(localSeedCmd)
+	localCmd.AddCommand(localUpgradeCmd)
 	rootCmd.AddCommand(localCmd)
 }
 
 func findComposePath() (string, error) {
 	// Try current directory first
-	path := "infra/docker-compose.yml"
+	path := filepath.Join("infra", "docker-compose.yml")
 	if _, err := os.Stat(path); err == nil {
 		return path, nil
 	}
@@ -69,20 +197,88 @@ func findComposePath() (string, error) {
 	return "", fmt.Errorf("oken repo not found\n\nTo self-host Oken, clone the repo and run from there:\n  git clone https://github.com/neult/oken.git\n  cd oken\n  oken local start")
 }
 
+// dockerCommand returns a docker command run against the Docker host chosen
+// with --context, or docker's own default
+func dockerCommand(args ...string) *exec.Cmd {
+	if localContext != "" {
+		args = append([]string{"--context", localContext}, args...)
+	}
+	return exec.Command("docker", args...)
+}
+
+// localCompose returns a docker compose command for the stack. host is
+// where the stack runs, which the platform needs for the URLs it hands out.
+func localCompose(composePath, host string, args ...string) *exec.Cmd {
+	cmd := dockerCommand(append([]string{"compose", "-f", composePath}, args...)...)
+	cmd.Env = append(os.Environ(), "OKEN_PLATFORM_URL="+localURL(host, "3000"))
+	return cmd
+}
+
+// localHost returns the hostname of the Docker host the stack runs on:
+// localhost, unless --context, DOCKER_HOST or the current docker context
+// point at another machine
+func localHost() string {
+	host := os.Getenv("DOCKER_HOST")
+	if localContext != "" || host == "" {
+		inspect := []string{"context", "inspect", "--format", "{{.Endpoints.docker.Host}}"}
+		if localContext != "" {
+			inspect = append(inspect, localContext)
+		}
+		out, err := exec.Command("docker", inspect...).Output()
+		if err != nil {
+			return "localhost"
+		}
+		host = strings.TrimSpace(string(out))
+	}
+	return dockerHostname(host)
+}
+
+// dockerHostname returns the machine a Docker host address such as
+// ssh://dev@oken-dev.internal or tcp://10.0.0.5:2376 refers to; sockets
+// and pipes are on this machine
+func dockerHostname(host string) string {
+	u, err := url.Parse(host)
+	if err != nil || u.Scheme == "unix" || u.Scheme == "npipe" || u.Hostname() == "" {
+		return "localhost"
+	}
+	return u.Hostname()
+}
+
+// localURL returns the http URL of a port published by the stack
+func localURL(host, port string) string {
+	return "http://" + net.JoinHostPort(host, port)
+}
+
 func runLocalStart(cmd *cobra.Command, args []string) error {
 	composePath, err := findComposePath()
 	if err != nil {
 		return err
 	}
 
-	dockerArgs := []string{"compose", "-f", composePath, "up", "-d"}
+	host := localHost()
+
+	var composeArgs []string
+	if localWithMetrics {
+		composeArgs = append(composeArgs, "--profile", localMetricsProfile)
+		if host != "localhost" {
+			// Bind mounts are resolved on the Docker host, which has no clone
+			ui.Warning("Prometheus reads infra/prometheus.yml from the Docker host; copy it to %s at the same path", host)
+		}
+	}
+	composeArgs = append(composeArgs, "up", "-d")
 	if localBuild {
-		dockerArgs = append(dockerArgs, "--build")
+		composeArgs = append(composeArgs, "--build")
 	}
 
-	fmt.Println("Starting Oken services...")
+	// Logs are followed from here on, so earlier runs don't bury this one
+	started := time.Now()
+	if host == "localhost" {
+		fmt.Println("Starting Oken services...")
+	} else {
+		fmt.Printf("Starting Oken services on %s...\n", host)
+	}
 
-	dockerCmd := exec.Command("docker", dockerArgs...)
+	dockerCmd := localCompose(composePath, host, composeArgs...)
 	dockerCmd.Stdout = os.Stdout
 	dockerCmd.Stderr = os.Stderr
 
@@ -92,15 +288,107 @@ func runLocalStart(cmd *cobra.Command, args []string) error {
 
 	fmt.Println()
 	fmt.Println("Oken is running:")
-	fmt.Println("  Platform: http://localhost:3000")
-	fmt.Println("  Runner:   http://localhost:8000")
-	fmt.Println("  Postgres: localhost:5432")
+	fmt.Printf("  Platform:   %s\n", localURL(host, "3000"))
+	fmt.Printf("  Runner:     %s\n", localURL(host, "8000"))
+	fmt.Printf("  Postgres:   %s\n", net.JoinHostPort(host, "5432"))
+	if localWithMetrics {
+		fmt.Printf("  Prometheus: %s\n", localURL(host, "9090"))
+	}
 	fmt.Println()
+	if host != "localhost" {
+		fmt.Printf("Point the CLI at it with 'oken login --endpoint %s'\n", localURL(host, "3000"))
+	}
 	fmt.Println("Run 'oken local stop' to stop all services")
 
+	if !localFollow {
+		return nil
+	}
+	return followLocalStack(composePath, host, started)
+}
+
+// followLocalStack waits for the platform and runner to answer, then streams
+// the logs of all services since started until interrupted
+func followLocalStack(composePath, host string, started time.Time) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Println()
+	checks := []struct{ name, url string }{
+		{"Platform", localURL(host, "3000")},
+		{"Runner", localURL(host, "8000") + "/health"},
+	}
+	for _, check := range checks {
+		ui.Info("Waiting for the %s at %s...", strings.ToLower(check.name), check.url)
+		if err := waitForHTTP(ctx, check.url, localHealthTimeout); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			ui.Warning("%s not answering after %s: %v", check.name, localHealthTimeout, err)
+			continue
+		}
+		ui.Success("%s is up", check.name)
+	}
+
+	fmt.Println()
+	ui.Info("Following logs; press Ctrl+C to stop following, the services keep running")
+	logs := localCompose(composePath, host, "logs", "--follow", "--since", started.Format(time.RFC3339))
+	logs.Stdout = os.Stdout
+	logs.Stderr = os.Stderr
+	if err := logs.Start(); err != nil {
+		return fmt.Errorf("failed to follow logs: %w", err)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// Ctrl+C reaches docker too, but a signal sent to oken alone doesn't
+			_ = logs.Process.Signal(os.Interrupt)
+		case <-done:
+		}
+	}()
+	err := logs.Wait()
+	// docker exits non-zero when interrupted
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to follow logs: %w", err)
+	}
 	return nil
 }
 
+// waitForHTTP polls target until it answers with a status below 500, ctx is
+// done, or timeout passes
+func waitForHTTP(ctx context.Context, target string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil
+			}
+			err = fmt.Errorf("status %d", resp.StatusCode)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
 func runLocalStop(cmd *cobra.Command, args []string) error {
 	composePath, err := findComposePath()
 	if err != nil {
@@ -109,7 +397,8 @@ func runLocalStop(cmd *cobra.Command, args []string) error {
 
 	fmt.Println("Stopping Oken services...")
 
-	dockerCmd := exec.Command("docker", "compose", "-f", composePath, "down")
+	// The profile makes down also stop the metrics services, if they were started
+	dockerCmd := localCompose(composePath, localHost(), "--profile", localMetricsProfile, "down")
 	dockerCmd.Stdout = os.Stdout
 	dockerCmd.Stderr = os.Stderr
 
@@ -121,3 +410,225 @@ func runLocalStop(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runLocalSeed(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	// The default endpoint may point at a hosted platform, so seeding only
+	// follows --endpoint
+	configured := cfg.Endpoint
+	cfg.Endpoint = cmp.Or(endpoint, localURL(localHost(), "3000"))
+	cfg.Token = ""
+
+	client := newClient(cfg)
+	// An org saved for another endpoint doesn't exist here
+	client.Org = ""
+
+	ui.Info("Creating demo user %s on %s...", localSeedEmail, cfg.Endpoint)
+
+	resp, err := client.PasswordLogin(localSeedEmail, localSeedPassword, "Demo")
+	if err != nil {
+		ui.Error("Failed to create demo user: %v", err)
+		var unreachable *api.UnreachableError
+		if errors.As(err, &unreachable) {
+			ui.Info("Is the local stack running? Start it with 'oken local start'.")
+		}
+		return err
+	}
+	if err := saveLogin(cfg, resp.Token, resp.TokenExpiresAt, localSeedEmail); err != nil {
+		return err
+	}
+	ui.Success("Logged in as %s", localSeedEmail)
+
+	if !localSeedNoAgent {
+		client = newClient(cfg)
+		client.Org = ""
+		agent, err := deploySeedAgent(client)
+		if err != nil {
+			ui.Error("Failed to deploy sample agent: %v", err)
+			return err
+		}
+		ui.Success("Deployed sample agent: %s (%s)", agent.Slug, ui.AgentStatus(agent.Status))
+	}
+
+	hint := ""
+	if configured != cfg.Endpoint {
+		hint = " --endpoint " + cfg.Endpoint
+	}
+	fmt.Println()
+	fmt.Println("Try it:")
+	if !localSeedNoAgent {
+		fmt.Printf("  oken invoke hello -i '{\"name\": \"Oken\"}'%s\n", hint)
+	}
+	fmt.Printf("  oken list%s\n", hint)
+	fmt.Printf("  Dashboard: %s (%s / %s)\n", cfg.Endpoint, localSeedEmail, localSeedPassword)
+
+	return nil
+}
+
+// deploySeedAgent writes the sample agent to a temporary directory and
+// deploys it, waiting until it can be invoked
+func deploySeedAgent(client *api.Client) (*api.Agent, error) {
+	dir, err := os.MkdirTemp("", "oken-seed-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for name, content := range localSeedAgent {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	ui.Info("Deploying sample agent...")
+	deployWait = true
+	return deployDir(client, dir, "", "")
+}
+
+func runLocalUpgrade(cmd *cobra.Command, args []string) error {
+	if !imageTagPattern.MatchString(localUpgradeVer) {
+		ui.Error("Invalid --version %q. Use a release tag such as v0.4.2, or latest.", localUpgradeVer)
+		return fmt.Errorf("invalid flags")
+	}
+
+	composePath, err := findComposePath()
+	if err != nil {
+		return err
+	}
+
+	host := localHost()
+	compose := func(args ...string) *exec.Cmd {
+		c := localCompose(composePath, host, args...)
+		c.Env = append(c.Env, localVersionEnv+"="+localUpgradeVer)
+		c.Stderr = os.Stderr
+		return c
+	}
+
+	out, err := compose(append([]string{"config", "--images"}, localUpgradeServices...)...).Output()
+	if err != nil {
+		return fmt.Errorf("failed to read compose file: %w", err)
+	}
+	images := strings.Fields(string(out))
+
+	before := make(map[string]string, len(images))
+	for _, image := range images {
+		before[image] = localImageID(image)
+	}
+
+	fmt.Printf("Pulling Oken %s images...\n", localUpgradeVer)
+	pull := compose(append([]string{"pull"}, localUpgradeServices...)...)
+	pull.Stdout = os.Stdout
+	if err := pull.Run(); err != nil {
+		return fmt.Errorf("failed to pull images: %w", err)
+	}
+
+	var changed []string
+	for _, image := range images {
+		if id := localImageID(image); id != before[image] {
+			changed = append(changed, fmt.Sprintf("  %s  %s -> %s", image, shortImageID(before[image]), shortImageID(id)))
+		}
+	}
+	if len(changed) == 0 {
+		ui.Success("Already up to date (%s)", localUpgradeVer)
+		return saveLocalVersion(composePath, localUpgradeVer)
+	}
+
+	fmt.Println()
+	fmt.Println("New images:")
+	for _, line := range changed {
+		fmt.Println(line)
+	}
+	fmt.Println()
+
+	if !localUpgradeYes {
+		confirmed, err := ui.Confirm("Migrate the database and restart the platform and runner?")
+		if errors.Is(err, ui.ErrNoInput) {
+			ui.Error("Cannot ask for confirmation. Pass --yes to upgrade without prompting.")
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Info("Aborted. The new images are pulled but not running.")
+			return nil
+		}
+	}
+
+	fmt.Println("Migrating database...")
+	if err := compose("up", "-d", "--wait", "postgres").Run(); err != nil {
+		return fmt.Errorf("failed to start postgres: %w", err)
+	}
+	// Only migrations the database doesn't have yet are applied
+	migrate := compose("run", "--rm", "--no-deps", "platform", "bunx", "drizzle-kit", "migrate")
+	migrate.Stdout = os.Stdout
+	if err := migrate.Run(); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	fmt.Println("Restarting services...")
+	up := compose(append([]string{"up", "-d"}, localUpgradeServices...)...)
+	up.Stdout = os.Stdout
+	if err := up.Run(); err != nil {
+		return fmt.Errorf("failed to restart services: %w", err)
+	}
+
+	if err := saveLocalVersion(composePath, localUpgradeVer); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	ui.Success("Upgraded to %s", localUpgradeVer)
+	for _, line := range changed {
+		fmt.Println(line)
+	}
+
+	return nil
+}
+
+// localImageID returns the ID of a local image, or "" if it isn't pulled
+func localImageID(image string) string {
+	out, err := dockerCommand("image", "inspect", "--format", "{{.Id}}", image).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// shortImageID shortens an image ID the way 'docker images' does
+func shortImageID(id string) string {
+	if id == "" {
+		return "(none)"
+	}
+	id = strings.TrimPrefix(id, "sha256:")
+	return id[:min(12, len(id))]
+}
+
+// saveLocalVersion pins the image version in the .env file next to the
+// compose file, which docker compose reads on every run, keeping any other
+// variables in it
+func saveLocalVersion(composePath, version string) error {
+	path := filepath.Join(filepath.Dir(composePath), ".env")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var lines []string
+	for line := range strings.Lines(string(data)) {
+		if !strings.HasPrefix(line, localVersionEnv+"=") {
+			lines = append(lines, strings.TrimRight(line, "\n"))
+		}
+	}
+	lines = append(lines, localVersionEnv+"="+version)
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to save version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	logDrainType    string
+	logDrainURL     string
+	logDrainHeaders []string
+)
+
+var logDrainsCmd = &cobra.Command{
+	Use:   "logdrains",
+	Short: "Manage log drains",
+	Long:  "Forward agent logs to Datadog, Grafana Loki, OpenTelemetry collectors or any HTTP endpoint.",
+}
+
+var logDrainsAddCmd = &cobra.Command{
+	Use:   "add <slug>",
+	Short: "Forward an agent's logs",
+	Long: `Start forwarding an agent's logs to an external service.
+
+--type picks the protocol: otlp for an OpenTelemetry collector over
+OTLP/HTTP, datadog for the Datadog logs intake, loki for Grafana Loki's push
+API, or http for newline-delimited JSON posted to any endpoint.
+
+--header adds a header to every batch, usually to authenticate. Header
+values are stored by the platform and never shown again.
+
+Examples:
+  oken logdrains add my-agent --type otlp --url https://otel.acme.com:4318/v1/logs
+  oken logdrains add my-agent --type datadog --url https://http-intake.logs.datadoghq.com/api/v2/logs -H "DD-API-KEY: $DD_API_KEY"
+  oken logdrains add my-agent --type loki --url https://logs-prod.grafana.net/loki/api/v1/push -H "Authorization: Basic $LOKI_AUTH"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogDrainsAdd,
+}
+
+var logDrainsListCmd = &cobra.Command{
+	Use:   "list <slug>",
+	Short: "List log drains for an agent",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runLogDrainsList,
+}
+
+var logDrainsRemoveCmd = &cobra.Command{
+	Use:   "remove <slug> <id>",
+	Short: "Stop forwarding logs to a drain",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runLogDrainsRemove,
+}
+
+func init() {
+	logDrainsAddCmd.Flags().StringVar(&logDrainType, "type", "", "Drain protocol: "+strings.Join(api.LogDrainTypes, ", ")+" (required)")
+	logDrainsAddCmd.Flags().StringVar(&logDrainURL, "url", "", "URL that receives the logs (required)")
+	logDrainsAddCmd.Flags().StringArrayVarP(&logDrainHeaders, "header", "H", nil, "Header to send with the logs, as 'Name: value' (repeatable)")
+	_ = logDrainsAddCmd.MarkFlagRequired("type")
+	_ = logDrainsAddCmd.MarkFlagRequired("url")
+
+	logDrainsCmd.AddCommand(logDrainsAddCmd)
+	logDrainsCmd.AddCommand(logDrainsListCmd)
+	logDrainsCmd.AddCommand(logDrainsRemoveCmd)
+
+	rootCmd.AddCommand(logDrainsCmd)
+}
+
+func runLogDrainsAdd(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	header, err := api.ParseHeaders(logDrainHeaders)
+	if err != nil {
+		ui.Error("Invalid --header: %v", err)
+		return fmt.Errorf("invalid flags")
+	}
+	req := api.AddLogDrainRequest{Type: logDrainType, URL: logDrainURL, Header: header}
+	if err := req.Validate(); err != nil {
+		ui.Error("%v", err)
+		return fmt.Errorf("invalid flags")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.AddLogDrain(slug, req)
+	if err != nil {
+		err = client.WithSuggestions(slug, err)
+		ui.Error("Failed to add log drain: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.LogDrain)
+	}
+
+	ui.Success("Log drain added: %s (agent: %s)", resp.LogDrain.ID, slug)
+	fmt.Printf("  Type:   %s\n", resp.LogDrain.Type)
+	fmt.Printf("  URL:    %s\n", resp.LogDrain.URL)
+
+	return nil
+}
+
+func runLogDrainsList(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.ListLogDrains(slug)
+	if err != nil {
+		err = client.WithSuggestions(slug, err)
+		ui.Error("Failed to list log drains: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.LogDrains)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(resp.LogDrains))
+		for _, d := range resp.LogDrains {
+			rows = append(rows, []string{d.ID, d.Type, d.URL, strings.Join(d.HeaderNames, ","), d.CreatedAt})
+		}
+		return ui.Table([]string{"id", "type", "url", "headers", "created"}, rows)
+	}
+
+	if len(resp.LogDrains) == 0 {
+		ui.Info("No log drains for agent '%s'", slug)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTYPE\tURL\tHEADERS\tCREATED")
+	for _, d := range resp.LogDrains {
+		headers := strings.Join(d.HeaderNames, ", ")
+		if headers == "" {
+			headers = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.ID, d.Type, d.URL, headers, dateOnly(d.CreatedAt))
+	}
+	_ = w.Flush()
+
+	return nil
+}
+
+func runLogDrainsRemove(cmd *cobra.Command, args []string) error {
+	slug, id := args[0], args[1]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	if _, err := client.RemoveLogDrain(slug, id); err != nil {
+		ui.Error("Failed to remove log drain: %v", err)
+		return err
+	}
+
+	ui.Success("Log drain removed: %s (agent: %s)", id, slug)
+
+	return nil
+}
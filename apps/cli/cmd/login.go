@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/pkg/browser"
@@ -9,16 +12,36 @@ import (
 
 	"github.com/neult/oken/apps/cli/internal/api"
 	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/oidc"
 	"github.com/neult/oken/apps/cli/internal/ui"
 )
 
+var loginSSO string
+
+// ssoTimeout bounds how long login --sso waits for the browser to return
+const ssoTimeout = 5 * time.Minute
+
 var loginCmd = &cobra.Command{
-	Use:   "login",
-	Short: "Authenticate with platform",
-	RunE:  runLogin,
+	Use:         "login",
+	Short:       "Authenticate with platform",
+	Annotations: textOutput,
+	Long: `Authenticate with the platform and save the token.
+
+By default, oken shows a code to approve in the browser. With --sso, it signs
+you in with your organization's identity provider instead, through a browser
+redirect to a temporary server on 127.0.0.1.
+
+Examples:
+  oken login
+  oken login --sso acme
+  oken login --endpoint https://api.example.com`,
+	Args: cobra.NoArgs,
+	RunE: runLogin,
 }
 
 func init() {
+	loginCmd.Flags().StringVar(&loginSSO, "sso", "", "Sign in with the single sign-on provider of an organization")
+
 	rootCmd.AddCommand(loginCmd)
 }
 
@@ -29,35 +52,66 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := api.NewClient(cfg.Endpoint, "")
+	if loginSSO != "" {
+		err = ssoLogin(cfg, loginSSO, os.Stdout)
+	} else {
+		err = deviceLogin(cfg, os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if cfg.User != nil && cfg.User.Email != "" {
+		ui.Success("Logged in as %s", ui.Bold(cfg.User.Email))
+	} else {
+		ui.Success("Logged in successfully")
+	}
+
+	configPath, _ := config.Path()
+	ui.Info("Token for %s saved to %s", cfg.Endpoint, configPath)
+
+	return nil
+}
+
+// deviceLogin signs in with the device flow, writing the login URL and code to
+// out, and saves the new token for cfg.Endpoint
+func deviceLogin(cfg *config.Config, out io.Writer) error {
+	client := newLoginClient(cfg)
 
 	// Start device auth
-	ui.Info("Starting authentication...")
+	ui.Progress("start", -1, "Starting authentication...")
 	authResp, err := client.StartDeviceAuth()
 	if err != nil {
 		ui.Error("Failed to start authentication: %v", err)
 		return err
 	}
 
-	// Try to open browser
-	fmt.Println()
-	browserOpened := false
-	if err := browser.OpenURL(authResp.LoginURL); err == nil {
-		browserOpened = true
-		ui.Success("Opened browser at %s", ui.Cyan(authResp.LoginURL))
+	// Wrappers get the URL and code in one event and decide how to show them
+	if ui.IsProgressJSON() {
+		_ = browser.OpenURL(authResp.LoginURL)
+		ui.Progress("approve", -1, "Open %s and enter code %s", authResp.LoginURL, authResp.UserCode)
 	} else {
-		ui.Warning("Could not open browser automatically")
-		fmt.Printf("  Open this URL in your browser:\n  %s\n", ui.Cyan(authResp.LoginURL))
-	}
+		// Try to open browser
+		_, _ = fmt.Fprintln(out)
+		browserOpened := false
+		if err := browser.OpenURL(authResp.LoginURL); err == nil {
+			browserOpened = true
+			ui.Success("Opened browser at %s", ui.Cyan(authResp.LoginURL))
+		} else {
+			ui.Warning("Could not open browser automatically")
+			_, _ = fmt.Fprintf(out, "  Open this URL in your browser:\n  %s\n", ui.Cyan(authResp.LoginURL))
+		}
 
-	fmt.Println()
-	fmt.Printf("  Your code: %s\n", ui.Bold(authResp.UserCode))
-	fmt.Println()
+		_, _ = fmt.Fprintln(out)
+		_, _ = fmt.Fprintf(out, "  Your code: %s\n", ui.Bold(authResp.UserCode))
+		_, _ = fmt.Fprintln(out)
 
-	if browserOpened {
-		ui.Info("Waiting for approval...")
-	} else {
-		ui.Info("Enter the code above, then waiting for approval...")
+		if browserOpened {
+			ui.Info("Waiting for approval...")
+		} else {
+			ui.Info("Enter the code above, then waiting for approval...")
+		}
 	}
 
 	// Poll for approval
@@ -73,28 +127,82 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Save config
-	cfg.Token = pollResp.Token
+	var email string
 	if pollResp.User != nil {
-		cfg.User = &config.User{
-			Email: pollResp.User.Email,
+		email = pollResp.User.Email
+	}
+	return saveLogin(cfg, pollResp.Token, pollResp.TokenExpiresAt, email)
+}
+
+// ssoLogin signs in with an organization's OpenID Connect provider and
+// exchanges the identity token for a platform token
+func ssoLogin(cfg *config.Config, org string, out io.Writer) error {
+	client := newLoginClient(cfg)
+
+	ui.Progress("start", -1, "Starting single sign-on for %s...", org)
+	sso, err := client.GetSSOConfig(org)
+	if err != nil {
+		if api.IsNotFound(err) {
+			ui.Error("Organization '%s' has no single sign-on configured", org)
+		} else {
+			ui.Error("Failed to start single sign-on: %v", err)
 		}
+		return err
 	}
 
-	if err := config.Save(cfg); err != nil {
-		ui.Error("Failed to save config: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), ssoTimeout)
+	defer cancel()
+
+	result, err := oidc.Login(ctx, oidc.Config{Issuer: sso.Issuer, ClientID: sso.ClientID, Scopes: sso.Scopes}, func(authURL string) error {
+		if ui.IsProgressJSON() {
+			_ = browser.OpenURL(authURL)
+			ui.Progress("approve", -1, "Open %s to sign in", authURL)
+			return nil
+		}
+
+		_, _ = fmt.Fprintln(out)
+		if err := browser.OpenURL(authURL); err == nil {
+			ui.Success("Opened browser to sign in with %s", sso.Issuer)
+		} else {
+			ui.Warning("Could not open browser automatically")
+			_, _ = fmt.Fprintf(out, "  Open this URL in your browser:\n  %s\n", ui.Cyan(authURL))
+		}
+		_, _ = fmt.Fprintln(out)
+		ui.Info("Waiting for sign-in...")
+		return nil
+	})
+	if err != nil {
+		ui.Error("Single sign-on failed: %v", err)
 		return err
 	}
 
-	fmt.Println()
-	if cfg.User != nil && cfg.User.Email != "" {
-		ui.Success("Logged in as %s", ui.Bold(cfg.User.Email))
-	} else {
-		ui.Success("Logged in successfully")
+	resp, err := client.ExchangeSSOToken(api.SSOExchangeRequest{Org: org, IDToken: result.IDToken, Nonce: result.Nonce})
+	if err != nil {
+		ui.Error("Failed to exchange identity token: %v", err)
+		return err
 	}
 
-	configPath, _ := config.Path()
-	ui.Info("Token saved to %s", configPath)
+	var email string
+	if resp.User != nil {
+		email = resp.User.Email
+	}
+	return saveLogin(cfg, resp.Token, resp.TokenExpiresAt, email)
+}
+
+// saveLogin stores a new token for cfg.Endpoint
+func saveLogin(cfg *config.Config, token, expiresAt, email string) error {
+	cfg.Token = token
+	cfg.TokenExpiresAt = expiresAt
+	if email != "" {
+		cfg.User = &config.User{
+			Email: email,
+		}
+	}
+
+	if err := config.Save(cfg); err != nil {
+		ui.Error("Failed to save config: %v", err)
+		return err
+	}
 
 	return nil
 }
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"context"
 	"fmt"
-	"net/http"
 	"os"
 	"os/signal"
 	"strings"
@@ -18,21 +17,33 @@ import (
 )
 
 var (
-	logsFollow bool
-	logsTail   int
+	logsFollow  bool
+	logsTail    int
+	logsRequest string
 )
 
 var logsCmd = &cobra.Command{
-	Use:   "logs <agent>",
-	Short: "View agent logs",
-	Long:  "View logs from a running agent. Use -f to stream logs in real-time.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runLogs,
+	Use:         "logs <agent>",
+	Short:       "View agent logs",
+	Annotations: textOutput,
+	Long: `View logs from a running agent. Use -f to stream logs in real-time.
+
+--request shows only the lines logged while handling one invocation. 'oken
+invoke' prints the request ID of each invocation, also when it fails.
+
+Examples:
+  oken logs my-agent
+  oken logs my-agent -f --tail 20
+  oken logs my-agent@staging
+  oken logs my-agent --request req_8f2c1a`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogs,
 }
 
 func init() {
 	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Stream logs in real-time")
 	logsCmd.Flags().IntVarP(&logsTail, "tail", "n", 100, "Number of lines to show (max 10000)")
+	logsCmd.Flags().StringVar(&logsRequest, "request", "", "Show only the logs of this request ID")
 	rootCmd.AddCommand(logsCmd)
 }
 
@@ -50,22 +61,27 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
-	client := api.NewClient(cfg.Endpoint, cfg.Token)
+	client := newClient(cfg)
 
 	if logsFollow {
-		return streamLogs(client, cfg, slug)
+		return streamLogs(client, slug)
 	}
 
 	return fetchLogs(client, slug)
 }
 
 func fetchLogs(client *api.Client, slug string) error {
-	resp, err := client.GetAgentLogs(slug, logsTail)
+	resp, err := client.GetAgentLogs(slug, logsTail, logsRequest)
 	if err != nil {
+		err = client.WithSuggestions(slug, err)
 		ui.Error("Failed to fetch logs: %v", err)
 		return err
 	}
 
+	if resp.Logs == "" && logsRequest != "" {
+		ui.Info("No logs for request %s", logsRequest)
+		return nil
+	}
 	if resp.Logs == "" {
 		ui.Info("No logs available")
 		return nil
@@ -75,22 +91,7 @@ func fetchLogs(client *api.Client, slug string) error {
 	return nil
 }
 
-func streamLogs(client *api.Client, cfg *config.Config, slug string) error {
-	url, err := client.GetAgentLogsStreamURL(slug, logsTail)
-	if err != nil {
-		ui.Error("Invalid agent slug: %v", err)
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		ui.Error("Failed to create request: %v", err)
-		return err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+cfg.Token)
-	req.Header.Set("Accept", "text/event-stream")
-
+func streamLogs(client *api.Client, slug string) error {
 	// Create context that cancels on interrupt
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -103,26 +104,28 @@ func streamLogs(client *api.Client, cfg *config.Config, slug string) error {
 		cancel()
 	}()
 
-	req = req.WithContext(ctx)
+	if ui.IsProgressJSON() {
+		ui.Progress("connect", -1, "Connecting to log stream for %s...", slug)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	stream, err := client.StreamAgentLogs(ctx, slug, logsTail, logsRequest)
 	if err != nil {
 		if ctx.Err() != nil {
 			// User cancelled
 			fmt.Println()
 			return nil
 		}
-		ui.Error("Failed to connect: %v", err)
+		err = client.WithSuggestions(slug, err)
+		ui.Error("Failed to stream logs: %v", err)
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
+	defer func() { _ = stream.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		ui.Error("Failed to stream logs: %s", resp.Status)
-		return fmt.Errorf("stream failed: %s", resp.Status)
+	if ui.IsProgressJSON() {
+		ui.Progress("stream", -1, "Streaming logs for %s", slug)
 	}
 
-	scanner := bufio.NewScanner(resp.Body)
+	scanner := bufio.NewScanner(stream)
 	for scanner.Scan() {
 		line := scanner.Text()
 		// SSE format: "data: <content>"
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var metricsWindow string
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics <slug>",
+	Short: "Show agent metrics",
+	Long: `Show invocation count, error rate, latency and resource usage for an agent.
+
+Examples:
+  oken metrics my-agent
+  oken metrics my-agent --window 24h
+  oken metrics my-agent --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMetrics,
+}
+
+func init() {
+	metricsCmd.Flags().StringVarP(&metricsWindow, "window", "w", "1h", "Time window (e.g. 15m, 1h, 7d)")
+	rootCmd.AddCommand(metricsCmd)
+}
+
+func runMetrics(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	metrics, err := client.GetAgentMetrics(slug, metricsWindow)
+	if err != nil {
+		ui.Error("Failed to get metrics: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(metrics)
+	}
+
+	fmt.Printf("Agent:        %s\n", slug)
+	fmt.Printf("Window:       %s\n", metricsWindow)
+	fmt.Printf("Invocations:  %d\n", metrics.Invocations)
+	fmt.Printf("Errors:       %d (%.1f%%)\n", metrics.Errors, metrics.ErrorRate*100)
+	fmt.Printf("Latency p50:  %.0fms\n", metrics.LatencyP50Ms)
+	fmt.Printf("Latency p95:  %.0fms\n", metrics.LatencyP95Ms)
+	fmt.Printf("CPU:          %.1f%%\n", metrics.CPUPercent)
+	fmt.Printf("Memory:       %s\n", ui.Bytes(metrics.MemoryBytes))
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var openPrint bool
+
+var openCmd = &cobra.Command{
+	Use:         "open [slug]",
+	Short:       "Open the web dashboard",
+	Annotations: textOutput,
+	Long: `Open the web dashboard for an agent, or the dashboard home with no arguments.
+
+Examples:
+  oken open
+  oken open my-agent
+  oken open my-agent --print`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runOpen,
+}
+
+func init() {
+	openCmd.Flags().BoolVarP(&openPrint, "print", "p", false, "Print the URL instead of opening it")
+	rootCmd.AddCommand(openCmd)
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	var slug string
+	if len(args) == 1 {
+		slug = args[0]
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	client := api.NewClient(cfg.Endpoint, "")
+
+	url, err := client.DashboardURL(slug)
+	if err != nil {
+		ui.Error("Invalid agent slug: %v", err)
+		return err
+	}
+
+	if openPrint {
+		fmt.Println(url)
+		return nil
+	}
+
+	if err := browser.OpenURL(url); err != nil {
+		ui.Warning("Could not open browser automatically")
+		fmt.Printf("  Open this URL in your browser:\n  %s\n", ui.Cyan(url))
+		return nil
+	}
+
+	ui.Success("Opened browser at %s", ui.Cyan(url))
+
+	return nil
+}
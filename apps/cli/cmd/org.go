@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var orgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Manage organization context",
+	Long: `Manage which organization commands act on.
+
+When an organization is selected, list, deploy, secrets and other commands
+are scoped to it instead of your personal account.`,
+}
+
+var orgListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your organizations",
+	Args:  cobra.NoArgs,
+	RunE:  runOrgList,
+}
+
+var orgSwitchCmd = &cobra.Command{
+	Use:   "switch <name>",
+	Short: "Switch to an organization",
+	Long: `Switch to an organization by slug or name.
+
+Examples:
+  oken org switch acme`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOrgSwitch,
+}
+
+var orgClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Switch back to your personal account",
+	Args:  cobra.NoArgs,
+	RunE:  runOrgClear,
+}
+
+func init() {
+	orgCmd.AddCommand(orgListCmd)
+	orgCmd.AddCommand(orgSwitchCmd)
+	orgCmd.AddCommand(orgClearCmd)
+	rootCmd.AddCommand(orgCmd)
+}
+
+func runOrgList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+	// Organizations are listed for the user, not within the selected one
+	client.Org = ""
+
+	resp, err := client.ListOrgs()
+	if err != nil {
+		ui.Error("Failed to list organizations: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Orgs)
+	}
+
+	if len(resp.Orgs) == 0 {
+		ui.Info("You are not a member of any organization")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "\tSLUG\tNAME\tROLE")
+	for _, org := range resp.Orgs {
+		current := ""
+		if org.Slug == cfg.Org {
+			current = "*"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", current, org.Slug, org.Name, org.Role)
+	}
+	_ = w.Flush()
+
+	if cfg.Org == "" {
+		fmt.Println()
+		ui.Info("Using your personal account. Run 'oken org switch <name>' to change.")
+	}
+
+	return nil
+}
+
+func runOrgSwitch(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+	// Organizations are listed for the user, not within the selected one
+	client.Org = ""
+
+	resp, err := client.ListOrgs()
+	if err != nil {
+		ui.Error("Failed to list organizations: %v", err)
+		return err
+	}
+
+	var org *api.Org
+	for i := range resp.Orgs {
+		if resp.Orgs[i].Slug == name || resp.Orgs[i].Name == name {
+			org = &resp.Orgs[i]
+			break
+		}
+	}
+	if org == nil {
+		ui.Error("Organization '%s' not found. Run 'oken org list' to see your organizations.", name)
+		return fmt.Errorf("organization not found")
+	}
+
+	cfg.Org = org.Slug
+	if err := config.Save(cfg); err != nil {
+		ui.Error("Failed to save config: %v", err)
+		return err
+	}
+
+	ui.Success("Switched to organization %s", ui.Bold(org.Name))
+	warnProjectOrg(cfg)
+
+	return nil
+}
+
+func runOrgClear(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	cfg.Org = ""
+	if err := config.Save(cfg); err != nil {
+		ui.Error("Failed to save config: %v", err)
+		return err
+	}
+
+	ui.Success("Switched to your personal account")
+	warnProjectOrg(cfg)
+
+	return nil
+}
+
+// warnProjectOrg points out that the project configuration still overrides
+// the org that was just saved
+func warnProjectOrg(cfg *config.Config) {
+	if cfg.Project != nil && cfg.Project.Org != "" && cfg.Project.Org != cfg.Org {
+		ui.Warning("Commands in this project still use organization %s, set in %s", cfg.Project.Org, cfg.Project.Path)
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/pack"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	packFile        string
+	packAnalyze     bool
+	packBuild       string
+	packNoGitignore bool
+)
+
+var packCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Package agent without deploying",
+	Long: `Package the current directory into a tarball, exactly as 'oken deploy'
+would, and write it to disk for inspection or upload by other tooling. The
+tarball is gzipped, or compressed with zstd as agent.tar.zst when oken.toml
+sets compression = "zstd".
+
+Examples:
+  oken pack
+  oken pack --file build/agent.tar.gz
+  oken pack --analyze`,
+	Args: cobra.NoArgs,
+	RunE: runPack,
+}
+
+func init() {
+	packCmd.Flags().StringVarP(&packFile, "file", "f", "agent.tar.gz", "Path to write the package to")
+	packCmd.Flags().BoolVar(&packAnalyze, "analyze", false, "Print a size breakdown of the package")
+	packCmd.Flags().StringVar(&packBuild, "build", "", "Build mode: docker packages a Docker build context")
+	packCmd.Flags().BoolVar(&packNoGitignore, "no-gitignore", false, "Package files excluded by .gitignore")
+	rootCmd.AddCommand(packCmd)
+}
+
+func runPack(cmd *cobra.Command, args []string) error {
+	if packBuild != "" && packBuild != api.BuildDocker {
+		ui.Error("Invalid build mode '%s'. Supported: %s", packBuild, api.BuildDocker)
+		return fmt.Errorf("invalid build mode")
+	}
+
+	okenCfg, err := loadOkenConfig(".")
+	if err != nil {
+		ui.Error("Failed to parse oken.toml: %v", err)
+		return err
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		ui.Error("Failed to get current directory: %v", err)
+		return err
+	}
+
+	if compression := okenCfg.Package.Compression; compression != "" {
+		if _, err := pack.ParseCompression(compression); err != nil {
+			ui.Error("%v in oken.toml", err)
+			return err
+		}
+	}
+
+	packOpts := okenCfg.packOptions()
+	packOpts.Docker = packBuild == api.BuildDocker
+	if packNoGitignore {
+		packOpts.NoGitignore = true
+	}
+
+	if !cmd.Flags().Changed("file") && packOpts.Compression == pack.Zstd {
+		packFile = "agent" + pack.Zstd.Extension()
+	}
+
+	// Never package a previous output of this command
+	if out, err := filepath.Abs(packFile); err == nil {
+		if rel, err := filepath.Rel(dir, out); err == nil && filepath.IsLocal(rel) {
+			packOpts.Exclude = append(packOpts.Exclude, filepath.ToSlash(rel))
+		}
+	}
+
+	tarball, err := pack.CreateTarball(dir, packOpts)
+	if err != nil {
+		ui.Error("Failed to create package: %v", err)
+		return err
+	}
+
+	file, err := os.Create(packFile)
+	if err != nil {
+		ui.Error("Failed to create %s: %v", packFile, err)
+		return err
+	}
+	if _, err := io.Copy(file, tarball); err != nil {
+		_ = file.Close()
+		ui.Error("Failed to write %s: %v", packFile, err)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		ui.Error("Failed to write %s: %v", packFile, err)
+		return err
+	}
+
+	var analysis *pack.Analysis
+	if packAnalyze {
+		if analysis, err = pack.Analyze(dir, packOpts); err != nil {
+			ui.Error("Failed to analyze package: %v", err)
+			return err
+		}
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(struct {
+			File     string         `json:"file"`
+			Size     int64          `json:"size"`
+			SHA256   string         `json:"sha256"`
+			Analysis *pack.Analysis `json:"analysis,omitempty"`
+		}{packFile, tarball.Size, tarball.SHA256, analysis})
+	}
+
+	ui.Success("Package written to %s (%s)", packFile, ui.Bytes(tarball.Size))
+	fmt.Printf("  Digest: sha256:%s\n", tarball.SHA256)
+
+	if analysis != nil {
+		fmt.Println()
+		printAnalysis(analysis)
+	}
+
+	return nil
+}
+
+// printAnalysis prints the package size breakdown, largest entries first,
+// followed by the largest files and suggested exclusions
+func printAnalysis(analysis *pack.Analysis) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PATH\tFILES\tSIZE\tCOMPRESSED")
+	for _, e := range analysis.Entries {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", e.Path, e.Files, ui.Bytes(e.Size), ui.Bytes(e.Compressed))
+	}
+	_, _ = fmt.Fprintf(w, "Total\t%d\t%s\t%s\n", analysis.Files, ui.Bytes(analysis.Size), ui.Bytes(analysis.Compressed))
+	_ = w.Flush()
+
+	if len(analysis.Largest) > 0 {
+		fmt.Println()
+		fmt.Println(ui.Bold("Largest files"))
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, f := range analysis.Largest {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", f.Path, ui.Bytes(f.Size), ui.Bytes(f.Compressed))
+		}
+		_ = w.Flush()
+	}
+
+	if len(analysis.Suggestions) > 0 {
+		fmt.Println()
+		fmt.Println(ui.Bold("Consider excluding"))
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		var paths []string
+		for _, s := range analysis.Suggestions {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", s.Path, ui.Bytes(s.Size), s.Reason)
+			paths = append(paths, strconv.Quote(s.Path))
+		}
+		_ = w.Flush()
+		fmt.Println()
+		ui.Info("Add to oken.toml if they aren't needed at runtime:")
+		fmt.Printf("  [package]\n  exclude = [%s]\n", strings.Join(paths, ", "))
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/manifest"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	planFile         string
+	planPrune        bool
+	planDetailedExit bool
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show how the platform differs from a manifest",
+	Long: `Compare a manifest with the platform and list the changes 'oken apply'
+would make, without making them: agents to create, update or delete,
+secrets that are missing and scale settings that differ.
+
+With --detailed-exitcode, the exit status is 0 when nothing differs, 2 when
+there are changes and 1 on errors, for CI drift checks.
+
+Examples:
+  oken plan
+  oken plan -f agents.yaml --detailed-exitcode`,
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runPlan,
+}
+
+func init() {
+	planCmd.Flags().StringVarP(&planFile, "file", "f", manifest.DefaultFile, "Manifest to compare")
+	planCmd.Flags().BoolVar(&planPrune, "prune", false, "Include agents, secrets and schedules that 'oken apply --prune' would delete")
+	planCmd.Flags().BoolVar(&planDetailedExit, "detailed-exitcode", false, "Exit with status 2 when there are changes")
+	rootCmd.AddCommand(planCmd)
+}
+
+// plannedChange is a change as printed by 'oken plan -o json' and
+// 'oken apply -o json'
+type plannedChange struct {
+	Action      manifest.Action `json:"action"`
+	Kind        manifest.Kind   `json:"kind"`
+	Agent       string          `json:"agent"`
+	Description string          `json:"description"`
+}
+
+// plannedChanges converts changes for JSON and YAML output
+func plannedChanges(changes []manifest.Change) []plannedChange {
+	planned := make([]plannedChange, 0, len(changes))
+	for _, c := range changes {
+		planned = append(planned, plannedChange{Action: c.Action, Kind: c.Kind, Agent: c.Agent, Description: c.String()})
+	}
+	return planned
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	_, changes, err := planManifest(planFile, planPrune)
+	if err != nil {
+		return err
+	}
+
+	if ui.IsStructured() {
+		if err := ui.Result(plannedChanges(changes)); err != nil {
+			return err
+		}
+	} else if len(changes) == 0 {
+		ui.Success("No changes. Agents match %s.", planFile)
+	} else {
+		ui.Info("Changes to converge %s:", planFile)
+		printPlan(os.Stdout, changes)
+	}
+
+	if planDetailedExit && len(changes) > 0 {
+		return &ExitError{Code: 2}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/plugin"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage CLI plugins",
+	Long: `Extend oken with plugins: any executable on your PATH named oken-<name>
+runs as 'oken <name>'. Built-in commands always take precedence.
+
+Arguments after the plugin name are passed to it unchanged. The plugin
+receives the CLI's settings in its environment:
+
+  OKEN_CONFIG       Path of the config file
+  OKEN_ENDPOINT     Platform URL
+  OKEN_TOKEN        Token for the endpoint, if logged in
+  OKEN_ORG          Selected organization, if any
+  OKEN_CLI_VERSION  Version of oken
+  OKEN_EXECUTABLE   Path of the oken executable`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List plugins found on PATH",
+	Args:  cobra.NoArgs,
+	RunE:  runPluginList,
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
+
+// pluginRow is a plugin as shown by 'oken plugin list'
+type pluginRow struct {
+	plugin.Plugin
+	// Builtin is set when a built-in command has the same name, so the
+	// plugin never runs
+	Builtin bool `json:"builtin,omitempty"`
+}
+
+func runPluginList(cmd *cobra.Command, args []string) error {
+	plugins := plugin.List()
+	rows := make([]pluginRow, 0, len(plugins))
+	for _, p := range plugins {
+		_, _, ok := findPlugin([]string{p.Name})
+		rows = append(rows, pluginRow{Plugin: p, Builtin: !ok})
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(rows)
+	}
+	if ui.IsTabular() {
+		table := make([][]string, 0, len(rows))
+		for _, r := range rows {
+			table = append(table, []string{r.Name, r.Path, fmt.Sprint(r.Builtin)})
+		}
+		return ui.Table([]string{"name", "path", "builtin"}, table)
+	}
+
+	if len(rows) == 0 {
+		ui.Info("No plugins found. Add an executable named %s<name> to your PATH.", plugin.Prefix)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tPATH")
+	for _, r := range rows {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", r.Name, r.Path)
+	}
+	_ = w.Flush()
+
+	for _, r := range rows {
+		if r.Builtin {
+			ui.Warning("%s is never run: 'oken %s' is a built-in command", r.Path, r.Name)
+		}
+		for _, path := range r.Shadowed {
+			ui.Warning("%s is never run: %s comes first on PATH", path, r.Path)
+		}
+	}
+
+	return nil
+}
+
+// findPlugin returns the plugin to run for args, which are the command line
+// without the program name, and the index of its name in args. Only the first
+// argument after any global flags can name a plugin, and built-in commands win.
+func findPlugin(args []string) (plugin.Plugin, int, bool) {
+	i := commandIndex(args)
+	if i < 0 || !plugin.ValidName(args[i]) || isBuiltin(args[i]) {
+		return plugin.Plugin{}, -1, false
+	}
+	p, ok := plugin.Find(args[i])
+	return p, i, ok
+}
+
+// isBuiltin reports whether name is a command of oken itself, including
+// cobra's help and completion commands
+func isBuiltin(name string) bool {
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd()
+	_, _, err := rootCmd.Find([]string{name})
+	return err == nil
+}
+
+// runPlugin runs a plugin with the rest of the command line, passing on the
+// endpoint, token and organization oken would use, and exits with its status
+func runPlugin(p plugin.Plugin, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+	configPath, err := config.Path()
+	if err != nil {
+		return err
+	}
+	executable, _ := os.Executable()
+
+	c := exec.Command(p.Path, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = plugin.Context{
+		ConfigPath: configPath,
+		Endpoint:   cfg.Endpoint,
+		Token:      cfg.Token,
+		Org:        cfg.Org,
+		Version:    Version,
+		Executable: executable,
+	}.Environ(os.Environ())
+
+	// Ctrl-C reaches the plugin too; let it decide when to stop
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	err = c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code < 0 {
+			// Killed by a signal
+			code = 1
+		}
+		return &ExitError{Code: code}
+	}
+	if err != nil {
+		ui.Error("Failed to run plugin %s: %v", p.Name, err)
+		return err
+	}
+	return nil
+}
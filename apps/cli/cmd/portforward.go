@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var portForwardAddress string
+
+var portForwardCmd = &cobra.Command{
+	Use:         "port-forward <slug> [local:]remote...",
+	Short:       "Forward local ports to a running agent",
+	Annotations: textOutput,
+	Long: `Forward local ports to ports of a running agent, so local tools such as
+browsers or Postman can reach an agent that isn't publicly exposed.
+
+Traffic is tunneled through the platform over a websocket, one per
+connection. Each mapping is local:remote, a single port to use the same
+number on both ends, or :remote to pick a free local port. Press Ctrl+C to
+stop forwarding.
+
+Examples:
+  oken port-forward my-agent 8080:8000
+  oken port-forward my-agent 8000 9090:9000
+  oken port-forward my-agent :8000 --address 0.0.0.0`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runPortForward,
+}
+
+func init() {
+	portForwardCmd.Flags().StringVar(&portForwardAddress, "address", "127.0.0.1", "Local address to listen on")
+	rootCmd.AddCommand(portForwardCmd)
+}
+
+func runPortForward(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	mappings := make([]api.PortMapping, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		mapping, err := api.ParsePortMapping(arg)
+		if err != nil {
+			ui.Error("%v", err)
+			return fmt.Errorf("invalid arguments")
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	agent, err := client.GetAgent(slug)
+	if err != nil {
+		err = client.WithSuggestions(slug, err)
+		ui.Error("Failed to get agent: %v", err)
+		return err
+	}
+	if !agent.Status.IsHealthy() {
+		ui.Error("Agent %s is %s. Port forwarding needs a running agent.", slug, ui.AgentStatus(agent.Status))
+		return fmt.Errorf("agent not running")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	listeners := make([]net.Listener, 0, len(mappings))
+	defer func() {
+		for _, l := range listeners {
+			_ = l.Close()
+		}
+	}()
+	for _, m := range mappings {
+		address := net.JoinHostPort(portForwardAddress, strconv.Itoa(m.Local))
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			ui.Error("Failed to listen on %s: %v", address, err)
+			return err
+		}
+		listeners = append(listeners, listener)
+		ui.Success("Forwarding %s -> %s:%d", listener.Addr(), slug, m.Remote)
+	}
+	ui.Info("Press Ctrl+C to stop")
+
+	var wg sync.WaitGroup
+	for i, listener := range listeners {
+		wg.Go(func() { acceptForwarded(ctx, client, slug, listener, mappings[i].Remote) })
+	}
+
+	<-ctx.Done()
+	for _, l := range listeners {
+		_ = l.Close()
+	}
+	wg.Wait()
+	fmt.Println()
+	return nil
+}
+
+// acceptForwarded tunnels each connection accepted by listener to the remote
+// port until the listener is closed
+func acceptForwarded(ctx context.Context, client *api.Client, slug string, listener net.Listener, remote int) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				ui.Error("Failed to accept connection: %v", err)
+			}
+			return
+		}
+		ui.Info("Handling connection for %s", listener.Addr())
+		wg.Go(func() {
+			if err := forwardConn(ctx, client, slug, conn, remote); err != nil {
+				ui.Error("Connection for %s: %v", listener.Addr(), err)
+			}
+		})
+	}
+}
+
+// forwardConn copies bytes between a local connection and a tunnel to the
+// remote port until either side closes
+func forwardConn(ctx context.Context, client *api.Client, slug string, conn net.Conn, remote int) error {
+	defer func() { _ = conn.Close() }()
+
+	tunnel, err := client.PortForward(ctx, slug, remote)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tunnel.Close() }()
+
+	// Closing both ends when ctx is done unblocks the copies
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+		_ = tunnel.Close()
+	})
+	defer stop()
+
+	done := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(tunnel, conn)
+		done <- err
+	}()
+	go func() {
+		_, err := io.Copy(conn, tunnel)
+		done <- err
+	}()
+	// Either side finishing ends the connection; the deferred closes stop
+	// the other copy
+	err = <-done
+	if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
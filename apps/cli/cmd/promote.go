@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	promoteFrom string
+	promoteTo   string
+)
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote <slug>",
+	Short: "Promote a deployment between environments",
+	Long: `Deploy the source currently running in one environment to another,
+without re-uploading it.
+
+Examples:
+  oken promote my-agent --from staging --to prod`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPromote,
+}
+
+func init() {
+	promoteCmd.Flags().StringVar(&promoteFrom, "from", "", "Source environment (required)")
+	promoteCmd.Flags().StringVar(&promoteTo, "to", "", "Target environment (required)")
+	_ = promoteCmd.MarkFlagRequired("from")
+	_ = promoteCmd.MarkFlagRequired("to")
+	rootCmd.AddCommand(promoteCmd)
+}
+
+func runPromote(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	ui.Info("Promoting %s from %s to %s...", slug, promoteFrom, promoteTo)
+
+	resp, err := client.PromoteAgent(slug, promoteFrom, promoteTo)
+	if err != nil {
+		ui.Error("Failed to promote agent: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Agent)
+	}
+
+	ui.Success("Agent promoted: %s (%s → %s)", resp.Agent.Slug, promoteFrom, promoteTo)
+	fmt.Printf("  Status:   %s\n", ui.AgentStatus(resp.Agent.Status))
+	if resp.Agent.Endpoint != nil && *resp.Agent.Endpoint != "" {
+		fmt.Printf("  Endpoint: %s\n", *resp.Agent.Endpoint)
+	}
+
+	return nil
+}
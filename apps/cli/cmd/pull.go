@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/pack"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	pullDeployment string
+	pullDir        string
+	pullForce      bool
+)
+
+var pullCmd = &cobra.Command{
+	Use:         "pull <slug>",
+	Short:       "Download deployed source",
+	Annotations: textOutput,
+	Long: `Download the source of a deployed agent and extract it into a directory.
+
+By default the currently running deployment is downloaded into ./<slug>.
+
+Examples:
+  oken pull my-agent
+  oken pull my-agent --dir ./recovered
+  oken pull my-agent --deployment dep_abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPull,
+}
+
+func init() {
+	pullCmd.Flags().StringVar(&pullDeployment, "deployment", "", "Deployment ID (default: current deployment)")
+	pullCmd.Flags().StringVarP(&pullDir, "dir", "d", "", "Directory to extract into (default: ./<slug>)")
+	pullCmd.Flags().BoolVarP(&pullForce, "force", "f", false, "Extract into a non-empty directory, overwriting files")
+	rootCmd.AddCommand(pullCmd)
+}
+
+func runPull(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	dir := pullDir
+	if dir == "" {
+		dir = slug
+	}
+
+	if !pullForce {
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+			ui.Error("Directory %s is not empty. Use --force to overwrite or --dir to pick another.", dir)
+			return fmt.Errorf("directory not empty")
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	ui.Info("Downloading %s...", slug)
+
+	body, err := client.DownloadSource(slug, pullDeployment)
+	if err != nil {
+		ui.Error("Failed to download source: %v", err)
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	if err := pack.ExtractTarball(body, dir); err != nil {
+		ui.Error("Failed to extract source: %v", err)
+		return err
+	}
+
+	ui.Success("Source extracted to %s", dir)
+
+	return nil
+}
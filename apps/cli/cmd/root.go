@@ -1,14 +1,208 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+// Version is the CLI version, set at build time with
+// -ldflags "-X github.com/neult/oken/apps/cli/cmd.Version=v1.2.3"
+var Version = "dev"
+
+var (
+	outputFormat   string
+	outputTemplate string
+	noInput        bool
+	progressMode   string
+	endpoint       string
+	autoLogin      bool
 )
 
+// textOutput marks commands, and their subcommands, that run sessions or
+// write streams and files, and so have no result to print as JSON or YAML
+var textOutput = map[string]string{"output": "text"}
+
 var rootCmd = &cobra.Command{
-	Use:   "oken",
-	Short: "Deploy agents with one command",
+	Use:               "oken",
+	Short:             "Deploy agents with one command",
+	Version:           Version,
+	PersistentPreRunE: setupOutput,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "Output format: text, json, yaml, csv, tsv, github (default: github when GITHUB_ACTIONS is set)")
+	rootCmd.PersistentFlags().StringVar(&progressMode, "progress", string(ui.ProgressText), "Progress reporting: text, or json for newline-delimited events on stderr")
+	rootCmd.PersistentFlags().BoolVar(&noInput, "no-input", false, "Never prompt; fail instead of asking for input")
+	rootCmd.PersistentFlags().StringVar(&outputTemplate, "format", "", "Render each result with a Go template, e.g. '{{.Slug}} {{.Status}}'")
+	rootCmd.PersistentFlags().BoolVar(&autoLogin, "auto-login", false, "Log in again without failing when the session has expired")
+	rootCmd.PersistentFlags().StringVar(&endpoint, "endpoint", "", "Platform URL for this command (overrides OKEN_ENDPOINT and the config file)")
+}
+
+// ExitError makes oken exit with a specific status, such as the exit status of
+// a remote command
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	args, err := expandAlias(os.Args[1:])
+	if err != nil {
+		ui.Error("%v", err)
+		return err
+	}
+	rootCmd.SetArgs(args)
+
+	if p, i, ok := findPlugin(args); ok {
+		// Global flags before the plugin name, such as --endpoint, set up the
+		// context the plugin runs with
+		if err := rootCmd.PersistentFlags().Parse(args[:i]); err != nil {
+			ui.Error("%v", err)
+			return err
+		}
+		config.SetEndpoint(endpoint)
+		return runPlugin(p, args[i+1:])
+	}
+
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	recordTelemetry(cmd, time.Since(start), err)
+	if errors.Is(err, api.ErrUnauthorized) {
+		explainUnauthorized()
+	}
+	var unreachable *api.UnreachableError
+	if errors.As(err, &unreachable) {
+		explainUnreachable(unreachable.Endpoint)
+	}
+	return err
+}
+
+// explainUnreachable tells the user what to check when no connection could
+// be made to the platform
+func explainUnreachable(endpoint string) {
+	ui.Warning("Could not connect to the Oken platform at %s.", endpoint)
+	ui.Info("Check your network connection, and any VPN or proxy settings (HTTPS_PROXY).")
+	ui.Info("If the endpoint is wrong, pass --endpoint, set %s, or run 'oken login --endpoint <url>'.", config.EndpointEnv)
+	ui.Info("Commands run in the next %s check the platform with a quick probe instead of waiting for a timeout.", api.DefaultBreakerCooldown)
+}
+
+// explainUnauthorized tells the user how to recover from a rejected token
+func explainUnauthorized() {
+	if os.Getenv(config.TokenEnv) != "" {
+		ui.Warning("The token in %s was rejected. It may have expired or been revoked; create a new one with 'oken tokens create'.", config.TokenEnv)
+		return
+	}
+
+	login := "oken login"
+	if endpoint != "" {
+		login = fmt.Sprintf("oken login --endpoint %s", endpoint)
+	}
+
+	if cfg, err := config.Load(); err == nil && cfg.TokenExpired(time.Now()) {
+		ui.Warning("Session expired at %s. Run '%s' to sign in again.", cfg.TokenExpiresAt, login)
+		return
+	}
+	if autoLogin {
+		// Logging in again was skipped or failed
+		ui.Warning("Session expired or revoked. Run '%s' in a terminal to sign in again.", login)
+		return
+	}
+	ui.Warning("Session expired or revoked. Run '%s' to sign in again, or retry with --auto-login.", login)
+}
+
+// setupOutput resolves the --output flag, auto-detecting GitHub Actions, and
+// the other global flags
+func setupOutput(cmd *cobra.Command, args []string) error {
+	if outputFormat == "" {
+		outputFormat = string(ui.FormatText)
+		if os.Getenv("GITHUB_ACTIONS") == "true" {
+			outputFormat = string(ui.FormatGitHub)
+		}
+	}
+
+	switch ui.Format(outputFormat) {
+	case ui.FormatText, ui.FormatJSON, ui.FormatYAML, ui.FormatCSV, ui.FormatTSV, ui.FormatGitHub:
+		ui.SetFormat(ui.Format(outputFormat))
+	default:
+		return fmt.Errorf("invalid output format %q (valid: text, json, yaml, csv, tsv, github)", outputFormat)
+	}
+	if ui.IsStructured() {
+		for c := cmd; c != nil; c = c.Parent() {
+			if c.Annotations["output"] == "text" {
+				return fmt.Errorf("'%s' does not support -o %s", cmd.CommandPath(), outputFormat)
+			}
+		}
+	}
+
+	switch ui.ProgressMode(progressMode) {
+	case ui.ProgressText, ui.ProgressJSON:
+		ui.SetProgress(ui.ProgressMode(progressMode))
+	default:
+		return fmt.Errorf("invalid progress mode %q (valid: text, json)", progressMode)
+	}
+
+	ui.SetNoInput(noInput)
+	config.SetEndpoint(endpoint)
+
+	if outputTemplate != "" {
+		if err := ui.SetTemplate(outputTemplate); err != nil {
+			return fmt.Errorf("invalid --format template: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// newClient creates an API client for the logged-in user and selected organization
+func newClient(cfg *config.Config) *api.Client {
+	client := api.NewClient(cfg.Endpoint, cfg.Token)
+	client.Org = cfg.Org
+	if cfg.User != nil {
+		client.Account = cfg.User.Email
+	}
+	client.UserAgent = api.UserAgent(Version)
+	client.OnDeprecation = func(msg string) {
+		ui.Warning("%s", msg)
+	}
+	if dir, err := config.CacheDir(); err == nil {
+		client.Cache = api.NewResponseCache(dir)
+		client.Breaker = api.NewBreaker(filepath.Join(dir, "unreachable.json"))
+	}
+	if autoLogin && os.Getenv(config.TokenEnv) == "" {
+		client.OnUnauthorized = func() (string, error) {
+			// The device flow needs someone to approve it in a browser
+			if !ui.CanPrompt() {
+				return "", ui.ErrNoInput
+			}
+			ui.Warning("Session expired, logging in again")
+			if err := deviceLogin(cfg, os.Stderr); err != nil {
+				return "", err
+			}
+			return cfg.Token, nil
+		}
+	}
+	return client
+}
+
+// newLoginClient creates an API client that sends no token or organization,
+// for signing in
+func newLoginClient(cfg *config.Config) *api.Client {
+	client := newClient(cfg)
+	client.Token = ""
+	client.Account = ""
+	client.Org = ""
+	client.OnUnauthorized = nil
+	return client
 }
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	sbomDeployment string
+	sbomFile       string
+)
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom <slug>",
+	Short: "Show the dependencies of a deployed agent",
+	Long: `Show the software bill of materials (SBOM) recorded when an agent was
+deployed: the Python packages read from its lockfile or requirements.txt.
+
+The SBOM is a CycloneDX 1.5 JSON document. It is summarized as a table by
+default; use -o json for the full document or --file to save it.
+
+Examples:
+  oken sbom my-agent
+  oken sbom my-agent -o json
+  oken sbom my-agent --deployment dep_abc123 --file sbom.cdx.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSBOM,
+}
+
+func init() {
+	sbomCmd.Flags().StringVar(&sbomDeployment, "deployment", "", "Deployment ID (default: current deployment)")
+	sbomCmd.Flags().StringVarP(&sbomFile, "file", "f", "", "Write the CycloneDX document to a file")
+	rootCmd.AddCommand(sbomCmd)
+}
+
+// sbomDocument is the part of a CycloneDX document shown by 'oken sbom'
+type sbomDocument struct {
+	Components []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		PURL    string `json:"purl"`
+	} `json:"components"`
+}
+
+func runSBOM(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	sbom, err := client.GetSBOM(slug, sbomDeployment)
+	if api.IsNotFound(err) {
+		ui.Error("No SBOM recorded for %s. Redeploy it with a requirements.txt or lockfile.", slug)
+		return err
+	}
+	if err != nil {
+		ui.Error("Failed to get SBOM: %v", err)
+		return err
+	}
+
+	if sbomFile != "" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, sbom, "", "  "); err != nil {
+			ui.Error("Invalid SBOM: %v", err)
+			return err
+		}
+		buf.WriteByte('\n')
+		if err := os.WriteFile(sbomFile, buf.Bytes(), 0644); err != nil {
+			ui.Error("Failed to write %s: %v", sbomFile, err)
+			return err
+		}
+		ui.Success("Saved SBOM of %s to %s", slug, sbomFile)
+		return nil
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(sbom)
+	}
+
+	var doc sbomDocument
+	if err := json.Unmarshal(sbom, &doc); err != nil {
+		ui.Error("Invalid SBOM: %v", err)
+		return err
+	}
+
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(doc.Components))
+		for _, c := range doc.Components {
+			rows = append(rows, []string{c.Name, c.Version, c.PURL})
+		}
+		return ui.Table([]string{"name", "version", "purl"}, rows)
+	}
+
+	if len(doc.Components) == 0 {
+		ui.Info("%s has no recorded dependencies.", slug)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tVERSION\tPURL")
+	for _, c := range doc.Components {
+		version := c.Version
+		if version == "" {
+			version = "unpinned"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", c.Name, version, c.PURL)
+	}
+	_ = w.Flush()
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	scaleReplicas int
+	scaleMemory   string
+	scaleCPU      string
+)
+
+var scaleCmd = &cobra.Command{
+	Use:   "scale <slug>",
+	Short: "Change agent replicas and resources",
+	Long: `Change the number of replicas and the resource limits of an agent.
+
+Examples:
+  oken scale my-agent --replicas 3
+  oken scale my-agent --memory 512Mi --cpu 0.5`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScale,
+}
+
+func init() {
+	scaleCmd.Flags().IntVarP(&scaleReplicas, "replicas", "r", 0, "Number of replicas")
+	scaleCmd.Flags().StringVar(&scaleMemory, "memory", "", "Memory limit (e.g. 512Mi)")
+	scaleCmd.Flags().StringVar(&scaleCPU, "cpu", "", "CPU limit in cores (e.g. 0.5)")
+	rootCmd.AddCommand(scaleCmd)
+}
+
+func runScale(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	var req api.ScaleRequest
+	if cmd.Flags().Changed("replicas") {
+		if scaleReplicas < 1 {
+			ui.Error("Replicas must be at least 1. Use 'oken stop' to stop an agent.")
+			return fmt.Errorf("invalid replicas")
+		}
+		req.Replicas = &scaleReplicas
+	}
+	if scaleMemory != "" {
+		req.Memory = &scaleMemory
+	}
+	if scaleCPU != "" {
+		req.CPU = &scaleCPU
+	}
+
+	if req.Replicas == nil && req.Memory == nil && req.CPU == nil {
+		ui.Error("Nothing to change. Use --replicas, --memory or --cpu.")
+		return fmt.Errorf("no scale options")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	ui.Info("Scaling agent %s...", slug)
+
+	resp, err := client.ScaleAgent(slug, req)
+	if err != nil {
+		ui.Error("Failed to scale agent: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Agent)
+	}
+
+	ui.Success("Agent scaled: %s", resp.Agent.Slug)
+	printScale(&resp.Agent, "  ")
+
+	return nil
+}
+
+// printScale prints the replica count and resource limits of an agent
+func printScale(agent *api.Agent, indent string) {
+	if agent.Replicas != nil {
+		fmt.Printf("%sReplicas:    %d\n", indent, *agent.Replicas)
+	}
+	if agent.Memory != nil && *agent.Memory != "" {
+		fmt.Printf("%sMemory:      %s\n", indent, *agent.Memory)
+	}
+	if agent.CPU != nil && *agent.CPU != "" {
+		fmt.Printf("%sCPU:         %s\n", indent, *agent.CPU)
+	}
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	scheduleCron  string
+	scheduleInput string
+	scheduleAgent string
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage scheduled invocations",
+	Long:  "Manage cron schedules that invoke your agents automatically.",
+}
+
+var scheduleCreateCmd = &cobra.Command{
+	Use:   "create <slug>",
+	Short: "Create a schedule",
+	Long: `Create a cron schedule that invokes an agent.
+
+The cron expression uses the standard five fields: minute hour day month weekday.
+
+Examples:
+  oken schedule create my-agent --cron "0 * * * *"
+  oken schedule create my-agent --cron "0 9 * * 1-5" --input '{"job":"daily-report"}'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScheduleCreate,
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List schedules",
+	Long: `List all schedules. Use --agent to filter by agent.
+
+Examples:
+  oken schedule list
+  oken schedule list --agent my-agent`,
+	Args: cobra.NoArgs,
+	RunE: runScheduleList,
+}
+
+var scheduleDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Delete a schedule",
+	Long: `Delete a schedule by ID. Run 'oken schedule list' to find IDs.
+
+Examples:
+  oken schedule delete sch_abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScheduleDelete,
+}
+
+func init() {
+	scheduleCreateCmd.Flags().StringVar(&scheduleCron, "cron", "", "Cron expression (required)")
+	scheduleCreateCmd.Flags().StringVarP(&scheduleInput, "input", "i", "", "JSON input passed to each invocation")
+	_ = scheduleCreateCmd.MarkFlagRequired("cron")
+
+	scheduleListCmd.Flags().StringVarP(&scheduleAgent, "agent", "a", "", "Filter by agent slug")
+
+	scheduleCmd.AddCommand(scheduleCreateCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleDeleteCmd)
+
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+func runScheduleCreate(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	input := map[string]any{}
+	if scheduleInput != "" {
+		if err := json.Unmarshal([]byte(scheduleInput), &input); err != nil {
+			ui.Error("Invalid JSON input: %v", err)
+			return err
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.CreateSchedule(slug, scheduleCron, input)
+	if err != nil {
+		ui.Error("Failed to create schedule: %v", err)
+		return err
+	}
+
+	ui.Success("Schedule created: %s (agent: %s)", resp.Schedule.ID, slug)
+	fmt.Printf("  Cron:     %s\n", resp.Schedule.Cron)
+	if resp.Schedule.NextRunAt != nil && *resp.Schedule.NextRunAt != "" {
+		fmt.Printf("  Next run: %s\n", *resp.Schedule.NextRunAt)
+	}
+
+	return nil
+}
+
+func runScheduleList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.ListSchedules(scheduleAgent)
+	if err != nil {
+		ui.Error("Failed to list schedules: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Schedules)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(resp.Schedules))
+		for _, s := range resp.Schedules {
+			rows = append(rows, []string{s.ID, s.AgentSlug, s.Cron, stringValue(s.NextRunAt)})
+		}
+		return ui.Table([]string{"id", "agent", "cron", "next_run"}, rows)
+	}
+
+	if len(resp.Schedules) == 0 {
+		ui.Info("No schedules found. Create one with 'oken schedule create'.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tAGENT\tCRON\tNEXT RUN")
+	for _, s := range resp.Schedules {
+		next := "-"
+		if s.NextRunAt != nil && *s.NextRunAt != "" {
+			next = *s.NextRunAt
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.ID, s.AgentSlug, s.Cron, next)
+	}
+	_ = w.Flush()
+
+	return nil
+}
+
+func runScheduleDelete(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	if _, err := client.DeleteSchedule(id); err != nil {
+		ui.Error("Failed to delete schedule: %v", err)
+		return err
+	}
+
+	ui.Success("Schedule deleted: %s", id)
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find agents by name, slug, description or label",
+	Long: `Find agents whose name, slug, description or labels contain every word of
+the query, ignoring case. Labels match as key=value, so 'team=ml' finds agents
+labeled team=ml.
+
+Matching agents are listed like 'oken list'. The platform runs the search if
+it supports it; otherwise all agents are listed and matched locally.
+
+Examples:
+  oken search support
+  oken search billing team=finance`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+	if strings.TrimSpace(query) == "" {
+		ui.Error("Search query is empty")
+		return fmt.Errorf("invalid query")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.SearchAgents(query)
+	if err != nil {
+		ui.Error("Failed to search agents: %v", err)
+		return err
+	}
+
+	return printAgents(resp.Agents, fmt.Sprintf("No agents match %q", query))
+}
@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
@@ -13,6 +20,20 @@ import (
 
 var secretsAgentSlug string
 
+var (
+	secretsRotateGenerate bool
+	secretsRotateRestart  bool
+)
+
+var (
+	secretsCopyFrom      string
+	secretsCopyTo        string
+	secretsCopyOverwrite bool
+)
+
+// generatedSecretBytes is the amount of randomness in a generated secret value
+const generatedSecretBytes = 32
+
 var secretsCmd = &cobra.Command{
 	Use:   "secrets",
 	Short: "Manage secrets",
@@ -57,12 +78,77 @@ Examples:
 	RunE: runSecretsDelete,
 }
 
+var secretsInspectCmd = &cobra.Command{
+	Use:   "inspect <KEY>",
+	Short: "Show where a secret is used",
+	Long: `Show when a secret was last updated and injected, and which agents see it.
+
+A user-level secret is seen by every agent that doesn't have an agent-specific
+secret of the same name. Check that no agent uses a secret before deleting it.
+
+Examples:
+  oken secrets inspect API_KEY
+  oken secrets inspect API_KEY --agent my-agent
+  oken secrets inspect API_KEY -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretsInspect,
+}
+
+var secretsRotateCmd = &cobra.Command{
+	Use:   "rotate <KEY>",
+	Short: "Replace the value of a secret",
+	Long: `Replace the value of an existing secret and report which agents use it.
+
+The new value is prompted for without echoing, read from stdin when it is piped,
+or generated with --generate (and printed once so you can store it elsewhere).
+
+Agents read secrets when they start, so they keep the old value until they are
+restarted. Use --restart to restart them right away; otherwise you are asked
+when running interactively.
+
+Examples:
+  oken secrets rotate API_KEY --agent my-agent
+  oken secrets rotate WEBHOOK_SECRET --generate --restart
+  vault read -field=key secret/openai | oken secrets rotate OPENAI_API_KEY`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretsRotate,
+}
+
+var secretsCopyCmd = &cobra.Command{
+	Use:   "copy --from <scope> --to <scope> [KEY...]",
+	Short: "Copy secrets between agents",
+	Long: `Copy secrets from one scope to another, such as from an old agent to its
+replacement. A scope is agent:<slug>, or user for user-level secrets.
+
+Values are copied by the platform and never sent to the CLI. Without keys, every
+secret in the source scope is copied. Secrets that already exist in the target
+scope are skipped unless --overwrite is given.
+
+Examples:
+  oken secrets copy --from agent:old-agent --to agent:new-agent
+  oken secrets copy --from user --to agent:my-agent OPENAI_API_KEY
+  oken secrets copy --from agent:staging --to agent:prod API_KEY DB_URL --overwrite`,
+	RunE: runSecretsCopy,
+}
+
 func init() {
+	secretsCopyCmd.Flags().StringVar(&secretsCopyFrom, "from", "", "Scope to copy from (agent:<slug> or user)")
+	secretsCopyCmd.Flags().StringVar(&secretsCopyTo, "to", "", "Scope to copy to (agent:<slug> or user)")
+	secretsCopyCmd.Flags().BoolVar(&secretsCopyOverwrite, "overwrite", false, "Replace secrets that already exist in the target scope")
+	_ = secretsCopyCmd.MarkFlagRequired("from")
+	_ = secretsCopyCmd.MarkFlagRequired("to")
+
+	secretsRotateCmd.Flags().BoolVar(&secretsRotateGenerate, "generate", false, "Generate a random value instead of asking for one")
+	secretsRotateCmd.Flags().BoolVar(&secretsRotateRestart, "restart", false, "Restart the agents that use the secret")
+
 	secretsCmd.PersistentFlags().StringVarP(&secretsAgentSlug, "agent", "a", "", "Agent slug (for agent-specific secrets)")
 
 	secretsCmd.AddCommand(secretsSetCmd)
 	secretsCmd.AddCommand(secretsListCmd)
 	secretsCmd.AddCommand(secretsDeleteCmd)
+	secretsCmd.AddCommand(secretsInspectCmd)
+	secretsCmd.AddCommand(secretsRotateCmd)
+	secretsCmd.AddCommand(secretsCopyCmd)
 
 	rootCmd.AddCommand(secretsCmd)
 }
@@ -99,7 +185,7 @@ func runSecretsSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
-	client := api.NewClient(cfg.Endpoint, cfg.Token)
+	client := newClient(cfg)
 
 	var agentSlugPtr *string
 	if secretsAgentSlug != "" {
@@ -133,7 +219,7 @@ func runSecretsList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
-	client := api.NewClient(cfg.Endpoint, cfg.Token)
+	client := newClient(cfg)
 
 	resp, err := client.ListSecrets(secretsAgentSlug)
 	if err != nil {
@@ -141,6 +227,14 @@ func runSecretsList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(resp.Secrets))
+		for _, s := range resp.Secrets {
+			rows = append(rows, []string{s.Name, stringValue(s.AgentSlug), s.CreatedAt})
+		}
+		return ui.Table([]string{"name", "agent", "created"}, rows)
+	}
+
 	if len(resp.Secrets) == 0 {
 		if secretsAgentSlug != "" {
 			ui.Info("No secrets found for agent '%s'", secretsAgentSlug)
@@ -184,7 +278,7 @@ func runSecretsDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
-	client := api.NewClient(cfg.Endpoint, cfg.Token)
+	client := newClient(cfg)
 
 	_, err = client.DeleteSecret(name, secretsAgentSlug)
 	if err != nil {
@@ -200,3 +294,337 @@ func runSecretsDelete(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runSecretsInspect(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	secret, err := client.InspectSecret(name, secretsAgentSlug)
+	if err != nil {
+		if api.IsNotFound(err) {
+			ui.Error("Secret not found: %s (%s)", name, secretScope(secretsAgentSlug))
+		} else {
+			ui.Error("Failed to inspect secret: %v", err)
+		}
+		return err
+	}
+
+	if ui.IsTemplate() {
+		return ui.Template(secret)
+	}
+	if ui.IsStructured() {
+		return ui.Result(secret)
+	}
+
+	fmt.Printf("Name:          %s\n", secret.Name)
+	fmt.Printf("Scope:         %s\n", secretScope(stringValue(secret.AgentSlug)))
+	fmt.Printf("Created:       %s\n", secret.CreatedAt)
+	fmt.Printf("Updated:       %s\n", secret.UpdatedAt)
+	fmt.Printf("Last injected: %s\n", injectedAt(secret.LastInjectedAt))
+
+	fmt.Println()
+	if len(secret.Agents) == 0 {
+		ui.Info("No agents use %s", secret.Name)
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "AGENT\tSTATUS\tLAST INJECTED")
+		for _, a := range secret.Agents {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", a.Slug, a.Status, injectedAt(a.LastInjectedAt))
+		}
+		_ = w.Flush()
+	}
+	if len(secret.OverriddenBy) > 0 {
+		fmt.Println()
+		ui.Info("Overridden by agent-specific secrets in: %s", strings.Join(secret.OverriddenBy, ", "))
+	}
+
+	return nil
+}
+
+// injectedAt formats when a secret was last injected
+func injectedAt(ts *string) string {
+	if ts == nil || *ts == "" {
+		return "never"
+	}
+	return *ts
+}
+
+// secretRotation is the structured output of secrets rotate
+type secretRotation struct {
+	Name  string `json:"name"`
+	Agent string `json:"agent,omitempty"`
+	// Value is only set when it was generated
+	Value     string            `json:"value,omitempty"`
+	Agents    []api.SecretAgent `json:"agents"`
+	Restarted []string          `json:"restarted"`
+}
+
+func runSecretsRotate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	// Rotating only replaces secrets, so a typo doesn't create a new one
+	list, err := client.ListSecrets(secretsAgentSlug)
+	if err != nil {
+		ui.Error("Failed to list secrets: %v", err)
+		return err
+	}
+	found := false
+	for _, s := range list.Secrets {
+		if s.Name == name && stringValue(s.AgentSlug) == stringValue(&secretsAgentSlug) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		ui.Error("Secret not found: %s (%s). Use 'oken secrets set' to create it.", name, secretScope(secretsAgentSlug))
+		return fmt.Errorf("secret not found")
+	}
+
+	value, err := newSecretValue(name)
+	if err != nil {
+		ui.Error("Failed to read the new value: %v", err)
+		return err
+	}
+
+	if _, err := client.UpdateSecret(name, value, secretsAgentSlug); err != nil {
+		ui.Error("Failed to update secret: %v", err)
+		return err
+	}
+
+	result := secretRotation{Name: name, Agent: secretsAgentSlug, Agents: []api.SecretAgent{}, Restarted: []string{}}
+	if secretsRotateGenerate {
+		result.Value = value
+	}
+
+	if secretsRotateGenerate {
+		// The only time the value is shown, alone on stdout so it can be piped
+		ui.ReserveStdout()
+		if !ui.IsStructured() {
+			fmt.Println(value)
+		}
+	}
+	ui.Success("Secret rotated: %s (%s)", name, secretScope(secretsAgentSlug))
+
+	agents, err := client.SecretAgents(name, secretsAgentSlug)
+	if err != nil {
+		ui.Warning("Could not find which agents use %s: %v", name, err)
+		return err
+	}
+	result.Agents = agents.Agents
+
+	if len(agents.Agents) == 0 {
+		ui.Info("No agents use %s", name)
+	} else {
+		ui.Info("Agents using %s:", name)
+		for _, a := range agents.Agents {
+			ui.Info("  %s (%s)", a.Slug, a.Status)
+		}
+	}
+
+	restart, err := shouldRestartAfterRotation(agents.Agents)
+	if err != nil {
+		return err
+	}
+	var failed int
+	if restart {
+		for _, a := range agents.Agents {
+			if a.Status == api.AgentStopped {
+				continue
+			}
+			if _, err := client.RestartAgent(a.Slug); err != nil {
+				ui.Error("Failed to restart %s: %v", a.Slug, err)
+				failed++
+				continue
+			}
+			ui.Success("Restarting %s", a.Slug)
+			result.Restarted = append(result.Restarted, a.Slug)
+		}
+	} else if runningAgents(agents.Agents) > 0 {
+		ui.Warning("Running agents keep the old value until they are restarted")
+	}
+
+	if ui.IsStructured() {
+		if err := ui.Result(result); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d agents failed to restart", failed, failed+len(result.Restarted))
+	}
+	return nil
+}
+
+// parseSecretScope parses agent:<slug>, or user for the user-level scope,
+// which is returned as nil
+func parseSecretScope(scope string) (*string, error) {
+	if scope == "user" || scope == "user-level" {
+		return nil, nil
+	}
+	slug, ok := strings.CutPrefix(scope, "agent:")
+	if !ok || slug == "" {
+		return nil, fmt.Errorf("invalid scope %q: use agent:<slug> or user", scope)
+	}
+	return &slug, nil
+}
+
+func runSecretsCopy(cmd *cobra.Command, args []string) error {
+	if secretsAgentSlug != "" {
+		ui.Error("--agent can't be used with copy; give the scopes with --from and --to")
+		return fmt.Errorf("invalid flags")
+	}
+	from, err := parseSecretScope(secretsCopyFrom)
+	if err != nil {
+		ui.Error("Invalid --from: %v", err)
+		return fmt.Errorf("invalid flags")
+	}
+	to, err := parseSecretScope(secretsCopyTo)
+	if err != nil {
+		ui.Error("Invalid --to: %v", err)
+		return fmt.Errorf("invalid flags")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.CopySecrets(api.CopySecretsRequest{
+		FromAgent: from,
+		ToAgent:   to,
+		Names:     args,
+		Overwrite: secretsCopyOverwrite,
+	})
+	if err != nil {
+		ui.Error("Failed to copy secrets: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp)
+	}
+
+	for _, name := range resp.Copied {
+		ui.Success("Copied %s", name)
+	}
+	for _, name := range resp.Skipped {
+		ui.Warning("Skipped %s: it already exists in %s (use --overwrite to replace it)", name, secretsCopyTo)
+	}
+	if len(resp.Copied) == 0 && len(resp.Skipped) == 0 {
+		ui.Info("No secrets to copy in %s", secretsCopyFrom)
+		return nil
+	}
+	ui.Info("%d copied, %d skipped", len(resp.Copied), len(resp.Skipped))
+
+	return nil
+}
+
+// secretScope describes the scope of a secret in messages
+func secretScope(agentSlug string) string {
+	if agentSlug != "" {
+		return "agent: " + agentSlug
+	}
+	return "user-level"
+}
+
+// newSecretValue generates a value with --generate, reads it from piped
+// stdin, or prompts for it twice without echoing
+func newSecretValue(name string) (string, error) {
+	if secretsRotateGenerate {
+		return generateSecretValue()
+	}
+
+	stat, err := os.Stdin.Stat()
+	if err == nil && stat.Mode()&os.ModeCharDevice == 0 {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		value := strings.TrimRight(line, "\r\n")
+		if value == "" {
+			return "", fmt.Errorf("no value on stdin")
+		}
+		return value, nil
+	}
+
+	value, err := ui.PromptSecret(fmt.Sprintf("New value for %s:", name))
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", fmt.Errorf("secret value cannot be empty")
+	}
+	again, err := ui.PromptSecret("Repeat the value:")
+	if err != nil {
+		return "", err
+	}
+	if again != value {
+		return "", fmt.Errorf("values don't match")
+	}
+	return value, nil
+}
+
+// generateSecretValue returns a random URL-safe value
+func generateSecretValue() (string, error) {
+	b := make([]byte, generatedSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// shouldRestartAfterRotation decides whether to restart the agents using a
+// rotated secret: with --restart, or if the user agrees when asked
+func shouldRestartAfterRotation(agents []api.SecretAgent) (bool, error) {
+	if secretsRotateRestart {
+		return true, nil
+	}
+	n := runningAgents(agents)
+	if n == 0 || !ui.CanPrompt() {
+		return false, nil
+	}
+	return ui.Confirm(fmt.Sprintf("Restart %d running agents now?", n))
+}
+
+// runningAgents counts the agents that would need a restart
+func runningAgents(agents []api.SecretAgent) int {
+	n := 0
+	for _, a := range agents {
+		if a.Status != api.AgentStopped {
+			n++
+		}
+	}
+	return n
+}
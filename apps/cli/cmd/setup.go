@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/deps"
+	"github.com/neult/oken/apps/cli/internal/ui"
+	"github.com/neult/oken/apps/cli/internal/venv"
+)
+
+var setupRecreate bool
+
+var setupCmd = &cobra.Command{
+	Use:         "setup",
+	Short:       "Create a local Python environment for the agent",
+	Annotations: textOutput,
+	Long: `Create a virtualenv in .venv for the Python agent in the current directory
+and install its dependencies, so it runs locally as it does when deployed.
+
+The Python version is python_version from oken.toml, then .python-version,
+then 3.12, the platform's default. Dependencies are installed from uv.lock or
+pyproject.toml with uv, from poetry.lock with poetry, or from
+requirements.txt with uv when it is installed and pip otherwise. A missing
+.python-version is written, so editors and uv pick the same version.
+
+An existing .venv is reused; --recreate deletes it first, which is needed
+after changing the Python version.
+
+Examples:
+  oken setup
+  oken setup --recreate`,
+	Args: cobra.NoArgs,
+	RunE: runSetup,
+}
+
+func init() {
+	setupCmd.Flags().BoolVar(&setupRecreate, "recreate", false, "Delete and recreate an existing .venv")
+	rootCmd.AddCommand(setupCmd)
+}
+
+func runSetup(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat("oken.toml"); err != nil {
+		ui.Error("No oken.toml in this directory. Run 'oken init' first.")
+		return fmt.Errorf("not an agent project")
+	}
+	okenCfg, err := loadOkenConfig(".")
+	if err != nil {
+		ui.Error("Failed to parse oken.toml: %v", err)
+		return err
+	}
+	if okenCfg.runtime() != "python" {
+		ui.Error("Setup creates Python environments; this agent runs on %s.", okenCfg.runtime())
+		return fmt.Errorf("unsupported runtime")
+	}
+
+	fileVersion, err := venv.ReadVersionFile(".")
+	if err != nil {
+		ui.Error("Failed to read %s: %v", venv.VersionFile, err)
+		return err
+	}
+	python := cmp.Or(okenCfg.PythonVersion, fileVersion, venv.DefaultPython)
+	if fileVersion != "" && !venv.Matches(fileVersion, python) && !venv.Matches(python, fileVersion) {
+		ui.Warning("%s pins Python %s, but oken.toml deploys with %s; using %s", venv.VersionFile, fileVersion, python, python)
+	}
+
+	set, err := deps.Load(".")
+	if err != nil {
+		ui.Error("Failed to read dependencies: %v", err)
+		return err
+	}
+
+	exists := false
+	if _, err := os.Stat(venv.Dir); err == nil {
+		exists = true
+		if setupRecreate {
+			ui.Info("Deleting %s...", venv.Dir)
+			if err := os.RemoveAll(venv.Dir); err != nil {
+				ui.Error("Failed to delete %s: %v", venv.Dir, err)
+				return err
+			}
+			exists = false
+		} else if version, err := venv.Version(venv.Dir); err == nil && !venv.Matches(version, python) {
+			ui.Error("%s uses Python %s, not %s. Run 'oken setup --recreate' to replace it.", venv.Dir, version, python)
+			return fmt.Errorf("python version mismatch")
+		}
+	}
+
+	opts := venv.Options{Python: python, Exists: exists, Windows: runtime.GOOS == "windows"}
+	if set != nil {
+		opts.DepsFile = set.File
+	}
+	_, err = exec.LookPath("uv")
+	opts.UV = err == nil
+	_, err = exec.LookPath("poetry")
+	opts.Poetry = err == nil
+
+	steps, err := venv.Plan(opts)
+	if err != nil {
+		ui.Error("%v", err)
+		return err
+	}
+
+	for _, step := range steps {
+		ui.Info("Running %s", step)
+		c := exec.Command(step.Args[0], step.Args[1:]...)
+		c.Env = append(os.Environ(), step.Env...)
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			ui.Error("Failed to run %s: %v", step, err)
+			return err
+		}
+	}
+
+	if fileVersion == "" {
+		if err := os.WriteFile(venv.VersionFile, []byte(python+"\n"), 0644); err != nil {
+			ui.Error("Failed to write %s: %v", venv.VersionFile, err)
+			return err
+		}
+		ui.Success("Wrote %s (%s)", venv.VersionFile, python)
+	}
+
+	if opts.DepsFile == "" {
+		ui.Success("%s is ready with Python %s; no dependencies to install", venv.Dir, python)
+	} else {
+		ui.Success("%s is ready with Python %s and the dependencies from %s", venv.Dir, python, opts.DepsFile)
+	}
+
+	fmt.Println()
+	fmt.Println("Activate it:")
+	if opts.Windows {
+		fmt.Println(`  .venv\Scripts\Activate.ps1      (PowerShell)`)
+		fmt.Println(`  .venv\Scripts\activate.bat      (cmd)`)
+	} else {
+		fmt.Println("  source .venv/bin/activate       (bash, zsh)")
+		fmt.Println("  source .venv/bin/activate.fish  (fish)")
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	shareWith string
+	shareRole string
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share <slug>",
+	Short: "Give a teammate access to an agent",
+	Long: `Grant a user a role on an agent. Sharing again with a different role replaces it.
+
+Roles: ` + strings.Join(api.AccessRoles, ", ") + `
+
+Examples:
+  oken share my-agent --with teammate@example.com
+  oken share my-agent --with teammate@example.com --role manager`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShare,
+}
+
+func init() {
+	shareCmd.Flags().StringVar(&shareWith, "with", "", "Email of the user to share with (required)")
+	shareCmd.Flags().StringVarP(&shareRole, "role", "r", "invoker", "Role to grant ("+strings.Join(api.AccessRoles, ", ")+")")
+	_ = shareCmd.MarkFlagRequired("with")
+	rootCmd.AddCommand(shareCmd)
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.ShareAgent(slug, shareWith, shareRole)
+	if err != nil {
+		ui.Error("Failed to share agent: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Grant)
+	}
+
+	ui.Success("Shared %s with %s (role: %s)", slug, resp.Grant.Email, resp.Grant.Role)
+
+	return nil
+}
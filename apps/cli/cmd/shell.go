@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var shellCommand string
+
+var shellCmd = &cobra.Command{
+	Use:         "shell <slug>",
+	Short:       "Open an interactive shell in a running agent",
+	Annotations: textOutput,
+	Long: `Open an interactive shell inside the agent's running environment. The
+terminal is switched to raw mode and window resizes are forwarded, so editors
+and pagers work as expected. Exit the shell to return.
+
+Examples:
+  oken shell my-agent
+  oken shell my-agent --shell bash`,
+	Args:          cobra.ExactArgs(1),
+	RunE:          runShell,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+func init() {
+	shellCmd.Flags().StringVar(&shellCommand, "shell", "", "Shell to start (default: the agent's /bin/sh)")
+	rootCmd.AddCommand(shellCmd)
+}
+
+func runShell(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	stdinFd := int(os.Stdin.Fd())
+	if !term.IsTerminal(stdinFd) {
+		ui.Error("oken shell needs an interactive terminal. Use 'oken exec' in scripts.")
+		return fmt.Errorf("stdin is not a terminal")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	opts := api.ShellOptions{}
+	if shellCommand != "" {
+		opts.Command = []string{shellCommand}
+	}
+	if cols, rows, err := term.GetSize(stdinFd); err == nil {
+		opts.Cols, opts.Rows = cols, rows
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	session, err := client.Shell(ctx, slug, opts)
+	if err != nil {
+		ui.Error("Failed to open shell: %v", err)
+		return err
+	}
+	defer func() { _ = session.Close() }()
+
+	// Raw mode passes Ctrl+C and friends through to the remote shell
+	state, err := term.MakeRaw(stdinFd)
+	if err != nil {
+		ui.Error("Failed to set terminal to raw mode: %v", err)
+		return err
+	}
+	defer func() { _ = term.Restore(stdinFd, state) }()
+
+	go watchResize(ctx, stdinFd, session)
+	go func() { _, _ = io.Copy(session, os.Stdin) }()
+
+	code, err := session.Wait(os.Stdout)
+	_ = term.Restore(stdinFd, state)
+	if err != nil {
+		ui.Error("Shell error: %v", err)
+		return err
+	}
+
+	if code != 0 {
+		return &ExitError{Code: code}
+	}
+	return nil
+}
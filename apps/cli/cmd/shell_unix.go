@@ -0,0 +1,32 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/term"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+)
+
+// watchResize forwards terminal window size changes to the remote shell
+func watchResize(ctx context.Context, fd int, session *api.ShellSession) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGWINCH)
+	defer signal.Stop(sigs)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigs:
+			if cols, rows, err := term.GetSize(fd); err == nil {
+				_ = session.Resize(cols, rows)
+			}
+		}
+	}
+}
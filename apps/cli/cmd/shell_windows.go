@@ -0,0 +1,34 @@
+//go:build windows
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+)
+
+// watchResize forwards terminal window size changes to the remote shell.
+// Windows has no SIGWINCH, so the console size is polled instead.
+func watchResize(ctx context.Context, fd int, session *api.ShellSession) {
+	cols, rows, _ := term.GetSize(fd)
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c, r, err := term.GetSize(fd)
+			if err != nil || (c == cols && r == rows) {
+				continue
+			}
+			cols, rows = c, r
+			_ = session.Resize(cols, rows)
+		}
+	}
+}
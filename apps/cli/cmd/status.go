@@ -5,7 +5,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/neult/oken/apps/cli/internal/api"
 	"github.com/neult/oken/apps/cli/internal/config"
 	"github.com/neult/oken/apps/cli/internal/ui"
 )
@@ -13,8 +12,14 @@ import (
 var statusCmd = &cobra.Command{
 	Use:   "status <slug>",
 	Short: "Get agent status",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runStatus,
+	Long: `Show an agent's status and settings. Qualify the slug with an
+environment, as in my-agent@staging, to see the agent in that environment.
+
+Examples:
+  oken status my-agent
+  oken status my-agent@staging`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStatus,
 }
 
 func init() {
@@ -35,30 +40,68 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
-	client := api.NewClient(cfg.Endpoint, cfg.Token)
+	client := newClient(cfg)
 
 	agent, err := client.GetAgent(slug)
 	if err != nil {
+		err = client.WithSuggestions(slug, err)
 		ui.Error("Failed to get agent: %v", err)
 		return err
 	}
 
-	fmt.Printf("Name:       %s\n", agent.Name)
-	fmt.Printf("Slug:       %s\n", agent.Slug)
-	fmt.Printf("Status:     %s\n", agent.Status)
+	if ui.IsTemplate() {
+		return ui.Template(agent)
+	}
+	if ui.IsStructured() {
+		return ui.Result(agent)
+	}
+
+	fmt.Printf("Name:        %s\n", agent.Name)
+	fmt.Printf("Slug:        %s\n", agent.Slug)
+	if agent.Environment != nil && *agent.Environment != "" {
+		fmt.Printf("Env:         %s\n", *agent.Environment)
+	}
+	fmt.Printf("Status:      %s\n", ui.AgentStatus(agent.Status))
+	if agent.Description != nil && *agent.Description != "" {
+		fmt.Printf("Description: %s\n", *agent.Description)
+	}
+	if agent.Owner != nil && *agent.Owner != "" {
+		fmt.Printf("Owner:       %s\n", *agent.Owner)
+	}
 
 	if agent.Endpoint != nil && *agent.Endpoint != "" {
-		fmt.Printf("Endpoint:   %s\n", *agent.Endpoint)
+		fmt.Printf("Endpoint:    %s\n", *agent.Endpoint)
+	}
+	if agent.Runtime != nil && *agent.Runtime != "" {
+		fmt.Printf("Runtime:     %s\n", *agent.Runtime)
+	}
+	if agent.Build != nil && *agent.Build != "" {
+		fmt.Printf("Build:       %s\n", *agent.Build)
 	}
 	if agent.PythonVersion != nil && *agent.PythonVersion != "" {
-		fmt.Printf("Python:     %s\n", *agent.PythonVersion)
+		fmt.Printf("Python:      %s\n", *agent.PythonVersion)
+	}
+	if agent.NodeVersion != nil && *agent.NodeVersion != "" {
+		fmt.Printf("Node:        %s\n", *agent.NodeVersion)
 	}
 	if agent.Entrypoint != nil && *agent.Entrypoint != "" {
-		fmt.Printf("Entrypoint: %s\n", *agent.Entrypoint)
+		fmt.Printf("Entrypoint:  %s\n", *agent.Entrypoint)
+	}
+	printScale(agent, "")
+	printLifecycle(agent, "")
+	printLabels(agent, "")
+	if agent.Git != nil && agent.Git.Commit != "" {
+		fmt.Printf("Commit:      %s\n", formatCommit(agent.Git))
+	}
+	// The repository set for the agent wins over the one it was deployed from
+	if agent.Repository != nil && *agent.Repository != "" {
+		fmt.Printf("Repository:  %s\n", *agent.Repository)
+	} else if agent.Git != nil && agent.Git.Repository != "" {
+		fmt.Printf("Repository:  %s\n", agent.Git.Repository)
 	}
 
-	fmt.Printf("Created:    %s\n", agent.CreatedAt)
-	fmt.Printf("Updated:    %s\n", agent.UpdatedAt)
+	fmt.Printf("Created:     %s\n", agent.CreatedAt)
+	fmt.Printf("Updated:     %s\n", agent.UpdatedAt)
 
 	return nil
 }
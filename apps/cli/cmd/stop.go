@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -10,19 +14,59 @@ import (
 	"github.com/neult/oken/apps/cli/internal/ui"
 )
 
+var (
+	stopAll         bool
+	stopStatus      string
+	stopForce       bool
+	stopConcurrency int
+)
+
 var stopCmd = &cobra.Command{
-	Use:   "stop <slug>",
-	Short: "Stop a running agent",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runStop,
+	Use:   "stop <slug>...",
+	Short: "Stop running agents",
+	Long: `Stop one or more agents.
+
+With --all, every agent that isn't already stopped is stopped, or with
+--status only agents in that status. A summary is shown for confirmation
+first; --force skips it.
+
+Several agents are stopped up to --concurrency at a time, and the result for
+each is printed as a table.
+
+Examples:
+  oken stop my-agent
+  oken stop agent-a agent-b agent-c
+  oken stop my-agent@staging
+  oken stop --all --status running`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if stopAll && len(args) > 0 {
+			return fmt.Errorf("--all can't be used with agent slugs")
+		}
+		if !stopAll && len(args) == 0 {
+			return fmt.Errorf("requires at least 1 agent slug, or --all")
+		}
+		return nil
+	},
+	RunE: runStop,
 }
 
 func init() {
+	stopCmd.Flags().BoolVar(&stopAll, "all", false, "Stop every agent that isn't stopped")
+	stopCmd.Flags().StringVar(&stopStatus, "status", "", "With --all, only stop agents in this status, e.g. running")
+	stopCmd.Flags().BoolVarP(&stopForce, "force", "f", false, "Skip confirmation prompt for --all")
+	stopCmd.Flags().IntVarP(&stopConcurrency, "concurrency", "j", 4, "How many agents to stop at once")
 	rootCmd.AddCommand(stopCmd)
 }
 
 func runStop(cmd *cobra.Command, args []string) error {
-	slug := args[0]
+	if stopStatus != "" && !stopAll {
+		ui.Error("--status can only be used with --all.")
+		return fmt.Errorf("invalid flags")
+	}
+	if stopConcurrency < 1 {
+		ui.Error("Invalid --concurrency %d. Use 1 or more.", stopConcurrency)
+		return fmt.Errorf("invalid concurrency")
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -35,17 +79,100 @@ func runStop(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not authenticated")
 	}
 
-	client := api.NewClient(cfg.Endpoint, cfg.Token)
+	client := newClient(cfg)
 
+	if len(args) == 1 {
+		return stopOne(client, args[0])
+	}
+
+	slugs := args
+	if stopAll {
+		slugs, err = selectAgentsToStop(client)
+		if err != nil || len(slugs) == 0 {
+			return err
+		}
+	}
+
+	ui.Info("Stopping %d agents, %d at a time...", len(slugs), min(stopConcurrency, len(slugs)))
+	results := runBulk(slugs, stopConcurrency, func(slug string) (string, error) {
+		resp, err := client.StopAgent(slug)
+		if err != nil {
+			return "", client.WithSuggestions(slug, err)
+		}
+		return string(resp.Agent.Status), nil
+	})
+	return printBulkResults(results, "Stopped")
+}
+
+// stopOne stops a single agent
+func stopOne(client *api.Client, slug string) error {
 	ui.Info("Stopping agent %s...", slug)
 
 	resp, err := client.StopAgent(slug)
 	if err != nil {
+		err = client.WithSuggestions(slug, err)
 		ui.Error("Failed to stop agent: %v", err)
 		return err
 	}
 
-	ui.Success("Agent stopped: %s (status: %s)", resp.Agent.Slug, resp.Agent.Status)
+	if ui.IsStructured() {
+		return ui.Result(resp.Agent)
+	}
+
+	ui.Success("Agent stopped: %s (status: %s)", resp.Agent.Slug, ui.AgentStatus(resp.Agent.Status))
 
 	return nil
 }
+
+// selectAgentsToStop returns the agents matched by --all and --status, once
+// the user has confirmed. It returns no agents if none match or the user
+// declines.
+func selectAgentsToStop(client *api.Client) ([]string, error) {
+	resp, err := client.ListAgents()
+	if err != nil {
+		ui.Error("Failed to list agents: %v", err)
+		return nil, err
+	}
+
+	var slugs []string
+	byStatus := map[api.AgentStatus]int{}
+	for _, agent := range resp.Agents {
+		if stopStatus != "" && string(agent.Status) != stopStatus {
+			continue
+		}
+		if stopStatus == "" && agent.Status == api.AgentStopped {
+			continue
+		}
+		slugs = append(slugs, agent.Slug)
+		byStatus[agent.Status]++
+	}
+	if len(slugs) == 0 {
+		if stopStatus != "" {
+			ui.Info("No agents with status %s", stopStatus)
+		} else {
+			ui.Info("No agents to stop")
+		}
+		return nil, nil
+	}
+	if stopForce {
+		return slugs, nil
+	}
+
+	counts := make([]string, 0, len(byStatus))
+	for _, status := range slices.Sorted(maps.Keys(byStatus)) {
+		counts = append(counts, fmt.Sprintf("%d %s", byStatus[status], status))
+	}
+	confirmed, err := ui.Confirm(fmt.Sprintf("Stop %d agents (%s)?", len(slugs), strings.Join(counts, ", ")))
+	if errors.Is(err, ui.ErrNoInput) {
+		ui.Error("Cannot ask for confirmation. Pass --force to stop without prompting.")
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		ui.Info("Aborted")
+		return nil, nil
+	}
+	return slugs, nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/telemetry"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Control anonymous usage reports",
+	Long: `Control anonymous usage reports, which help the maintainers see which
+features matter. Reports are off unless you turn them on.
+
+When on, each command reports its name (such as "oken secrets list"), how
+long it took, the category of error it failed with, the CLI version, and your
+OS and architecture, with a random ID for this installation. Arguments, flags,
+agent names, inputs, outputs and error messages are never sent.
+
+OKEN_TELEMETRY=0 or DO_NOT_TRACK=1 turns reports off regardless of this setting.`,
+}
+
+var telemetryOnCmd = &cobra.Command{
+	Use:   "on",
+	Short: "Send anonymous usage reports",
+	Args:  cobra.NoArgs,
+	RunE:  runTelemetryOn,
+}
+
+var telemetryOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Stop sending usage reports",
+	Args:  cobra.NoArgs,
+	RunE:  runTelemetryOff,
+}
+
+var telemetryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether usage reports are sent",
+	Args:  cobra.NoArgs,
+	RunE:  runTelemetryStatus,
+}
+
+func init() {
+	telemetryCmd.AddCommand(telemetryOnCmd)
+	telemetryCmd.AddCommand(telemetryOffCmd)
+	telemetryCmd.AddCommand(telemetryStatusCmd)
+	rootCmd.AddCommand(telemetryCmd)
+}
+
+func runTelemetryOn(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.TelemetryID == "" {
+		if cfg.TelemetryID, err = telemetry.NewID(); err != nil {
+			return err
+		}
+	}
+	cfg.Telemetry = true
+	if err := config.Save(cfg); err != nil {
+		ui.Error("Failed to save config: %v", err)
+		return err
+	}
+
+	ui.Success("Anonymous usage reports are on. Thank you!")
+	if env := telemetry.DisabledByEnv(); env != "" {
+		ui.Warning("%s is set, so nothing is sent from this environment", env)
+	}
+	return nil
+}
+
+func runTelemetryOff(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	// A new ID is made if reports are turned on again
+	cfg.Telemetry, cfg.TelemetryID = false, ""
+	if err := config.Save(cfg); err != nil {
+		ui.Error("Failed to save config: %v", err)
+		return err
+	}
+
+	ui.Success("Usage reports are off")
+	return nil
+}
+
+// telemetryStatus is the result of 'oken telemetry status'
+type telemetryStatus struct {
+	Enabled bool   `json:"enabled"`
+	Setting bool   `json:"setting"`
+	Reason  string `json:"disabledBy,omitempty"`
+	ID      string `json:"id,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+func runTelemetryStatus(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	status := telemetryStatus{
+		Setting: cfg.Telemetry,
+		Reason:  telemetry.DisabledByEnv(),
+		ID:      cfg.TelemetryID,
+	}
+	status.Enabled = status.Setting && status.Reason == ""
+	if status.Enabled {
+		status.URL = telemetry.URL(cfg.Endpoint)
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(status)
+	}
+
+	switch {
+	case status.Enabled:
+		fmt.Printf("Usage reports: %s\n", ui.Green("on"))
+		fmt.Printf("Installation ID: %s\n", status.ID)
+		fmt.Printf("Sent to: %s\n", status.URL)
+	case status.Setting:
+		fmt.Printf("Usage reports: %s (%s is set)\n", ui.Yellow("off"), status.Reason)
+	default:
+		fmt.Println("Usage reports: off")
+		ui.Info("Run 'oken telemetry on' to help improve oken.")
+	}
+	return nil
+}
+
+// recordTelemetry reports a finished command if the user opted in. It never
+// fails the command. The opt-in is read from the config the command loaded,
+// so commands that never read it, like 'oken pack', aren't reported.
+func recordTelemetry(cmd *cobra.Command, duration time.Duration, err error) {
+	if cmd == nil || telemetry.DisabledByEnv() != "" {
+		return
+	}
+	cfg := config.Loaded()
+	if cfg == nil || !cfg.Telemetry || cfg.TelemetryID == "" {
+		return
+	}
+
+	category := telemetry.Category(err)
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		category = telemetry.ErrorExitStatus
+	}
+	event := telemetry.NewEvent(cfg.TelemetryID, cmd.CommandPath(), duration, category, Version)
+	_ = telemetry.Send(telemetry.URL(cfg.Endpoint), event)
+}
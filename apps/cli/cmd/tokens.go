@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+// tokenScopes are the permissions a service token can have
+var tokenScopes = []string{
+	"read",
+	"deploy",
+	"invoke",
+	"secrets",
+}
+
+var (
+	tokenName    string
+	tokenScope   []string
+	tokenAgents  []string
+	tokenExpires string
+)
+
+var tokensCmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "Manage service tokens",
+	Long: `Manage service tokens for CI and other automation.
+
+Service tokens are scoped and expire, unlike the personal token saved by
+'oken login'. Give one to the CLI with the OKEN_TOKEN environment variable.`,
+}
+
+var tokensCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a service token",
+	Long: `Create a service token. The token is shown once; store it in your CI secrets.
+
+Scopes: ` + strings.Join(tokenScopes, ", ") + `
+
+Without --agent, the token works for all agents of the account or organization.
+
+Examples:
+  oken tokens create --name github-deploy --scope deploy --agent my-agent
+  oken tokens create --name nightly --scope invoke --scope read --expires 90d`,
+	Args: cobra.NoArgs,
+	RunE: runTokensCreate,
+}
+
+var tokensListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List service tokens",
+	Args:  cobra.NoArgs,
+	RunE:  runTokensList,
+}
+
+var tokensRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke a service token",
+	Long: `Revoke a service token. Anything using it is rejected from then on.
+
+Examples:
+  oken tokens revoke tok_123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTokensRevoke,
+}
+
+func init() {
+	tokensCreateCmd.Flags().StringVar(&tokenName, "name", "", "Name to recognize the token by (required)")
+	tokensCreateCmd.Flags().StringSliceVar(&tokenScope, "scope", nil, "Permission to grant (repeatable, required)")
+	tokensCreateCmd.Flags().StringSliceVarP(&tokenAgents, "agent", "a", nil, "Limit the token to an agent (repeatable)")
+	tokensCreateCmd.Flags().StringVar(&tokenExpires, "expires", "30d", "How long the token is valid, e.g. 12h, 30d")
+	_ = tokensCreateCmd.MarkFlagRequired("name")
+	_ = tokensCreateCmd.MarkFlagRequired("scope")
+
+	tokensCmd.AddCommand(tokensCreateCmd)
+	tokensCmd.AddCommand(tokensListCmd)
+	tokensCmd.AddCommand(tokensRevokeCmd)
+
+	rootCmd.AddCommand(tokensCmd)
+}
+
+// parseTokenExpiry parses a duration like 12h, or a number of days like 30d
+func parseTokenExpiry(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a duration like 12h or a number of days like 30d", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("%q is not a duration like 12h or a number of days like 30d", s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%q must be positive", s)
+	}
+	return d, nil
+}
+
+func runTokensCreate(cmd *cobra.Command, args []string) error {
+	for _, scope := range tokenScope {
+		if !slices.Contains(tokenScopes, scope) {
+			ui.Error("Unknown scope '%s'. Valid scopes: %s", scope, strings.Join(tokenScopes, ", "))
+			return fmt.Errorf("unknown scope")
+		}
+	}
+	expiry, err := parseTokenExpiry(tokenExpires)
+	if err != nil {
+		ui.Error("Invalid --expires: %v", err)
+		return fmt.Errorf("invalid flags")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.CreateToken(api.CreateTokenRequest{
+		Name:      tokenName,
+		Scopes:    tokenScope,
+		Agents:    tokenAgents,
+		ExpiresAt: time.Now().Add(expiry).UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		ui.Error("Failed to create token: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp)
+	}
+
+	ui.Success("Token created: %s (%s)", resp.Token.ID, resp.Token.Name)
+	fmt.Printf("  Scopes:  %s\n", strings.Join(resp.Token.Scopes, ", "))
+	fmt.Printf("  Agents:  %s\n", tokenAgentsLabel(resp.Token.Agents))
+	fmt.Printf("  Expires: %s\n", resp.Token.ExpiresAt)
+	fmt.Println()
+	fmt.Printf("  Token: %s\n", ui.Bold(resp.Secret))
+	ui.Warning("Save the token now. It will not be shown again.")
+	ui.Info("Use it in CI by setting %s", config.TokenEnv)
+
+	return nil
+}
+
+// tokenAgentsLabel describes which agents a token works for
+func tokenAgentsLabel(agents []string) string {
+	if len(agents) == 0 {
+		return "all"
+	}
+	return strings.Join(agents, ", ")
+}
+
+func runTokensList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.ListTokens()
+	if err != nil {
+		ui.Error("Failed to list tokens: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Tokens)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(resp.Tokens))
+		for _, t := range resp.Tokens {
+			rows = append(rows, []string{t.ID, t.Name, strings.Join(t.Scopes, ","), strings.Join(t.Agents, ","), t.ExpiresAt, stringValue(t.LastUsedAt)})
+		}
+		return ui.Table([]string{"id", "name", "scopes", "agents", "expires", "last_used"}, rows)
+	}
+
+	if len(resp.Tokens) == 0 {
+		ui.Info("No service tokens. Create one with 'oken tokens create'.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tSCOPES\tAGENTS\tEXPIRES\tLAST USED")
+	for _, t := range resp.Tokens {
+		lastUsed := "never"
+		if t.LastUsedAt != nil && *t.LastUsedAt != "" {
+			lastUsed = dateOnly(*t.LastUsedAt)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.Name, strings.Join(t.Scopes, ","), tokenAgentsLabel(t.Agents), dateOnly(t.ExpiresAt), lastUsed)
+	}
+	_ = w.Flush()
+
+	return nil
+}
+
+func runTokensRevoke(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	if _, err := client.RevokeToken(id); err != nil {
+		ui.Error("Failed to revoke token: %v", err)
+		return err
+	}
+
+	ui.Success("Token revoked: %s", id)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+// topWindow is the metrics window used for rates shown by oken top
+const topWindow = "5m"
+
+var topInterval time.Duration
+
+var topCmd = &cobra.Command{
+	Use:         "top",
+	Short:       "Live resource monitor for running agents",
+	Annotations: textOutput,
+	Long: `Show CPU, memory and active invocations for all running agents,
+refreshing until interrupted. Prints a single snapshot when stdout is not a terminal.`,
+	Args: cobra.NoArgs,
+	RunE: runTop,
+}
+
+func init() {
+	topCmd.Flags().DurationVar(&topInterval, "interval", 2*time.Second, "Refresh interval")
+	rootCmd.AddCommand(topCmd)
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	if topInterval < time.Second {
+		ui.Error("Refresh interval must be at least 1s")
+		return fmt.Errorf("invalid interval")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	if !ui.IsTerminal() {
+		resp, err := client.ListAgentMetrics(topWindow)
+		if err != nil {
+			ui.Error("Failed to get metrics: %v", err)
+			return err
+		}
+		renderTop(resp.Agents)
+		return nil
+	}
+
+	// Handle Ctrl+C
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(topInterval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := client.ListAgentMetrics(topWindow)
+		ui.ClearScreen()
+		fmt.Printf("oken top - %s (every %s, Ctrl+C to quit)\n\n", time.Now().Format("15:04:05"), topInterval)
+		if err != nil {
+			ui.Error("Failed to get metrics: %v", err)
+		} else {
+			renderTop(resp.Agents)
+		}
+
+		select {
+		case <-sigChan:
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// renderTop prints one frame of the monitor, busiest agents first
+func renderTop(agents []api.AgentMetrics) {
+	if len(agents) == 0 {
+		ui.Info("No running agents")
+		return
+	}
+
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].CPUPercent > agents[j].CPUPercent
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "AGENT\tCPU\tMEMORY\tACTIVE\tINVOCATIONS\tERRORS\tP95")
+	for _, m := range agents {
+		_, _ = fmt.Fprintf(w, "%s\t%.1f%%\t%s\t%d\t%d\t%.1f%%\t%.0fms\n",
+			m.Slug, m.CPUPercent, ui.Bytes(m.MemoryBytes), m.ActiveInvocations,
+			m.Invocations, m.ErrorRate*100, m.LatencyP95Ms)
+	}
+	_ = w.Flush()
+}
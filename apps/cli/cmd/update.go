@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	updateMinInstances  int
+	updateAutosleep     string
+	updateRestartPolicy string
+	updateDescription   string
+	updateRepository    string
+	updateOwner         string
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update <slug>",
+	Short: "Change agent settings and metadata",
+	Long: `Change an agent's lifecycle settings and metadata without redeploying it.
+
+--min-instances is how many instances are kept running while the agent is
+idle; 0 lets it scale to zero. --autosleep stops idle instances after a
+duration such as 15m, or never with 'off'. --restart-policy restarts
+instances whenever they exit (always), only when they crash (on-failure), or
+never. Settings not given are kept.
+
+--description, --repository and --owner change what 'oken status' shows
+about the agent; pass an empty value to clear one.
+
+The same settings can be set in oken.toml, which applies them on every
+deploy.
+
+Examples:
+  oken update my-agent --min-instances 1
+  oken update my-agent --autosleep 15m --restart-policy on-failure
+  oken update my-agent --min-instances 0 --autosleep off
+  oken update my-agent --owner ml-team@acme.com --description ""`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUpdate,
+}
+
+func init() {
+	updateCmd.Flags().IntVar(&updateMinInstances, "min-instances", 0, "Instances kept running while idle, 0 to scale to zero")
+	updateCmd.Flags().StringVar(&updateAutosleep, "autosleep", "", "Stop idle instances after this duration (e.g. 15m), or 'off'")
+	updateCmd.Flags().StringVar(&updateRestartPolicy, "restart-policy", "", "Restart policy: "+strings.Join(api.RestartPolicies, ", "))
+	updateCmd.Flags().StringVar(&updateDescription, "description", "", "What the agent is for")
+	updateCmd.Flags().StringVar(&updateRepository, "repository", "", "http(s) URL of the agent's source code")
+	updateCmd.Flags().StringVar(&updateOwner, "owner", "", "Who to contact about the agent, such as a team email")
+	rootCmd.AddCommand(updateCmd)
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	lifecycle := api.Lifecycle{Autosleep: updateAutosleep, RestartPolicy: updateRestartPolicy}
+	if cmd.Flags().Changed("min-instances") {
+		lifecycle.MinInstances = &updateMinInstances
+	}
+	req := api.UpdateAgentRequest{Lifecycle: &lifecycle}
+	if lifecycle.IsZero() {
+		req.Lifecycle = nil
+	}
+	// Changed rather than non-empty, so an empty value clears the field
+	if cmd.Flags().Changed("description") {
+		req.Description = &updateDescription
+	}
+	if cmd.Flags().Changed("repository") {
+		req.Repository = &updateRepository
+	}
+	if cmd.Flags().Changed("owner") {
+		req.Owner = &updateOwner
+	}
+
+	if req.IsZero() {
+		ui.Error("Nothing to change. Use --min-instances, --autosleep, --restart-policy, --description, --repository or --owner.")
+		return fmt.Errorf("no update options")
+	}
+	if err := req.Validate(); err != nil {
+		ui.Error("%v", err)
+		return fmt.Errorf("invalid flags")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	ui.Info("Updating agent %s...", slug)
+
+	resp, err := client.UpdateAgent(slug, req)
+	if err != nil {
+		err = client.WithSuggestions(slug, err)
+		ui.Error("Failed to update agent: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Agent)
+	}
+
+	ui.Success("Agent updated: %s", resp.Agent.Slug)
+	if req.Description != nil && stringValue(resp.Agent.Description) != "" {
+		fmt.Printf("  Description: %s\n", *resp.Agent.Description)
+	}
+	if req.Repository != nil && stringValue(resp.Agent.Repository) != "" {
+		fmt.Printf("  Repository:  %s\n", *resp.Agent.Repository)
+	}
+	if req.Owner != nil && stringValue(resp.Agent.Owner) != "" {
+		fmt.Printf("  Owner:       %s\n", *resp.Agent.Owner)
+	}
+	if req.Lifecycle != nil {
+		printLifecycle(&resp.Agent, "  ")
+	}
+
+	return nil
+}
+
+// printLifecycle prints the min instances, autosleep and restart policy of
+// an agent
+func printLifecycle(agent *api.Agent, indent string) {
+	l := agent.Lifecycle
+	if l == nil {
+		return
+	}
+	if l.MinInstances != nil {
+		fmt.Printf("%sInstances:   at least %d\n", indent, *l.MinInstances)
+	}
+	switch l.Autosleep {
+	case "":
+	case api.AutosleepOff:
+		fmt.Printf("%sAutosleep:   off\n", indent)
+	default:
+		fmt.Printf("%sAutosleep:   after %s idle\n", indent, l.Autosleep)
+	}
+	if l.RestartPolicy != "" {
+		fmt.Printf("%sRestart:     %s\n", indent, l.RestartPolicy)
+	}
+}
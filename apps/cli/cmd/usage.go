@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+// usageWarnThreshold is the quota fraction at which a warning is shown
+const usageWarnThreshold = 0.8
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show account usage and quotas",
+	Long: `Show invocations, compute time and storage for the current billing period,
+per agent and against your plan's quotas.
+
+Examples:
+  oken usage
+  oken usage --output json`,
+	Args: cobra.NoArgs,
+	RunE: runUsage,
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	usage, err := client.GetUsage()
+	if err != nil {
+		ui.Error("Failed to get usage: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(usage)
+	}
+
+	fmt.Printf("Plan:    %s\n", usage.Plan)
+	if usage.PeriodStart != "" && usage.PeriodEnd != "" {
+		fmt.Printf("Period:  %s to %s\n", dateOnly(usage.PeriodStart), dateOnly(usage.PeriodEnd))
+	}
+	fmt.Println()
+
+	quotas := []struct {
+		name   string
+		quota  api.Quota
+		format func(int64) string
+	}{
+		{"Invocations", usage.Invocations, formatCount},
+		{"Compute", usage.ComputeSeconds, formatSeconds},
+		{"Storage", usage.StorageBytes, ui.Bytes},
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "RESOURCE\tUSED\tLIMIT\tPERCENT")
+	for _, q := range quotas {
+		limit, percent := "unlimited", "-"
+		if q.quota.Limit > 0 {
+			limit = q.format(q.quota.Limit)
+			percent = fmt.Sprintf("%.0f%%", q.quota.Fraction()*100)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", q.name, q.format(q.quota.Used), limit, percent)
+	}
+	_ = w.Flush()
+
+	if len(usage.Agents) > 0 {
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "AGENT\tINVOCATIONS\tCOMPUTE\tSTORAGE")
+		for _, a := range usage.Agents {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", a.Slug, formatCount(a.Invocations), formatSeconds(a.ComputeSeconds), ui.Bytes(a.StorageBytes))
+		}
+		_ = w.Flush()
+	}
+
+	warned := false
+	for _, q := range quotas {
+		if q.quota.Fraction() >= usageWarnThreshold {
+			if !warned {
+				fmt.Println()
+				warned = true
+			}
+			ui.Warning("%s at %.0f%% of plan limit", q.name, q.quota.Fraction()*100)
+		}
+	}
+
+	return nil
+}
+
+// dateOnly trims an ISO timestamp to its date part
+func dateOnly(ts string) string {
+	if len(ts) > 10 {
+		return ts[:10]
+	}
+	return ts
+}
+
+func formatCount(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+// formatSeconds formats compute seconds as hours or minutes when large enough
+func formatSeconds(s int64) string {
+	switch {
+	case s >= 3600:
+		return fmt.Sprintf("%.1fh", float64(s)/3600)
+	case s >= 60:
+		return fmt.Sprintf("%.1fm", float64(s)/60)
+	default:
+		return fmt.Sprintf("%ds", s)
+	}
+}
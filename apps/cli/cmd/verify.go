@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/sign"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	verifyDeployment string
+	verifyKey        string
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <slug>",
+	Short: "Check the signature of a deployed package",
+	Long: `Download the package of a deployment, check that its digest matches the
+signature made with 'oken deploy --sign', and that the signature was made
+with a trusted key.
+
+The trusted key is the local signing key by default. To verify deployments
+signed by someone else, pass --key with their public key, which they can
+export with 'openssl pkey -in signing.key -pubout'.
+
+Examples:
+  oken verify my-agent
+  oken verify my-agent --deployment dep_abc123 --key release.pub`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVerify,
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyDeployment, "deployment", "", "Deployment ID (default: current deployment)")
+	verifyCmd.Flags().StringVar(&verifyKey, "key", "", "Trusted public or private key file (default: the local signing key)")
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// verifyResult is printed by 'oken verify -o json'
+type verifyResult struct {
+	Slug        string `json:"slug"`
+	Deployment  string `json:"deployment,omitempty"`
+	Digest      string `json:"digest"`
+	Fingerprint string `json:"fingerprint"`
+	Verified    bool   `json:"verified"`
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	keyPath := verifyKey
+	if keyPath == "" {
+		var err error
+		if keyPath, err = config.SigningKeyPath(); err != nil {
+			ui.Error("Failed to locate signing key: %v", err)
+			return err
+		}
+	}
+	trusted, err := sign.LoadPublicKey(keyPath)
+	if err != nil {
+		ui.Error("Failed to load trusted key: %v", err)
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	signature, err := client.GetSignature(slug, verifyDeployment)
+	if api.IsNotFound(err) {
+		ui.Error("%s has no signature. Deploy it with 'oken deploy --sign'.", slug)
+		return err
+	}
+	if err != nil {
+		ui.Error("Failed to get signature: %v", err)
+		return err
+	}
+	if signature.Algorithm != sign.Algorithm {
+		ui.Error("Unsupported signature algorithm %q", signature.Algorithm)
+		return fmt.Errorf("unsupported signature")
+	}
+
+	digest, err := downloadDigest(client, slug, verifyDeployment)
+	if err != nil {
+		ui.Error("Failed to download package: %v", err)
+		return err
+	}
+
+	if digest != signature.Digest {
+		ui.Error("Deployed package sha256:%s doesn't match the signed digest sha256:%s", digest, signature.Digest)
+		return fmt.Errorf("digest mismatch")
+	}
+	if err := sign.Verify(trusted, digest, signature.Value); err != nil {
+		ui.Error("Signature is not valid for %s: %v", sign.Fingerprint(trusted), err)
+		if signer, err := sign.ParsePublicKey([]byte(signature.PublicKey)); err == nil {
+			ui.Info("The package was signed by %s.", sign.Fingerprint(signer))
+		}
+		return fmt.Errorf("invalid signature")
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(verifyResult{
+			Slug:        slug,
+			Deployment:  verifyDeployment,
+			Digest:      digest,
+			Fingerprint: sign.Fingerprint(trusted),
+			Verified:    true,
+		})
+	}
+
+	ui.Success("Package of %s is signed by %s", slug, sign.Fingerprint(trusted))
+	fmt.Printf("  Digest: sha256:%s\n", digest)
+	return nil
+}
+
+// downloadDigest returns the hex SHA-256 of a deployment's package
+func downloadDigest(client *api.Client, slug, deploymentID string) (string, error) {
+	body, err := client.DownloadSource(slug, deploymentID)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = body.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+var (
+	warmInterval time.Duration
+	warmFor      time.Duration
+)
+
+var warmCmd = &cobra.Command{
+	Use:   "warm <slug>",
+	Short: "Keep an agent warm by pinging it",
+	Long: `Ping an agent's health endpoint on a schedule so it stays warm, avoiding
+cold-start latency during demos and load tests.
+
+Pings go through the platform, like 'oken health', every --interval until
+--for has passed, or until Ctrl+C with --for 0. Each ping is printed with its
+latency, and a summary is shown at the end. Pings that fail are reported and
+warming continues; the command fails only if every ping failed.
+
+To keep an agent warm without leaving a terminal open, set a minimum number
+of instances with 'oken update --min-instances' instead.
+
+Examples:
+  oken warm my-agent
+  oken warm my-agent --interval 5m --for 2h
+  oken warm my-agent --interval 30s --for 0`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWarm,
+}
+
+func init() {
+	warmCmd.Flags().DurationVar(&warmInterval, "interval", 5*time.Minute, "Time between pings")
+	warmCmd.Flags().DurationVar(&warmFor, "for", time.Hour, "How long to keep the agent warm, 0 until interrupted")
+	rootCmd.AddCommand(warmCmd)
+}
+
+// warmSummary is the outcome of a warm session
+type warmSummary struct {
+	Slug         string `json:"slug"`
+	Pings        int    `json:"pings"`
+	Failures     int    `json:"failures"`
+	ColdStarts   int    `json:"coldStarts"`
+	AvgLatencyMs int64  `json:"avgLatencyMs"`
+}
+
+func runWarm(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	if warmInterval < time.Second {
+		ui.Error("Interval must be at least 1s")
+		return fmt.Errorf("invalid interval")
+	}
+	if warmFor < 0 {
+		ui.Error("Invalid --for %s. Use a positive duration, or 0 to run until interrupted.", warmFor)
+		return fmt.Errorf("invalid flags")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	if _, err := client.GetAgent(slug); err != nil {
+		err = client.WithSuggestions(slug, err)
+		ui.Error("Failed to get agent: %v", err)
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if warmFor > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, warmFor)
+		defer cancelTimeout()
+		ui.Info("Warming %s every %s for %s (Ctrl+C to stop)", slug, warmInterval, warmFor)
+	} else {
+		ui.Info("Warming %s every %s (Ctrl+C to stop)", slug, warmInterval)
+	}
+
+	summary := warmSummary{Slug: slug}
+	var totalLatency int64
+	ticker := time.NewTicker(warmInterval)
+	defer ticker.Stop()
+
+	for {
+		health, err := client.CheckHealth(slug)
+		summary.Pings++
+		if health != nil && health.Healthy {
+			totalLatency += health.LatencyMs
+			if health.ColdStart {
+				summary.ColdStarts++
+			}
+		} else {
+			summary.Failures++
+		}
+		printWarmPing(health, err)
+
+		select {
+		case <-ctx.Done():
+			return finishWarm(summary, totalLatency)
+		case <-ticker.C:
+		}
+	}
+}
+
+// printWarmPing reports the outcome of one ping
+func printWarmPing(health *api.HealthResponse, err error) {
+	now := time.Now().Format("15:04:05")
+	switch {
+	case err != nil:
+		ui.Error("%s ping failed: %v", now, err)
+	case !health.Healthy && health.Error != "":
+		ui.Error("%s unhealthy: %s", now, health.Error)
+	case !health.Healthy:
+		ui.Error("%s unhealthy", now)
+	case health.ColdStart:
+		ui.Warning("%s %dms, cold start", now, health.LatencyMs)
+	default:
+		ui.Success("%s %dms", now, health.LatencyMs)
+	}
+}
+
+// finishWarm prints the summary of a warm session and fails if no ping
+// succeeded
+func finishWarm(summary warmSummary, totalLatency int64) error {
+	if ok := summary.Pings - summary.Failures; ok > 0 {
+		summary.AvgLatencyMs = totalLatency / int64(ok)
+	}
+
+	if ui.IsStructured() {
+		if err := ui.Result(summary); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		fmt.Printf("Pings:       %d (%d failed)\n", summary.Pings, summary.Failures)
+		fmt.Printf("Cold starts: %d\n", summary.ColdStarts)
+		fmt.Printf("Avg latency: %dms\n", summary.AvgLatencyMs)
+	}
+
+	if summary.Failures == summary.Pings {
+		return fmt.Errorf("every ping failed")
+	}
+	return nil
+}
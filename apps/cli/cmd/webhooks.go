@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/neult/oken/apps/cli/internal/config"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+// webhookEvents are the lifecycle events a webhook can subscribe to
+var webhookEvents = []string{
+	"deploy.succeeded",
+	"deploy.failed",
+	"invocation.succeeded",
+	"invocation.failed",
+	"agent.stopped",
+}
+
+var (
+	webhookURL    string
+	webhookEvent  []string
+	webhooksAgent string
+)
+
+var webhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "Manage webhooks",
+	Long:  "Manage webhooks that receive deploy and invocation events for your agents.",
+}
+
+var webhooksCreateCmd = &cobra.Command{
+	Use:   "create <slug>",
+	Short: "Create a webhook",
+	Long: `Create a webhook that receives events for an agent.
+
+Events: ` + strings.Join(webhookEvents, ", ") + `
+
+Examples:
+  oken webhooks create my-agent --url https://example.com/hook --event invocation.failed
+  oken webhooks create my-agent --url https://example.com/hook --event deploy.succeeded --event deploy.failed`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWebhooksCreate,
+}
+
+var webhooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhooks",
+	Long: `List all webhooks. Use --agent to filter by agent.
+
+Examples:
+  oken webhooks list
+  oken webhooks list --agent my-agent`,
+	Args: cobra.NoArgs,
+	RunE: runWebhooksList,
+}
+
+var webhooksDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Delete a webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWebhooksDelete,
+}
+
+var webhooksTestCmd = &cobra.Command{
+	Use:   "test <id>",
+	Short: "Send a test event to a webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWebhooksTest,
+}
+
+func init() {
+	webhooksCreateCmd.Flags().StringVar(&webhookURL, "url", "", "URL that receives events (required)")
+	webhooksCreateCmd.Flags().StringSliceVarP(&webhookEvent, "event", "e", nil, "Event to subscribe to (repeatable, required)")
+	_ = webhooksCreateCmd.MarkFlagRequired("url")
+	_ = webhooksCreateCmd.MarkFlagRequired("event")
+
+	webhooksListCmd.Flags().StringVarP(&webhooksAgent, "agent", "a", "", "Filter by agent slug")
+
+	webhooksCmd.AddCommand(webhooksCreateCmd)
+	webhooksCmd.AddCommand(webhooksListCmd)
+	webhooksCmd.AddCommand(webhooksDeleteCmd)
+	webhooksCmd.AddCommand(webhooksTestCmd)
+
+	rootCmd.AddCommand(webhooksCmd)
+}
+
+func runWebhooksCreate(cmd *cobra.Command, args []string) error {
+	slug := args[0]
+
+	for _, event := range webhookEvent {
+		if !slices.Contains(webhookEvents, event) {
+			ui.Error("Unknown event '%s'. Valid events: %s", event, strings.Join(webhookEvents, ", "))
+			return fmt.Errorf("unknown event")
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.CreateWebhook(slug, webhookURL, webhookEvent)
+	if err != nil {
+		ui.Error("Failed to create webhook: %v", err)
+		return err
+	}
+
+	ui.Success("Webhook created: %s (agent: %s)", resp.Webhook.ID, slug)
+	fmt.Printf("  URL:    %s\n", resp.Webhook.URL)
+	fmt.Printf("  Events: %s\n", strings.Join(resp.Webhook.Events, ", "))
+	if resp.Secret != "" {
+		fmt.Println()
+		fmt.Printf("  Signing secret: %s\n", ui.Bold(resp.Secret))
+		ui.Warning("Save the signing secret now. It will not be shown again.")
+	}
+
+	return nil
+}
+
+func runWebhooksList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	resp, err := client.ListWebhooks(webhooksAgent)
+	if err != nil {
+		ui.Error("Failed to list webhooks: %v", err)
+		return err
+	}
+
+	if ui.IsStructured() {
+		return ui.Result(resp.Webhooks)
+	}
+	if ui.IsTabular() {
+		rows := make([][]string, 0, len(resp.Webhooks))
+		for _, wh := range resp.Webhooks {
+			rows = append(rows, []string{wh.ID, wh.AgentSlug, wh.URL, strings.Join(wh.Events, ",")})
+		}
+		return ui.Table([]string{"id", "agent", "url", "events"}, rows)
+	}
+
+	if len(resp.Webhooks) == 0 {
+		ui.Info("No webhooks found. Create one with 'oken webhooks create'.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tAGENT\tURL\tEVENTS")
+	for _, wh := range resp.Webhooks {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", wh.ID, wh.AgentSlug, wh.URL, strings.Join(wh.Events, ","))
+	}
+	_ = w.Flush()
+
+	return nil
+}
+
+func runWebhooksDelete(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	if _, err := client.DeleteWebhook(id); err != nil {
+		ui.Error("Failed to delete webhook: %v", err)
+		return err
+	}
+
+	ui.Success("Webhook deleted: %s", id)
+
+	return nil
+}
+
+func runWebhooksTest(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		ui.Error("Failed to load config: %v", err)
+		return err
+	}
+
+	if cfg.Token == "" {
+		ui.Error("Not logged in. Run 'oken login' first.")
+		return fmt.Errorf("not authenticated")
+	}
+
+	client := newClient(cfg)
+
+	ui.Info("Sending test event to webhook %s...", id)
+
+	resp, err := client.TestWebhook(id)
+	if err != nil {
+		ui.Error("Failed to test webhook: %v", err)
+		return err
+	}
+
+	if resp.Error != "" {
+		ui.Error("Delivery failed: %s", resp.Error)
+		return fmt.Errorf("delivery failed: %s", resp.Error)
+	}
+	if resp.StatusCode >= 300 {
+		ui.Error("Endpoint responded with status %d (%dms)", resp.StatusCode, resp.DurationMs)
+		return fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+
+	ui.Success("Delivered: status %d (%dms)", resp.StatusCode, resp.DurationMs)
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package alias expands user-defined shortcuts for oken commands, such as
+// "dp" for "deploy --wait".
+package alias
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidName reports whether name can be used as an alias: a single word that
+// isn't a flag
+func ValidName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, "-") && !strings.ContainsAny(name, " \t\n'\"\\")
+}
+
+// CommandIndex returns the index of the first argument in args that isn't a
+// flag or a flag's value, which is where a command or alias name would be,
+// or -1 if there is none. takesValue reports whether the flag with a long
+// name or shorthand is followed by a value, as in "-o json".
+func CommandIndex(args []string, takesValue func(name string) bool) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			// Everything after "--" is an argument, not a command
+			return -1
+		case strings.HasPrefix(arg, "--"):
+			if !strings.Contains(arg, "=") && takesValue(arg[2:]) {
+				i++
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			if len(arg) == 2 && takesValue(arg[1:]) {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return -1
+}
+
+// Expand replaces an alias in the first argument with its expansion, keeping
+// the rest of the arguments after it. Expansions aren't expanded again, so
+// aliases can't loop.
+func Expand(aliases map[string]string, args []string) ([]string, bool, error) {
+	if len(args) == 0 {
+		return args, false, nil
+	}
+	expansion, ok := aliases[args[0]]
+	if !ok {
+		return args, false, nil
+	}
+
+	words, err := Split(expansion)
+	if err != nil {
+		return nil, false, fmt.Errorf("alias %s: %w", args[0], err)
+	}
+	if len(words) == 0 {
+		return nil, false, fmt.Errorf("alias %s is empty", args[0])
+	}
+	return append(words, args[1:]...), true, nil
+}
+
+// Split breaks an expansion into arguments the way a POSIX shell would,
+// honoring single quotes, double quotes and backslashes, but without
+// variables or globs
+func Split(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("unfinished escape at the end of %q", s)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unclosed %c quote in %q", quote, s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,91 @@
+package alias
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"deploy --wait", []string{"deploy", "--wait"}},
+		{"  logs   -f\tmy-agent ", []string{"logs", "-f", "my-agent"}},
+		{`invoke my-agent -i '{"q": "hi there"}'`, []string{"invoke", "my-agent", "-i", `{"q": "hi there"}`}},
+		{`list --format "{{.Slug}} {{.Status}}"`, []string{"list", "--format", "{{.Slug}} {{.Status}}"}},
+		{`secrets set A=one\ two B=""`, []string{"secrets", "set", "A=one two", "B="}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	_, err := Split(`invoke -i '{"q": 1}`)
+	assert.Error(t, err)
+	_, err = Split(`deploy \`)
+	assert.Error(t, err)
+}
+
+func TestExpand(t *testing.T) {
+	aliases := map[string]string{
+		"dp":    "deploy --wait",
+		"loop":  "loop --again",
+		"empty": "",
+	}
+
+	args, ok, err := Expand(aliases, []string{"dp", "--name", "my-agent"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"deploy", "--wait", "--name", "my-agent"}, args)
+
+	args, ok, err = Expand(aliases, []string{"loop"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"loop", "--again"}, args)
+
+	args, ok, err = Expand(aliases, []string{"list", "dp"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"list", "dp"}, args)
+
+	_, _, err = Expand(aliases, []string{"empty"})
+	assert.Error(t, err)
+}
+
+func TestValidName(t *testing.T) {
+	assert.True(t, ValidName("dp"))
+	assert.True(t, ValidName("logs-prod"))
+	assert.False(t, ValidName(""))
+	assert.False(t, ValidName("-d"))
+	assert.False(t, ValidName("two words"))
+}
+
+func TestCommandIndex(t *testing.T) {
+	takesValue := func(name string) bool {
+		return name == "o" || name == "output" || name == "endpoint"
+	}
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"dp"}, 0},
+		{[]string{"dp", "-o", "json"}, 0},
+		{[]string{"-o", "json", "dp"}, 2},
+		{[]string{"-ojson", "dp"}, 1},
+		{[]string{"--output", "yaml", "--no-input", "dp", "x"}, 3},
+		{[]string{"--output=yaml", "dp"}, 1},
+		{[]string{"--endpoint", "https://oken.example", "dp"}, 2},
+		{[]string{"--no-input"}, -1},
+		{[]string{"-o"}, -1},
+		{[]string{"--", "dp"}, -1},
+		{nil, -1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CommandIndex(tt.args, takesValue), "%q", tt.args)
+	}
+}
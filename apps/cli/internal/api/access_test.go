@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEmail(t *testing.T) {
+	assert.NoError(t, validateEmail("user@example.com"))
+	assert.Error(t, validateEmail(""))
+	assert.Error(t, validateEmail("user"))
+	assert.Error(t, validateEmail("@example.com"))
+	assert.Error(t, validateEmail("user@"))
+	assert.Error(t, validateEmail("user name@example.com"))
+}
+
+func TestListAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/access", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(AccessListResponse{
+			Grants: []AccessGrant{{Email: "teammate@example.com", Role: "invoker"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListAccess("my-agent")
+	require.NoError(t, err)
+	require.Len(t, resp.Grants, 1)
+	assert.Equal(t, "invoker", resp.Grants[0].Role)
+}
+
+func TestShareAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/access", r.URL.Path)
+
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "teammate@example.com", body["email"])
+		assert.Equal(t, "invoker", body["role"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ShareResponse{
+			Grant:   AccessGrant{Email: body["email"], Role: body["role"]},
+			Message: "Access granted",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ShareAgent("my-agent", "teammate@example.com", "invoker")
+	require.NoError(t, err)
+	assert.Equal(t, "Access granted", resp.Message)
+}
+
+func TestShareAgentInvalidRole(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.ShareAgent("my-agent", "teammate@example.com", "owner")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid role")
+}
+
+func TestRevokeAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/access", r.URL.Path)
+		assert.Equal(t, "teammate+oken@example.com", r.URL.Query().Get("email"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RevokeResponse{Message: "Access revoked"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.RevokeAccess("my-agent", "teammate+oken@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Access revoked", resp.Message)
+}
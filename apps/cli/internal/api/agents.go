@@ -2,12 +2,17 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"net/url"
 	"regexp"
+	"slices"
+	"strings"
 )
 
 var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*[a-z0-9]$|^[a-z0-9]$`)
@@ -26,17 +31,126 @@ func validateSlug(slug string) error {
 	return nil
 }
 
+// validateEnvironment checks that an environment name follows the slug rules
+func validateEnvironment(env string) error {
+	if len(env) > 32 || !slugPattern.MatchString(env) {
+		return fmt.Errorf("invalid environment %q: must be up to 32 lowercase letters, numbers, and hyphens", env)
+	}
+	return nil
+}
+
+// ParseAgentRef splits an agent reference into its slug and environment. A
+// reference is a slug, optionally qualified with an environment as in
+// "my-agent@staging"; env is empty when it isn't.
+func ParseAgentRef(ref string) (slug, env string, err error) {
+	slug, env, qualified := strings.Cut(ref, "@")
+	if err := validateSlug(slug); err != nil {
+		return "", "", err
+	}
+	if qualified {
+		if err := validateEnvironment(env); err != nil {
+			return "", "", err
+		}
+	}
+	return slug, env, nil
+}
+
+// agentPath returns the API path of the agent ref names followed by suffix,
+// which may have a query. The environment of a qualified reference is sent as
+// the environment query parameter.
+func agentPath(ref, suffix string) (string, error) {
+	slug, env, err := ParseAgentRef(ref)
+	if err != nil {
+		return "", err
+	}
+	path := "/api/agents/" + slug + suffix
+	if env == "" {
+		return path, nil
+	}
+	if strings.Contains(path, "?") {
+		return path + "&environment=" + url.QueryEscape(env), nil
+	}
+	return path + "?environment=" + url.QueryEscape(env), nil
+}
+
+// Runtimes are the agent runtimes supported by the platform
+var Runtimes = []string{"python", "node"}
+
+// validateRuntime checks that a runtime is supported
+func validateRuntime(runtime string) error {
+	if !slices.Contains(Runtimes, runtime) {
+		return fmt.Errorf("invalid runtime %q: must be one of %s", runtime, strings.Join(Runtimes, ", "))
+	}
+	return nil
+}
+
+// BuildDocker marks a deployment that is built from the project's Dockerfile
+const BuildDocker = "docker"
+
+// Compressions are the codecs a deployed package may be compressed with;
+// gzip is assumed when none is sent
+var Compressions = []string{"gzip", "zstd"}
+
+// compressionTypes are the media types of packages by codec
+var compressionTypes = map[string]string{
+	"gzip": "application/gzip",
+	"zstd": "application/zstd",
+}
+
+// AgentStatus is the lifecycle state of an agent
+type AgentStatus string
+
+const (
+	AgentDeploying AgentStatus = "deploying"
+	AgentRunning   AgentStatus = "running"
+	AgentStopped   AgentStatus = "stopped"
+	AgentFailed    AgentStatus = "error"
+)
+
+// IsTerminal reports whether the agent has settled, so polling for a change
+// is pointless until the user acts
+func (s AgentStatus) IsTerminal() bool {
+	switch s {
+	case AgentRunning, AgentStopped, AgentFailed:
+		return true
+	}
+	return false
+}
+
+// IsHealthy reports whether the agent is up and serving requests
+func (s AgentStatus) IsHealthy() bool {
+	return s == AgentRunning
+}
+
 // Agent represents an agent from the platform
 type Agent struct {
-	ID            string  `json:"id"`
-	Name          string  `json:"name"`
-	Slug          string  `json:"slug"`
-	Status        string  `json:"status"`
-	Endpoint      *string `json:"endpoint"`
-	PythonVersion *string `json:"pythonVersion"`
-	Entrypoint    *string `json:"entrypoint"`
-	CreatedAt     string  `json:"createdAt"`
-	UpdatedAt     string  `json:"updatedAt"`
+	ID            string      `json:"id"`
+	Name          string      `json:"name"`
+	Slug          string      `json:"slug"`
+	Status        AgentStatus `json:"status"`
+	Environment   *string     `json:"environment"`
+	Endpoint      *string     `json:"endpoint"`
+	Runtime       *string     `json:"runtime"`
+	Build         *string     `json:"build"`
+	PythonVersion *string     `json:"pythonVersion"`
+	NodeVersion   *string     `json:"nodeVersion"`
+	Entrypoint    *string     `json:"entrypoint"`
+	Replicas      *int        `json:"replicas"`
+	Memory        *string     `json:"memory"`
+	CPU           *string     `json:"cpu"`
+	CreatedAt     string      `json:"createdAt"`
+	UpdatedAt     string      `json:"updatedAt"`
+	// Git is the commit the running deployment was built from, if known
+	Git *GitMetadata `json:"git,omitempty"`
+	// Labels are key=value tags for finding and grouping agents
+	Labels map[string]string `json:"labels,omitempty"`
+	// Description, Repository and Owner say what the agent is for, where its
+	// code lives and who to contact about it
+	Description *string `json:"description,omitempty"`
+	Repository  *string `json:"repository,omitempty"`
+	Owner       *string `json:"owner,omitempty"`
+	// Lifecycle holds the min instances, autosleep and restart policy
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
 }
 
 // AgentListResponse is returned when listing agents
@@ -44,6 +158,104 @@ type AgentListResponse struct {
 	Agents []Agent `json:"agents"`
 }
 
+// Resources describes the runtime sizing of an agent
+type Resources struct {
+	Memory  string `json:"memory,omitempty"`
+	CPU     string `json:"cpu,omitempty"`
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// GitMetadata records which commit a deployment was built from
+type GitMetadata struct {
+	Repository string `json:"repository,omitempty"`
+	Ref        string `json:"ref,omitempty"`
+	Commit     string `json:"commit"`
+	Branch     string `json:"branch,omitempty"`
+	// Dirty is true when the agent directory had uncommitted changes
+	Dirty bool `json:"dirty,omitempty"`
+}
+
+// ShortCommit returns the abbreviated commit SHA, as git shows it
+func (g *GitMetadata) ShortCommit() string {
+	if len(g.Commit) > 7 {
+		return g.Commit[:7]
+	}
+	return g.Commit
+}
+
+// Signature is a signature of a deployment's package digest
+type Signature struct {
+	Algorithm string `json:"algorithm"`
+	// PublicKey is the PEM-encoded key that made the signature
+	PublicKey string `json:"publicKey"`
+	// Digest is the hex SHA-256 of the package that was signed
+	Digest string `json:"digest"`
+	// Value is the base64-encoded signature
+	Value string `json:"signature"`
+}
+
+// DeployOptions holds optional settings sent with a deploy
+type DeployOptions struct {
+	Resources      *Resources
+	Lifecycle      *Lifecycle
+	Environment    string
+	Runtime        string
+	RuntimeVersion string
+	Build          string
+	// Compression is the tarball's codec, one of Compressions; empty means
+	// gzip
+	Compression string
+	// Checksum is the hex SHA-256 of the tarball, verified by the platform
+	Checksum string
+	// Message describes the release, like a commit message
+	Message string
+	// IdempotencyKey identifies the deploy across retries; generated if empty
+	IdempotencyKey string
+	// Git is recorded on the deployment when the source came from git
+	Git *GitMetadata
+	// Signature is stored with the deployment for 'oken verify'
+	Signature *Signature
+	// SBOM is a CycloneDX JSON document of the package's dependencies,
+	// retrieved with 'oken sbom'
+	SBOM []byte
+	// Labels are added to the agent, or change the values of existing ones
+	Labels map[string]string
+	// Metadata describes the agent; empty fields keep the agent's values
+	Metadata AgentMetadata
+}
+
+// AgentMetadata says what an agent is for, where its code lives and who owns
+// it
+type AgentMetadata struct {
+	Description string
+	// Repository is an http(s) URL of the agent's source code
+	Repository string
+	// Owner is a contact, such as a team email or chat channel
+	Owner string
+}
+
+const (
+	maxDescriptionLength = 500
+	maxOwnerLength       = 200
+)
+
+// Validate checks the lengths of the fields and that Repository is a URL
+func (m AgentMetadata) Validate() error {
+	if len(m.Description) > maxDescriptionLength {
+		return fmt.Errorf("description too long (max %d characters)", maxDescriptionLength)
+	}
+	if len(m.Owner) > maxOwnerLength {
+		return fmt.Errorf("owner too long (max %d characters)", maxOwnerLength)
+	}
+	if m.Repository != "" {
+		u, err := url.Parse(m.Repository)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid repository %q: must be an http or https URL", m.Repository)
+		}
+	}
+	return nil
+}
+
 // DeployResponse is returned when deploying an agent
 type DeployResponse struct {
 	Agent      Agent `json:"agent"`
@@ -53,10 +265,29 @@ type DeployResponse struct {
 	} `json:"deployment"`
 }
 
+// InvokeOptions holds optional invocation settings
+type InvokeOptions struct {
+	// SessionID lets stateful agents correlate the turns of a conversation
+	SessionID string
+	// Attachments are files sent alongside the input
+	Attachments []Attachment
+	// Trace asks the platform to record and return an execution trace
+	Trace bool
+	// Header holds extra headers the platform forwards to the agent, such
+	// as trace IDs or feature flags
+	Header http.Header
+}
+
 // InvokeResponse is returned when invoking an agent
 type InvokeResponse struct {
 	Output map[string]any `json:"output"`
 	Error  string         `json:"error,omitempty"`
+	// SessionID echoes the session the invocation belonged to
+	SessionID string `json:"sessionId,omitempty"`
+	// RequestID identifies the invocation in the agent's logs
+	RequestID string `json:"requestId,omitempty"`
+	// Trace is set when the invocation asked for one
+	Trace *Trace `json:"trace,omitempty"`
 }
 
 // StopResponse is returned when stopping an agent
@@ -65,6 +296,80 @@ type StopResponse struct {
 	Message string `json:"message"`
 }
 
+// RestartResponse is returned when restarting an agent
+type RestartResponse struct {
+	Agent   Agent  `json:"agent"`
+	Message string `json:"message"`
+}
+
+// ScaleRequest is the request body for scaling an agent
+type ScaleRequest struct {
+	Replicas *int    `json:"replicas,omitempty"`
+	Memory   *string `json:"memory,omitempty"`
+	CPU      *string `json:"cpu,omitempty"`
+}
+
+// ScaleResponse is returned when scaling an agent
+type ScaleResponse struct {
+	Agent   Agent  `json:"agent"`
+	Message string `json:"message"`
+}
+
+// UpdateAgentRequest is the request body for changing an agent's name,
+// metadata and lifecycle settings. Nil fields are kept; an empty string
+// clears a metadata field.
+type UpdateAgentRequest struct {
+	Name        *string    `json:"name,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Repository  *string    `json:"repository,omitempty"`
+	Owner       *string    `json:"owner,omitempty"`
+	Lifecycle   *Lifecycle `json:"lifecycle,omitempty"`
+}
+
+// IsZero reports whether the request changes nothing
+func (r UpdateAgentRequest) IsZero() bool {
+	return r.Name == nil && r.Description == nil && r.Repository == nil && r.Owner == nil && (r.Lifecycle == nil || r.Lifecycle.IsZero())
+}
+
+// Validate checks the metadata and lifecycle settings set in the request
+func (r UpdateAgentRequest) Validate() error {
+	if r.Name != nil && strings.TrimSpace(*r.Name) == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	metadata := AgentMetadata{}
+	if r.Description != nil {
+		metadata.Description = *r.Description
+	}
+	if r.Repository != nil {
+		metadata.Repository = *r.Repository
+	}
+	if r.Owner != nil {
+		metadata.Owner = *r.Owner
+	}
+	if err := metadata.Validate(); err != nil {
+		return err
+	}
+	if r.Lifecycle != nil {
+		return r.Lifecycle.Validate()
+	}
+	return nil
+}
+
+// UpdateAgentResponse is returned when updating an agent
+type UpdateAgentResponse struct {
+	Agent   Agent  `json:"agent"`
+	Message string `json:"message"`
+}
+
+// PromoteResponse is returned when promoting a deployment between environments
+type PromoteResponse struct {
+	Agent      Agent `json:"agent"`
+	Deployment struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	} `json:"deployment"`
+}
+
 // DeleteResponse is returned when deleting an agent
 type DeleteResponse struct {
 	Message string `json:"message"`
@@ -81,18 +386,32 @@ func (c *Client) ListAgents() (*AgentListResponse, error) {
 
 // GetAgent returns a single agent by slug
 func (c *Client) GetAgent(slug string) (*Agent, error) {
-	if err := validateSlug(slug); err != nil {
+	path, err := agentPath(slug, "")
+	if err != nil {
 		return nil, err
 	}
 	var resp Agent
-	if err := c.Get(fmt.Sprintf("/api/agents/%s", slug), &resp); err != nil {
+	if err := c.Get(path, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
+// InspectAgent returns an agent exactly as the platform sent it, including
+// fields the CLI doesn't model
+func (c *Client) InspectAgent(slug string) (json.RawMessage, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	var resp json.RawMessage
+	if err := c.Get(fmt.Sprintf("/api/agents/%s", slug), &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // DeployAgent deploys an agent with the given tarball
-func (c *Client) DeployAgent(name, slug string, tarball io.Reader) (*DeployResponse, error) {
+func (c *Client) DeployAgent(name, slug string, tarball io.Reader, opts DeployOptions) (*DeployResponse, error) {
 	if err := validateSlug(slug); err != nil {
 		return nil, err
 	}
@@ -105,13 +424,163 @@ func (c *Client) DeployAgent(name, slug string, tarball io.Reader) (*DeployRespo
 	if err := writer.WriteField("slug", slug); err != nil {
 		return nil, err
 	}
+	if opts.Environment != "" {
+		if err := validateEnvironment(opts.Environment); err != nil {
+			return nil, err
+		}
+		if err := writer.WriteField("environment", opts.Environment); err != nil {
+			return nil, err
+		}
+	}
+	if err := opts.Metadata.Validate(); err != nil {
+		return nil, err
+	}
+	for _, field := range [][2]string{
+		{"description", opts.Metadata.Description},
+		{"repository", opts.Metadata.Repository},
+		{"owner", opts.Metadata.Owner},
+	} {
+		if field[1] == "" {
+			continue
+		}
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Message != "" {
+		if err := writer.WriteField("message", opts.Message); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Build != "" {
+		if opts.Build != BuildDocker {
+			return nil, fmt.Errorf("invalid build mode %q: must be %s", opts.Build, BuildDocker)
+		}
+		if err := writer.WriteField("build", opts.Build); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Runtime != "" && opts.Build == "" {
+		if err := validateRuntime(opts.Runtime); err != nil {
+			return nil, err
+		}
+		if err := writer.WriteField("runtime", opts.Runtime); err != nil {
+			return nil, err
+		}
+		if opts.RuntimeVersion != "" {
+			if err := writer.WriteField("runtimeVersion", opts.RuntimeVersion); err != nil {
+				return nil, err
+			}
+		}
+	}
+	compression := opts.Compression
+	if compression == "" {
+		compression = "gzip"
+	}
+	if !slices.Contains(Compressions, compression) {
+		return nil, fmt.Errorf("invalid compression %q: must be one of %s", compression, strings.Join(Compressions, ", "))
+	}
+	if err := writer.WriteField("compression", compression); err != nil {
+		return nil, err
+	}
+	if opts.Checksum != "" {
+		if err := writer.WriteField("sha256", opts.Checksum); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Signature != nil {
+		signature, err := json.Marshal(opts.Signature)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.WriteField("signature", string(signature)); err != nil {
+			return nil, err
+		}
+	}
+	if opts.SBOM != nil {
+		part, err := writer.CreateFormFile("sbom", "sbom.cdx.json")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(opts.SBOM); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Git != nil {
+		git, err := json.Marshal(opts.Git)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.WriteField("git", string(git)); err != nil {
+			return nil, err
+		}
+	}
+	if len(opts.Labels) > 0 {
+		for key, value := range opts.Labels {
+			if err := validateLabel(key, value); err != nil {
+				return nil, err
+			}
+		}
+		labels, err := json.Marshal(opts.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.WriteField("labels", string(labels)); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Resources != nil {
+		resources, err := json.Marshal(opts.Resources)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.WriteField("resources", string(resources)); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Lifecycle != nil {
+		if err := opts.Lifecycle.Validate(); err != nil {
+			return nil, err
+		}
+		lifecycle, err := json.Marshal(opts.Lifecycle)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.WriteField("lifecycle", string(lifecycle)); err != nil {
+			return nil, err
+		}
+	}
 
-	part, err := writer.CreateFormFile("tarball", "agent.tar.gz")
+	data, err := io.ReadAll(tarball)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := io.Copy(part, tarball); err != nil {
-		return nil, err
+
+	// Large packages are uploaded separately and referenced by ID instead
+	// of being sent inline
+	if len(data) > largeUploadThreshold {
+		uploadID, err := c.uploadLarge(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.WriteField("upload", uploadID); err != nil {
+			return nil, err
+		}
+	} else {
+		filename := "agent.tar.gz"
+		if compression == "zstd" {
+			filename = "agent.tar.zst"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="tarball"; filename="%s"`, filename))
+		header.Set("Content-Type", compressionTypes[compression])
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(data); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := writer.Close(); err != nil {
@@ -123,16 +592,21 @@ func (c *Client) DeployAgent(name, slug string, tarball io.Reader) (*DeployRespo
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
+	idempotencyKey := opts.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = NewIdempotencyKey()
 	}
 
-	httpResp, err := c.UploadClient.Do(req)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set(IdempotencyHeader, idempotencyKey)
+	c.setHeaders(req)
+
+	httpResp, err := c.send(c.UploadClient, req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = httpResp.Body.Close() }()
+	c.noteDeprecation(httpResp)
 
 	respBody, err := io.ReadAll(httpResp.Body)
 	if err != nil {
@@ -140,21 +614,7 @@ func (c *Client) DeployAgent(name, slug string, tarball io.Reader) (*DeployRespo
 	}
 
 	if httpResp.StatusCode >= 400 {
-		var errResp struct {
-			Error string `json:"error"`
-			Code  string `json:"code"`
-		}
-		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Error != "" {
-			return nil, &APIError{
-				StatusCode: httpResp.StatusCode,
-				Message:    errResp.Error,
-				Code:       errResp.Code,
-			}
-		}
-		return nil, &APIError{
-			StatusCode: httpResp.StatusCode,
-			Message:    fmt.Sprintf("request failed with status %d", httpResp.StatusCode),
-		}
+		return nil, decodeAPIError(httpResp.StatusCode, respBody)
 	}
 
 	var resp DeployResponse
@@ -167,11 +627,76 @@ func (c *Client) DeployAgent(name, slug string, tarball io.Reader) (*DeployRespo
 
 // StopAgent stops a running agent
 func (c *Client) StopAgent(slug string) (*StopResponse, error) {
-	if err := validateSlug(slug); err != nil {
+	path, err := agentPath(slug, "/stop")
+	if err != nil {
 		return nil, err
 	}
 	var resp StopResponse
-	if err := c.Post(fmt.Sprintf("/api/agents/%s/stop", slug), nil, &resp); err != nil {
+	if err := c.Post(path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RestartAgent restarts an agent so it picks up changed secrets and
+// environment variables
+func (c *Client) RestartAgent(slug string) (*RestartResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	var resp RestartResponse
+	if err := c.Post(fmt.Sprintf("/api/agents/%s/restart", slug), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ScaleAgent updates the replica count and resource limits of an agent
+func (c *Client) ScaleAgent(slug string, req ScaleRequest) (*ScaleResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	var resp ScaleResponse
+	if err := c.Post(fmt.Sprintf("/api/agents/%s/scale", slug), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateAgent changes an agent's metadata and lifecycle settings without
+// redeploying it. Fields not set in req are kept.
+func (c *Client) UpdateAgent(slug string, req UpdateAgentRequest) (*UpdateAgentResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var resp UpdateAgentResponse
+	if err := c.Patch(fmt.Sprintf("/api/agents/%s", slug), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PromoteAgent deploys the source currently running in one environment to another
+func (c *Client) PromoteAgent(slug, from, to string) (*PromoteResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	if err := validateEnvironment(from); err != nil {
+		return nil, err
+	}
+	if err := validateEnvironment(to); err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, fmt.Errorf("source and target environment are the same")
+	}
+	body := map[string]string{"from": from, "to": to}
+	var resp PromoteResponse
+	if err := c.Post(fmt.Sprintf("/api/agents/%s/promote", slug), body, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -190,16 +715,15 @@ func (c *Client) DeleteAgent(slug string) (*DeleteResponse, error) {
 }
 
 // InvokeAgent invokes an agent with the given input
-func (c *Client) InvokeAgent(slug string, input map[string]any) (*InvokeResponse, error) {
-	if err := validateSlug(slug); err != nil {
+func (c *Client) InvokeAgent(slug string, input map[string]any, opts InvokeOptions) (*InvokeResponse, error) {
+	if _, _, err := ParseAgentRef(slug); err != nil {
 		return nil, err
 	}
-	body := map[string]any{"input": input}
-	var resp InvokeResponse
-	if err := c.Post(fmt.Sprintf("/api/agents/%s/invoke", slug), body, &resp); err != nil {
+	payload, err := c.encodeInvoke(input, opts)
+	if err != nil {
 		return nil, err
 	}
-	return &resp, nil
+	return c.sendInvoke(slug, payload, NewIdempotencyKey())
 }
 
 // LogsResponse is returned when fetching agent logs
@@ -207,22 +731,154 @@ type LogsResponse struct {
 	Logs string `json:"logs"`
 }
 
-// GetAgentLogs fetches logs from a running agent
-func (c *Client) GetAgentLogs(slug string, tail int) (*LogsResponse, error) {
-	if err := validateSlug(slug); err != nil {
+// GetAgentLogs fetches logs from a running agent. If requestID is set, only
+// the lines logged while handling that request are returned.
+func (c *Client) GetAgentLogs(slug string, tail int, requestID string) (*LogsResponse, error) {
+	path, err := agentPath(slug, "/logs?"+logsQuery(tail, requestID))
+	if err != nil {
 		return nil, err
 	}
 	var resp LogsResponse
-	if err := c.Get(fmt.Sprintf("/api/agents/%s/logs?tail=%d", slug, tail), &resp); err != nil {
+	if err := c.Get(path, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
-// GetAgentLogsStreamURL returns the URL for streaming logs
-func (c *Client) GetAgentLogsStreamURL(slug string, tail int) (string, error) {
+// GetAgentLogsStreamURL returns the URL for streaming logs, optionally only
+// those of one request
+func (c *Client) GetAgentLogsStreamURL(slug string, tail int, requestID string) (string, error) {
+	path, err := agentPath(slug, "/logs?follow=true&"+logsQuery(tail, requestID))
+	if err != nil {
+		return "", err
+	}
+	return c.BaseURL + path, nil
+}
+
+// StreamAgentLogs opens a stream of an agent's logs as server-sent events,
+// optionally only those of one request. The caller closes the stream, which
+// ends when ctx is cancelled.
+func (c *Client) StreamAgentLogs(ctx context.Context, slug string, tail int, requestID string) (io.ReadCloser, error) {
+	url, err := c.GetAgentLogsStreamURL(slug, tail, requestID)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	c.setHeaders(req)
+
+	// Logs are followed until ctx cancels, so no client timeout
+	resp, err := c.send(c.StreamClient, req)
+	if err != nil {
+		return nil, err
+	}
+	c.noteDeprecation(resp)
+
+	if resp.StatusCode >= 400 {
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if err != nil {
+			return nil, err
+		}
+		return nil, decodeAPIError(resp.StatusCode, body)
+	}
+	return resp.Body, nil
+}
+
+// logsQuery builds the query parameters of a logs request
+func logsQuery(tail int, requestID string) string {
+	query := fmt.Sprintf("tail=%d", tail)
+	if requestID != "" {
+		query += "&request=" + url.QueryEscape(requestID)
+	}
+	return query
+}
+
+// maxErrorBodySize caps how much of an error response is read when downloading
+const maxErrorBodySize = 1 << 20
+
+// DownloadSource returns the deployed tarball for an agent. If deploymentID is
+// empty, the currently running deployment is used. The caller must close the reader.
+func (c *Client) DownloadSource(slug, deploymentID string) (io.ReadCloser, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/api/agents/%s/source", slug)
+	if deploymentID != "" {
+		path = fmt.Sprintf("%s?deployment=%s", path, url.QueryEscape(deploymentID))
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/gzip")
+	c.setHeaders(req)
+
+	resp, err := c.send(c.UploadClient, req)
+	if err != nil {
+		return nil, err
+	}
+	c.noteDeprecation(resp)
+
+	if resp.StatusCode >= 400 {
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if err != nil {
+			return nil, err
+		}
+		return nil, decodeAPIError(resp.StatusCode, body)
+	}
+
+	return resp.Body, nil
+}
+
+// GetSignature returns the signature stored with an agent's deployment. If
+// deploymentID is empty, the currently running deployment is used.
+func (c *Client) GetSignature(slug, deploymentID string) (*Signature, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/api/agents/%s/signature", slug)
+	if deploymentID != "" {
+		path = fmt.Sprintf("%s?deployment=%s", path, url.QueryEscape(deploymentID))
+	}
+
+	var resp Signature
+	if err := c.Get(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetSBOM returns the CycloneDX SBOM stored with an agent's deployment. If
+// deploymentID is empty, the currently running deployment is used.
+func (c *Client) GetSBOM(slug, deploymentID string) (json.RawMessage, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/api/agents/%s/sbom", slug)
+	if deploymentID != "" {
+		path = fmt.Sprintf("%s?deployment=%s", path, url.QueryEscape(deploymentID))
+	}
+
+	var resp json.RawMessage
+	if err := c.Get(path, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DashboardURL returns the web dashboard URL for an agent, or the dashboard home if slug is empty
+func (c *Client) DashboardURL(slug string) (string, error) {
+	if slug == "" {
+		return c.BaseURL + "/", nil
+	}
 	if err := validateSlug(slug); err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("%s/api/agents/%s/logs?follow=true&tail=%d", c.BaseURL, slug, tail), nil
+	return fmt.Sprintf("%s/agents/%s", c.BaseURL, slug), nil
 }
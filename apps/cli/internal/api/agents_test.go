@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -107,7 +109,71 @@ func TestGetAgent(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "123", agent.ID)
 	assert.Equal(t, "My Agent", agent.Name)
-	assert.Equal(t, "running", agent.Status)
+	assert.Equal(t, AgentRunning, agent.Status)
+}
+
+func TestParseAgentRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		slug string
+		env  string
+	}{
+		{"my-agent", "my-agent", ""},
+		{"my-agent@staging", "my-agent", "staging"},
+		{"a@prod", "a", "prod"},
+	}
+	for _, tt := range tests {
+		slug, env, err := ParseAgentRef(tt.ref)
+		require.NoError(t, err, tt.ref)
+		assert.Equal(t, tt.slug, slug, tt.ref)
+		assert.Equal(t, tt.env, env, tt.ref)
+	}
+
+	for _, ref := range []string{"", "@staging", "my-agent@", "my-agent@Staging", "My-Agent@prod", "my-agent@prod@eu"} {
+		_, _, err := ParseAgentRef(ref)
+		assert.Error(t, err, ref)
+	}
+}
+
+func TestAgentInEnvironment(t *testing.T) {
+	env := "staging"
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/agents/my-agent":
+			_ = json.NewEncoder(w).Encode(Agent{Slug: "my-agent", Environment: &env})
+		case "/api/agents/my-agent/invoke":
+			_ = json.NewEncoder(w).Encode(InvokeResponse{Output: map[string]any{"ok": true}})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	agent, err := client.GetAgent("my-agent@staging")
+	require.NoError(t, err)
+	assert.Equal(t, "staging", *agent.Environment)
+	_, err = client.InvokeAgent("my-agent@staging", map[string]any{"q": "hi"}, InvokeOptions{})
+	require.NoError(t, err)
+	_, err = client.GetAgentLogs("my-agent@staging", 10, "")
+	require.NoError(t, err)
+	_, err = client.StopAgent("my-agent@staging")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"GET /api/agents/my-agent?environment=staging",
+		"POST /api/agents/my-agent/invoke?environment=staging",
+		"GET /api/agents/my-agent/logs?tail=10&environment=staging",
+		"POST /api/agents/my-agent/stop?environment=staging",
+	}, paths)
+
+	url, err := client.GetAgentLogsStreamURL("my-agent@staging", 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/api/agents/my-agent/logs?follow=true&tail=10&environment=staging", url)
 }
 
 func TestGetAgentInvalidSlug(t *testing.T) {
@@ -118,6 +184,25 @@ func TestGetAgentInvalidSlug(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid slug")
 }
 
+func TestInspectAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/agents/my-agent", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"slug":"my-agent","status":"running","digest":"sha256:abc","labels":{"team":"ml"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	raw, err := client.InspectAgent("my-agent")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"slug":"my-agent","status":"running","digest":"sha256:abc","labels":{"team":"ml"}}`, string(raw))
+
+	_, err = client.InspectAgent("INVALID")
+	require.Error(t, err)
+}
+
 func TestStopAgent(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPost, r.Method)
@@ -135,7 +220,7 @@ func TestStopAgent(t *testing.T) {
 
 	resp, err := client.StopAgent("my-agent")
 	require.NoError(t, err)
-	assert.Equal(t, "stopped", resp.Agent.Status)
+	assert.Equal(t, AgentStopped, resp.Agent.Status)
 	assert.Equal(t, "Agent stopped", resp.Message)
 }
 
@@ -147,6 +232,67 @@ func TestStopAgentInvalidSlug(t *testing.T) {
 	assert.Contains(t, err.Error(), "empty")
 }
 
+func TestRestartAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/restart", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RestartResponse{
+			Agent:   Agent{ID: "123", Slug: "my-agent", Status: "deploying"},
+			Message: "Agent restarting",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.RestartAgent("my-agent")
+	require.NoError(t, err)
+	assert.Equal(t, AgentDeploying, resp.Agent.Status)
+	assert.Equal(t, "Agent restarting", resp.Message)
+}
+
+func TestScaleAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/scale", r.URL.Path)
+
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, float64(3), body["replicas"])
+		assert.Equal(t, "512Mi", body["memory"])
+		assert.NotContains(t, body, "cpu")
+
+		replicas := 3
+		memory := "512Mi"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ScaleResponse{
+			Agent:   Agent{ID: "123", Slug: "my-agent", Status: "running", Replicas: &replicas, Memory: &memory},
+			Message: "Agent scaled",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	replicas := 3
+	memory := "512Mi"
+	resp, err := client.ScaleAgent("my-agent", ScaleRequest{Replicas: &replicas, Memory: &memory})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Agent.Replicas)
+	assert.Equal(t, 3, *resp.Agent.Replicas)
+	assert.Equal(t, "Agent scaled", resp.Message)
+}
+
+func TestScaleAgentInvalidSlug(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.ScaleAgent("My Agent", ScaleRequest{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid slug")
+}
+
 func TestDeleteAgent(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodDelete, r.Method)
@@ -172,6 +318,75 @@ func TestDeleteAgentInvalidSlug(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid slug")
 }
 
+func TestGetAgentLogsForRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/agents/my-agent/logs", r.URL.Path)
+		assert.Equal(t, "50", r.URL.Query().Get("tail"))
+		assert.Equal(t, "req 1&2", r.URL.Query().Get("request"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(LogsResponse{Logs: "handled\n"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.GetAgentLogs("my-agent", 50, "req 1&2")
+	require.NoError(t, err)
+	assert.Equal(t, "handled\n", resp.Logs)
+}
+
+func TestGetAgentLogsStreamURL(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	url, err := client.GetAgentLogsStreamURL("my-agent", 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost/api/agents/my-agent/logs?follow=true&tail=10", url)
+
+	url, err = client.GetAgentLogsStreamURL("my-agent", 10, "req_1")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost/api/agents/my-agent/logs?follow=true&tail=10&request=req_1", url)
+}
+
+func TestStreamAgentLogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/agents/my-agent/logs", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("follow"))
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "acme", r.Header.Get(OrgHeader))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: started\n\n"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	client.Org = "acme"
+
+	stream, err := client.StreamAgentLogs(context.Background(), "my-agent", 10, "")
+	require.NoError(t, err)
+	defer func() { _ = stream.Close() }()
+	data, err := io.ReadAll(stream)
+	require.NoError(t, err)
+	assert.Equal(t, "data: started\n\n", string(data))
+}
+
+func TestStreamAgentLogsNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "Agent not found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.StreamAgentLogs(context.Background(), "my-agent", 10, "")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+}
+
 func TestInvokeAgent(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPost, r.Method)
@@ -190,15 +405,66 @@ func TestInvokeAgent(t *testing.T) {
 
 	client := NewClient(server.URL, "test-token")
 
-	resp, err := client.InvokeAgent("my-agent", map[string]any{"query": "test"})
+	resp, err := client.InvokeAgent("my-agent", map[string]any{"query": "test"}, InvokeOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, "success", resp.Output["result"])
 }
 
+func TestInvokeAgentWithSession(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "ses_123", body["sessionId"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InvokeResponse{Output: map[string]any{}, SessionID: "ses_123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.InvokeAgent("my-agent", map[string]any{}, InvokeOptions{SessionID: "ses_123"})
+	require.NoError(t, err)
+	assert.Equal(t, "ses_123", resp.SessionID)
+}
+
+func TestInvokeAgentWithHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, map[string]any{"X-Trace-Id": []any{"abc"}}, body["headers"])
+		assert.Empty(t, r.Header.Get("X-Trace-Id"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InvokeResponse{Output: map[string]any{}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.InvokeAgent("my-agent", map[string]any{}, InvokeOptions{Header: http.Header{"X-Trace-Id": {"abc"}}})
+	require.NoError(t, err)
+}
+
+func TestInvokeAgentIdempotencyKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Len(t, r.Header.Get(IdempotencyHeader), 32)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InvokeResponse{Output: map[string]any{}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.InvokeAgent("my-agent", map[string]any{}, InvokeOptions{})
+	require.NoError(t, err)
+}
+
 func TestInvokeAgentInvalidSlug(t *testing.T) {
 	client := NewClient("http://localhost", "test-token")
 
-	_, err := client.InvokeAgent("agent-", map[string]any{})
+	_, err := client.InvokeAgent("agent-", map[string]any{}, InvokeOptions{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid slug")
 }
@@ -215,10 +481,13 @@ func TestDeployAgent(t *testing.T) {
 
 		assert.Equal(t, "My Agent", r.FormValue("name"))
 		assert.Equal(t, "my-agent", r.FormValue("slug"))
+		assert.Empty(t, r.FormValue("resources"))
+		assert.Equal(t, "gzip", r.FormValue("compression"))
 
-		file, _, err := r.FormFile("tarball")
+		file, header, err := r.FormFile("tarball")
 		require.NoError(t, err)
 		defer func() { _ = file.Close() }()
+		assert.Equal(t, "application/gzip", header.Header.Get("Content-Type"))
 
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(DeployResponse{
@@ -242,18 +511,446 @@ func TestDeployAgent(t *testing.T) {
 	client := NewClient(server.URL, "test-token")
 
 	tarball := strings.NewReader("fake tarball content")
-	resp, err := client.DeployAgent("My Agent", "my-agent", tarball)
+	resp, err := client.DeployAgent("My Agent", "my-agent", tarball, DeployOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, "123", resp.Agent.ID)
 	assert.Equal(t, "my-agent", resp.Agent.Slug)
-	assert.Equal(t, "deploying", resp.Agent.Status)
+	assert.Equal(t, AgentDeploying, resp.Agent.Status)
 	assert.Equal(t, "deploy-456", resp.Deployment.ID)
 }
 
+func TestDeployAgentWithResources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseMultipartForm(10 << 20)
+		require.NoError(t, err)
+
+		var resources Resources
+		require.NoError(t, json.Unmarshal([]byte(r.FormValue("resources")), &resources))
+		assert.Equal(t, "512Mi", resources.Memory)
+		assert.Equal(t, "0.5", resources.CPU)
+		assert.Equal(t, "60s", resources.Timeout)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	opts := DeployOptions{Resources: &Resources{Memory: "512Mi", CPU: "0.5", Timeout: "60s"}}
+	resp, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader("fake tarball content"), opts)
+	require.NoError(t, err)
+	assert.Equal(t, "my-agent", resp.Agent.Slug)
+}
+
+func TestUpdateAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/api/agents/my-agent", r.URL.Path)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{
+			"owner":     "",
+			"lifecycle": map[string]any{"minInstances": float64(0), "autosleep": "off"},
+		}, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(UpdateAgentResponse{
+			Agent:   Agent{Slug: "my-agent", Lifecycle: &Lifecycle{Autosleep: AutosleepOff, RestartPolicy: "always"}},
+			Message: "Agent updated",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	zero, owner := 0, ""
+	resp, err := client.UpdateAgent("my-agent", UpdateAgentRequest{
+		Owner:     &owner,
+		Lifecycle: &Lifecycle{MinInstances: &zero, Autosleep: AutosleepOff},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "always", resp.Agent.Lifecycle.RestartPolicy)
+}
+
+func TestUpdateAgentInvalid(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.UpdateAgent("My Agent", UpdateAgentRequest{})
+	assert.Error(t, err)
+
+	_, err = client.UpdateAgent("my-agent", UpdateAgentRequest{Lifecycle: &Lifecycle{RestartPolicy: "sometimes"}})
+	assert.Error(t, err)
+
+	repository := "git@github.com:acme/agents.git"
+	_, err = client.UpdateAgent("my-agent", UpdateAgentRequest{Repository: &repository})
+	assert.Error(t, err)
+
+	name := " "
+	_, err = client.UpdateAgent("my-agent", UpdateAgentRequest{Name: &name})
+	assert.Error(t, err)
+}
+
+func TestUpdateAgentRequestIsZero(t *testing.T) {
+	owner := ""
+	assert.True(t, UpdateAgentRequest{}.IsZero())
+	assert.True(t, UpdateAgentRequest{Lifecycle: &Lifecycle{}}.IsZero())
+	assert.False(t, UpdateAgentRequest{Owner: &owner}.IsZero())
+}
+
+func TestDeployAgentWithLifecycle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseMultipartForm(10 << 20)
+		require.NoError(t, err)
+
+		var lifecycle Lifecycle
+		require.NoError(t, json.Unmarshal([]byte(r.FormValue("lifecycle")), &lifecycle))
+		require.NotNil(t, lifecycle.MinInstances)
+		assert.Equal(t, 1, *lifecycle.MinInstances)
+		assert.Equal(t, "10m", lifecycle.Autosleep)
+		assert.Equal(t, "on-failure", lifecycle.RestartPolicy)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	one := 1
+	opts := DeployOptions{Lifecycle: &Lifecycle{MinInstances: &one, Autosleep: "10m", RestartPolicy: "on-failure"}}
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader("fake tarball content"), opts)
+	require.NoError(t, err)
+
+	opts = DeployOptions{Lifecycle: &Lifecycle{RestartPolicy: "sometimes"}}
+	_, err = client.DeployAgent("My Agent", "my-agent", strings.NewReader("fake tarball content"), opts)
+	assert.Error(t, err)
+}
+
+func TestDeployAgentWithEnvironment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseMultipartForm(10 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, "staging", r.FormValue("environment"))
+
+		env := "staging"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent", Environment: &env}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{Environment: "staging"})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Agent.Environment)
+	assert.Equal(t, "staging", *resp.Agent.Environment)
+}
+
+func TestDeployAgentInvalidEnvironment(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{Environment: "Prod!"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid environment")
+}
+
+func TestDeployAgentWithRuntime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseMultipartForm(10 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, "node", r.FormValue("runtime"))
+		assert.Equal(t, "20", r.FormValue("runtimeVersion"))
+
+		runtime := "node"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent", Runtime: &runtime}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	opts := DeployOptions{Runtime: "node", RuntimeVersion: "20"}
+	resp, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), opts)
+	require.NoError(t, err)
+	require.NotNil(t, resp.Agent.Runtime)
+	assert.Equal(t, "node", *resp.Agent.Runtime)
+}
+
+func TestDeployAgentWithChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseMultipartForm(10 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", r.FormValue("sha256"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{Checksum: "abc123"})
+	require.NoError(t, err)
+}
+
+func TestDeployAgentWithCompression(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+		assert.Equal(t, "zstd", r.FormValue("compression"))
+
+		_, header, err := r.FormFile("tarball")
+		require.NoError(t, err)
+		assert.Equal(t, "agent.tar.zst", header.Filename)
+		assert.Equal(t, "application/zstd", header.Header.Get("Content-Type"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{Compression: "zstd"})
+	require.NoError(t, err)
+
+	_, err = client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{Compression: "xz"})
+	assert.Error(t, err)
+}
+
+func TestDeployAgentWithGitMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+
+		var git GitMetadata
+		require.NoError(t, json.Unmarshal([]byte(r.FormValue("git")), &git))
+		assert.Equal(t, GitMetadata{Repository: "https://github.com/acme/agents", Ref: "main", Commit: "0123abcd"}, git)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{
+		Git: &GitMetadata{Repository: "https://github.com/acme/agents", Ref: "main", Commit: "0123abcd"},
+	})
+	require.NoError(t, err)
+}
+
+func TestDeployAgentWithSignature(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+
+		var signature Signature
+		require.NoError(t, json.Unmarshal([]byte(r.FormValue("signature")), &signature))
+		assert.Equal(t, "ed25519", signature.Algorithm)
+		assert.Equal(t, "abc123", signature.Digest)
+		assert.Equal(t, "c2lnbmF0dXJl", signature.Value)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{
+		Signature: &Signature{Algorithm: "ed25519", PublicKey: "PEM", Digest: "abc123", Value: "c2lnbmF0dXJl"},
+	})
+	require.NoError(t, err)
+}
+
+func TestDeployAgentWithMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+		assert.Equal(t, "fix retry loop", r.FormValue("message"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{Message: "fix retry loop"})
+	require.NoError(t, err)
+}
+
+func TestDeployAgentWithMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+		assert.Equal(t, "Summarizes support tickets", r.FormValue("description"))
+		assert.Equal(t, "https://github.com/acme/agents", r.FormValue("repository"))
+		assert.Empty(t, r.FormValue("owner"))
+		_, sent := r.MultipartForm.Value["owner"]
+		assert.False(t, sent)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{
+		Metadata: AgentMetadata{Description: "Summarizes support tickets", Repository: "https://github.com/acme/agents"},
+	})
+	require.NoError(t, err)
+}
+
+func TestAgentMetadataValidate(t *testing.T) {
+	assert.NoError(t, AgentMetadata{}.Validate())
+	assert.NoError(t, AgentMetadata{Repository: "http://git.internal/agents", Owner: "#ml-oncall"}.Validate())
+	assert.Error(t, AgentMetadata{Repository: "git@github.com:acme/agents.git"}.Validate())
+	assert.Error(t, AgentMetadata{Repository: "https://"}.Validate())
+	assert.Error(t, AgentMetadata{Description: strings.Repeat("x", 501)}.Validate())
+	assert.Error(t, AgentMetadata{Owner: strings.Repeat("x", 201)}.Validate())
+}
+
+func TestDeployAgentWithSBOM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+
+		file, header, err := r.FormFile("sbom")
+		require.NoError(t, err)
+		defer func() { _ = file.Close() }()
+		assert.Equal(t, "sbom.cdx.json", header.Filename)
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"bomFormat":"CycloneDX"}`, string(data))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{
+		SBOM: []byte(`{"bomFormat":"CycloneDX"}`),
+	})
+	require.NoError(t, err)
+}
+
+func TestAgentStatus(t *testing.T) {
+	tests := []struct {
+		status   AgentStatus
+		terminal bool
+		healthy  bool
+	}{
+		{AgentRunning, true, true},
+		{AgentStopped, true, false},
+		{AgentFailed, true, false},
+		{AgentDeploying, false, false},
+		{"sleeping", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.terminal, tt.status.IsTerminal())
+			assert.Equal(t, tt.healthy, tt.status.IsHealthy())
+		})
+	}
+}
+
+func TestDeployAgentIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyHeader))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{IdempotencyKey: "deploy-1"})
+	require.NoError(t, err)
+	_, err = client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{})
+	require.NoError(t, err)
+	_, err = client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, keys, 3)
+	assert.Equal(t, "deploy-1", keys[0])
+	assert.Len(t, keys[1], 32)
+	assert.NotEqual(t, keys[1], keys[2])
+}
+
+func TestDeployAgentInvalidRuntime(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{Runtime: "ruby"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid runtime")
+}
+
+func TestDeployAgentWithDockerBuild(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseMultipartForm(10 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, "docker", r.FormValue("build"))
+		assert.Empty(t, r.FormValue("runtime"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	opts := DeployOptions{Build: BuildDocker, Runtime: "python"}
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), opts)
+	require.NoError(t, err)
+}
+
+func TestDeployAgentInvalidBuild(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{Build: "nix"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid build mode")
+}
+
+func TestPromoteAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/promote", r.URL.Path)
+
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "staging", body["from"])
+		assert.Equal(t, "prod", body["to"])
+
+		env := "prod"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(PromoteResponse{Agent: Agent{Slug: "my-agent", Environment: &env, Status: "deploying"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.PromoteAgent("my-agent", "staging", "prod")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", *resp.Agent.Environment)
+}
+
+func TestPromoteAgentSameEnvironment(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.PromoteAgent("my-agent", "prod", "prod")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "same")
+}
+
 func TestDeployAgentInvalidSlug(t *testing.T) {
 	client := NewClient("http://localhost", "test-token")
 
-	_, err := client.DeployAgent("My Agent", "INVALID", strings.NewReader(""))
+	_, err := client.DeployAgent("My Agent", "INVALID", strings.NewReader(""), DeployOptions{})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid slug")
 }
@@ -271,7 +968,7 @@ func TestDeployAgentServerError(t *testing.T) {
 
 	client := NewClient(server.URL, "test-token")
 
-	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""))
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{})
 	require.Error(t, err)
 
 	apiErr, ok := err.(*APIError)
@@ -280,3 +977,99 @@ func TestDeployAgentServerError(t *testing.T) {
 	assert.Equal(t, "Agent already exists", apiErr.Message)
 	assert.Equal(t, "DUPLICATE_SLUG", apiErr.Code)
 }
+
+func TestDownloadSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/source", r.URL.Path)
+		assert.Equal(t, "deploy-456", r.URL.Query().Get("deployment"))
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/gzip")
+		_, _ = w.Write([]byte("fake tarball content"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	body, err := client.DownloadSource("my-agent", "deploy-456")
+	require.NoError(t, err)
+	defer func() { _ = body.Close() }()
+
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "fake tarball content", string(data))
+}
+
+func TestDownloadSourceNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.URL.Query().Get("deployment"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Agent not found", "code": "NOT_FOUND"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DownloadSource("my-agent", "")
+	require.Error(t, err)
+
+	apiErr, ok := err.(*APIError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "NOT_FOUND", apiErr.Code)
+}
+
+func TestGetSignature(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/signature", r.URL.Path)
+		assert.Equal(t, "deploy-456", r.URL.Query().Get("deployment"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Signature{Algorithm: "ed25519", PublicKey: "PEM", Digest: "abc123", Value: "c2ln"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	signature, err := client.GetSignature("my-agent", "deploy-456")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", signature.Digest)
+	assert.Equal(t, "c2ln", signature.Value)
+}
+
+func TestGetSBOM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/sbom", r.URL.Path)
+		assert.Empty(t, r.URL.Query().Get("deployment"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"bomFormat":"CycloneDX","components":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	sbom, err := client.GetSBOM("my-agent", "")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"bomFormat":"CycloneDX","components":[]}`, string(sbom))
+}
+
+func TestDashboardURL(t *testing.T) {
+	client := NewClient("https://oken.example.com", "test-token")
+
+	url, err := client.DashboardURL("")
+	require.NoError(t, err)
+	assert.Equal(t, "https://oken.example.com/", url)
+
+	url, err = client.DashboardURL("my-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "https://oken.example.com/agents/my-agent", url)
+
+	_, err = client.DashboardURL("My Agent")
+	require.Error(t, err)
+}
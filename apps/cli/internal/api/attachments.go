@@ -0,0 +1,198 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// Attachment is a file sent to an agent with an invocation
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+	// Upload is the ID of Data uploaded separately by UploadAttachments,
+	// which invocations reference instead of sending Data
+	Upload string
+}
+
+// UploadAttachments uploads the attachments too large to send inline, so
+// invocations that reuse them reference one upload instead of sending them
+// again. It returns the attachments with Upload set on those.
+func (c *Client) UploadAttachments(attachments []Attachment) ([]Attachment, error) {
+	uploaded := make([]Attachment, len(attachments))
+	for i, a := range attachments {
+		if a.Upload == "" && len(a.Data) > largeUploadThreshold {
+			uploadID, err := c.uploadLarge(a.Data)
+			if err != nil {
+				return nil, fmt.Errorf("upload %s: %w", a.Name, err)
+			}
+			a.Upload = uploadID
+		}
+		uploaded[i] = a
+	}
+	return uploaded, nil
+}
+
+// attachmentUpload references an attachment too large to send inline, which
+// was uploaded separately
+type attachmentUpload struct {
+	Upload      string `json:"upload"`
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+}
+
+// invokePayload is an encoded invocation request, reused when it is retried
+type invokePayload struct {
+	body        []byte
+	contentType string
+}
+
+// encodeInvoke encodes an invocation as JSON or, when it has attachments, as
+// a multipart form with the input in the "input" field and each file in an
+// "attachment" part. Attachments above the large upload threshold are
+// listed in the "attachmentUploads" field, after uploading them first unless
+// UploadAttachments already has.
+func (c *Client) encodeInvoke(input map[string]any, opts InvokeOptions) (*invokePayload, error) {
+	if len(opts.Attachments) == 0 {
+		body := map[string]any{"input": input}
+		if opts.SessionID != "" {
+			body["sessionId"] = opts.SessionID
+		}
+		if opts.Trace {
+			body["trace"] = true
+		}
+		if len(opts.Header) > 0 {
+			body["headers"] = opts.Header
+		}
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		return &invokePayload{body: data, contentType: "application/json"}, nil
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.WriteField("input", string(inputJSON)); err != nil {
+		return nil, err
+	}
+	if opts.SessionID != "" {
+		if err := writer.WriteField("sessionId", opts.SessionID); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Trace {
+		if err := writer.WriteField("trace", "true"); err != nil {
+			return nil, err
+		}
+	}
+	if len(opts.Header) > 0 {
+		headers, err := json.Marshal(opts.Header)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.WriteField("headers", string(headers)); err != nil {
+			return nil, err
+		}
+	}
+
+	attachments, err := c.UploadAttachments(opts.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	var uploads []attachmentUpload
+	for _, a := range attachments {
+		if a.Upload != "" {
+			uploads = append(uploads, attachmentUpload{Upload: a.Upload, Name: a.Name, ContentType: a.ContentType, Size: len(a.Data)})
+			continue
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachment"; filename="%s"`, escapeQuotes(a.Name)))
+		header.Set("Content-Type", a.ContentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(a.Data); err != nil {
+			return nil, err
+		}
+	}
+	if len(uploads) > 0 {
+		data, err := json.Marshal(uploads)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.WriteField("attachmentUploads", string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return &invokePayload{body: buf.Bytes(), contentType: writer.FormDataContentType()}, nil
+}
+
+// sendInvoke posts an encoded invocation. Invocations with attachments use
+// the upload client, whose timeout allows for larger bodies.
+func (c *Client) sendInvoke(slug string, payload *invokePayload, idempotencyKey string) (*InvokeResponse, error) {
+	path, err := agentPath(slug, "/invoke")
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{IdempotencyHeader: idempotencyKey}
+
+	var resp InvokeResponse
+	if payload.contentType == "application/json" {
+		if err := c.doWithHeaders(http.MethodPost, path, headers, json.RawMessage(payload.body), &resp); err != nil {
+			return nil, err
+		}
+		return &resp, nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.BaseURL+path, bytes.NewReader(payload.body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", payload.contentType)
+	req.Header.Set(IdempotencyHeader, idempotencyKey)
+	c.setHeaders(req)
+
+	httpResp, err := c.send(c.UploadClient, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+	c.noteDeprecation(httpResp)
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode >= 400 {
+		return nil, decodeAPIError(httpResp.StatusCode, respBody)
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// escapeQuotes escapes a filename for a Content-Disposition header, as
+// mime/multipart does for CreateFormFile
+func escapeQuotes(s string) string {
+	return strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(s)
+}
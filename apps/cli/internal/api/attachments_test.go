@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvokeAgentWithAttachments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/agents/my-agent/invoke", r.URL.Path)
+		assert.True(t, strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data"))
+		assert.Len(t, r.Header.Get(IdempotencyHeader), 32)
+
+		reader, err := r.MultipartReader()
+		require.NoError(t, err)
+
+		var fields []string
+		files := map[string]string{}
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			data, _ := io.ReadAll(part)
+			if part.FileName() != "" {
+				assert.Equal(t, "attachment", part.FormName())
+				files[part.FileName()] = part.Header.Get("Content-Type") + ":" + string(data)
+				continue
+			}
+			fields = append(fields, part.FormName()+"="+string(data))
+		}
+		assert.Equal(t, []string{`input={"question":"what is this?"}`, "sessionId=ses_123", `headers={"X-Flag":["beta"]}`}, fields)
+		assert.Equal(t, map[string]string{
+			"report.pdf": "application/pdf:%PDF-1.7",
+			"image.png":  "image/png:PNG",
+		}, files)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InvokeResponse{Output: map[string]any{"answer": "a report"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.InvokeAgent("my-agent", map[string]any{"question": "what is this?"}, InvokeOptions{
+		SessionID: "ses_123",
+		Header:    http.Header{"X-Flag": {"beta"}},
+		Attachments: []Attachment{
+			{Name: "report.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.7")},
+			{Name: "image.png", ContentType: "image/png", Data: []byte("PNG")},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "a report", resp.Output["answer"])
+}
+
+func TestInvokeAgentUploadsLargeAttachments(t *testing.T) {
+	useSmallUploads(t)
+
+	fake := &fakeUploadServer{parts: map[string][]byte{}}
+	uploads := fake.handler(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/agents/my-agent/invoke" {
+			uploads(w, r)
+			return
+		}
+
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+		assert.Empty(t, r.MultipartForm.File["attachment"])
+		var refs []attachmentUpload
+		require.NoError(t, json.Unmarshal([]byte(r.FormValue("attachmentUploads")), &refs))
+		assert.Equal(t, []attachmentUpload{{Upload: "up_1", Name: "audio.wav", ContentType: "audio/wav", Size: 10}}, refs)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InvokeResponse{Output: map[string]any{}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.InvokeAgent("my-agent", map[string]any{}, InvokeOptions{
+		Attachments: []Attachment{{Name: "audio.wav", ContentType: "audio/wav", Data: []byte("0123456789")}},
+	})
+	require.NoError(t, err)
+	assert.True(t, fake.completed)
+	assert.Equal(t, []byte("0123"), fake.parts["1"])
+}
+
+func TestUploadAttachmentsOnce(t *testing.T) {
+	useSmallUploads(t)
+
+	fake := &fakeUploadServer{parts: map[string][]byte{}}
+	uploads := fake.handler(t)
+	sessions, invocations := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/agents/my-agent/invoke" {
+			if r.URL.Path == "/api/uploads" {
+				sessions++
+			}
+			uploads(w, r)
+			return
+		}
+
+		invocations++
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+		assert.Equal(t, []string{"small"}, partNames(r.MultipartForm.File["attachment"]))
+		var refs []attachmentUpload
+		require.NoError(t, json.Unmarshal([]byte(r.FormValue("attachmentUploads")), &refs))
+		assert.Equal(t, []attachmentUpload{{Upload: "up_1", Name: "audio.wav", ContentType: "audio/wav", Size: 10}}, refs)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InvokeResponse{Output: map[string]any{}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	attachments, err := client.UploadAttachments([]Attachment{
+		{Name: "audio.wav", ContentType: "audio/wav", Data: []byte("0123456789")},
+		{Name: "small", ContentType: "text/plain", Data: []byte("hi")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "up_1", attachments[0].Upload)
+	assert.Empty(t, attachments[1].Upload)
+
+	for range 2 {
+		_, err := client.InvokeAgent("my-agent", map[string]any{}, InvokeOptions{Attachments: attachments})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, sessions)
+	assert.Equal(t, 2, invocations)
+}
+
+// partNames returns the file names of multipart file parts
+func partNames(files []*multipart.FileHeader) []string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Filename
+	}
+	return names
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AuditEvent is an entry in the account audit log
+type AuditEvent struct {
+	ID   string `json:"id"`
+	Time string `json:"time"`
+	// Actor is the email of the user, or the name of the token, that acted
+	Actor string `json:"actor"`
+	// Action is what happened, e.g. "agent.deploy" or "secret.delete"
+	Action    string         `json:"action"`
+	AgentSlug *string        `json:"agentSlug"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+// AuditLogResponse is returned when listing audit events, newest first
+type AuditLogResponse struct {
+	Events []AuditEvent `json:"events"`
+}
+
+// AuditFilter narrows the audit events returned by ListAuditEvents
+type AuditFilter struct {
+	// Since excludes events before this time, unless zero
+	Since time.Time
+	Actor string
+	Agent string
+	// Limit caps how many events are returned; 0 uses the platform default
+	Limit int
+}
+
+// ParseSince parses an audit --since value: a duration back from now such as
+// 15m, 24h or 7d, a date, or an RFC 3339 timestamp
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if windowPattern.MatchString(s) {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid duration %q", s)
+		}
+		unit := time.Minute
+		switch s[len(s)-1] {
+		case 'h':
+			unit = time.Hour
+		case 'd':
+			unit = 24 * time.Hour
+		}
+		return now.Add(-time.Duration(n) * unit), nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a duration (e.g. 24h, 7d), a date (2006-01-02) or an RFC 3339 timestamp", s)
+}
+
+// ListAuditEvents returns audit events of the account or selected
+// organization, newest first
+func (c *Client) ListAuditEvents(filter AuditFilter) (*AuditLogResponse, error) {
+	query := url.Values{}
+	if !filter.Since.IsZero() {
+		query.Set("since", filter.Since.UTC().Format(time.RFC3339))
+	}
+	if filter.Actor != "" {
+		query.Set("actor", filter.Actor)
+	}
+	if filter.Agent != "" {
+		if err := validateSlug(filter.Agent); err != nil {
+			return nil, err
+		}
+		query.Set("agent", filter.Agent)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	path := "/api/audit"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	var resp AuditLogResponse
+	if err := c.Get(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
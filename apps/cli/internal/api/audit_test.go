@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		since string
+		want  time.Time
+	}{
+		{"15m", now.Add(-15 * time.Minute)},
+		{"24h", now.Add(-24 * time.Hour)},
+		{"7d", now.Add(-7 * 24 * time.Hour)},
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026-03-01T08:30:00Z", time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.since, now)
+		require.NoError(t, err, tt.since)
+		assert.True(t, tt.want.Equal(got), "%s: got %s", tt.since, got)
+	}
+
+	for _, since := range []string{"", "0d", "30s", "yesterday", "03/01/2026"} {
+		_, err := ParseSince(since, now)
+		assert.Error(t, err, since)
+	}
+}
+
+func TestListAuditEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/audit", r.URL.Path)
+		assert.Equal(t, "2026-03-01T00:00:00Z", r.URL.Query().Get("since"))
+		assert.Equal(t, "ana@example.com", r.URL.Query().Get("actor"))
+		assert.Equal(t, "my-agent", r.URL.Query().Get("agent"))
+		assert.Equal(t, "50", r.URL.Query().Get("limit"))
+
+		slug := "my-agent"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(AuditLogResponse{Events: []AuditEvent{
+			{ID: "evt_1", Time: "2026-03-02T10:00:00Z", Actor: "ana@example.com", Action: "agent.deploy", AgentSlug: &slug},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListAuditEvents(AuditFilter{
+		Since: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Actor: "ana@example.com",
+		Agent: "my-agent",
+		Limit: 50,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Events, 1)
+	assert.Equal(t, "agent.deploy", resp.Events[0].Action)
+	assert.Equal(t, "my-agent", *resp.Events[0].AgentSlug)
+}
+
+func TestListAuditEventsWithoutFilters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.URL.RawQuery)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(AuditLogResponse{})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListAuditEvents(AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Events)
+
+	_, err = client.ListAuditEvents(AuditFilter{Agent: "Not A Slug"})
+	assert.Error(t, err)
+}
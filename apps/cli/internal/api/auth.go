@@ -1,7 +1,12 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"time"
 )
@@ -19,7 +24,9 @@ type DeviceAuthResponse struct {
 type DeviceAuthPollResponse struct {
 	Status string `json:"status"` // "pending" or "approved"
 	Token  string `json:"token,omitempty"`
-	User   *struct {
+	// TokenExpiresAt is when the token expires, if it does (RFC 3339)
+	TokenExpiresAt string `json:"tokenExpiresAt,omitempty"`
+	User           *struct {
 		Email string `json:"email"`
 	} `json:"user,omitempty"`
 }
@@ -73,3 +80,82 @@ func (c *Client) WaitForDeviceAuth(sessionID string, interval time.Duration, tim
 		time.Sleep(interval)
 	}
 }
+
+// PasswordLogin logs in without a browser, for local stacks: it signs up with
+// an email and password, or signs in if the user already exists, and uses
+// that web session to approve a new device auth session. The result is the
+// same as a completed 'oken login'.
+func (c *Client) PasswordLogin(email, password, name string) (*DeviceAuthPollResponse, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	web := &http.Client{Jar: jar, Timeout: c.HTTPClient.Timeout, Transport: c.HTTPClient.Transport}
+
+	signUp := map[string]string{"email": email, "password": password, "name": name}
+	err = c.postWebSession(web, "/api/auth/sign-up/email", signUp)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		signIn := map[string]string{"email": email, "password": password}
+		if err := c.postWebSession(web, "/api/auth/sign-in/email", signIn); err != nil {
+			return nil, fmt.Errorf("sign in as %s: %w", email, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("sign up as %s: %w", email, err)
+	}
+
+	device, err := c.StartDeviceAuth()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.postWebSession(web, fmt.Sprintf("/api/auth/device/%s/approve", url.PathEscape(device.SessionID)), nil); err != nil {
+		return nil, fmt.Errorf("approve login: %w", err)
+	}
+
+	resp, err := c.PollDeviceAuth(device.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != "approved" {
+		return nil, fmt.Errorf("login not approved (status: %s)", resp.Status)
+	}
+	return resp, nil
+}
+
+// postWebSession posts a JSON body with web's cookies, as the dashboard
+// does. Errors carry the message of either platform or auth error bodies.
+func (c *Client) postWebSession(web *http.Client, path string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.BaseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Cookie-authenticated requests must come from the platform's origin
+	req.Header.Set("Origin", c.BaseURL)
+	c.setHeaders(req)
+
+	resp, err := c.send(web, req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	var authErr struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	}
+	if json.Unmarshal(respBody, &authErr) == nil && authErr.Message != "" {
+		return &APIError{StatusCode: resp.StatusCode, Message: authErr.Message, Code: authErr.Code}
+	}
+	return decodeAPIError(resp.StatusCode, respBody)
+}
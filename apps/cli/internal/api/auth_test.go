@@ -171,3 +171,73 @@ func TestWaitForDeviceAuthServerError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "Server error")
 }
+
+// passwordLoginServer fakes sign-up, sign-in and device auth. Sign-up fails
+// for emails in existing.
+func passwordLoginServer(t *testing.T, existing map[string]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.URL.Path {
+		case "/api/auth/sign-up/email":
+			assert.Equal(t, "Demo", body["name"])
+			if _, ok := existing[body["email"]]; ok {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = w.Write([]byte(`{"code": "USER_ALREADY_EXISTS", "message": "User already exists"}`))
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "web-session", Path: "/"})
+			_, _ = w.Write([]byte(`{}`))
+		case "/api/auth/sign-in/email":
+			if existing[body["email"]] != body["password"] {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"code": "INVALID_EMAIL_OR_PASSWORD", "message": "Invalid email or password"}`))
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "web-session", Path: "/"})
+			_, _ = w.Write([]byte(`{}`))
+		case "/api/auth/device":
+			_ = json.NewEncoder(w).Encode(DeviceAuthResponse{SessionID: "session-123"})
+		case "/api/auth/device/session-123/approve":
+			cookie, err := r.Cookie("session")
+			if assert.NoError(t, err) {
+				assert.Equal(t, "web-session", cookie.Value)
+			}
+			assert.Equal(t, "http://"+r.Host, r.Header.Get("Origin"))
+			_, _ = w.Write([]byte(`{"success": true}`))
+		case "/api/auth/device/session-123":
+			_ = json.NewEncoder(w).Encode(DeviceAuthPollResponse{Status: "approved", Token: "ok_demo"})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestPasswordLoginSignsUp(t *testing.T) {
+	server := passwordLoginServer(t, nil)
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+
+	resp, err := client.PasswordLogin("demo@oken.local", "secret", "Demo")
+	require.NoError(t, err)
+	assert.Equal(t, "ok_demo", resp.Token)
+}
+
+func TestPasswordLoginSignsInExistingUser(t *testing.T) {
+	server := passwordLoginServer(t, map[string]string{"demo@oken.local": "secret"})
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+
+	resp, err := client.PasswordLogin("demo@oken.local", "secret", "Demo")
+	require.NoError(t, err)
+	assert.Equal(t, "ok_demo", resp.Token)
+
+	_, err = client.PasswordLogin("demo@oken.local", "wrong", "Demo")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid email or password")
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultBreakerCooldown is how long a connection failure is remembered
+const DefaultBreakerCooldown = 30 * time.Second
+
+// connectTimeout bounds establishing a connection to the platform, which
+// is much shorter than the time a request may take once connected
+const connectTimeout = 10 * time.Second
+
+// probeTimeout bounds the request that checks whether a platform that was
+// unreachable is back
+const probeTimeout = 3 * time.Second
+
+// UnreachableError is returned when no connection could be made to the
+// platform, as opposed to the platform answering with an error
+type UnreachableError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("platform unreachable at %s: %v", e.Endpoint, e.Err)
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// Breaker remembers connection failures on disk for a short while, so
+// commands run right after one fail fast instead of each waiting out the
+// connection timeout. While a failure is remembered, requests first probe
+// the platform with a short timeout.
+type Breaker struct {
+	Path     string
+	Cooldown time.Duration
+
+	now func() time.Time
+}
+
+// breakerFailure is a connection failure remembered for one endpoint
+type breakerFailure struct {
+	FailedAt time.Time `json:"failedAt"`
+	Error    string    `json:"error"`
+}
+
+// NewBreaker creates a breaker that keeps its state in the file at path
+func NewBreaker(path string) *Breaker {
+	return &Breaker{Path: path, Cooldown: DefaultBreakerCooldown, now: time.Now}
+}
+
+// load reads the remembered failures. A missing or corrupt file means none.
+func (b *Breaker) load() map[string]breakerFailure {
+	failures := map[string]breakerFailure{}
+	data, err := os.ReadFile(b.Path)
+	if err != nil {
+		return failures
+	}
+	_ = json.Unmarshal(data, &failures)
+	return failures
+}
+
+// save writes the remembered failures. Failures are ignored, since the
+// breaker only saves time.
+func (b *Breaker) save(failures map[string]breakerFailure) {
+	if len(failures) == 0 {
+		_ = os.Remove(b.Path)
+		return
+	}
+	data, err := json.Marshal(failures)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(b.Path), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(b.Path, data, 0600)
+}
+
+// recent returns the failure remembered for endpoint, if it is within the
+// cooldown
+func (b *Breaker) recent(endpoint string) (breakerFailure, bool) {
+	failure, ok := b.load()[endpoint]
+	if !ok || b.now().Sub(failure.FailedAt) > b.Cooldown {
+		return breakerFailure{}, false
+	}
+	return failure, true
+}
+
+// trip remembers a connection failure for endpoint
+func (b *Breaker) trip(endpoint string, err error) {
+	failures := b.load()
+	failures[endpoint] = breakerFailure{FailedAt: b.now(), Error: err.Error()}
+	b.save(failures)
+}
+
+// reset forgets the failure remembered for endpoint, if any
+func (b *Breaker) reset(endpoint string) {
+	failures := b.load()
+	if _, ok := failures[endpoint]; !ok {
+		return
+	}
+	delete(failures, endpoint)
+	b.save(failures)
+}
+
+// newTransport returns a transport that gives up on connecting after
+// connectTimeout
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}).DialContext
+	return transport
+}
+
+// isConnectError reports whether err means no connection could be made, as
+// opposed to a request that failed or timed out once connected
+func isConnectError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// send performs a request to the platform with httpClient. While the
+// breaker remembers a connection failure, the platform is probed first and
+// the request fails fast if it is still unreachable.
+func (c *Client) send(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	if c.Breaker != nil {
+		if _, ok := c.Breaker.recent(c.BaseURL); ok {
+			if err := c.probe(httpClient); err != nil {
+				c.Breaker.trip(c.BaseURL, err)
+				return nil, &UnreachableError{Endpoint: c.BaseURL, Err: err}
+			}
+			c.Breaker.reset(c.BaseURL)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if !isConnectError(err) {
+			return nil, err
+		}
+		if c.Breaker != nil {
+			c.Breaker.trip(c.BaseURL, err)
+		}
+		return nil, &UnreachableError{Endpoint: c.BaseURL, Err: err}
+	}
+	return resp, nil
+}
+
+// probe checks that the platform answers at all. Any HTTP response counts.
+func (c *Client) probe(httpClient *http.Client) error {
+	probeClient := &http.Client{Timeout: probeTimeout, Transport: httpClient.Transport}
+	resp, err := probeClient.Get(c.BaseURL)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+	return resp.Body.Close()
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closedServerURL returns the URL of a server that no longer listens
+func closedServerURL() string {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	return server.URL
+}
+
+func TestClientUnreachable(t *testing.T) {
+	endpoint := closedServerURL()
+	client := NewClient(endpoint, "test-token")
+	client.Breaker = NewBreaker(filepath.Join(t.TempDir(), "unreachable.json"))
+
+	err := client.Get("/api/agents", nil)
+	var unreachable *UnreachableError
+	require.ErrorAs(t, err, &unreachable)
+	assert.Equal(t, endpoint, unreachable.Endpoint)
+	assert.Contains(t, err.Error(), "platform unreachable at "+endpoint)
+
+	_, tripped := client.Breaker.recent(endpoint)
+	assert.True(t, tripped)
+
+	// The next request fails on the probe
+	err = client.Get("/api/agents", nil)
+	require.ErrorAs(t, err, &unreachable)
+}
+
+func TestStreamsUseBreaker(t *testing.T) {
+	endpoint := closedServerURL()
+	client := NewClient(endpoint, "test-token")
+	client.Breaker = NewBreaker(filepath.Join(t.TempDir(), "unreachable.json"))
+	ctx := context.Background()
+
+	var unreachable *UnreachableError
+	_, err := client.StreamAgentLogs(ctx, "my-agent", 10, "")
+	require.ErrorAs(t, err, &unreachable)
+	_, tripped := client.Breaker.recent(endpoint)
+	assert.True(t, tripped)
+
+	_, err = client.StreamChat(ctx, "my-agent", ChatRequest{Message: "hi"}, func(string) {})
+	require.ErrorAs(t, err, &unreachable)
+	_, err = client.Exec(ctx, "my-agent", []string{"ls"}, io.Discard, io.Discard)
+	require.ErrorAs(t, err, &unreachable)
+	err = client.GetDeploymentLogsStream(ctx, "dep_123", io.Discard)
+	require.ErrorAs(t, err, &unreachable)
+}
+
+func TestClientResetsBreakerWhenPlatformIsBack(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			assert.Empty(t, r.Header.Get("Authorization"))
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	client.Breaker = NewBreaker(filepath.Join(t.TempDir(), "unreachable.json"))
+	client.Breaker.trip(server.URL, errors.New("connection refused"))
+
+	require.NoError(t, client.Get("/api/agents", nil))
+	assert.Equal(t, 1, calls)
+	_, tripped := client.Breaker.recent(server.URL)
+	assert.False(t, tripped)
+}
+
+func TestClientAPIErrorDoesNotTripBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	client.Breaker = NewBreaker(filepath.Join(t.TempDir(), "unreachable.json"))
+
+	err := client.Get("/api/agents", nil)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	_, tripped := client.Breaker.recent(server.URL)
+	assert.False(t, tripped)
+}
+
+func TestBreakerCooldown(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(filepath.Join(t.TempDir(), "unreachable.json"))
+	breaker.now = func() time.Time { return now }
+
+	breaker.trip("https://oken.example.com", errors.New("no such host"))
+	failure, tripped := breaker.recent("https://oken.example.com")
+	require.True(t, tripped)
+	assert.Equal(t, "no such host", failure.Error)
+
+	_, tripped = breaker.recent("https://other.example.com")
+	assert.False(t, tripped)
+
+	now = now.Add(DefaultBreakerCooldown + time.Second)
+	_, tripped = breaker.recent("https://oken.example.com")
+	assert.False(t, tripped)
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxCachedBodySize keeps the on-disk cache small; larger responses are not cached
+const maxCachedBodySize = 1 << 20
+
+// Entries unused for maxCacheAge are removed, and beyond maxCacheEntries the
+// least recently used ones are
+const (
+	maxCacheAge     = 30 * 24 * time.Hour
+	maxCacheEntries = 500
+)
+
+// ResponseCache stores GET response bodies on disk with their ETag, so
+// unchanged resources can be revalidated with If-None-Match instead of
+// downloaded again
+type ResponseCache struct {
+	Dir string
+}
+
+type cacheEntry struct {
+	ETag string          `json:"etag"`
+	Body json.RawMessage `json:"body"`
+}
+
+// NewResponseCache creates a cache that keeps entries in dir
+func NewResponseCache(dir string) *ResponseCache {
+	return &ResponseCache{Dir: dir}
+}
+
+// key identifies a response by URL, which includes the endpoint, account and
+// organization, so cached bodies are never shared between accounts or
+// organizations and survive the account's token being renewed
+func (rc *ResponseCache) key(url, account, org string) string {
+	sum := sha256.Sum256([]byte(account + "\x00" + org + "\x00" + url))
+	return hex.EncodeToString(sum[:])
+}
+
+// load returns the cached entry for key, if any, and marks it as used
+func (rc *ResponseCache) load(key string) (*cacheEntry, bool) {
+	path := filepath.Join(rc.Dir, key+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.ETag == "" {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return &entry, true
+}
+
+// store saves a response body under key. Failures are ignored, since the
+// cache is only an optimization.
+func (rc *ResponseCache) store(key, etag string, body []byte) {
+	if etag == "" || len(body) > maxCachedBodySize || !json.Valid(body) {
+		return
+	}
+	data, err := json.Marshal(cacheEntry{ETag: etag, Body: body})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(rc.Dir, 0700); err != nil {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(rc.Dir, key+".json"), data, 0600); err != nil {
+		return
+	}
+	rc.prune(time.Now())
+}
+
+// prune removes entries unused for maxCacheAge, then the least recently used
+// ones beyond maxCacheEntries. Other files in Dir are left alone.
+func (rc *ResponseCache) prune(now time.Time) {
+	dirEntries, err := os.ReadDir(rc.Dir)
+	if err != nil {
+		return
+	}
+
+	type file struct {
+		path    string
+		modTime time.Time
+	}
+	var files []file
+	for _, e := range dirEntries {
+		if !isCacheFile(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(rc.Dir, e.Name())
+		if now.Sub(info.ModTime()) > maxCacheAge {
+			_ = os.Remove(path)
+			continue
+		}
+		files = append(files, file{path: path, modTime: info.ModTime()})
+	}
+
+	if len(files) <= maxCacheEntries {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+	for _, f := range files[maxCacheEntries:] {
+		_ = os.Remove(f.path)
+	}
+}
+
+// isCacheFile reports whether name is a cache entry, named after its key
+func isCacheFile(name string) bool {
+	key, ok := strings.CutSuffix(name, ".json")
+	if !ok || len(key) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCacheRevalidatesWithETag(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode(AgentListResponse{Agents: []Agent{{Slug: "my-agent", Status: "running"}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	client.Cache = NewResponseCache(t.TempDir())
+
+	first, err := client.ListAgents()
+	require.NoError(t, err)
+	require.Len(t, first.Agents, 1)
+
+	second, err := client.ListAgents()
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 2, requests)
+}
+
+func TestClientCacheIsPerToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode(AgentListResponse{})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	client := NewClient(server.URL, "token-a")
+	client.Cache = NewResponseCache(dir)
+	_, err := client.ListAgents()
+	require.NoError(t, err)
+
+	other := NewClient(server.URL, "token-b")
+	other.Cache = NewResponseCache(dir)
+	_, err = other.ListAgents()
+	require.NoError(t, err)
+}
+
+func TestClientCacheIsPerAccount(t *testing.T) {
+	var revalidated []bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		revalidated = append(revalidated, r.Header.Get("If-None-Match") != "")
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode(AgentListResponse{})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	get := func(token, account string) {
+		client := NewClient(server.URL, token)
+		client.Account = account
+		client.Cache = NewResponseCache(dir)
+		_, err := client.ListAgents()
+		require.NoError(t, err)
+	}
+
+	get("token-a", "ada@example.com")
+	// A renewed token for the same account keeps its cache
+	get("token-b", "ada@example.com")
+	// Another account on the same endpoint doesn't share it
+	get("token-c", "grace@example.com")
+
+	assert.Equal(t, []bool{false, true, false}, revalidated)
+}
+
+func TestResponseCachePrune(t *testing.T) {
+	dir := t.TempDir()
+	rc := NewResponseCache(dir)
+	now := time.Now()
+
+	write := func(name string, age time.Duration) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(`{}`), 0600))
+		require.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+		return path
+	}
+	stale := write(rc.key("/stale", "", "")+".json", maxCacheAge+time.Hour)
+	var recent []string
+	for i := range maxCacheEntries + 2 {
+		recent = append(recent, write(rc.key(fmt.Sprintf("/%d", i), "", "")+".json", time.Duration(i)*time.Minute))
+	}
+	other := write("unreachable.json", 2*maxCacheAge)
+
+	rc.prune(now)
+
+	assert.NoFileExists(t, stale)
+	assert.FileExists(t, recent[0])
+	assert.FileExists(t, recent[maxCacheEntries-1])
+	assert.NoFileExists(t, recent[maxCacheEntries])
+	assert.NoFileExists(t, recent[maxCacheEntries+1])
+	assert.FileExists(t, other)
+}
+
+func TestClientCacheSkipsResponsesWithoutETag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(AgentListResponse{})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(server.URL, "test-token")
+	client.Cache = NewResponseCache(dir)
+
+	_, err := client.ListAgents()
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Chat message roles
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// ChatMessage is one turn of a conversation with an agent
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest sends one user message to an agent's session
+type ChatRequest struct {
+	SessionID string `json:"sessionId"`
+	Message   string `json:"message"`
+}
+
+// ChatReply is the agent's complete answer to a chat message
+type ChatReply struct {
+	SessionID string `json:"sessionId"`
+	Content   string `json:"content"`
+}
+
+// NewSessionID returns a random ID for a new conversation with an agent
+func NewSessionID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "ses_" + hex.EncodeToString(b)
+}
+
+// StreamChat sends a message to an agent and calls onDelta with each piece
+// of the reply as it arrives. It returns the whole reply once the agent is
+// done, or when ctx is cancelled, the part received so far with ctx's error.
+func (c *Client) StreamChat(ctx context.Context, slug string, chat ChatRequest, onDelta func(text string)) (*ChatReply, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(chat)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/agents/%s/chat", c.BaseURL, slug), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	c.setHeaders(req)
+	req.Header.Set(IdempotencyHeader, NewIdempotencyKey())
+
+	// Agents may think for a while, so no client timeout; ctx cancels
+	resp, err := c.send(c.StreamClient, req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	c.noteDeprecation(resp)
+
+	if resp.StatusCode >= 400 {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if err != nil {
+			return nil, err
+		}
+		return nil, decodeAPIError(resp.StatusCode, body)
+	}
+
+	reply := &ChatReply{SessionID: chat.SessionID}
+	var text strings.Builder
+	err = readEvents(resp.Body, func(event, data string) (bool, error) {
+		switch event {
+		case "done":
+			return true, nil
+		case "error":
+			var e struct {
+				Error string `json:"error"`
+			}
+			if json.Unmarshal([]byte(data), &e) != nil || e.Error == "" {
+				e.Error = data
+			}
+			return true, &AgentError{Message: e.Error}
+		}
+
+		var delta struct {
+			Delta string `json:"delta"`
+		}
+		if err := json.Unmarshal([]byte(data), &delta); err != nil {
+			return false, fmt.Errorf("invalid chat event: %w", err)
+		}
+		text.WriteString(delta.Delta)
+		if onDelta != nil {
+			onDelta(delta.Delta)
+		}
+		return false, nil
+	})
+	reply.Content = text.String()
+	if ctx.Err() != nil {
+		return reply, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// readEvents parses a server-sent event stream, calling handle with each
+// event's name ("" for plain messages) and data until it returns true or an
+// error, or the stream ends
+func readEvents(r io.Reader, handle func(event, data string) (bool, error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line dispatches the event
+			if event == "" && data == nil {
+				continue
+			}
+			done, err := handle(event, strings.Join(data, "\n"))
+			if done || err != nil {
+				return err
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	// The stream may end without a blank line after the last event
+	if event != "" || data != nil {
+		done, err := handle(event, strings.Join(data, "\n"))
+		if done || err != nil {
+			return err
+		}
+	}
+	return io.ErrUnexpectedEOF
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSessionID(t *testing.T) {
+	id := NewSessionID()
+	assert.Regexp(t, `^ses_[0-9a-f]{24}$`, id)
+	assert.NotEqual(t, id, NewSessionID())
+}
+
+func TestStreamChat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/chat", r.URL.Path)
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+
+		var chat ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&chat))
+		assert.Equal(t, ChatRequest{SessionID: "ses_123", Message: "hi"}, chat)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: {\"delta\": \"Hello\"}\n\n")
+		_, _ = fmt.Fprint(w, ": keepalive\n\n")
+		_, _ = fmt.Fprint(w, "data: {\"delta\": \", world\"}\n\n")
+		_, _ = fmt.Fprint(w, "event: done\ndata: {}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	var deltas []string
+	reply, err := client.StreamChat(context.Background(), "my-agent", ChatRequest{SessionID: "ses_123", Message: "hi"}, func(text string) {
+		deltas = append(deltas, text)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Hello", ", world"}, deltas)
+	assert.Equal(t, &ChatReply{SessionID: "ses_123", Content: "Hello, world"}, reply)
+}
+
+func TestStreamChatAgentError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: {\"delta\": \"Let me\"}\n\n")
+		_, _ = fmt.Fprint(w, "event: error\ndata: {\"error\": \"tool call failed\"}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.StreamChat(context.Background(), "my-agent", ChatRequest{SessionID: "ses_123", Message: "hi"}, nil)
+	var agentErr *AgentError
+	require.ErrorAs(t, err, &agentErr)
+	assert.Equal(t, "tool call failed", agentErr.Message)
+}
+
+func TestStreamChatUnexpectedEOF(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: {\"delta\": \"Hel\"}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.StreamChat(context.Background(), "my-agent", ChatRequest{SessionID: "ses_123", Message: "hi"}, nil)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestStreamChatAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Agent not found"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.StreamChat(context.Background(), "my-agent", ChatRequest{SessionID: "ses_123", Message: "hi"}, nil)
+	assert.True(t, IsNotFound(err))
+}
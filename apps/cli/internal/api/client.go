@@ -2,31 +2,97 @@ package api
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"runtime"
+	"sync"
 	"time"
 )
 
+// DefaultUploadTimeout bounds deploy uploads and source downloads
+const DefaultUploadTimeout = 5 * time.Minute
+
+// OrgHeader scopes a request to an organization instead of the personal account
+const OrgHeader = "X-Oken-Org"
+
+// DeprecationHeader carries a notice when the platform is phasing out the
+// calling CLI version
+const DeprecationHeader = "X-Oken-Deprecation"
+
+// IdempotencyHeader lets the platform recognize a re-sent mutating request, so
+// retries never create a second deployment or invocation. Go's transport also
+// treats requests carrying it as safe to replay on connection errors.
+const IdempotencyHeader = "Idempotency-Key"
+
+// NewIdempotencyKey returns a random key identifying one logical operation
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// UserAgent returns the User-Agent sent by this CLI version
+func UserAgent(version string) string {
+	return fmt.Sprintf("oken-cli/%s (%s/%s)", version, runtime.GOOS, runtime.GOARCH)
+}
+
 // Client handles communication with the Oken platform API
 type Client struct {
 	BaseURL      string
 	Token        string
+	Org          string
 	HTTPClient   *http.Client
 	UploadClient *http.Client
+	// StreamClient sends requests whose responses stream for as long as
+	// their context allows, such as followed logs, so it has no timeout
+	StreamClient *http.Client
+	UserAgent    string
+	// Cache revalidates GET responses with ETags when set
+	Cache *ResponseCache
+	// Account identifies the logged-in user, such as by email, so cached
+	// responses outlive the token. Without it, they are kept per token.
+	Account string
+	// Breaker makes requests fail fast after a recent connection failure
+	// when set
+	Breaker *Breaker
+	// OnDeprecation is called once with the first deprecation notice the
+	// platform sends
+	OnDeprecation func(msg string)
+	// OnUploadProgress is called as parts of a large package are uploaded,
+	// with the bytes the platform has so far out of the total
+	OnUploadProgress func(sent, total int64)
+	// OnUnauthorized is called once, when the platform first rejects the
+	// token, to log in again. Requests rejected with the old token are retried
+	// with the token it returns.
+	OnUnauthorized func() (string, error)
+
+	deprecationOnce sync.Once
+	reauthOnce      sync.Once
+	// tokenMu guards Token once requests may be in flight
+	tokenMu sync.RWMutex
 }
 
 // NewClient creates a new API client
 func NewClient(baseURL, token string) *Client {
 	return &Client{
-		BaseURL: baseURL,
-		Token:   token,
+		BaseURL:   baseURL,
+		Token:     token,
+		UserAgent: UserAgent("dev"),
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: newTransport(),
 		},
 		UploadClient: &http.Client{
-			Timeout: 5 * time.Minute,
+			Timeout:   DefaultUploadTimeout,
+			Transport: newTransport(),
+		},
+		StreamClient: &http.Client{
+			Transport: newTransport(),
 		},
 	}
 }
@@ -36,6 +102,9 @@ type APIError struct {
 	StatusCode int
 	Message    string
 	Code       string
+	// RequestID identifies the failed request in the agent's logs, if the
+	// platform said
+	RequestID string
 }
 
 func (e *APIError) Error() string {
@@ -45,8 +114,109 @@ func (e *APIError) Error() string {
 	return e.Message
 }
 
+// ErrUnauthorized matches 401 responses with errors.Is, such as when the
+// token has expired or was revoked
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Is makes errors.Is(err, ErrUnauthorized) match 401 responses
+func (e *APIError) Is(target error) bool {
+	return target == ErrUnauthorized && e.StatusCode == http.StatusUnauthorized
+}
+
+// RequestIDOf returns the request ID carried by an API or agent error, or ""
+func RequestIDOf(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RequestID
+	}
+	var agentErr *AgentError
+	if errors.As(err, &agentErr) {
+		return agentErr.RequestID
+	}
+	return ""
+}
+
+// IsNotFound reports whether err is a 404 response from the platform
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// decodeAPIError builds an APIError from an error response body
+func decodeAPIError(statusCode int, body []byte) error {
+	var errResp struct {
+		Error     string `json:"error"`
+		Code      string `json:"code"`
+		RequestID string `json:"requestId"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+		return &APIError{
+			StatusCode: statusCode,
+			Message:    errResp.Error,
+			Code:       errResp.Code,
+			RequestID:  errResp.RequestID,
+		}
+	}
+	return &APIError{
+		StatusCode: statusCode,
+		Message:    fmt.Sprintf("request failed with status %d", statusCode),
+	}
+}
+
+// setHeaders adds identification, authentication and organization headers to a request
+func (c *Client) setHeaders(req *http.Request) {
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if token := c.token(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.Org != "" {
+		req.Header.Set(OrgHeader, c.Org)
+	}
+}
+
+// token returns the current token, which OnUnauthorized may replace
+func (c *Client) token() string {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.Token
+}
+
+// reauthenticate logs in again through OnUnauthorized after a request sent
+// with token was rejected, and reports whether to retry it with the new token
+func (c *Client) reauthenticate(token string) bool {
+	if c.OnUnauthorized == nil {
+		return false
+	}
+	c.reauthOnce.Do(func() {
+		newToken, err := c.OnUnauthorized()
+		if err != nil || newToken == "" {
+			return
+		}
+		c.tokenMu.Lock()
+		c.Token = newToken
+		c.tokenMu.Unlock()
+	})
+	return c.token() != token
+}
+
+// noteDeprecation passes the platform's deprecation notice, if any, to OnDeprecation
+func (c *Client) noteDeprecation(resp *http.Response) {
+	msg := resp.Header.Get(DeprecationHeader)
+	if msg == "" || c.OnDeprecation == nil {
+		return
+	}
+	c.deprecationOnce.Do(func() { c.OnDeprecation(msg) })
+}
+
 // do performs an HTTP request and decodes the response
 func (c *Client) do(method, path string, body any, result any) error {
+	return c.doWithHeaders(method, path, nil, body, result)
+}
+
+// doWithHeaders is like do, but adds extra request headers
+func (c *Client) doWithHeaders(method, path string, headers map[string]string, body any, result any) error {
 	var bodyReader io.Reader
 	if body != nil {
 		data, err := json.Marshal(body)
@@ -62,37 +232,49 @@ func (c *Client) do(method, path string, body any, result any) error {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
+	token := c.token()
+	c.setHeaders(req)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	var cacheKey string
+	var cached *cacheEntry
+	if c.Cache != nil && method == http.MethodGet {
+		account := c.Account
+		if account == "" {
+			account = "token:" + token
+		}
+		cacheKey = c.Cache.key(req.URL.String(), account, c.Org)
+		if entry, ok := c.Cache.load(cacheKey); ok {
+			cached = entry
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
 	}
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(c.HTTPClient, req)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
+	c.noteDeprecation(resp)
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		respBody = cached.Body
+	} else if cacheKey != "" && resp.StatusCode == http.StatusOK {
+		c.Cache.store(cacheKey, resp.Header.Get("ETag"), respBody)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && c.reauthenticate(token) {
+		return c.doWithHeaders(method, path, headers, body, result)
+	}
 	if resp.StatusCode >= 400 {
-		var errResp struct {
-			Error string `json:"error"`
-			Code  string `json:"code"`
-		}
-		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Error != "" {
-			return &APIError{
-				StatusCode: resp.StatusCode,
-				Message:    errResp.Error,
-				Code:       errResp.Code,
-			}
-		}
-		return &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    fmt.Sprintf("request failed with status %d", resp.StatusCode),
-		}
+		return decodeAPIError(resp.StatusCode, respBody)
 	}
 
 	if result != nil {
@@ -114,6 +296,16 @@ func (c *Client) Post(path string, body any, result any) error {
 	return c.do(http.MethodPost, path, body, result)
 }
 
+// Put performs a PUT request, replacing the resource at path
+func (c *Client) Put(path string, body any, result any) error {
+	return c.do(http.MethodPut, path, body, result)
+}
+
+// Patch performs a PATCH request, changing only the fields set in body
+func (c *Client) Patch(path string, body any, result any) error {
+	return c.do(http.MethodPatch, path, body, result)
+}
+
 // Delete performs a DELETE request
 func (c *Client) Delete(path string, result any) error {
 	return c.do(http.MethodDelete, path, nil, result)
@@ -2,8 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -60,6 +62,36 @@ func TestClientPost(t *testing.T) {
 	assert.Equal(t, "created", result["status"])
 }
 
+func TestClientPutAndPatch(t *testing.T) {
+	for _, method := range []string{http.MethodPut, http.MethodPatch} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, method, r.Method)
+			assert.Equal(t, "/api/test", r.URL.Path)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, "value", body["key"])
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
+		}))
+
+		client := NewClient(server.URL, "")
+
+		var result map[string]string
+		var err error
+		if method == http.MethodPut {
+			err = client.Put("/api/test", map[string]string{"key": "value"}, &result)
+		} else {
+			err = client.Patch("/api/test", map[string]string{"key": "value"}, &result)
+		}
+		require.NoError(t, err, method)
+		assert.Equal(t, "updated", result["status"], method)
+		server.Close()
+	}
+}
+
 func TestClientDelete(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodDelete, r.Method)
@@ -110,6 +142,73 @@ func TestClientNoAuthHeaderWhenNoToken(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClientOrgHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "acme", r.Header.Get(OrgHeader))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	client.Org = "acme"
+
+	var result map[string]string
+	err := client.Get("/api/test", &result)
+	require.NoError(t, err)
+}
+
+func TestClientUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "oken-cli/1.2.3 ("+runtime.GOOS+"/"+runtime.GOARCH+")", r.Header.Get("User-Agent"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+	client.UserAgent = UserAgent("1.2.3")
+
+	var result map[string]string
+	err := client.Get("/api/test", &result)
+	require.NoError(t, err)
+}
+
+func TestClientDeprecationNoticeReportedOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(DeprecationHeader, "oken-cli 0.1 is deprecated, please upgrade")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{})
+	}))
+	defer server.Close()
+
+	var notices []string
+	client := NewClient(server.URL, "test-token")
+	client.OnDeprecation = func(msg string) { notices = append(notices, msg) }
+
+	require.NoError(t, client.Get("/api/test", nil))
+	require.NoError(t, client.Get("/api/test", nil))
+	assert.Equal(t, []string{"oken-cli 0.1 is deprecated, please upgrade"}, notices)
+}
+
+func TestClientNoOrgHeaderByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(OrgHeader))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	var result map[string]string
+	err := client.Get("/api/test", &result)
+	require.NoError(t, err)
+}
+
 func TestClientAPIErrorWithCode(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -135,6 +234,25 @@ func TestClientAPIErrorWithCode(t *testing.T) {
 	assert.Equal(t, "Invalid request (INVALID_REQUEST)", apiErr.Error())
 }
 
+func TestClientAPIErrorWithRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error":     "Agent crashed",
+			"requestId": "req_8f2c1a",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+
+	err := client.Get("/api/test", nil)
+	require.Error(t, err)
+	assert.Equal(t, "req_8f2c1a", RequestIDOf(err))
+	assert.Equal(t, "", RequestIDOf(errors.New("other")))
+}
+
 func TestClientAPIErrorWithoutCode(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -206,3 +324,66 @@ func TestClientGetWithNilResult(t *testing.T) {
 	err := client.Get("/api/test", nil)
 	require.NoError(t, err)
 }
+
+func TestIsNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Agent not found"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+
+	_, err := client.GetAgent("missing")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsNotFound(&APIError{StatusCode: http.StatusForbidden}))
+	assert.False(t, IsNotFound(nil))
+}
+
+func TestErrUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Token expired", "code": "TOKEN_EXPIRED"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "expired-token")
+
+	err := client.Get("/api/test", nil)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.EqualError(t, err, "Token expired (TOKEN_EXPIRED)")
+	assert.NotErrorIs(t, &APIError{StatusCode: http.StatusForbidden}, ErrUnauthorized)
+}
+
+func TestClientReauthenticatesOnce(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "old-token")
+	logins := 0
+	client.OnUnauthorized = func() (string, error) {
+		logins++
+		return "new-token", nil
+	}
+
+	var result map[string]string
+	require.NoError(t, client.Get("/api/test", &result))
+	assert.Equal(t, "ok", result["status"])
+	assert.Equal(t, []string{"Bearer old-token", "Bearer new-token"}, tokens)
+
+	// A token rejected after logging in again isn't retried
+	client.Token = "revoked-token"
+	err := client.Get("/api/test", nil)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.Equal(t, 1, logins)
+}
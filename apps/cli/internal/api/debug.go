@@ -0,0 +1,56 @@
+package api
+
+import "fmt"
+
+// DefaultDebugPort is the port debugpy listens on by default, which editors
+// also default to when attaching
+const DefaultDebugPort = 5678
+
+// DebugRequest is the request body for restarting an agent under debugpy
+type DebugRequest struct {
+	// Port is the port debugpy listens on in the agent's runtime
+	Port int `json:"port"`
+	// WaitForClient holds the agent's startup until a debugger attaches, so
+	// breakpoints in startup code are hit
+	WaitForClient bool `json:"waitForClient,omitempty"`
+}
+
+// DebugResponse is returned when an agent is restarted with or without the
+// debugger
+type DebugResponse struct {
+	Agent Agent `json:"agent"`
+	Port  int   `json:"port,omitempty"`
+	// RemoteRoot is the directory the agent's code runs from, which editors
+	// need to map local files to remote ones
+	RemoteRoot string `json:"remoteRoot,omitempty"`
+	Message    string `json:"message"`
+}
+
+// StartDebug restarts an agent with debugpy listening on a port of its
+// runtime, which can then be reached with PortForward. Only Python agents
+// can be debugged.
+func (c *Client) StartDebug(slug string, req DebugRequest) (*DebugResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	if req.Port < 1 || req.Port > 65535 {
+		return nil, fmt.Errorf("invalid debug port %d: must be a number from 1 to 65535", req.Port)
+	}
+	var resp DebugResponse
+	if err := c.Post(fmt.Sprintf("/api/agents/%s/debug", slug), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StopDebug restarts an agent without the debugger
+func (c *Client) StopDebug(slug string) (*DebugResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	var resp DebugResponse
+	if err := c.Delete(fmt.Sprintf("/api/agents/%s/debug", slug), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/debug", r.URL.Path)
+
+		var req DebugRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, DefaultDebugPort, req.Port)
+		assert.True(t, req.WaitForClient)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DebugResponse{
+			Agent:      Agent{Slug: "my-agent", Status: AgentDeploying},
+			Port:       req.Port,
+			RemoteRoot: "/app",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.StartDebug("my-agent", DebugRequest{Port: DefaultDebugPort, WaitForClient: true})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultDebugPort, resp.Port)
+	assert.Equal(t, "/app", resp.RemoteRoot)
+}
+
+func TestStartDebugInvalid(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.StartDebug("My Agent", DebugRequest{Port: DefaultDebugPort})
+	assert.Error(t, err)
+
+	_, err = client.StartDebug("my-agent", DebugRequest{Port: 0})
+	assert.Error(t, err)
+
+	_, err = client.StartDebug("my-agent", DebugRequest{Port: 70000})
+	assert.Error(t, err)
+}
+
+func TestStopDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/debug", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DebugResponse{Agent: Agent{Slug: "my-agent"}, Message: "Debugger disabled"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.StopDebug("my-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "Debugger disabled", resp.Message)
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Deployment is a single deploy of an agent
+type Deployment struct {
+	ID         string  `json:"id"`
+	Status     string  `json:"status"` // "pending", "building", "running", "error" or "cancelled"
+	Error      string  `json:"error,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+	FinishedAt *string `json:"finishedAt"`
+	// Message is the release message given with 'oken deploy -m'
+	Message string `json:"message,omitempty"`
+	// Git is the commit the deployment was built from, if known
+	Git *GitMetadata `json:"git,omitempty"`
+}
+
+// Done reports whether the deployment has stopped building, successfully or not
+func (d *Deployment) Done() bool {
+	switch d.Status {
+	case "pending", "building", "deploying":
+		return false
+	}
+	return true
+}
+
+// Failed reports whether the deployment finished without a running agent
+func (d *Deployment) Failed() bool {
+	return d.Status == "error" || d.Status == "failed" || d.Status == "cancelled"
+}
+
+// DeploymentsListResponse is returned when listing an agent's deployments
+type DeploymentsListResponse struct {
+	Deployments []Deployment `json:"deployments"`
+}
+
+// CancelDeploymentResponse is returned when cancelling a deployment
+type CancelDeploymentResponse struct {
+	Deployment Deployment `json:"deployment"`
+	Message    string     `json:"message"`
+}
+
+// validateDeploymentID checks that a deployment ID is non-empty
+func validateDeploymentID(id string) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("deployment ID cannot be empty")
+	}
+	return nil
+}
+
+// ListDeployments returns the deployments of an agent, newest first
+func (c *Client) ListDeployments(slug string) (*DeploymentsListResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	var resp DeploymentsListResponse
+	if err := c.Get(fmt.Sprintf("/api/agents/%s/deployments", slug), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetDeployment returns a single deployment by ID
+func (c *Client) GetDeployment(id string) (*Deployment, error) {
+	if err := validateDeploymentID(id); err != nil {
+		return nil, err
+	}
+	var resp Deployment
+	if err := c.Get(fmt.Sprintf("/api/deployments/%s", url.PathEscape(id)), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CancelDeployment stops an in-flight deployment. The previously running
+// deployment, if any, keeps serving.
+func (c *Client) CancelDeployment(id string) (*CancelDeploymentResponse, error) {
+	if err := validateDeploymentID(id); err != nil {
+		return nil, err
+	}
+	var resp CancelDeploymentResponse
+	if err := c.Post(fmt.Sprintf("/api/deployments/%s/cancel", url.PathEscape(id)), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// WaitForDeployment polls until the deployment is done or the timeout expires
+func (c *Client) WaitForDeployment(id string, interval time.Duration, timeout time.Duration) (*Deployment, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		resp, err := c.GetDeployment(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.Done() {
+			return resp, nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return resp, fmt.Errorf("deployment did not finish before timeout")
+		}
+
+		// Wait before next poll
+		time.Sleep(interval)
+	}
+}
+
+// GetDeploymentLogsStream streams the build logs of a deployment to w as
+// they are written, returning when the build finishes or ctx is cancelled
+func (c *Client) GetDeploymentLogsStream(ctx context.Context, id string, w io.Writer) error {
+	if err := validateDeploymentID(id); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/deployments/%s/logs?follow=true", c.BaseURL, url.PathEscape(id)), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	c.setHeaders(req)
+
+	// Builds may take a while, so no client timeout; ctx cancels
+	resp, err := c.send(c.StreamClient, req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	c.noteDeprecation(resp)
+
+	if resp.StatusCode >= 400 {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if err != nil {
+			return err
+		}
+		return decodeAPIError(resp.StatusCode, body)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// SSE format: "event: done" ends the stream, "data: <line>" carries output
+		if line == "event: done" {
+			return nil
+		}
+		if content, found := strings.CutPrefix(line, "data: "); found {
+			if _, err := fmt.Fprintln(w, content); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDeploymentLogsStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/deployments/dep_123/logs", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("follow"))
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "data: Collecting requests\n\n")
+		_, _ = fmt.Fprint(w, ": keepalive\n\n")
+		_, _ = fmt.Fprint(w, "data: Successfully installed requests-2.32.0\n\n")
+		_, _ = fmt.Fprint(w, "event: done\ndata: \n\n")
+		_, _ = fmt.Fprint(w, "data: ignored\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	var out bytes.Buffer
+	err := client.GetDeploymentLogsStream(context.Background(), "dep_123", &out)
+	require.NoError(t, err)
+	assert.Equal(t, "Collecting requests\nSuccessfully installed requests-2.32.0\n", out.String())
+}
+
+func TestGetDeploymentLogsStreamAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Deployment not found"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	err := client.GetDeploymentLogsStream(context.Background(), "dep_missing", &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Deployment not found")
+
+	err = client.GetDeploymentLogsStream(context.Background(), "", &bytes.Buffer{})
+	require.Error(t, err)
+}
+
+func TestWaitForDeployment(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/deployments/dep_123", r.URL.Path)
+
+		polls++
+		status := "building"
+		if polls >= 2 {
+			status = "error"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Deployment{ID: "dep_123", Status: status, Error: "pip install failed"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	dep, err := client.WaitForDeployment("dep_123", 10*time.Millisecond, 5*time.Second)
+	require.NoError(t, err)
+	assert.True(t, dep.Done())
+	assert.True(t, dep.Failed())
+	assert.Equal(t, 2, polls)
+}
+
+func TestWaitForDeploymentTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Deployment{ID: "dep_123", Status: "pending"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	dep, err := client.WaitForDeployment("dep_123", 10*time.Millisecond, 50*time.Millisecond)
+	require.Error(t, err)
+	require.NotNil(t, dep)
+	assert.False(t, dep.Done())
+}
+
+func TestCancelDeployment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/deployments/dep_123/cancel", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(CancelDeploymentResponse{
+			Deployment: Deployment{ID: "dep_123", Status: "cancelled"},
+			Message:    "Deployment cancelled",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.CancelDeployment("dep_123")
+	require.NoError(t, err)
+	assert.Equal(t, "cancelled", resp.Deployment.Status)
+	assert.True(t, resp.Deployment.Done())
+	assert.True(t, resp.Deployment.Failed())
+
+	_, err = client.CancelDeployment(" ")
+	require.Error(t, err)
+}
+
+func TestListDeployments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/deployments", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeploymentsListResponse{Deployments: []Deployment{
+			{ID: "dep_2", Status: "building", Git: &GitMetadata{Commit: "0123456789abcdef", Branch: "main", Dirty: true}},
+			{ID: "dep_1", Status: "running"},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListDeployments("my-agent")
+	require.NoError(t, err)
+	require.Len(t, resp.Deployments, 2)
+	assert.Equal(t, "dep_2", resp.Deployments[0].ID)
+	assert.False(t, resp.Deployments[0].Done())
+	require.NotNil(t, resp.Deployments[0].Git)
+	assert.Equal(t, "0123456", resp.Deployments[0].Git.ShortCommit())
+	assert.True(t, resp.Deployments[0].Git.Dirty)
+	assert.Nil(t, resp.Deployments[1].Git)
+}
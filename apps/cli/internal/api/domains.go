@@ -0,0 +1,147 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// validateDomain checks that a domain is a plausible fully qualified hostname
+func validateDomain(domain string) error {
+	if domain == "" {
+		return fmt.Errorf("domain cannot be empty")
+	}
+	if len(domain) > 253 {
+		return fmt.Errorf("domain too long (max 253 characters)")
+	}
+	if !domainPattern.MatchString(domain) {
+		return fmt.Errorf("invalid domain %q: must be a lowercase hostname like api.example.com", domain)
+	}
+	return nil
+}
+
+// DNSRecord is a DNS record that must exist for a custom domain to verify
+type DNSRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Domain is a custom domain attached to an agent
+type Domain struct {
+	Name      string      `json:"name"`
+	Status    string      `json:"status"` // "pending", "verified" or "failed"
+	Records   []DNSRecord `json:"records"`
+	CreatedAt string      `json:"createdAt"`
+}
+
+// DomainsListResponse is returned when listing an agent's domains
+type DomainsListResponse struct {
+	Domains []Domain `json:"domains"`
+}
+
+// DomainResponse is returned when adding or verifying a domain
+type DomainResponse struct {
+	Domain  Domain `json:"domain"`
+	Message string `json:"message"`
+}
+
+// RemoveDomainResponse is returned when removing a domain
+type RemoveDomainResponse struct {
+	Message string `json:"message"`
+}
+
+// ListDomains returns the custom domains attached to an agent
+func (c *Client) ListDomains(slug string) (*DomainsListResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	var resp DomainsListResponse
+	if err := c.Get(fmt.Sprintf("/api/agents/%s/domains", slug), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddDomain attaches a custom domain to an agent
+func (c *Client) AddDomain(slug, domain string) (*DomainResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	domain = strings.ToLower(domain)
+	if err := validateDomain(domain); err != nil {
+		return nil, err
+	}
+	body := map[string]string{"domain": domain}
+	var resp DomainResponse
+	if err := c.Post(fmt.Sprintf("/api/agents/%s/domains", slug), body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// VerifyDomain asks the platform to check the DNS records of a domain
+func (c *Client) VerifyDomain(slug, domain string) (*DomainResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	domain = strings.ToLower(domain)
+	if err := validateDomain(domain); err != nil {
+		return nil, err
+	}
+	var resp DomainResponse
+	if err := c.Post(fmt.Sprintf("/api/agents/%s/domains/%s/verify", slug, url.PathEscape(domain)), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// WaitForDomainVerification polls until the domain is verified, its
+// verification fails or the timeout expires
+func (c *Client) WaitForDomainVerification(slug, domain string, interval time.Duration, timeout time.Duration) (*DomainResponse, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		resp, err := c.VerifyDomain(slug, domain)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.Domain.Status {
+		case "verified":
+			return resp, nil
+		case "failed":
+			if resp.Message != "" {
+				return resp, fmt.Errorf("domain verification failed: %s", resp.Message)
+			}
+			return resp, fmt.Errorf("domain verification failed")
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return resp, fmt.Errorf("domain verification timed out")
+		}
+
+		// Wait before next poll
+		time.Sleep(interval)
+	}
+}
+
+// RemoveDomain detaches a custom domain from an agent
+func (c *Client) RemoveDomain(slug, domain string) (*RemoveDomainResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	domain = strings.ToLower(domain)
+	if err := validateDomain(domain); err != nil {
+		return nil, err
+	}
+	var resp RemoveDomainResponse
+	if err := c.Delete(fmt.Sprintf("/api/agents/%s/domains/%s", slug, url.PathEscape(domain)), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		wantErr bool
+	}{
+		{"apex", "example.com", false},
+		{"subdomain", "api.mycompany.com", false},
+		{"with hyphen", "my-api.example.co", false},
+		{"empty", "", true},
+		{"no tld", "localhost", true},
+		{"uppercase", "API.example.com", true},
+		{"with scheme", "https://example.com", true},
+		{"with path", "example.com/api", true},
+		{"too long", strings.Repeat("a.", 127) + "com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDomain(tt.domain)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAddDomain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/domains", r.URL.Path)
+
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "api.mycompany.com", body["domain"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DomainResponse{
+			Domain: Domain{
+				Name:    "api.mycompany.com",
+				Status:  "pending",
+				Records: []DNSRecord{{Type: "CNAME", Name: "api", Value: "my-agent.oken.dev"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.AddDomain("my-agent", "API.mycompany.com")
+	require.NoError(t, err)
+	assert.Equal(t, "pending", resp.Domain.Status)
+	require.Len(t, resp.Domain.Records, 1)
+	assert.Equal(t, "CNAME", resp.Domain.Records[0].Type)
+}
+
+func TestListDomains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/domains", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DomainsListResponse{
+			Domains: []Domain{{Name: "api.mycompany.com", Status: "verified"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListDomains("my-agent")
+	require.NoError(t, err)
+	require.Len(t, resp.Domains, 1)
+	assert.Equal(t, "verified", resp.Domains[0].Status)
+}
+
+func TestWaitForDomainVerification(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/domains/api.mycompany.com/verify", r.URL.Path)
+
+		polls++
+		status := "pending"
+		if polls >= 3 {
+			status = "verified"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DomainResponse{Domain: Domain{Name: "api.mycompany.com", Status: status}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.WaitForDomainVerification("my-agent", "api.mycompany.com", 10*time.Millisecond, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "verified", resp.Domain.Status)
+	assert.Equal(t, 3, polls)
+}
+
+func TestWaitForDomainVerificationTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DomainResponse{Domain: Domain{Name: "api.mycompany.com", Status: "pending"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.WaitForDomainVerification("my-agent", "api.mycompany.com", 10*time.Millisecond, 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+	require.NotNil(t, resp)
+	assert.Equal(t, "pending", resp.Domain.Status)
+}
+
+func TestWaitForDomainVerificationFailed(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DomainResponse{
+			Domain:  Domain{Name: "api.mycompany.com", Status: "failed"},
+			Message: "CNAME record points to another host",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.WaitForDomainVerification("my-agent", "api.mycompany.com", 10*time.Millisecond, 5*time.Second)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CNAME record points to another host")
+	require.NotNil(t, resp)
+	assert.Equal(t, "failed", resp.Domain.Status)
+	assert.Equal(t, 1, polls)
+}
+
+func TestRemoveDomain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/domains/api.mycompany.com", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RemoveDomainResponse{Message: "Domain removed"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.RemoveDomain("my-agent", "api.mycompany.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Domain removed", resp.Message)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// headerNamePattern matches HTTP header field names (RFC 9110 tokens)
+var headerNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// ParseHeaders parses 'Name: value' arguments into a header. A name may be
+// repeated to send several values.
+func ParseHeaders(args []string) (http.Header, error) {
+	header := http.Header{}
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, ":")
+		name = strings.TrimSpace(name)
+		if !ok || !headerNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid header %q: use 'Name: value'", arg)
+		}
+		value = strings.TrimSpace(value)
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid header %q: value can't contain line breaks", arg)
+		}
+		header.Add(name, value)
+	}
+	return header, nil
+}
+
+// EndpointURL joins an agent's public endpoint and a path, which may carry a
+// query string
+func EndpointURL(endpoint, path string) string {
+	endpoint = strings.TrimRight(endpoint, "/")
+	if path == "" {
+		return endpoint
+	}
+	return endpoint + "/" + strings.TrimLeft(path, "/")
+}
+
+// EndpointRequest sends a request to an agent's public endpoint, adding the
+// client's token and organization unless header sets them. header may be
+// nil. The caller must close the response body.
+func (c *Client) EndpointRequest(endpoint, method, path string, header http.Header, body io.Reader) (*http.Response, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("agent has no endpoint")
+	}
+	req, err := http.NewRequest(method, EndpointURL(endpoint, path), body)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	return c.HTTPClient.Do(req)
+}
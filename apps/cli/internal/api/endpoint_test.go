@@ -0,0 +1,85 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointURL(t *testing.T) {
+	assert.Equal(t, "https://my-agent.oken.dev", EndpointURL("https://my-agent.oken.dev/", ""))
+	assert.Equal(t, "https://my-agent.oken.dev/health", EndpointURL("https://my-agent.oken.dev", "health"))
+	assert.Equal(t, "https://my-agent.oken.dev/v1/chat?stream=true", EndpointURL("https://my-agent.oken.dev/", "/v1/chat?stream=true"))
+}
+
+func TestParseHeaders(t *testing.T) {
+	header, err := ParseHeaders([]string{"X-Trace-Id: abc", "x-flag:on", "X-Flag: beta", "X-Empty:"})
+	require.NoError(t, err)
+	assert.Equal(t, http.Header{
+		"X-Trace-Id": {"abc"},
+		"X-Flag":     {"on", "beta"},
+		"X-Empty":    {""},
+	}, header)
+
+	for _, arg := range []string{"X-Trace-Id", ": abc", "X Trace: abc", "X-Trace: a\nb"} {
+		_, err := ParseHeaders([]string{arg})
+		assert.Error(t, err, arg)
+	}
+}
+
+func TestEndpointRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/echo", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "acme", r.Header.Get(OrgHeader))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "1", r.Header.Get("X-Debug"))
+
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient("http://platform.invalid", "test-token")
+	client.Org = "acme"
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Debug", "1")
+	resp, err := client.EndpointRequest(server.URL, http.MethodPost, "/v1/echo", header, strings.NewReader(`{"a":1}`))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `{"a":1}`, string(body))
+}
+
+func TestEndpointRequestOverridesAuthorization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer agent-key", r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	client := NewClient("http://platform.invalid", "test-token")
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer agent-key")
+	resp, err := client.EndpointRequest(server.URL, http.MethodGet, "", header, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+}
+
+func TestEndpointRequestWithoutEndpoint(t *testing.T) {
+	client := NewClient("http://platform.invalid", "test-token")
+
+	_, err := client.EndpointRequest("", http.MethodGet, "/", nil, nil)
+	require.Error(t, err)
+}
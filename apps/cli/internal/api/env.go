@@ -0,0 +1,97 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateEnvName checks that a name can be used as an environment variable
+func validateEnvName(name string) error {
+	if !envNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid variable name %q: use letters, digits and underscores, not starting with a digit", name)
+	}
+	return nil
+}
+
+// EnvVar is a plain configuration variable. Unlike secrets, values can be
+// read back.
+type EnvVar struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	AgentSlug *string `json:"agentSlug"`
+	UpdatedAt string  `json:"updatedAt"`
+}
+
+// EnvListResponse is returned when listing environment variables
+type EnvListResponse struct {
+	Vars []EnvVar `json:"vars"`
+}
+
+// SetEnvRequest is the request body for setting an environment variable
+type SetEnvRequest struct {
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	AgentSlug *string `json:"agentSlug,omitempty"`
+}
+
+// SetEnvResponse is returned when setting an environment variable
+type SetEnvResponse struct {
+	Message string `json:"message"`
+	Var     EnvVar `json:"var"`
+}
+
+// UnsetEnvResponse is returned when removing an environment variable
+type UnsetEnvResponse struct {
+	Message string `json:"message"`
+	Name    string `json:"name"`
+}
+
+// ListEnv returns the environment variables of the account, or of one agent
+func (c *Client) ListEnv(agentSlug string) (*EnvListResponse, error) {
+	path := "/api/env"
+	if agentSlug != "" {
+		path = fmt.Sprintf("/api/env?agent=%s", url.QueryEscape(agentSlug))
+	}
+
+	var resp EnvListResponse
+	if err := c.Get(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetEnv creates or updates an environment variable
+func (c *Client) SetEnv(name, value string, agentSlug *string) (*SetEnvResponse, error) {
+	if err := validateEnvName(name); err != nil {
+		return nil, err
+	}
+	body := SetEnvRequest{
+		Name:      name,
+		Value:     value,
+		AgentSlug: agentSlug,
+	}
+
+	var resp SetEnvResponse
+	if err := c.Post("/api/env", body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UnsetEnv removes an environment variable
+func (c *Client) UnsetEnv(name string, agentSlug string) (*UnsetEnvResponse, error) {
+	path := fmt.Sprintf("/api/env?name=%s", url.QueryEscape(name))
+	if agentSlug != "" {
+		path = fmt.Sprintf("%s&agent=%s", path, url.QueryEscape(agentSlug))
+	}
+
+	var resp UnsetEnvResponse
+	if err := c.Delete(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
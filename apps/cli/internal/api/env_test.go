@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEnvName(t *testing.T) {
+	for _, name := range []string{"LOG_LEVEL", "_PRIVATE", "debug", "A1"} {
+		assert.NoError(t, validateEnvName(name), name)
+	}
+	for _, name := range []string{"", "1ST", "LOG-LEVEL", "A B", "X=Y"} {
+		assert.Error(t, validateEnvName(name), name)
+	}
+}
+
+func TestListEnv(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/env", r.URL.Path)
+		assert.Equal(t, "my-agent", r.URL.Query().Get("agent"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(EnvListResponse{Vars: []EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListEnv("my-agent")
+	require.NoError(t, err)
+	require.Len(t, resp.Vars, 1)
+	assert.Equal(t, "debug", resp.Vars[0].Value)
+}
+
+func TestSetEnv(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/env", r.URL.Path)
+
+		var body SetEnvRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "LOG_LEVEL", body.Name)
+		assert.Equal(t, "", body.Value)
+		require.NotNil(t, body.AgentSlug)
+		assert.Equal(t, "my-agent", *body.AgentSlug)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SetEnvResponse{Message: "Variable set", Var: EnvVar{Name: body.Name}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	agent := "my-agent"
+	resp, err := client.SetEnv("LOG_LEVEL", "", &agent)
+	require.NoError(t, err)
+	assert.Equal(t, "Variable set", resp.Message)
+}
+
+func TestSetEnvInvalidName(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.SetEnv("LOG-LEVEL", "debug", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid variable name")
+}
+
+func TestUnsetEnv(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/env", r.URL.Path)
+		assert.Equal(t, "LOG_LEVEL", r.URL.Query().Get("name"))
+		assert.Empty(t, r.URL.Query().Get("agent"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(UnsetEnvResponse{Message: "Variable removed", Name: "LOG_LEVEL"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.UnsetEnv("LOG_LEVEL", "")
+	require.NoError(t, err)
+}
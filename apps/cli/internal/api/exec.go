@@ -0,0 +1,108 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ExecRequest is the request body for running a command in an agent
+type ExecRequest struct {
+	Command []string `json:"command"`
+	Stdin   []byte   `json:"stdin,omitempty"`
+}
+
+// ExecFrame is one line of an exec output stream. Output frames carry a
+// stream name and data; the final frame carries the exit code or an error.
+type ExecFrame struct {
+	Stream   string `json:"stream,omitempty"`
+	Data     []byte `json:"data,omitempty"`
+	ExitCode *int   `json:"exitCode,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Exec runs a one-off command in the agent's running environment, copying its
+// output to stdout and stderr as it arrives, and returns the exit code
+func (c *Client) Exec(ctx context.Context, slug string, command []string, stdout, stderr io.Writer) (int, error) {
+	return c.ExecWithInput(ctx, slug, command, nil, stdout, stderr)
+}
+
+// ExecWithInput is like Exec, but sends stdin to the command as its standard input
+func (c *Client) ExecWithInput(ctx context.Context, slug string, command []string, stdin []byte, stdout, stderr io.Writer) (int, error) {
+	if err := validateSlug(slug); err != nil {
+		return 0, err
+	}
+	if len(command) == 0 {
+		return 0, fmt.Errorf("command cannot be empty")
+	}
+
+	data, err := json.Marshal(ExecRequest{Command: command, Stdin: stdin})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/agents/%s/exec", c.BaseURL, slug), bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/x-ndjson")
+	c.setHeaders(req)
+
+	// Commands may run for a while, so no client timeout; ctx cancels
+	resp, err := c.send(c.StreamClient, req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	c.noteDeprecation(resp)
+
+	if resp.StatusCode >= 400 {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if err != nil {
+			return 0, err
+		}
+		return 0, decodeAPIError(resp.StatusCode, body)
+	}
+
+	return readExecStream(resp.Body, stdout, stderr)
+}
+
+// readExecStream copies output frames to stdout and stderr until the exit frame
+func readExecStream(r io.Reader, stdout, stderr io.Writer) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var frame ExecFrame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return 0, fmt.Errorf("invalid exec frame: %w", err)
+		}
+
+		switch {
+		case frame.Error != "":
+			return 0, fmt.Errorf("%s", frame.Error)
+		case frame.ExitCode != nil:
+			return *frame.ExitCode, nil
+		case frame.Stream == "stderr":
+			if _, err := stderr.Write(frame.Data); err != nil {
+				return 0, err
+			}
+		default:
+			if _, err := stdout.Write(frame.Data); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("exec stream ended without exit status")
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFrame(w http.ResponseWriter, frame ExecFrame) {
+	_ = json.NewEncoder(w).Encode(frame)
+}
+
+func TestExec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/exec", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		var body ExecRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, []string{"pip", "list"}, body.Command)
+
+		code := 3
+		writeFrame(w, ExecFrame{Stream: "stdout", Data: []byte("requests 2.32\n")})
+		writeFrame(w, ExecFrame{Stream: "stderr", Data: []byte("warning\n")})
+		writeFrame(w, ExecFrame{Stream: "stdout", Data: []byte("\x00binary")})
+		writeFrame(w, ExecFrame{ExitCode: &code})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	var stdout, stderr bytes.Buffer
+	code, err := client.Exec(context.Background(), "my-agent", []string{"pip", "list"}, &stdout, &stderr)
+	require.NoError(t, err)
+	assert.Equal(t, 3, code)
+	assert.Equal(t, "requests 2.32\n\x00binary", stdout.String())
+	assert.Equal(t, "warning\n", stderr.String())
+}
+
+func TestExecWithInput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body ExecRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, []string{"tar", "-xzf", "-"}, body.Command)
+		assert.Equal(t, []byte("\x1f\x8barchive"), body.Stdin)
+
+		code := 0
+		writeFrame(w, ExecFrame{ExitCode: &code})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	var out bytes.Buffer
+	code, err := client.ExecWithInput(context.Background(), "my-agent", []string{"tar", "-xzf", "-"}, []byte("\x1f\x8barchive"), &out, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+}
+
+func TestExecErrorFrame(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeFrame(w, ExecFrame{Error: "agent is not running"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	var out bytes.Buffer
+	_, err := client.Exec(context.Background(), "my-agent", []string{"ls"}, &out, &out)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "agent is not running")
+}
+
+func TestExecTruncatedStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeFrame(w, ExecFrame{Stream: "stdout", Data: []byte("partial")})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	var out bytes.Buffer
+	_, err := client.Exec(context.Background(), "my-agent", []string{"ls"}, &out, &out)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "without exit status")
+}
+
+func TestExecAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "exec requires manager access"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	var out bytes.Buffer
+	_, err := client.Exec(context.Background(), "my-agent", []string{"ls"}, &out, &out)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "manager access")
+}
+
+func TestExecValidation(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	var out bytes.Buffer
+	_, err := client.Exec(context.Background(), "my-agent", nil, &out, &out)
+	require.Error(t, err)
+
+	_, err = client.Exec(context.Background(), "../x", []string{"ls"}, &out, &out)
+	require.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HealthResponse is returned when probing an agent's health endpoint
+type HealthResponse struct {
+	Healthy    bool   `json:"healthy"`
+	StatusCode int    `json:"statusCode"`
+	LatencyMs  int64  `json:"latencyMs"`
+	ColdStart  bool   `json:"coldStart"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CheckHealth asks the platform to probe an agent's health endpoint
+func (c *Client) CheckHealth(slug string) (*HealthResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	var resp HealthResponse
+	if err := c.Get(fmt.Sprintf("/api/agents/%s/health", slug), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProbeEndpoint calls the health endpoint of an agent directly at its public
+// endpoint, measuring latency from this machine. No credentials are sent.
+// Only the platform knows whether an instance had to start, so ColdStart is
+// never set.
+func (c *Client) ProbeEndpoint(endpoint string) (*HealthResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(endpoint, "/")+"/health", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return &HealthResponse{Error: err.Error()}, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+
+	health := &HealthResponse{
+		Healthy:    resp.StatusCode < 300,
+		StatusCode: resp.StatusCode,
+		LatencyMs:  time.Since(start).Milliseconds(),
+	}
+	if !health.Healthy {
+		health.Error = fmt.Sprintf("health endpoint returned status %d", resp.StatusCode)
+	}
+	return health, nil
+}
+
+// WaitForHealthy polls until the agent is healthy or the timeout expires
+func (c *Client) WaitForHealthy(slug string, interval time.Duration, timeout time.Duration) (*HealthResponse, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		resp, err := c.CheckHealth(slug)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.Healthy {
+			return resp, nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return resp, fmt.Errorf("agent not healthy before timeout")
+		}
+
+		// Wait before next poll
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/health", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(HealthResponse{Healthy: true, StatusCode: 200, LatencyMs: 120, ColdStart: true})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.CheckHealth("my-agent")
+	require.NoError(t, err)
+	assert.True(t, resp.Healthy)
+	assert.Equal(t, int64(120), resp.LatencyMs)
+	assert.True(t, resp.ColdStart)
+}
+
+func TestCheckHealthInvalidSlug(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.CheckHealth("../admin")
+	require.Error(t, err)
+}
+
+func TestProbeEndpoint(t *testing.T) {
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"))
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("http://localhost", "test-token")
+
+	resp, err := client.ProbeEndpoint(server.URL + "/")
+	require.NoError(t, err)
+	assert.True(t, resp.Healthy)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	healthy = false
+	resp, err = client.ProbeEndpoint(server.URL)
+	require.NoError(t, err)
+	assert.False(t, resp.Healthy)
+	assert.Contains(t, resp.Error, "503")
+}
+
+func TestWaitForHealthy(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(HealthResponse{Healthy: calls >= 3})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.WaitForHealthy("my-agent", time.Millisecond, time.Second)
+	require.NoError(t, err)
+	assert.True(t, resp.Healthy)
+	assert.Equal(t, 3, calls)
+}
+
+func TestWaitForHealthyTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(HealthResponse{Healthy: false, Error: "starting"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.WaitForHealthy("my-agent", 10*time.Millisecond, 30*time.Millisecond)
+	require.Error(t, err)
+	assert.Equal(t, "starting", resp.Error)
+}
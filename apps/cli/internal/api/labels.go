@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	labelKeyPattern   = regexp.MustCompile(`^[a-z0-9]([a-z0-9._/-]*[a-z0-9])?$`)
+	labelValuePattern = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?)?$`)
+)
+
+// validateLabel checks a label key and value: keys are up to 63 lowercase
+// letters, numbers, '.', '_', '/' and '-', values up to 63 characters of
+// letters, numbers, '.', '_' and '-'
+func validateLabel(key, value string) error {
+	if len(key) > 63 || !labelKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid label key %q: use up to 63 lowercase letters, numbers, '.', '_', '/' and '-', starting and ending with a letter or number", key)
+	}
+	if len(value) > 63 || !labelValuePattern.MatchString(value) {
+		return fmt.Errorf("invalid label value %q for %s: use up to 63 letters, numbers, '.', '_' and '-', starting and ending with a letter or number", value, key)
+	}
+	return nil
+}
+
+// ParseLabels parses key=value arguments into labels
+func ParseLabels(args []string) (map[string]string, error) {
+	labels := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid label %q: use key=value", arg)
+		}
+		if err := validateLabel(key, value); err != nil {
+			return nil, err
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// MatchLabels reports whether an agent has every label in selector
+func MatchLabels(agent Agent, selector map[string]string) bool {
+	for key, value := range selector {
+		if v, ok := agent.Labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatLabels renders labels as key=value pairs sorted by key, separated by
+// commas
+func FormatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// LabelRequest is the request body for changing an agent's labels
+type LabelRequest struct {
+	// Set adds labels or changes their values
+	Set map[string]string `json:"set,omitempty"`
+	// Remove deletes labels by key
+	Remove []string `json:"remove,omitempty"`
+}
+
+// LabelResponse is returned when changing an agent's labels
+type LabelResponse struct {
+	Agent   Agent  `json:"agent"`
+	Message string `json:"message"`
+}
+
+// SetLabels adds, changes and removes labels on an agent. Labels not named
+// in the request are kept.
+func (c *Client) SetLabels(slug string, req LabelRequest) (*LabelResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	for key, value := range req.Set {
+		if err := validateLabel(key, value); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range req.Remove {
+		if err := validateLabel(key, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp LabelResponse
+	if err := c.Post(fmt.Sprintf("/api/agents/%s/labels", slug), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels([]string{"team=ml", "tier=prod", "oken.dev/owner=ana", "note="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "ml", "tier": "prod", "oken.dev/owner": "ana", "note": ""}, labels)
+
+	for _, arg := range []string{"team", "Team=ml", "=ml", "team=m l", "team=-ml", strings.Repeat("k", 64) + "=v"} {
+		_, err := ParseLabels([]string{arg})
+		assert.Error(t, err, arg)
+	}
+}
+
+func TestMatchLabels(t *testing.T) {
+	agent := Agent{Labels: map[string]string{"team": "ml", "tier": "prod"}}
+
+	assert.True(t, MatchLabels(agent, nil))
+	assert.True(t, MatchLabels(agent, map[string]string{"team": "ml"}))
+	assert.True(t, MatchLabels(agent, map[string]string{"team": "ml", "tier": "prod"}))
+	assert.False(t, MatchLabels(agent, map[string]string{"team": "web"}))
+	assert.False(t, MatchLabels(agent, map[string]string{"owner": ""}))
+	assert.False(t, MatchLabels(Agent{}, map[string]string{"team": "ml"}))
+}
+
+func TestFormatLabels(t *testing.T) {
+	assert.Equal(t, "team=ml,tier=prod", FormatLabels(map[string]string{"tier": "prod", "team": "ml"}))
+	assert.Equal(t, "", FormatLabels(nil))
+}
+
+func TestSetLabels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/labels", r.URL.Path)
+
+		var req LabelRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, map[string]string{"tier": "prod"}, req.Set)
+		assert.Equal(t, []string{"team"}, req.Remove)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(LabelResponse{
+			Agent:   Agent{Slug: "my-agent", Labels: map[string]string{"tier": "prod"}},
+			Message: "Labels updated",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.SetLabels("my-agent", LabelRequest{Set: map[string]string{"tier": "prod"}, Remove: []string{"team"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"tier": "prod"}, resp.Agent.Labels)
+
+	_, err = client.SetLabels("my-agent", LabelRequest{Set: map[string]string{"Tier": "prod"}})
+	assert.Error(t, err)
+}
+
+func TestDeployAgentWithLabels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(10<<20))
+		assert.JSONEq(t, `{"team":"ml","tier":"prod"}`, r.FormValue("labels"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader(""), DeployOptions{
+		Labels: map[string]string{"team": "ml", "tier": "prod"},
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RestartPolicies are the restart policies supported by the platform:
+// restart instances whenever they exit, only when they crash, or never
+var RestartPolicies = []string{"always", "on-failure", "never"}
+
+// AutosleepOff disables autosleep, keeping idle instances running
+const AutosleepOff = "off"
+
+// Lifecycle controls how the platform keeps an agent's instances running.
+// Unset fields keep their current value.
+type Lifecycle struct {
+	// MinInstances is how many instances are kept running while the agent
+	// is idle; 0 lets it scale to zero
+	MinInstances *int `json:"minInstances,omitempty"`
+	// Autosleep is how long instances may be idle before they are stopped,
+	// as a duration like 15m, or AutosleepOff
+	Autosleep string `json:"autosleep,omitempty"`
+	// RestartPolicy is one of RestartPolicies
+	RestartPolicy string `json:"restartPolicy,omitempty"`
+}
+
+// IsZero reports whether no setting is set
+func (l Lifecycle) IsZero() bool {
+	return l.MinInstances == nil && l.Autosleep == "" && l.RestartPolicy == ""
+}
+
+// Validate checks that min instances isn't negative, that autosleep is a
+// positive duration or off, and that the restart policy is supported
+func (l Lifecycle) Validate() error {
+	if l.MinInstances != nil && *l.MinInstances < 0 {
+		return fmt.Errorf("invalid min instances %d: must be 0 or more", *l.MinInstances)
+	}
+	if l.Autosleep != "" && l.Autosleep != AutosleepOff {
+		if d, err := time.ParseDuration(l.Autosleep); err != nil || d <= 0 {
+			return fmt.Errorf("invalid autosleep %q: must be a duration like 15m, or %s", l.Autosleep, AutosleepOff)
+		}
+	}
+	if l.RestartPolicy != "" && !slices.Contains(RestartPolicies, l.RestartPolicy) {
+		return fmt.Errorf("invalid restart policy %q: must be one of %s", l.RestartPolicy, strings.Join(RestartPolicies, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleValidate(t *testing.T) {
+	zero, one, negative := 0, 1, -1
+
+	valid := []Lifecycle{
+		{},
+		{MinInstances: &zero},
+		{MinInstances: &one, Autosleep: "15m", RestartPolicy: "on-failure"},
+		{Autosleep: AutosleepOff, RestartPolicy: "never"},
+	}
+	for _, l := range valid {
+		assert.NoError(t, l.Validate(), "%+v", l)
+	}
+
+	invalid := []Lifecycle{
+		{MinInstances: &negative},
+		{Autosleep: "15"},
+		{Autosleep: "0s"},
+		{Autosleep: "never"},
+		{RestartPolicy: "sometimes"},
+	}
+	for _, l := range invalid {
+		assert.Error(t, l.Validate(), "%+v", l)
+	}
+}
+
+func TestLifecycleIsZero(t *testing.T) {
+	zero := 0
+	assert.True(t, Lifecycle{}.IsZero())
+	assert.False(t, Lifecycle{MinInstances: &zero}.IsZero())
+	assert.False(t, Lifecycle{RestartPolicy: "always"}.IsZero())
+}
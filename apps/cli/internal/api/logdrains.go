@@ -0,0 +1,104 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// LogDrainTypes are the log drain protocols supported by the platform: an
+// OpenTelemetry collector over OTLP/HTTP, the Datadog logs intake, Grafana
+// Loki's push API, or newline-delimited JSON posted to any HTTP endpoint
+var LogDrainTypes = []string{"otlp", "datadog", "loki", "http"}
+
+// LogDrain forwards an agent's logs to an external service
+type LogDrain struct {
+	ID        string `json:"id"`
+	AgentSlug string `json:"agentSlug"`
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	// HeaderNames are the headers sent with each batch; their values are
+	// write-only, since they usually hold API keys
+	HeaderNames []string `json:"headerNames,omitempty"`
+	CreatedAt   string   `json:"createdAt"`
+}
+
+// LogDrainsListResponse is returned when listing an agent's log drains
+type LogDrainsListResponse struct {
+	LogDrains []LogDrain `json:"logDrains"`
+}
+
+// AddLogDrainRequest is the request body for adding a log drain
+type AddLogDrainRequest struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	// Header is sent with each batch of logs, for example to authenticate
+	Header http.Header `json:"headers,omitempty"`
+}
+
+// LogDrainResponse is returned when adding a log drain
+type LogDrainResponse struct {
+	LogDrain LogDrain `json:"logDrain"`
+	Message  string   `json:"message"`
+}
+
+// RemoveLogDrainResponse is returned when removing a log drain
+type RemoveLogDrainResponse struct {
+	Message string `json:"message"`
+}
+
+// Validate checks the drain type and that the URL is an absolute http(s) URL
+func (r AddLogDrainRequest) Validate() error {
+	if !slices.Contains(LogDrainTypes, r.Type) {
+		return fmt.Errorf("invalid log drain type %q: must be one of %s", r.Type, strings.Join(LogDrainTypes, ", "))
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid log drain URL %q: must be an absolute http or https URL", r.URL)
+	}
+	return nil
+}
+
+// ListLogDrains returns the log drains of an agent
+func (c *Client) ListLogDrains(slug string) (*LogDrainsListResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	var resp LogDrainsListResponse
+	if err := c.Get(fmt.Sprintf("/api/agents/%s/log-drains", slug), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddLogDrain starts forwarding an agent's logs to an external service
+func (c *Client) AddLogDrain(slug string, req AddLogDrainRequest) (*LogDrainResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	var resp LogDrainResponse
+	if err := c.Post(fmt.Sprintf("/api/agents/%s/log-drains", slug), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RemoveLogDrain stops forwarding logs to a drain, by ID
+func (c *Client) RemoveLogDrain(slug, id string) (*RemoveLogDrainResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, fmt.Errorf("log drain ID cannot be empty")
+	}
+	var resp RemoveLogDrainResponse
+	if err := c.Delete(fmt.Sprintf("/api/agents/%s/log-drains/%s", slug, url.PathEscape(id)), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddLogDrainRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     AddLogDrainRequest
+		wantErr bool
+	}{
+		{"otlp", AddLogDrainRequest{Type: "otlp", URL: "https://otel.acme.com:4318/v1/logs"}, false},
+		{"datadog", AddLogDrainRequest{Type: "datadog", URL: "https://http-intake.logs.datadoghq.com/api/v2/logs"}, false},
+		{"unknown type", AddLogDrainRequest{Type: "syslog", URL: "https://logs.acme.com"}, true},
+		{"no type", AddLogDrainRequest{URL: "https://logs.acme.com"}, true},
+		{"grpc scheme", AddLogDrainRequest{Type: "otlp", URL: "grpc://otel.acme.com:4317"}, true},
+		{"relative URL", AddLogDrainRequest{Type: "http", URL: "/logs"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAddLogDrain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/log-drains", r.URL.Path)
+
+		var req AddLogDrainRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "datadog", req.Type)
+		assert.Equal(t, "https://http-intake.logs.datadoghq.com/api/v2/logs", req.URL)
+		assert.Equal(t, "secret", req.Header.Get("DD-API-KEY"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(LogDrainResponse{
+			LogDrain: LogDrain{ID: "ld_123", AgentSlug: "my-agent", Type: "datadog", URL: req.URL, HeaderNames: []string{"DD-API-KEY"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.AddLogDrain("my-agent", AddLogDrainRequest{
+		Type:   "datadog",
+		URL:    "https://http-intake.logs.datadoghq.com/api/v2/logs",
+		Header: http.Header{"Dd-Api-Key": {"secret"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ld_123", resp.LogDrain.ID)
+	assert.Equal(t, []string{"DD-API-KEY"}, resp.LogDrain.HeaderNames)
+}
+
+func TestAddLogDrainInvalid(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.AddLogDrain("My Agent", AddLogDrainRequest{Type: "otlp", URL: "https://otel.acme.com"})
+	assert.Error(t, err)
+
+	_, err = client.AddLogDrain("my-agent", AddLogDrainRequest{Type: "syslog", URL: "https://otel.acme.com"})
+	assert.Error(t, err)
+}
+
+func TestListLogDrains(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/log-drains", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(LogDrainsListResponse{LogDrains: []LogDrain{{ID: "ld_123", Type: "otlp"}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListLogDrains("my-agent")
+	require.NoError(t, err)
+	require.Len(t, resp.LogDrains, 1)
+	assert.Equal(t, "otlp", resp.LogDrains[0].Type)
+}
+
+func TestRemoveLogDrain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/log-drains/ld_123", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RemoveLogDrainResponse{Message: "Log drain removed"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.RemoveLogDrain("my-agent", "ld_123")
+	require.NoError(t, err)
+
+	_, err = client.RemoveLogDrain("my-agent", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+var windowPattern = regexp.MustCompile(`^[1-9][0-9]*[mhd]$`)
+
+// validateWindow checks that a metrics window looks like 15m, 1h or 7d
+func validateWindow(window string) error {
+	if !windowPattern.MatchString(window) {
+		return fmt.Errorf("invalid window %q: use minutes, hours or days (e.g. 15m, 1h, 7d)", window)
+	}
+	return nil
+}
+
+// AgentMetrics holds aggregated runtime metrics for an agent over a window
+type AgentMetrics struct {
+	Slug         string  `json:"slug"`
+	Window       string  `json:"window"`
+	Invocations  int64   `json:"invocations"`
+	Errors       int64   `json:"errors"`
+	ErrorRate    float64 `json:"errorRate"`
+	LatencyP50Ms float64 `json:"latencyP50Ms"`
+	LatencyP95Ms float64 `json:"latencyP95Ms"`
+	CPUPercent   float64 `json:"cpuPercent"`
+	MemoryBytes  int64   `json:"memoryBytes"`
+	// ActiveInvocations is a point-in-time count of in-flight invocations
+	ActiveInvocations int `json:"activeInvocations"`
+}
+
+// MetricsListResponse is returned when fetching metrics for all agents
+type MetricsListResponse struct {
+	Agents []AgentMetrics `json:"agents"`
+}
+
+// GetAgentMetrics returns metrics for an agent over the given window
+func (c *Client) GetAgentMetrics(slug, window string) (*AgentMetrics, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	if err := validateWindow(window); err != nil {
+		return nil, err
+	}
+	var resp AgentMetrics
+	if err := c.Get(fmt.Sprintf("/api/agents/%s/metrics?window=%s", slug, url.QueryEscape(window)), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListAgentMetrics returns metrics for all running agents over the given window
+func (c *Client) ListAgentMetrics(window string) (*MetricsListResponse, error) {
+	if err := validateWindow(window); err != nil {
+		return nil, err
+	}
+	var resp MetricsListResponse
+	if err := c.Get(fmt.Sprintf("/api/metrics?window=%s", url.QueryEscape(window)), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
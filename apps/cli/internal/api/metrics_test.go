@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  string
+		wantErr bool
+	}{
+		{"minutes", "15m", false},
+		{"hours", "1h", false},
+		{"days", "7d", false},
+		{"empty", "", true},
+		{"zero", "0h", true},
+		{"seconds", "30s", true},
+		{"no unit", "60", true},
+		{"go duration", "1h30m", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWindow(tt.window)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetAgentMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/agents/my-agent/metrics", r.URL.Path)
+		assert.Equal(t, "1h", r.URL.Query().Get("window"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(AgentMetrics{
+			Slug:         "my-agent",
+			Window:       "1h",
+			Invocations:  120,
+			Errors:       6,
+			ErrorRate:    0.05,
+			LatencyP50Ms: 210,
+			LatencyP95Ms: 980,
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	metrics, err := client.GetAgentMetrics("my-agent", "1h")
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), metrics.Invocations)
+	assert.Equal(t, 0.05, metrics.ErrorRate)
+	assert.Equal(t, float64(980), metrics.LatencyP95Ms)
+}
+
+func TestGetAgentMetricsInvalidWindow(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.GetAgentMetrics("my-agent", "forever")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid window")
+}
+
+func TestListAgentMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/metrics", r.URL.Path)
+		assert.Equal(t, "5m", r.URL.Query().Get("window"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MetricsListResponse{
+			Agents: []AgentMetrics{
+				{Slug: "agent-1", CPUPercent: 12.5, ActiveInvocations: 2},
+				{Slug: "agent-2", MemoryBytes: 1024},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListAgentMetrics("5m")
+	require.NoError(t, err)
+	require.Len(t, resp.Agents, 2)
+	assert.Equal(t, 2, resp.Agents[0].ActiveInvocations)
+	assert.Equal(t, int64(1024), resp.Agents[1].MemoryBytes)
+}
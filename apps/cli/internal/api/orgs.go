@@ -0,0 +1,23 @@
+package api
+
+// Org represents an organization the user belongs to
+type Org struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	Role string `json:"role"`
+}
+
+// OrgsListResponse is returned when listing organizations
+type OrgsListResponse struct {
+	Orgs []Org `json:"orgs"`
+}
+
+// ListOrgs returns the organizations the authenticated user belongs to
+func (c *Client) ListOrgs() (*OrgsListResponse, error) {
+	var resp OrgsListResponse
+	if err := c.Get("/api/orgs", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOrgs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/orgs", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(OrgsListResponse{
+			Orgs: []Org{
+				{ID: "1", Name: "Acme Inc", Slug: "acme", Role: "admin"},
+				{ID: "2", Name: "Side Project", Slug: "side-project", Role: "member"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListOrgs()
+	require.NoError(t, err)
+	require.Len(t, resp.Orgs, 2)
+	assert.Equal(t, "acme", resp.Orgs[0].Slug)
+	assert.Equal(t, "member", resp.Orgs[1].Role)
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// PortMapping forwards a local port to a port of an agent's runtime
+type PortMapping struct {
+	// Local is the port to listen on; 0 picks a free one
+	Local  int
+	Remote int
+}
+
+// ParsePortMapping parses "8080:8000" (local:remote), "8000" (the same port
+// on both ends) or ":8000" (any free local port)
+func ParsePortMapping(s string) (PortMapping, error) {
+	local, remote, found := strings.Cut(s, ":")
+	if !found {
+		remote = local
+	}
+	r, err := parsePort(remote, false)
+	if err != nil {
+		return PortMapping{}, fmt.Errorf("invalid port mapping %q: %w", s, err)
+	}
+	l := r
+	if found {
+		if l, err = parsePort(local, true); err != nil {
+			return PortMapping{}, fmt.Errorf("invalid port mapping %q: %w", s, err)
+		}
+	}
+	return PortMapping{Local: l, Remote: r}, nil
+}
+
+// parsePort parses a TCP port. An empty port is 0 if allowEmpty is set.
+func parsePort(s string, allowEmpty bool) (int, error) {
+	if s == "" && allowEmpty {
+		return 0, nil
+	}
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("port must be a number from 1 to 65535")
+	}
+	return port, nil
+}
+
+// Tunnel is a TCP stream to a port of an agent's runtime, carried over a
+// websocket as binary messages in both directions
+type Tunnel struct {
+	conn *websocket.Conn
+	// reader is the rest of the message being read
+	reader io.Reader
+	// writeMu serializes writes, which gorilla/websocket requires
+	writeMu sync.Mutex
+}
+
+// PortForward opens a tunnel to a port of the agent's running environment.
+// Each tunnel carries one TCP connection.
+func (c *Client) PortForward(ctx context.Context, slug string, port int) (*Tunnel, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	conn, err := c.dialWebsocket(ctx, fmt.Sprintf("/api/agents/%s/port-forward?port=%d", slug, port))
+	if err != nil {
+		return nil, err
+	}
+	return &Tunnel{conn: conn}, nil
+}
+
+// Read reads bytes sent by the agent. It returns io.EOF once the agent side
+// closes the connection.
+func (t *Tunnel) Read(p []byte) (int, error) {
+	for {
+		if t.reader != nil {
+			n, err := t.reader.Read(p)
+			if err == io.EOF {
+				t.reader = nil
+				if n == 0 {
+					continue
+				}
+				err = nil
+			}
+			return n, err
+		}
+
+		msgType, reader, err := t.conn.NextReader()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return 0, io.EOF
+			}
+			return 0, err
+		}
+		if msgType == websocket.BinaryMessage {
+			t.reader = reader
+		}
+	}
+}
+
+// Write sends bytes to the agent
+func (t *Tunnel) Write(p []byte) (int, error) {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if err := t.conn.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close ends the tunnel
+func (t *Tunnel) Close() error {
+	t.writeMu.Lock()
+	_ = t.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	t.writeMu.Unlock()
+	return t.conn.Close()
+}
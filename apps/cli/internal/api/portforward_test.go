@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortMapping(t *testing.T) {
+	tests := []struct {
+		in   string
+		want PortMapping
+	}{
+		{"8080:8000", PortMapping{Local: 8080, Remote: 8000}},
+		{"8000", PortMapping{Local: 8000, Remote: 8000}},
+		{":8000", PortMapping{Local: 0, Remote: 8000}},
+	}
+	for _, tt := range tests {
+		got, err := ParsePortMapping(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"", "http", "8080:", "0", "70000", "8080:x", "a:8000"} {
+		_, err := ParsePortMapping(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestPortForward(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/agents/my-agent/port-forward", r.URL.Path)
+		assert.Equal(t, "8000", r.URL.Query().Get("port"))
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		msgType, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, websocket.BinaryMessage, msgType)
+		assert.Equal(t, "GET / HTTP/1.1\r\n\r\n", string(data))
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("ignored"))
+		_ = conn.WriteMessage(websocket.BinaryMessage, []byte("HTTP/1.1 200 OK\r\n"))
+		_ = conn.WriteMessage(websocket.BinaryMessage, []byte("\r\n"))
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	tunnel, err := client.PortForward(context.Background(), "my-agent", 8000)
+	require.NoError(t, err)
+	defer func() { _ = tunnel.Close() }()
+
+	_, err = tunnel.Write([]byte("GET / HTTP/1.1\r\n\r\n"))
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(tunnel)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/1.1 200 OK\r\n\r\n", string(data))
+}
+
+func TestPortForwardAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error":"agent is not running"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.PortForward(context.Background(), "my-agent", 8000)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "agent is not running", apiErr.Message)
+}
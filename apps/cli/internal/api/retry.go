@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// invokeRetryDelay is the wait before the first invocation retry; it doubles
+// for every retry after, up to maxInvokeRetryDelay
+var invokeRetryDelay = 500 * time.Millisecond
+
+// maxInvokeRetryDelay caps the backoff between invocation retries
+const maxInvokeRetryDelay = 10 * time.Second
+
+// RetryOn selects which failed invocations are retried
+type RetryOn struct {
+	// ServerErrors are 5xx responses from the platform
+	ServerErrors bool
+	// RateLimits are 429 responses
+	RateLimits bool
+	// Timeouts are requests that timed out, including 408 and 504 responses
+	Timeouts bool
+	// Network are connection errors other than timeouts
+	Network bool
+	// AgentErrors are invocations where the agent itself returned an error
+	AgentErrors bool
+}
+
+// RetryConditions lists the names accepted by ParseRetryOn
+var RetryConditions = []string{"5xx", "429", "timeout", "network", "agent"}
+
+// ParseRetryOn parses a comma-separated list of retry conditions, such as
+// "5xx,timeout"
+func ParseRetryOn(s string) (RetryOn, error) {
+	var on RetryOn
+	for _, name := range strings.Split(s, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "5xx":
+			on.ServerErrors = true
+		case "429":
+			on.RateLimits = true
+		case "timeout":
+			on.Timeouts = true
+		case "network":
+			on.Network = true
+		case "agent":
+			on.AgentErrors = true
+		case "":
+		default:
+			return RetryOn{}, fmt.Errorf("unknown retry condition %q (use %s)", name, strings.Join(RetryConditions, ", "))
+		}
+	}
+	return on, nil
+}
+
+// matches reports whether a failed attempt should be retried
+func (o RetryOn) matches(err error) bool {
+	var agentErr *AgentError
+	if errors.As(err, &agentErr) {
+		return o.AgentErrors
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return o.RateLimits
+		case apiErr.StatusCode == http.StatusRequestTimeout:
+			return o.Timeouts
+		case apiErr.StatusCode == http.StatusGatewayTimeout:
+			return o.Timeouts || o.ServerErrors
+		case apiErr.StatusCode >= 500:
+			return o.ServerErrors
+		}
+		return false
+	}
+	if isTimeout(err) {
+		return o.Timeouts
+	}
+	return o.Network
+}
+
+// isTimeout reports whether err is a request that ran out of time
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// AgentError is an invocation that reached the agent, which returned an error
+type AgentError struct {
+	Message string
+	// RequestID identifies the invocation in the agent's logs
+	RequestID string
+	// Trace is set when the invocation asked for one
+	Trace *Trace
+}
+
+func (e *AgentError) Error() string {
+	return e.Message
+}
+
+// RetryError is returned when every attempt of a retried invocation failed.
+// Transport errors are failures to get a response from the platform;
+// agent errors came from the agent itself.
+type RetryError struct {
+	Attempts        int
+	TransportErrors int
+	AgentErrors     int
+	// Last is the error of the final attempt
+	Last error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("all %d attempts failed (%s, %s), last: %v", e.Attempts,
+		plural(e.TransportErrors, "transport error"), plural(e.AgentErrors, "agent error"), e.Last)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Last
+}
+
+// plural formats a count with a noun, adding an s unless the count is one
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// RetryPolicy controls how InvokeAgentWithRetry retries failed invocations
+type RetryPolicy struct {
+	// Retries is how many times to retry after the first attempt
+	Retries int
+	On      RetryOn
+	// OnRetry is called before waiting to retry a failed attempt
+	OnRetry func(attempt int, err error, wait time.Duration)
+}
+
+// InvokeAgentWithRetry invokes an agent, retrying failures that match the
+// policy with exponential backoff. An error returned by the agent is an
+// *AgentError. If an invocation was retried and every attempt failed, the
+// error is a *RetryError.
+//
+// Transport retries re-send the same idempotency key, so the platform runs
+// the invocation at most once. Agent errors are retried with a new key.
+func (c *Client) InvokeAgentWithRetry(slug string, input map[string]any, opts InvokeOptions, policy RetryPolicy) (*InvokeResponse, error) {
+	if _, _, err := ParseAgentRef(slug); err != nil {
+		return nil, err
+	}
+
+	payload, err := c.encodeInvoke(input, opts)
+	if err != nil {
+		return nil, err
+	}
+	key := NewIdempotencyKey()
+	failed := &RetryError{}
+	wait := invokeRetryDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendInvoke(slug, payload, key)
+		if err == nil && resp.Error == "" {
+			return resp, nil
+		}
+
+		failed.Attempts = attempt
+		if err != nil {
+			failed.TransportErrors++
+		} else {
+			err = &AgentError{Message: resp.Error, RequestID: resp.RequestID, Trace: resp.Trace}
+			failed.AgentErrors++
+			key = NewIdempotencyKey()
+		}
+		failed.Last = err
+
+		if attempt > policy.Retries || !policy.On.matches(err) {
+			if attempt == 1 {
+				return nil, err
+			}
+			return nil, failed
+		}
+
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, wait)
+		}
+		time.Sleep(wait)
+		wait = min(wait*2, maxInvokeRetryDelay)
+	}
+}
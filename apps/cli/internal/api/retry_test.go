@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useShortRetryDelay(t *testing.T) {
+	delay := invokeRetryDelay
+	invokeRetryDelay = time.Millisecond
+	t.Cleanup(func() { invokeRetryDelay = delay })
+}
+
+func TestParseRetryOn(t *testing.T) {
+	on, err := ParseRetryOn("5xx, timeout")
+	require.NoError(t, err)
+	assert.Equal(t, RetryOn{ServerErrors: true, Timeouts: true}, on)
+
+	on, err = ParseRetryOn("429,network,agent")
+	require.NoError(t, err)
+	assert.Equal(t, RetryOn{RateLimits: true, Network: true, AgentErrors: true}, on)
+
+	_, err = ParseRetryOn("5xx,4xx")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown retry condition "4xx"`)
+}
+
+func TestRetryOnMatches(t *testing.T) {
+	on := RetryOn{ServerErrors: true}
+	assert.True(t, on.matches(&APIError{StatusCode: 502}))
+	assert.True(t, on.matches(&APIError{StatusCode: 504}))
+	assert.False(t, on.matches(&APIError{StatusCode: 429}))
+	assert.False(t, on.matches(&APIError{StatusCode: 400}))
+	assert.False(t, on.matches(&AgentError{Message: "boom"}))
+	assert.False(t, on.matches(errors.New("connection refused")))
+
+	on = RetryOn{Timeouts: true}
+	assert.True(t, on.matches(&APIError{StatusCode: 504}))
+	assert.False(t, on.matches(&APIError{StatusCode: 500}))
+}
+
+func TestInvokeAgentWithRetry(t *testing.T) {
+	useShortRetryDelay(t)
+
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyHeader))
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InvokeResponse{Output: map[string]any{"result": "success"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	var retried []int
+	resp, err := client.InvokeAgentWithRetry("my-agent", map[string]any{}, InvokeOptions{}, RetryPolicy{
+		Retries: 3,
+		On:      RetryOn{ServerErrors: true},
+		OnRetry: func(attempt int, err error, wait time.Duration) {
+			retried = append(retried, attempt)
+			assert.Equal(t, time.Duration(attempt)*time.Millisecond, wait)
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "success", resp.Output["result"])
+	assert.Equal(t, []int{1, 2}, retried)
+
+	// Transport retries reuse the key so the platform runs the invocation once
+	require.Len(t, keys, 3)
+	assert.Equal(t, keys[0], keys[1])
+	assert.Equal(t, keys[0], keys[2])
+}
+
+func TestInvokeAgentWithRetryGivesUp(t *testing.T) {
+	useShortRetryDelay(t)
+
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyHeader))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InvokeResponse{Error: "model overloaded"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.InvokeAgentWithRetry("my-agent", map[string]any{}, InvokeOptions{}, RetryPolicy{
+		Retries: 2,
+		On:      RetryOn{ServerErrors: true, AgentErrors: true},
+	})
+	var retryErr *RetryError
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 3, retryErr.Attempts)
+	assert.Equal(t, 1, retryErr.TransportErrors)
+	assert.Equal(t, 2, retryErr.AgentErrors)
+	assert.EqualError(t, err, "all 3 attempts failed (1 transport error, 2 agent errors), last: model overloaded")
+
+	var agentErr *AgentError
+	require.ErrorAs(t, err, &agentErr)
+
+	// Agent errors are retried as new invocations
+	require.Len(t, keys, 3)
+	assert.Equal(t, keys[0], keys[1])
+	assert.NotEqual(t, keys[1], keys[2])
+}
+
+func TestInvokeAgentWithRetrySkipsOtherErrors(t *testing.T) {
+	useShortRetryDelay(t)
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InvokeResponse{Error: "bad input", RequestID: "req_1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.InvokeAgentWithRetry("my-agent", map[string]any{}, InvokeOptions{}, RetryPolicy{
+		Retries: 3,
+		On:      RetryOn{ServerErrors: true},
+	})
+	var agentErr *AgentError
+	require.ErrorAs(t, err, &agentErr)
+	assert.Equal(t, "bad input", agentErr.Message)
+	assert.Equal(t, "req_1", RequestIDOf(err))
+	assert.Equal(t, 1, calls)
+}
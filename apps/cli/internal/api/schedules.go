@@ -0,0 +1,96 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// validateCron checks that a cron expression has the standard five fields
+func validateCron(cron string) error {
+	if len(strings.Fields(cron)) != 5 {
+		return fmt.Errorf("invalid cron expression %q: expected 5 fields (minute hour day month weekday)", cron)
+	}
+	return nil
+}
+
+// Schedule represents a cron-triggered invocation of an agent
+type Schedule struct {
+	ID        string         `json:"id"`
+	AgentSlug string         `json:"agentSlug"`
+	Cron      string         `json:"cron"`
+	Input     map[string]any `json:"input"`
+	NextRunAt *string        `json:"nextRunAt"`
+	LastRunAt *string        `json:"lastRunAt"`
+	CreatedAt string         `json:"createdAt"`
+}
+
+// SchedulesListResponse is returned when listing schedules
+type SchedulesListResponse struct {
+	Schedules []Schedule `json:"schedules"`
+}
+
+// CreateScheduleRequest is the request body for creating a schedule
+type CreateScheduleRequest struct {
+	AgentSlug string         `json:"agentSlug"`
+	Cron      string         `json:"cron"`
+	Input     map[string]any `json:"input"`
+}
+
+// CreateScheduleResponse is returned when creating a schedule
+type CreateScheduleResponse struct {
+	Schedule Schedule `json:"schedule"`
+	Message  string   `json:"message"`
+}
+
+// DeleteScheduleResponse is returned when deleting a schedule
+type DeleteScheduleResponse struct {
+	Message string `json:"message"`
+}
+
+// ListSchedules returns all schedules, optionally filtered by agent
+func (c *Client) ListSchedules(agentSlug string) (*SchedulesListResponse, error) {
+	path := "/api/schedules"
+	if agentSlug != "" {
+		path = fmt.Sprintf("/api/schedules?agent=%s", url.QueryEscape(agentSlug))
+	}
+
+	var resp SchedulesListResponse
+	if err := c.Get(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateSchedule creates a cron schedule that invokes an agent with the given input
+func (c *Client) CreateSchedule(slug, cron string, input map[string]any) (*CreateScheduleResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	if err := validateCron(cron); err != nil {
+		return nil, err
+	}
+	body := CreateScheduleRequest{
+		AgentSlug: slug,
+		Cron:      cron,
+		Input:     input,
+	}
+
+	var resp CreateScheduleResponse
+	if err := c.Post("/api/schedules", body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteSchedule deletes a schedule by ID
+func (c *Client) DeleteSchedule(id string) (*DeleteScheduleResponse, error) {
+	if id == "" {
+		return nil, fmt.Errorf("schedule ID cannot be empty")
+	}
+	var resp DeleteScheduleResponse
+	if err := c.Delete(fmt.Sprintf("/api/schedules/%s", url.PathEscape(id)), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
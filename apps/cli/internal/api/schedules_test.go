@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCron(t *testing.T) {
+	assert.NoError(t, validateCron("0 * * * *"))
+	assert.NoError(t, validateCron("*/15 9-17 * * 1-5"))
+	assert.Error(t, validateCron(""))
+	assert.Error(t, validateCron("* * * *"))
+	assert.Error(t, validateCron("0 0 * * * *"))
+}
+
+func TestListSchedules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/schedules", r.URL.Path)
+		assert.Equal(t, "my-agent", r.URL.Query().Get("agent"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SchedulesListResponse{
+			Schedules: []Schedule{{ID: "sch_1", AgentSlug: "my-agent", Cron: "0 * * * *"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListSchedules("my-agent")
+	require.NoError(t, err)
+	require.Len(t, resp.Schedules, 1)
+	assert.Equal(t, "0 * * * *", resp.Schedules[0].Cron)
+}
+
+func TestCreateSchedule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/schedules", r.URL.Path)
+
+		var body CreateScheduleRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "my-agent", body.AgentSlug)
+		assert.Equal(t, "0 * * * *", body.Cron)
+		assert.Equal(t, "hourly", body.Input["job"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(CreateScheduleResponse{
+			Schedule: Schedule{ID: "sch_1", AgentSlug: body.AgentSlug, Cron: body.Cron},
+			Message:  "Schedule created",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.CreateSchedule("my-agent", "0 * * * *", map[string]any{"job": "hourly"})
+	require.NoError(t, err)
+	assert.Equal(t, "sch_1", resp.Schedule.ID)
+}
+
+func TestCreateScheduleInvalidCron(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.CreateSchedule("my-agent", "hourly", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid cron")
+}
+
+func TestDeleteSchedule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/schedules/sch_1", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeleteScheduleResponse{Message: "Schedule deleted"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.DeleteSchedule("sch_1")
+	require.NoError(t, err)
+	assert.Equal(t, "Schedule deleted", resp.Message)
+}
+
+func TestDeleteScheduleEmptyID(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.DeleteSchedule("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "empty")
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SearchAgents returns the agents matching query. Platforms without a search
+// endpoint answer 404, in which case the agents are listed and matched here
+// with MatchQuery.
+func (c *Client) SearchAgents(query string) (*AgentListResponse, error) {
+	var resp AgentListResponse
+	err := c.Get(fmt.Sprintf("/api/agents/search?q=%s", url.QueryEscape(query)), &resp)
+	if err == nil {
+		return &resp, nil
+	}
+	if !IsNotFound(err) {
+		return nil, err
+	}
+
+	all, err := c.ListAgents()
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]Agent, 0, len(all.Agents))
+	for _, agent := range all.Agents {
+		if MatchQuery(agent, query) {
+			matches = append(matches, agent)
+		}
+	}
+	return &AgentListResponse{Agents: matches}, nil
+}
+
+// MatchQuery reports whether every word of query appears, ignoring case, in
+// the agent's name, slug or description, or in one of its labels written as
+// key=value
+func MatchQuery(agent Agent, query string) bool {
+	fields := []string{agent.Name, agent.Slug}
+	if agent.Description != nil {
+		fields = append(fields, *agent.Description)
+	}
+	for key, value := range agent.Labels {
+		fields = append(fields, key+"="+value)
+	}
+	text := strings.ToLower(strings.Join(fields, "\n"))
+
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchAgents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/agents/search", r.URL.Path)
+		assert.Equal(t, "support bot", r.URL.Query().Get("q"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(AgentListResponse{Agents: []Agent{{Slug: "support-bot"}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.SearchAgents("support bot")
+	require.NoError(t, err)
+	require.Len(t, resp.Agents, 1)
+	assert.Equal(t, "support-bot", resp.Agents[0].Slug)
+}
+
+func TestSearchAgentsFallsBackToListing(t *testing.T) {
+	description := "Summarizes support tickets"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/agents/search" {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "not found"})
+			return
+		}
+		assert.Equal(t, "/api/agents", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(AgentListResponse{Agents: []Agent{
+			{Name: "Summarizer", Slug: "summarizer", Description: &description},
+			{Name: "Router", Slug: "router", Labels: map[string]string{"team": "support"}},
+			{Name: "Billing", Slug: "billing"},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.SearchAgents("SUPPORT")
+	require.NoError(t, err)
+	require.Len(t, resp.Agents, 2)
+	assert.Equal(t, "summarizer", resp.Agents[0].Slug)
+	assert.Equal(t, "router", resp.Agents[1].Slug)
+}
+
+func TestMatchQuery(t *testing.T) {
+	description := "Answers billing questions"
+	agent := Agent{Name: "Billing Bot", Slug: "billing-bot", Description: &description, Labels: map[string]string{"team": "finance"}}
+
+	assert.True(t, MatchQuery(agent, ""))
+	assert.True(t, MatchQuery(agent, "bot"))
+	assert.True(t, MatchQuery(agent, "questions billing"))
+	assert.True(t, MatchQuery(agent, "team=finance"))
+	assert.True(t, MatchQuery(agent, "Finance"))
+	assert.False(t, MatchQuery(agent, "bot support"))
+	assert.False(t, MatchQuery(agent, "team=ml"))
+}
@@ -32,12 +32,61 @@ type SetSecretResponse struct {
 	AgentSlug *string `json:"agentSlug"`
 }
 
+// UpdateSecretRequest is the request body for replacing a secret's value
+type UpdateSecretRequest struct {
+	Value string `json:"value"`
+}
+
 // DeleteSecretResponse is returned when deleting a secret
 type DeleteSecretResponse struct {
 	Message string `json:"message"`
 	Name    string `json:"name"`
 }
 
+// SecretAgent is an agent that can see a secret
+type SecretAgent struct {
+	Slug   string      `json:"slug"`
+	Status AgentStatus `json:"status"`
+	// LastInjectedAt is when the agent last started with the secret
+	LastInjectedAt *string `json:"lastInjectedAt,omitempty"`
+}
+
+// SecretDetails describes a secret and where it is used
+type SecretDetails struct {
+	Name           string  `json:"name"`
+	AgentSlug      *string `json:"agentSlug"`
+	CreatedAt      string  `json:"createdAt"`
+	UpdatedAt      string  `json:"updatedAt"`
+	LastInjectedAt *string `json:"lastInjectedAt"`
+	// Agents are the agents that receive the secret
+	Agents []SecretAgent `json:"agents"`
+	// OverriddenBy lists agents with an agent-specific secret of the same
+	// name, which they get instead of a user-level secret
+	OverriddenBy []string `json:"overriddenBy"`
+}
+
+// SecretAgentsResponse is returned when listing the agents that use a secret
+type SecretAgentsResponse struct {
+	Agents []SecretAgent `json:"agents"`
+}
+
+// CopySecretsRequest is the request body for copying secrets between scopes.
+// A nil agent slug is the user-level scope.
+type CopySecretsRequest struct {
+	FromAgent *string `json:"fromAgent"`
+	ToAgent   *string `json:"toAgent"`
+	// Names limits the copy to some secrets; empty copies all of them
+	Names     []string `json:"names,omitempty"`
+	Overwrite bool     `json:"overwrite"`
+}
+
+// CopySecretsResponse is returned when copying secrets
+type CopySecretsResponse struct {
+	Copied []string `json:"copied"`
+	// Skipped secrets already exist in the target scope
+	Skipped []string `json:"skipped"`
+}
+
 // ListSecrets returns all secrets for the authenticated user
 func (c *Client) ListSecrets(agentSlug string) (*SecretsListResponse, error) {
 	path := "/api/secrets"
@@ -67,6 +116,21 @@ func (c *Client) SetSecret(name, value string, agentSlug *string) (*SetSecretRes
 	return &resp, nil
 }
 
+// UpdateSecret replaces the value of an existing secret. Unlike SetSecret, it
+// fails with a not found error instead of creating the secret.
+func (c *Client) UpdateSecret(name, value string, agentSlug string) (*SetSecretResponse, error) {
+	path := fmt.Sprintf("/api/secrets?name=%s", url.QueryEscape(name))
+	if agentSlug != "" {
+		path = fmt.Sprintf("%s&agent=%s", path, url.QueryEscape(agentSlug))
+	}
+
+	var resp SetSecretResponse
+	if err := c.Put(path, UpdateSecretRequest{Value: value}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // DeleteSecret deletes a secret
 func (c *Client) DeleteSecret(name string, agentSlug string) (*DeleteSecretResponse, error) {
 	path := fmt.Sprintf("/api/secrets?name=%s", url.QueryEscape(name))
@@ -80,3 +144,64 @@ func (c *Client) DeleteSecret(name string, agentSlug string) (*DeleteSecretRespo
 	}
 	return &resp, nil
 }
+
+// SecretAgents returns the agents that receive a secret: every agent for a
+// user-level secret that no agent-specific secret overrides, or the one agent
+// it is scoped to
+func (c *Client) SecretAgents(name string, agentSlug string) (*SecretAgentsResponse, error) {
+	path := fmt.Sprintf("/api/secrets/agents?name=%s", url.QueryEscape(name))
+	if agentSlug != "" {
+		path = fmt.Sprintf("%s&agent=%s", path, url.QueryEscape(agentSlug))
+	}
+
+	var resp SecretAgentsResponse
+	if err := c.Get(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// InspectSecret returns when a secret was updated and last injected, and
+// which agents see it
+func (c *Client) InspectSecret(name string, agentSlug string) (*SecretDetails, error) {
+	path := fmt.Sprintf("/api/secrets/inspect?name=%s", url.QueryEscape(name))
+	if agentSlug != "" {
+		path = fmt.Sprintf("%s&agent=%s", path, url.QueryEscape(agentSlug))
+	}
+
+	var resp SecretDetails
+	if err := c.Get(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CopySecrets copies secrets from one scope to another on the platform, so
+// their values never reach the client
+func (c *Client) CopySecrets(req CopySecretsRequest) (*CopySecretsResponse, error) {
+	for _, slug := range []*string{req.FromAgent, req.ToAgent} {
+		if slug == nil {
+			continue
+		}
+		if err := validateSlug(*slug); err != nil {
+			return nil, err
+		}
+	}
+	if stringEqual(req.FromAgent, req.ToAgent) {
+		return nil, fmt.Errorf("source and target scope are the same")
+	}
+
+	var resp CopySecretsResponse
+	if err := c.Post("/api/secrets/copy", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// stringEqual compares optional strings
+func stringEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
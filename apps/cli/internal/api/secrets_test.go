@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/secrets", r.URL.Path)
+		assert.Equal(t, "API_KEY", r.URL.Query().Get("name"))
+		assert.Equal(t, "my-agent", r.URL.Query().Get("agent"))
+
+		var req UpdateSecretRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "new-value", req.Value)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SetSecretResponse{Message: "Secret updated", Name: "API_KEY"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.UpdateSecret("API_KEY", "new-value", "my-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "API_KEY", resp.Name)
+}
+
+func TestUpdateSecretNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "Secret not found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.UpdateSecret("API_KEY", "new-value", "")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestSecretAgents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/secrets/agents", r.URL.Path)
+		assert.Equal(t, "API_KEY", r.URL.Query().Get("name"))
+		assert.Equal(t, "my-agent", r.URL.Query().Get("agent"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SecretAgentsResponse{Agents: []SecretAgent{{Slug: "my-agent", Status: AgentRunning}}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.SecretAgents("API_KEY", "my-agent")
+	require.NoError(t, err)
+	assert.Equal(t, []SecretAgent{{Slug: "my-agent", Status: AgentRunning}}, resp.Agents)
+}
+
+func TestInspectSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/secrets/inspect", r.URL.Path)
+		assert.Equal(t, "API_KEY", r.URL.Query().Get("name"))
+		assert.False(t, r.URL.Query().Has("agent"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"name": "API_KEY",
+			"agentSlug": null,
+			"updatedAt": "2026-03-01T10:00:00Z",
+			"lastInjectedAt": null,
+			"agents": [{"slug": "my-agent", "status": "stopped"}],
+			"overriddenBy": ["other-agent"]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.InspectSecret("API_KEY", "")
+	require.NoError(t, err)
+	assert.Nil(t, resp.AgentSlug)
+	assert.Nil(t, resp.LastInjectedAt)
+	assert.Equal(t, []SecretAgent{{Slug: "my-agent", Status: AgentStopped}}, resp.Agents)
+	assert.Equal(t, []string{"other-agent"}, resp.OverriddenBy)
+}
+
+func TestCopySecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/secrets/copy", r.URL.Path)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{
+			"fromAgent": "old-agent",
+			"toAgent":   nil,
+			"names":     []any{"API_KEY", "DB_URL"},
+			"overwrite": false,
+		}, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(CopySecretsResponse{Copied: []string{"API_KEY"}, Skipped: []string{"DB_URL"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	from := "old-agent"
+	resp, err := client.CopySecrets(CopySecretsRequest{FromAgent: &from, Names: []string{"API_KEY", "DB_URL"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"API_KEY"}, resp.Copied)
+	assert.Equal(t, []string{"DB_URL"}, resp.Skipped)
+}
+
+func TestCopySecretsSameScope(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	from, to := "my-agent", "my-agent"
+	_, err := client.CopySecrets(CopySecretsRequest{FromAgent: &from, ToAgent: &to})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "same")
+
+	_, err = client.CopySecrets(CopySecretsRequest{})
+	require.Error(t, err)
+}
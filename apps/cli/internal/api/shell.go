@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// ShellMessage is one websocket message of an interactive shell session.
+// The client sends stdin and resize messages; the platform sends stdout
+// messages and finishes with an exit or error message.
+type ShellMessage struct {
+	Type     string   `json:"type"`
+	Command  []string `json:"command,omitempty"`
+	Data     []byte   `json:"data,omitempty"`
+	Cols     int      `json:"cols,omitempty"`
+	Rows     int      `json:"rows,omitempty"`
+	ExitCode *int     `json:"exitCode,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// ShellOptions configures an interactive shell session
+type ShellOptions struct {
+	// Command is the shell to start; the platform default is used if empty
+	Command []string
+	Cols    int
+	Rows    int
+}
+
+// ShellSession is an open interactive shell in a running agent
+type ShellSession struct {
+	conn *websocket.Conn
+	// writeMu serializes writes, which gorilla/websocket requires
+	writeMu sync.Mutex
+}
+
+// Shell opens an interactive shell in the agent's running environment over a websocket
+func (c *Client) Shell(ctx context.Context, slug string, opts ShellOptions) (*ShellSession, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+
+	conn, err := c.dialWebsocket(ctx, fmt.Sprintf("/api/agents/%s/shell", slug))
+	if err != nil {
+		return nil, err
+	}
+
+	session := &ShellSession{conn: conn}
+	start := ShellMessage{Type: "start", Command: opts.Command, Cols: opts.Cols, Rows: opts.Rows}
+	if err := session.send(start); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return session, nil
+}
+
+// dialWebsocket opens an authenticated websocket to a platform path. Error
+// responses to the upgrade are returned as an *APIError.
+func (c *Client) dialWebsocket(ctx context.Context, path string) (*websocket.Conn, error) {
+	wsURL, err := websocketURL(c.BaseURL + path)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, wsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, wsURL, req.Header)
+	if err != nil {
+		if resp != nil && resp.StatusCode >= 400 {
+			defer func() { _ = resp.Body.Close() }()
+			body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+			if readErr != nil {
+				return nil, readErr
+			}
+			return nil, decodeAPIError(resp.StatusCode, body)
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+// websocketURL converts an http(s) URL to the matching ws(s) URL
+func websocketURL(u string) (string, error) {
+	switch {
+	case strings.HasPrefix(u, "https://"):
+		return "wss://" + strings.TrimPrefix(u, "https://"), nil
+	case strings.HasPrefix(u, "http://"):
+		return "ws://" + strings.TrimPrefix(u, "http://"), nil
+	default:
+		return "", fmt.Errorf("unsupported endpoint scheme in %q", u)
+	}
+}
+
+func (s *ShellSession) send(msg ShellMessage) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteJSON(msg)
+}
+
+// Write sends keystrokes to the remote shell
+func (s *ShellSession) Write(p []byte) (int, error) {
+	if err := s.send(ShellMessage{Type: "stdin", Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Resize tells the remote terminal about a new window size
+func (s *ShellSession) Resize(cols, rows int) error {
+	return s.send(ShellMessage{Type: "resize", Cols: cols, Rows: rows})
+}
+
+// Wait copies shell output to w until the shell exits and returns its exit code
+func (s *ShellSession) Wait(w io.Writer) (int, error) {
+	for {
+		var msg ShellMessage
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return 0, fmt.Errorf("shell closed without exit status")
+			}
+			return 0, err
+		}
+
+		switch msg.Type {
+		case "stdout":
+			if _, err := w.Write(msg.Data); err != nil {
+				return 0, err
+			}
+		case "exit":
+			if msg.ExitCode == nil {
+				return 0, nil
+			}
+			return *msg.ExitCode, nil
+		case "error":
+			return 0, fmt.Errorf("%s", msg.Error)
+		}
+	}
+}
+
+// Close ends the session
+func (s *ShellSession) Close() error {
+	s.writeMu.Lock()
+	_ = s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	s.writeMu.Unlock()
+	return s.conn.Close()
+}
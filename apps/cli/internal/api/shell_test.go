@@ -0,0 +1,93 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShell(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/agents/my-agent/shell", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		var start ShellMessage
+		require.NoError(t, conn.ReadJSON(&start))
+		assert.Equal(t, "start", start.Type)
+		assert.Equal(t, []string{"bash"}, start.Command)
+		assert.Equal(t, 120, start.Cols)
+		assert.Equal(t, 40, start.Rows)
+
+		var resize ShellMessage
+		require.NoError(t, conn.ReadJSON(&resize))
+		assert.Equal(t, "resize", resize.Type)
+		assert.Equal(t, 80, resize.Cols)
+
+		var stdin ShellMessage
+		require.NoError(t, conn.ReadJSON(&stdin))
+		assert.Equal(t, "stdin", stdin.Type)
+		assert.Equal(t, "exit\r", string(stdin.Data))
+
+		code := 2
+		_ = conn.WriteJSON(ShellMessage{Type: "stdout", Data: []byte("$ exit\r\n")})
+		_ = conn.WriteJSON(ShellMessage{Type: "exit", ExitCode: &code})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	session, err := client.Shell(context.Background(), "my-agent", ShellOptions{Command: []string{"bash"}, Cols: 120, Rows: 40})
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	require.NoError(t, session.Resize(80, 24))
+	_, err = session.Write([]byte("exit\r"))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	code, err := session.Wait(&out)
+	require.NoError(t, err)
+	assert.Equal(t, 2, code)
+	assert.Equal(t, "$ exit\r\n", out.String())
+}
+
+func TestShellAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error":"agent is not running"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.Shell(context.Background(), "my-agent", ShellOptions{})
+	require.Error(t, err)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.Equal(t, "agent is not running", apiErr.Message)
+}
+
+func TestWebsocketURL(t *testing.T) {
+	u, err := websocketURL("https://oken.dev/api/agents/a/shell")
+	require.NoError(t, err)
+	assert.Equal(t, "wss://oken.dev/api/agents/a/shell", u)
+
+	u, err = websocketURL("http://localhost:3000/x")
+	require.NoError(t, err)
+	assert.Equal(t, "ws://localhost:3000/x", u)
+
+	_, err = websocketURL("ftp://example.com")
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package api
+
+import "fmt"
+
+// SSOConfig describes an organization's OpenID Connect provider
+type SSOConfig struct {
+	Issuer   string   `json:"issuer"`
+	ClientID string   `json:"clientId"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// SSOExchangeRequest is the request body for trading an identity token from
+// the provider for a platform token
+type SSOExchangeRequest struct {
+	Org     string `json:"org"`
+	IDToken string `json:"idToken"`
+	Nonce   string `json:"nonce"`
+}
+
+// SSOExchangeResponse is returned when an identity token is accepted
+type SSOExchangeResponse struct {
+	Token string `json:"token"`
+	// TokenExpiresAt is when the token expires, if it does (RFC 3339)
+	TokenExpiresAt string `json:"tokenExpiresAt,omitempty"`
+	User           *struct {
+		Email string `json:"email"`
+	} `json:"user,omitempty"`
+}
+
+// GetSSOConfig returns the identity provider an organization signs in with
+func (c *Client) GetSSOConfig(org string) (*SSOConfig, error) {
+	if err := validateSlug(org); err != nil {
+		return nil, fmt.Errorf("invalid organization: %w", err)
+	}
+	var resp SSOConfig
+	if err := c.Get(fmt.Sprintf("/api/auth/sso/%s", org), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExchangeSSOToken trades an identity token for a platform token. The
+// platform verifies the token's signature, audience and nonce.
+func (c *Client) ExchangeSSOToken(req SSOExchangeRequest) (*SSOExchangeResponse, error) {
+	if err := validateSlug(req.Org); err != nil {
+		return nil, fmt.Errorf("invalid organization: %w", err)
+	}
+	var resp SSOExchangeResponse
+	if err := c.Post("/api/auth/sso/exchange", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
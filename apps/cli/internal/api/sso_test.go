@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSSOConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/auth/sso/acme", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SSOConfig{Issuer: "https://idp.example.com", ClientID: "oken-cli"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+
+	resp, err := client.GetSSOConfig("acme")
+	require.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com", resp.Issuer)
+	assert.Equal(t, "oken-cli", resp.ClientID)
+
+	_, err = client.GetSSOConfig("Acme Corp")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid organization")
+}
+
+func TestExchangeSSOToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/auth/sso/exchange", r.URL.Path)
+
+		var body SSOExchangeRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, SSOExchangeRequest{Org: "acme", IDToken: "id-token", Nonce: "nonce"}, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"ok_sso","tokenExpiresAt":"2026-12-01T00:00:00Z","user":{"email":"me@acme.com"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+
+	resp, err := client.ExchangeSSOToken(SSOExchangeRequest{Org: "acme", IDToken: "id-token", Nonce: "nonce"})
+	require.NoError(t, err)
+	assert.Equal(t, "ok_sso", resp.Token)
+	assert.Equal(t, "2026-12-01T00:00:00Z", resp.TokenExpiresAt)
+	require.NotNil(t, resp.User)
+	assert.Equal(t, "me@acme.com", resp.User.Email)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps how many similar slugs a not-found error names
+const maxSuggestions = 3
+
+// AgentNotFoundError is a 404 for an agent, naming agents with similar slugs
+type AgentNotFoundError struct {
+	Slug        string
+	Suggestions []string
+	Err         error
+}
+
+func (e *AgentNotFoundError) Error() string {
+	msg := fmt.Sprintf("agent '%s' not found", e.Slug)
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+	quoted := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		quoted[i] = "'" + s + "'"
+	}
+	return fmt.Sprintf("%s — did you mean %s?", msg, strings.Join(quoted, " or "))
+}
+
+func (e *AgentNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// WithSuggestions turns a 404 for slug into an *AgentNotFoundError that
+// names agents with similar slugs, listing the agents to find them. Other
+// errors are returned as they are.
+func (c *Client) WithSuggestions(slug string, err error) error {
+	if !IsNotFound(err) {
+		return err
+	}
+	notFound := &AgentNotFoundError{Slug: slug, Err: err}
+	if resp, listErr := c.ListAgents(); listErr == nil {
+		slugs := make([]string, len(resp.Agents))
+		for i, agent := range resp.Agents {
+			slugs[i] = agent.Slug
+		}
+		// Only the slug of a reference like "my-agent@staging" can be a
+		// typo of another agent's
+		name, _, _ := strings.Cut(slug, "@")
+		notFound.Suggestions = SimilarSlugs(name, slugs)
+	}
+	return notFound
+}
+
+// SimilarSlugs returns the slugs that slug may be a typo or prefix of,
+// closest first
+func SimilarSlugs(slug string, slugs []string) []string {
+	maxDistance := max(2, len(slug)/3)
+	distances := map[string]int{}
+	for _, s := range slugs {
+		if s == slug {
+			continue
+		}
+		if strings.HasPrefix(s, slug) {
+			distances[s] = 0
+			continue
+		}
+		if d := editDistance(slug, s); d <= maxDistance {
+			distances[s] = d
+		}
+	}
+
+	similar := make([]string, 0, len(distances))
+	for s := range distances {
+		similar = append(similar, s)
+	}
+	sort.Slice(similar, func(i, j int) bool {
+		if distances[similar[i]] != distances[similar[j]] {
+			return distances[similar[i]] < distances[similar[j]]
+		}
+		return similar[i] < similar[j]
+	})
+	if len(similar) > maxSuggestions {
+		similar = similar[:maxSuggestions]
+	}
+	return similar
+}
+
+// editDistance is the number of single-character insertions, deletions,
+// substitutions and swaps of adjacent characters that turn a into b
+func editDistance(a, b string) int {
+	// d[i][j] is the distance between a[:i] and b[:j]
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"my-agent", "my-agent", 0},
+		{"my-agnet", "my-agent", 1},
+		{"my-agen", "my-agent", 1},
+		{"my-agentt", "my-agent", 1},
+		{"my-bgent", "my-agent", 1},
+		{"", "abc", 3},
+		{"support", "billing", 7},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, editDistance(tt.a, tt.b), "%q -> %q", tt.a, tt.b)
+	}
+}
+
+func TestSimilarSlugs(t *testing.T) {
+	slugs := []string{"my-agent", "my-agent-2", "support-bot", "support-bot-staging", "billing", "my-agnt"}
+
+	assert.Equal(t, []string{"my-agent", "my-agnt"}, SimilarSlugs("my-agnet", slugs))
+	assert.Equal(t, []string{"support-bot", "support-bot-staging"}, SimilarSlugs("support", slugs))
+	assert.Empty(t, SimilarSlugs("weather", slugs))
+}
+
+func TestWithSuggestions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/agents" {
+			_ = json.NewEncoder(w).Encode(AgentListResponse{Agents: []Agent{{Slug: "my-agent"}, {Slug: "billing"}}})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "Agent not found"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.GetAgent("my-agnet")
+	err = client.WithSuggestions("my-agnet", err)
+
+	var notFound *AgentNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, []string{"my-agent"}, notFound.Suggestions)
+	assert.Equal(t, "agent 'my-agnet' not found — did you mean 'my-agent'?", err.Error())
+	assert.True(t, IsNotFound(err))
+
+	other := errors.New("connection reset")
+	assert.Same(t, other, client.WithSuggestions("my-agnet", other))
+}
+
+func TestAgentNotFoundErrorWithoutSuggestions(t *testing.T) {
+	err := &AgentNotFoundError{Slug: "weather"}
+	assert.Equal(t, "agent 'weather' not found", err.Error())
+
+	err.Suggestions = []string{"a", "b"}
+	assert.Equal(t, "agent 'weather' not found — did you mean 'a' or 'b'?", err.Error())
+}
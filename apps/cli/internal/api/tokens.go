@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ServiceToken is a machine token for CI and other automation, separate from
+// the personal tokens created by 'oken login'
+type ServiceToken struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// Agents limits the token to some agents; empty means all of them
+	Agents     []string `json:"agents"`
+	ExpiresAt  string   `json:"expiresAt"`
+	LastUsedAt *string  `json:"lastUsedAt"`
+	CreatedBy  string   `json:"createdBy"`
+	CreatedAt  string   `json:"createdAt"`
+}
+
+// TokensListResponse is returned when listing service tokens
+type TokensListResponse struct {
+	Tokens []ServiceToken `json:"tokens"`
+}
+
+// CreateTokenRequest is the request body for creating a service token
+type CreateTokenRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	Agents    []string `json:"agents,omitempty"`
+	ExpiresAt string   `json:"expiresAt"`
+}
+
+// CreateTokenResponse is returned when creating a service token
+type CreateTokenResponse struct {
+	Token ServiceToken `json:"token"`
+	// Secret is the token value; it is only returned once
+	Secret string `json:"secret"`
+}
+
+// RevokeTokenResponse is returned when revoking a service token
+type RevokeTokenResponse struct {
+	Message string `json:"message"`
+}
+
+// ListTokens returns the service tokens of the account or organization
+func (c *Client) ListTokens() (*TokensListResponse, error) {
+	var resp TokensListResponse
+	if err := c.Get("/api/tokens", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateToken mints a service token
+func (c *Client) CreateToken(req CreateTokenRequest) (*CreateTokenResponse, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("token name cannot be empty")
+	}
+	if len(req.Scopes) == 0 {
+		return nil, fmt.Errorf("a token needs at least one scope")
+	}
+	for _, slug := range req.Agents {
+		if err := validateSlug(slug); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp CreateTokenResponse
+	if err := c.Post("/api/tokens", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RevokeToken revokes a service token by ID
+func (c *Client) RevokeToken(id string) (*RevokeTokenResponse, error) {
+	if id == "" {
+		return nil, fmt.Errorf("token ID cannot be empty")
+	}
+	var resp RevokeTokenResponse
+	if err := c.Delete(fmt.Sprintf("/api/tokens/%s", url.PathEscape(id)), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/tokens", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(TokensListResponse{
+			Tokens: []ServiceToken{{ID: "tok_1", Name: "ci", Scopes: []string{"deploy"}, Agents: []string{"my-agent"}}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListTokens()
+	require.NoError(t, err)
+	require.Len(t, resp.Tokens, 1)
+	assert.Equal(t, []string{"my-agent"}, resp.Tokens[0].Agents)
+}
+
+func TestCreateToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/tokens", r.URL.Path)
+
+		var body CreateTokenRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, CreateTokenRequest{
+			Name:      "ci",
+			Scopes:    []string{"deploy"},
+			Agents:    []string{"my-agent"},
+			ExpiresAt: "2026-12-01T00:00:00Z",
+		}, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(CreateTokenResponse{Token: ServiceToken{ID: "tok_1"}, Secret: "oks_123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.CreateToken(CreateTokenRequest{
+		Name:      "ci",
+		Scopes:    []string{"deploy"},
+		Agents:    []string{"my-agent"},
+		ExpiresAt: "2026-12-01T00:00:00Z",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "tok_1", resp.Token.ID)
+	assert.Equal(t, "oks_123", resp.Secret)
+}
+
+func TestCreateTokenValidation(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.CreateToken(CreateTokenRequest{Scopes: []string{"deploy"}})
+	assert.ErrorContains(t, err, "name")
+
+	_, err = client.CreateToken(CreateTokenRequest{Name: "ci"})
+	assert.ErrorContains(t, err, "scope")
+
+	_, err = client.CreateToken(CreateTokenRequest{Name: "ci", Scopes: []string{"deploy"}, Agents: []string{"My Agent"}})
+	assert.ErrorContains(t, err, "invalid slug")
+}
+
+func TestRevokeToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/tokens/tok_1", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RevokeTokenResponse{Message: "Token revoked"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.RevokeToken("tok_1")
+	require.NoError(t, err)
+	assert.Equal(t, "Token revoked", resp.Message)
+
+	_, err = client.RevokeToken("")
+	require.Error(t, err)
+}
@@ -0,0 +1,28 @@
+package api
+
+// Trace is the execution trace of one invocation, returned when
+// InvokeOptions.Trace is set
+type Trace struct {
+	DurationMs float64     `json:"durationMs"`
+	Spans      []TraceSpan `json:"spans"`
+}
+
+// Span kinds reported by the platform. Other kinds are shown as they are.
+const (
+	SpanStep = "step"
+	SpanTool = "tool"
+	SpanLLM  = "llm"
+)
+
+// TraceSpan is one step of an invocation, such as a tool or model call, with
+// the steps it ran
+type TraceSpan struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// StartMs is when the span started, relative to the invocation
+	StartMs    float64        `json:"startMs"`
+	DurationMs float64        `json:"durationMs"`
+	Error      string         `json:"error,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Children   []TraceSpan    `json:"children,omitempty"`
+}
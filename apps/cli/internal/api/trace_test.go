@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvokeAgentWithTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, true, body["trace"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"output": {"answer": "42"},
+			"trace": {
+				"durationMs": 812.5,
+				"spans": [
+					{"name": "search", "kind": "tool", "startMs": 3, "durationMs": 400,
+					 "children": [{"name": "GET /docs", "kind": "step", "startMs": 5, "durationMs": 390}]},
+					{"name": "gpt-4o", "kind": "llm", "startMs": 405, "durationMs": 400, "error": "rate limited"}
+				]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.InvokeAgent("my-agent", map[string]any{}, InvokeOptions{Trace: true})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Trace)
+	assert.Equal(t, 812.5, resp.Trace.DurationMs)
+	require.Len(t, resp.Trace.Spans, 2)
+	assert.Equal(t, SpanTool, resp.Trace.Spans[0].Kind)
+	assert.Equal(t, "GET /docs", resp.Trace.Spans[0].Children[0].Name)
+	assert.Equal(t, "rate limited", resp.Trace.Spans[1].Error)
+}
+
+func TestInvokeAgentWithoutTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.NotContains(t, body, "trace")
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(InvokeResponse{Output: map[string]any{}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.InvokeAgent("my-agent", map[string]any{}, InvokeOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Trace)
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// largeUploadThreshold is the package size above which DeployAgent uploads
+// the tarball separately instead of inline in the deploy request
+var largeUploadThreshold = 64 << 20
+
+// defaultPartSize is used when the platform doesn't choose a part size
+const defaultPartSize = 8 << 20
+
+// maxPartAttempts is how many times a part upload is tried before giving up
+const maxPartAttempts = 3
+
+// partRetryDelay is multiplied by the attempt number between part retries
+var partRetryDelay = time.Second
+
+// UploadSession is a resumable chunked upload
+type UploadSession struct {
+	ID            string `json:"id"`
+	PartSize      int    `json:"partSize"`
+	UploadedParts []int  `json:"uploadedParts"`
+}
+
+// UploadSessionResponse is returned when starting or resuming an upload
+type UploadSessionResponse struct {
+	Upload UploadSession `json:"upload"`
+}
+
+// PresignedUpload is an object storage URL the package can be PUT to directly
+type PresignedUpload struct {
+	ID      string            `json:"id"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+}
+
+// PresignedUploadResponse is returned when requesting a presigned upload URL
+type PresignedUploadResponse struct {
+	Upload PresignedUpload `json:"upload"`
+}
+
+// PresignUpload requests a presigned storage URL for a package of the given
+// size and checksum
+func (c *Client) PresignUpload(size int64, checksum string) (*PresignedUpload, error) {
+	body := map[string]any{"size": size, "sha256": checksum}
+
+	var resp PresignedUploadResponse
+	if err := c.Post("/api/uploads/presign", body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Upload, nil
+}
+
+// PutPresigned uploads data to a presigned storage URL. The request goes to
+// object storage, so it carries the presigned headers but no credentials.
+func (c *Client) PutPresigned(upload *PresignedUpload, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, upload.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("User-Agent", c.UserAgent)
+	for k, v := range upload.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.UploadClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("storage upload failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// CreateUpload starts a chunked upload. If an unfinished upload with the same
+// checksum exists, the platform returns it with the parts already received.
+func (c *Client) CreateUpload(size int64, checksum string) (*UploadSession, error) {
+	body := map[string]any{"size": size, "sha256": checksum}
+
+	var resp UploadSessionResponse
+	if err := c.Post("/api/uploads", body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Upload, nil
+}
+
+// UploadPart uploads one part of a chunked upload. Parts are numbered from 1.
+func (c *Client) UploadPart(id string, number int, data []byte) error {
+	path := fmt.Sprintf("/api/uploads/%s/parts/%d", url.PathEscape(id), number)
+	req, err := http.NewRequest(http.MethodPut, c.BaseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/octet-stream")
+	c.setHeaders(req)
+
+	resp, err := c.send(c.UploadClient, req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return decodeAPIError(resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// CompleteUpload finishes a chunked or presigned upload once all data is uploaded
+func (c *Client) CompleteUpload(id string) error {
+	return c.Post("/api/uploads/"+url.PathEscape(id)+"/complete", nil, nil)
+}
+
+// uploadLarge uploads a package too large to send inline and returns the
+// upload ID. It prefers a presigned storage URL, falling back to a chunked
+// upload through the platform when presigning isn't available.
+func (c *Client) uploadLarge(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	upload, err := c.PresignUpload(int64(len(data)), checksum)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusNotImplemented) {
+		return c.uploadChunked(data, checksum)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.PutPresigned(upload, data); err != nil {
+		return "", err
+	}
+	if err := c.CompleteUpload(upload.ID); err != nil {
+		return "", err
+	}
+	return upload.ID, nil
+}
+
+// uploadChunked uploads data in parts, skipping parts the platform already
+// has from an earlier attempt, and returns the upload ID
+func (c *Client) uploadChunked(data []byte, checksum string) (string, error) {
+	session, err := c.CreateUpload(int64(len(data)), checksum)
+	if err != nil {
+		return "", err
+	}
+
+	partSize := session.PartSize
+	if partSize <= 0 {
+		partSize = defaultPartSize
+	}
+
+	uploaded := make(map[int]bool, len(session.UploadedParts))
+	for _, n := range session.UploadedParts {
+		uploaded[n] = true
+	}
+
+	for n, off := 1, 0; off < len(data); n, off = n+1, off+partSize {
+		part := data[off:min(off+partSize, len(data))]
+		if !uploaded[n] {
+			if err := c.uploadPartWithRetry(session.ID, n, part); err != nil {
+				return "", fmt.Errorf("upload part %d: %w", n, err)
+			}
+		}
+		if c.OnUploadProgress != nil {
+			c.OnUploadProgress(int64(off+len(part)), int64(len(data)))
+		}
+	}
+
+	if err := c.CompleteUpload(session.ID); err != nil {
+		return "", err
+	}
+	return session.ID, nil
+}
+
+// uploadPartWithRetry retries network and server errors with a linear backoff
+func (c *Client) uploadPartWithRetry(id string, number int, data []byte) error {
+	var err error
+	for attempt := 1; attempt <= maxPartAttempts; attempt++ {
+		if err = c.UploadPart(id, number, data); err == nil {
+			return nil
+		}
+
+		// Client errors won't succeed on retry
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < 500 {
+			return err
+		}
+
+		if attempt < maxPartAttempts {
+			time.Sleep(time.Duration(attempt) * partRetryDelay)
+		}
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUploadServer records the parts of a chunked upload
+type fakeUploadServer struct {
+	mu        sync.Mutex
+	parts     map[string][]byte
+	uploaded  []int
+	failPart  string
+	failCount int
+	completed bool
+	presign   bool
+	stored    []byte
+	serverURL string
+}
+
+func (f *fakeUploadServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/uploads/presign":
+			if !f.presign {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": "not found"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(PresignedUploadResponse{Upload: PresignedUpload{
+				ID:      "up_1",
+				URL:     f.serverURL + "/storage/up_1?signature=abc",
+				Headers: map[string]string{"Content-Type": "application/gzip"},
+			}})
+		case r.Method == http.MethodPut && r.URL.Path == "/storage/up_1":
+			assert.Empty(t, r.Header.Get("Authorization"))
+			assert.Equal(t, "abc", r.URL.Query().Get("signature"))
+			assert.Equal(t, "application/gzip", r.Header.Get("Content-Type"))
+			f.stored, _ = io.ReadAll(r.Body)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/uploads":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			assert.NotEmpty(t, body["sha256"])
+			_ = json.NewEncoder(w).Encode(UploadSessionResponse{Upload: UploadSession{ID: "up_1", PartSize: 4, UploadedParts: f.uploaded}})
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/uploads/up_1/parts/"):
+			n := strings.TrimPrefix(r.URL.Path, "/api/uploads/up_1/parts/")
+			if n == f.failPart && f.failCount > 0 {
+				f.failCount--
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			f.parts[n] = data
+		case r.Method == http.MethodPost && r.URL.Path == "/api/uploads/up_1/complete":
+			f.completed = true
+			_, _ = w.Write([]byte("{}"))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/agents":
+			require.NoError(t, r.ParseMultipartForm(10<<20))
+			assert.Equal(t, "up_1", r.FormValue("upload"))
+			_, _, err := r.FormFile("tarball")
+			assert.Error(t, err)
+			_ = json.NewEncoder(w).Encode(DeployResponse{Agent: Agent{Slug: "my-agent"}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}
+}
+
+func useSmallUploads(t *testing.T) {
+	threshold, delay := largeUploadThreshold, partRetryDelay
+	largeUploadThreshold, partRetryDelay = 8, time.Millisecond
+	t.Cleanup(func() { largeUploadThreshold, partRetryDelay = threshold, delay })
+}
+
+func TestDeployAgentChunkedUpload(t *testing.T) {
+	useSmallUploads(t)
+
+	fake := &fakeUploadServer{parts: map[string][]byte{}, failPart: "2", failCount: 1}
+	server := httptest.NewServer(fake.handler(t))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader("0123456789"), DeployOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "my-agent", resp.Agent.Slug)
+
+	assert.True(t, fake.completed)
+	assert.Equal(t, []byte("0123"), fake.parts["1"])
+	assert.Equal(t, []byte("4567"), fake.parts["2"])
+	assert.Equal(t, []byte("89"), fake.parts["3"])
+}
+
+func TestDeployAgentChunkedUploadResumes(t *testing.T) {
+	useSmallUploads(t)
+
+	fake := &fakeUploadServer{parts: map[string][]byte{}, uploaded: []int{1, 2}}
+	server := httptest.NewServer(fake.handler(t))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	var progress []int64
+	client.OnUploadProgress = func(sent, total int64) {
+		assert.Equal(t, int64(10), total)
+		progress = append(progress, sent)
+	}
+
+	_, err := client.DeployAgent("My Agent", "my-agent", bytes.NewReader([]byte("0123456789")), DeployOptions{})
+	require.NoError(t, err)
+
+	assert.Len(t, fake.parts, 1)
+	assert.Equal(t, []byte("89"), fake.parts["3"])
+	assert.Equal(t, []int64{4, 8, 10}, progress)
+}
+
+func TestDeployAgentPresignedUpload(t *testing.T) {
+	useSmallUploads(t)
+
+	fake := &fakeUploadServer{parts: map[string][]byte{}, presign: true}
+	server := httptest.NewServer(fake.handler(t))
+	defer server.Close()
+	fake.serverURL = server.URL
+
+	client := NewClient(server.URL, "test-token")
+
+	_, err := client.DeployAgent("My Agent", "my-agent", strings.NewReader("0123456789"), DeployOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("0123456789"), fake.stored)
+	assert.Empty(t, fake.parts)
+	assert.True(t, fake.completed)
+}
+
+func TestUploadPartGivesUpOnClientError(t *testing.T) {
+	useSmallUploads(t)
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "part too large"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	err := client.uploadPartWithRetry("up_1", 1, []byte("data"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "part too large")
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,43 @@
+package api
+
+// Quota is a usage counter measured against a plan limit
+type Quota struct {
+	Used  int64 `json:"used"`
+	Limit int64 `json:"limit"` // 0 means unlimited
+}
+
+// Fraction returns the share of the quota that has been used, or 0 if unlimited
+func (q Quota) Fraction() float64 {
+	if q.Limit <= 0 {
+		return 0
+	}
+	return float64(q.Used) / float64(q.Limit)
+}
+
+// AgentUsage is the usage of a single agent in the current billing period
+type AgentUsage struct {
+	Slug           string `json:"slug"`
+	Invocations    int64  `json:"invocations"`
+	ComputeSeconds int64  `json:"computeSeconds"`
+	StorageBytes   int64  `json:"storageBytes"`
+}
+
+// UsageResponse is returned when fetching account usage
+type UsageResponse struct {
+	Plan           string       `json:"plan"`
+	PeriodStart    string       `json:"periodStart"`
+	PeriodEnd      string       `json:"periodEnd"`
+	Invocations    Quota        `json:"invocations"`
+	ComputeSeconds Quota        `json:"computeSeconds"`
+	StorageBytes   Quota        `json:"storageBytes"`
+	Agents         []AgentUsage `json:"agents"`
+}
+
+// GetUsage returns account-level and per-agent usage for the current billing period
+func (c *Client) GetUsage() (*UsageResponse, error) {
+	var resp UsageResponse
+	if err := c.Get("/api/usage", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
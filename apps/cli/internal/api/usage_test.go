@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaFraction(t *testing.T) {
+	assert.Equal(t, 0.5, Quota{Used: 50, Limit: 100}.Fraction())
+	assert.Equal(t, 1.2, Quota{Used: 120, Limit: 100}.Fraction())
+	assert.Equal(t, float64(0), Quota{Used: 50, Limit: 0}.Fraction())
+}
+
+func TestGetUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/usage", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(UsageResponse{
+			Plan:        "free",
+			Invocations: Quota{Used: 900, Limit: 1000},
+			Agents: []AgentUsage{
+				{Slug: "agent-1", Invocations: 600},
+				{Slug: "agent-2", Invocations: 300},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.GetUsage()
+	require.NoError(t, err)
+	assert.Equal(t, "free", resp.Plan)
+	assert.Equal(t, int64(1000), resp.Invocations.Limit)
+	assert.Len(t, resp.Agents, 2)
+	assert.Equal(t, int64(600), resp.Agents[0].Invocations)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Webhook represents an endpoint that receives agent lifecycle events
+type Webhook struct {
+	ID        string   `json:"id"`
+	AgentSlug string   `json:"agentSlug"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	CreatedAt string   `json:"createdAt"`
+}
+
+// WebhooksListResponse is returned when listing webhooks
+type WebhooksListResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// CreateWebhookRequest is the request body for creating a webhook
+type CreateWebhookRequest struct {
+	AgentSlug string   `json:"agentSlug"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+}
+
+// CreateWebhookResponse is returned when creating a webhook
+type CreateWebhookResponse struct {
+	Webhook Webhook `json:"webhook"`
+	// Secret is used to verify webhook signatures; it is only returned once
+	Secret  string `json:"secret"`
+	Message string `json:"message"`
+}
+
+// DeleteWebhookResponse is returned when deleting a webhook
+type DeleteWebhookResponse struct {
+	Message string `json:"message"`
+}
+
+// TestWebhookResponse is returned when sending a test event
+type TestWebhookResponse struct {
+	StatusCode int    `json:"statusCode"`
+	DurationMs int    `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute http(s) URL
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q: must be an absolute http or https URL", rawURL)
+	}
+	return nil
+}
+
+// ListWebhooks returns all webhooks, optionally filtered by agent
+func (c *Client) ListWebhooks(agentSlug string) (*WebhooksListResponse, error) {
+	path := "/api/webhooks"
+	if agentSlug != "" {
+		path = fmt.Sprintf("/api/webhooks?agent=%s", url.QueryEscape(agentSlug))
+	}
+
+	var resp WebhooksListResponse
+	if err := c.Get(path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateWebhook registers a webhook for the given agent events
+func (c *Client) CreateWebhook(slug, webhookURL string, events []string) (*CreateWebhookResponse, error) {
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookURL(webhookURL); err != nil {
+		return nil, err
+	}
+	body := CreateWebhookRequest{
+		AgentSlug: slug,
+		URL:       webhookURL,
+		Events:    events,
+	}
+
+	var resp CreateWebhookResponse
+	if err := c.Post("/api/webhooks", body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteWebhook deletes a webhook by ID
+func (c *Client) DeleteWebhook(id string) (*DeleteWebhookResponse, error) {
+	if id == "" {
+		return nil, fmt.Errorf("webhook ID cannot be empty")
+	}
+	var resp DeleteWebhookResponse
+	if err := c.Delete(fmt.Sprintf("/api/webhooks/%s", url.PathEscape(id)), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TestWebhook sends a test event to a webhook and reports the delivery result
+func (c *Client) TestWebhook(id string) (*TestWebhookResponse, error) {
+	if id == "" {
+		return nil, fmt.Errorf("webhook ID cannot be empty")
+	}
+	var resp TestWebhookResponse
+	if err := c.Post(fmt.Sprintf("/api/webhooks/%s/test", url.PathEscape(id)), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
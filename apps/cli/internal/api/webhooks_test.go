@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	assert.NoError(t, validateWebhookURL("https://example.com/hooks/oken"))
+	assert.NoError(t, validateWebhookURL("http://localhost:8080"))
+	assert.Error(t, validateWebhookURL(""))
+	assert.Error(t, validateWebhookURL("example.com/hook"))
+	assert.Error(t, validateWebhookURL("ftp://example.com"))
+}
+
+func TestListWebhooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/webhooks", r.URL.Path)
+		assert.Empty(t, r.URL.Query().Get("agent"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(WebhooksListResponse{
+			Webhooks: []Webhook{{ID: "wh_1", AgentSlug: "my-agent", URL: "https://example.com", Events: []string{"invocation.failed"}}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.ListWebhooks("")
+	require.NoError(t, err)
+	require.Len(t, resp.Webhooks, 1)
+	assert.Equal(t, []string{"invocation.failed"}, resp.Webhooks[0].Events)
+}
+
+func TestCreateWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/webhooks", r.URL.Path)
+
+		var body CreateWebhookRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		assert.Equal(t, "my-agent", body.AgentSlug)
+		assert.Equal(t, "https://example.com/hook", body.URL)
+		assert.Equal(t, []string{"deploy.succeeded", "invocation.failed"}, body.Events)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(CreateWebhookResponse{
+			Webhook: Webhook{ID: "wh_1"},
+			Secret:  "whsec_123",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.CreateWebhook("my-agent", "https://example.com/hook", []string{"deploy.succeeded", "invocation.failed"})
+	require.NoError(t, err)
+	assert.Equal(t, "wh_1", resp.Webhook.ID)
+	assert.Equal(t, "whsec_123", resp.Secret)
+}
+
+func TestCreateWebhookInvalidURL(t *testing.T) {
+	client := NewClient("http://localhost", "test-token")
+
+	_, err := client.CreateWebhook("my-agent", "not-a-url", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid webhook URL")
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/webhooks/wh_1", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DeleteWebhookResponse{Message: "Webhook deleted"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.DeleteWebhook("wh_1")
+	require.NoError(t, err)
+	assert.Equal(t, "Webhook deleted", resp.Message)
+}
+
+func TestTestWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/webhooks/wh_1/test", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(TestWebhookResponse{StatusCode: 200, DurationMs: 42})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-token")
+
+	resp, err := client.TestWebhook("wh_1")
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, 42, resp.DurationMs)
+}
@@ -0,0 +1,46 @@
+// Package batch reads the inputs of 'oken invoke --batch', one JSON object per
+// line, and summarizes the latencies and errors of 'oken invoke --bench'.
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Input is one line of a batch file
+type Input struct {
+	// Line is the line number in the file, counting from 1
+	Line  int
+	Input map[string]any
+}
+
+// Read reads one JSON object per line of up to maxLineSize bytes, skipping
+// blank lines. Errors name the line they are on.
+func Read(r io.Reader, maxLineSize int) ([]Input, error) {
+	var inputs []Input
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(64*1024, maxLineSize)), maxLineSize)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var input map[string]any
+		if err := json.Unmarshal(line, &input); err != nil {
+			return nil, fmt.Errorf("line %d: invalid JSON object: %w", n, err)
+		}
+		if input == nil {
+			return nil, fmt.Errorf("line %d: invalid JSON object: got null", n)
+		}
+		inputs = append(inputs, Input{Line: n, Input: input})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", n+1, err)
+	}
+	return inputs, nil
+}
@@ -0,0 +1,54 @@
+package batch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []Input
+	}{
+		{"empty", "", nil},
+		{"one line", `{"q": "hi"}`, []Input{{Line: 1, Input: map[string]any{"q": "hi"}}}},
+		{
+			"blank lines keep line numbers",
+			"\n{\"q\": 1}\n  \n\t\n{\"q\": 2}\n\n",
+			[]Input{{Line: 2, Input: map[string]any{"q": float64(1)}}, {Line: 5, Input: map[string]any{"q": float64(2)}}},
+		},
+		{"CRLF line endings", "{\"q\": 1}\r\n{}\r\n", []Input{{Line: 1, Input: map[string]any{"q": float64(1)}}, {Line: 2, Input: map[string]any{}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputs, err := Read(strings.NewReader(tt.in), 1024)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, inputs)
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"bad JSON", "{\"q\": 1}\n\n{\"q\": \n", "line 3: invalid JSON object"},
+		{"array", "[1, 2]\n", "line 1: invalid JSON object"},
+		{"string", "{}\n\"hi\"\n", "line 2: invalid JSON object"},
+		{"null", "null\n", "line 1: invalid JSON object: got null"},
+		{"too long", "{}\n{\"q\": \"" + strings.Repeat("x", 64) + "\"}\n", "line 2: bufio.Scanner: token too long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.in), 32)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
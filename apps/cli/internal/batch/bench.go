@@ -0,0 +1,85 @@
+package batch
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxBenchErrors caps the distinct error messages in a bench report
+const maxBenchErrors = 5
+
+// BenchResult is the report of 'oken invoke --bench'. Latencies include
+// failed invocations.
+type BenchResult struct {
+	Slug         string       `json:"slug"`
+	Requests     int          `json:"requests"`
+	Concurrency  int          `json:"concurrency"`
+	Errors       int          `json:"errors"`
+	ErrorRate    float64      `json:"errorRate"`
+	DurationMs   int64        `json:"durationMs"`
+	Throughput   float64      `json:"throughput"`
+	LatencyMinMs float64      `json:"latencyMinMs"`
+	LatencyP50Ms float64      `json:"latencyP50Ms"`
+	LatencyP90Ms float64      `json:"latencyP90Ms"`
+	LatencyP95Ms float64      `json:"latencyP95Ms"`
+	LatencyP99Ms float64      `json:"latencyP99Ms"`
+	LatencyMaxMs float64      `json:"latencyMaxMs"`
+	TopErrors    []BenchError `json:"topErrors,omitempty"`
+}
+
+// BenchError counts the invocations that failed with one message
+type BenchError struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// SummarizeBench computes a bench report from each invocation's latency,
+// of which there must be at least one, and the count of each error message.
+// The most frequent errors come first, ties in alphabetical order.
+func SummarizeBench(slug string, concurrency int, elapsed time.Duration, latencies []time.Duration, failures map[string]int) BenchResult {
+	slices.Sort(latencies)
+	result := BenchResult{
+		Slug:         slug,
+		Requests:     len(latencies),
+		Concurrency:  concurrency,
+		DurationMs:   elapsed.Milliseconds(),
+		LatencyMinMs: milliseconds(latencies[0]),
+		LatencyP50Ms: milliseconds(percentile(latencies, 50)),
+		LatencyP90Ms: milliseconds(percentile(latencies, 90)),
+		LatencyP95Ms: milliseconds(percentile(latencies, 95)),
+		LatencyP99Ms: milliseconds(percentile(latencies, 99)),
+		LatencyMaxMs: milliseconds(latencies[len(latencies)-1]),
+	}
+	if elapsed > 0 {
+		result.Throughput = float64(len(latencies)) / elapsed.Seconds()
+	}
+
+	for message, count := range failures {
+		result.Errors += count
+		result.TopErrors = append(result.TopErrors, BenchError{Message: message, Count: count})
+	}
+	result.ErrorRate = float64(result.Errors) / float64(len(latencies))
+	slices.SortFunc(result.TopErrors, func(a, b BenchError) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Message, b.Message)
+	})
+	if len(result.TopErrors) > maxBenchErrors {
+		result.TopErrors = result.TopErrors[:maxBenchErrors]
+	}
+	return result
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank-1, 0)]
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
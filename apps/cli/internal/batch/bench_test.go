@@ -0,0 +1,82 @@
+package batch
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ms(n ...int) []time.Duration {
+	durations := make([]time.Duration, len(n))
+	for i, v := range n {
+		durations[i] = time.Duration(v) * time.Millisecond
+	}
+	return durations
+}
+
+func TestPercentile(t *testing.T) {
+	hundred := make([]int, 100)
+	for i := range hundred {
+		hundred[i] = i + 1
+	}
+
+	tests := []struct {
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{ms(7), 50, 7 * time.Millisecond},
+		{ms(7), 99, 7 * time.Millisecond},
+		{ms(7), 0, 7 * time.Millisecond},
+		{ms(1, 2), 50, 1 * time.Millisecond},
+		{ms(1, 2), 99, 2 * time.Millisecond},
+		{ms(1, 2, 3, 4), 50, 2 * time.Millisecond},
+		{ms(hundred...), 50, 50 * time.Millisecond},
+		{ms(hundred...), 99, 99 * time.Millisecond},
+		{ms(hundred...), 100, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, percentile(tt.sorted, tt.p), "p%v of %d", tt.p, len(tt.sorted))
+	}
+}
+
+func TestSummarizeBench(t *testing.T) {
+	result := SummarizeBench("my-agent", 2, 2*time.Second, ms(30, 10, 20, 40), map[string]int{"timeout": 1})
+
+	assert.Equal(t, 4, result.Requests)
+	assert.Equal(t, 2, result.Concurrency)
+	assert.Equal(t, int64(2000), result.DurationMs)
+	assert.InDelta(t, 2.0, result.Throughput, 0.001)
+	assert.Equal(t, 10.0, result.LatencyMinMs)
+	assert.Equal(t, 20.0, result.LatencyP50Ms)
+	assert.Equal(t, 40.0, result.LatencyP99Ms)
+	assert.Equal(t, 40.0, result.LatencyMaxMs)
+	assert.Equal(t, 1, result.Errors)
+	assert.InDelta(t, 0.25, result.ErrorRate, 0.001)
+}
+
+func TestSummarizeBenchTopErrors(t *testing.T) {
+	failures := map[string]int{
+		"timeout":     3,
+		"rate limit":  5,
+		"bad gateway": 3,
+		"agent error": 3,
+	}
+	for i := range 4 {
+		failures[fmt.Sprintf("rare %d", i)] = 1
+	}
+
+	result := SummarizeBench("my-agent", 1, time.Second, ms(make([]int, 20)...), failures)
+
+	assert.Equal(t, 18, result.Errors)
+	// Ties are in alphabetical order, and only the first five are kept
+	assert.Equal(t, []BenchError{
+		{Message: "rate limit", Count: 5},
+		{Message: "agent error", Count: 3},
+		{Message: "bad gateway", Count: 3},
+		{Message: "timeout", Count: 3},
+		{Message: "rare 0", Count: 1},
+	}, result.TopErrors)
+}
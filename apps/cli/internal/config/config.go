@@ -3,8 +3,11 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 type User struct {
@@ -12,27 +15,150 @@ type User struct {
 }
 
 type Config struct {
+	// Version is the layout of the config file; see CurrentVersion
+	Version  int    `json:"version"`
 	Endpoint string `json:"endpoint"`
-	Token    string `json:"token"`
-	User     *User  `json:"user,omitempty"`
+	// Token and User are the login for Endpoint. Every endpoint's login is
+	// kept in Credentials; the file also repeats the one for its own endpoint
+	// here for older versions of the CLI.
+	Token string `json:"token"`
+	User  *User  `json:"user,omitempty"`
+	// TokenExpiresAt is when Token expires (RFC 3339), if the platform said
+	TokenExpiresAt string `json:"tokenExpiresAt,omitempty"`
+	Org            string `json:"org,omitempty"`
+	// UploadTimeout is a duration like "15m"; "0" disables the timeout
+	UploadTimeout string `json:"uploadTimeout,omitempty"`
+	// Telemetry is set once the user opts in to anonymous usage reports with
+	// 'oken telemetry on'
+	Telemetry bool `json:"telemetry,omitempty"`
+	// TelemetryID is the random installation ID sent with usage reports
+	TelemetryID string `json:"telemetryId,omitempty"`
+	// Aliases maps a shortcut to the command line it stands for, such as
+	// "dp" to "deploy --wait"
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Credentials holds the login for each endpoint, keyed by endpoint URL,
+	// so switching endpoints doesn't require logging in again
+	Credentials map[string]Credential `json:"credentials,omitempty"`
+
+	// Project is the project configuration found in the working directory
+	// or its parents, if any. Its settings override the config file.
+	Project *Project `json:"-"`
+
+	// fileEndpoint is the endpoint in the config file, which Save keeps when
+	// it is overridden with --endpoint, OKEN_ENDPOINT or the project
+	fileEndpoint string
+	// fileOrg is the org in the config file, which Save keeps unless Org is
+	// changed from the project's
+	fileOrg string
+	// envToken is the token from OKEN_TOKEN, which Save doesn't store
+	envToken string
+}
+
+// Credential is the login for one endpoint
+type Credential struct {
+	Token     string `json:"token"`
+	User      *User  `json:"user,omitempty"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
+}
+
+// endpointOverride is set from the --endpoint flag
+var endpointOverride string
+
+// loaded is the config returned by the last successful Load
+var loaded *Config
+
+// Loaded returns the config from the last successful Load, or nil if it
+// hasn't been loaded, without reading the file again
+func Loaded() *Config {
+	return loaded
+}
+
+// SetEndpoint makes Load use endpoint instead of the one in the config file
+// or OKEN_ENDPOINT
+func SetEndpoint(endpoint string) {
+	endpointOverride = endpoint
+}
+
+// credentialKey normalizes an endpoint so that trailing slashes don't matter
+func credentialKey(endpoint string) string {
+	return strings.TrimRight(endpoint, "/")
 }
 
 const (
 	DefaultEndpoint = "http://localhost:3000"
 	configDir       = ".oken"
 	configFile      = "config.json"
+	cacheDir        = "cache"
+	signingKeyFile  = "signing.key"
+
+	// UploadTimeoutEnv overrides the uploadTimeout config setting
+	UploadTimeoutEnv = "OKEN_UPLOAD_TIMEOUT"
+	// EndpointEnv overrides the endpoint config setting
+	EndpointEnv = "OKEN_ENDPOINT"
+	// TokenEnv supplies a token, such as a service token in CI, instead of
+	// the saved login
+	TokenEnv = "OKEN_TOKEN"
 )
 
-// Path returns the full path to the config file
+// Path returns the full path to the config file: ~/.oken/config.json, or
+// %APPDATA%\oken\config.json on Windows
 func Path() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := baseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFile), nil
+}
+
+// CacheDir returns the directory for cached API responses
+func CacheDir() (string, error) {
+	dir, err := baseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, cacheDir), nil
+}
+
+// SigningKeyPath returns the default private key for 'oken deploy --sign'
+func SigningKeyPath() (string, error) {
+	dir, err := baseDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, configDir, configFile), nil
+	return filepath.Join(dir, signingKeyFile), nil
+}
+
+// ResolveUploadTimeout returns the upload timeout from OKEN_UPLOAD_TIMEOUT or
+// the config file, in that order, or fallback if neither is set. A zero
+// duration disables the timeout.
+func (c *Config) ResolveUploadTimeout(fallback time.Duration) (time.Duration, error) {
+	value, source := os.Getenv(UploadTimeoutEnv), UploadTimeoutEnv
+	if value == "" {
+		value, source = c.UploadTimeout, "uploadTimeout"
+	}
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a duration like 15m, or 0 to disable", source, value)
+	}
+	return d, nil
 }
 
-// Load reads the config from disk, returning defaults if not found
+// TokenExpired reports whether the token is known to have expired by now
+func (c *Config) TokenExpired(now time.Time) bool {
+	if c.TokenExpiresAt == "" {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, c.TokenExpiresAt)
+	return err == nil && !now.Before(expiresAt)
+}
+
+// Load reads the config from disk, returning defaults if not found. The
+// endpoint comes from --endpoint, OKEN_ENDPOINT, the project configuration or
+// the file, in that order, and Token and User are the login for it unless
+// OKEN_TOKEN is set. The project's org also overrides the file's.
 func Load() (*Config, error) {
 	cfg := &Config{
 		Endpoint: DefaultEndpoint,
@@ -44,34 +170,67 @@ func Load() (*Config, error) {
 	}
 
 	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return cfg, nil
-		}
+	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
+	if err == nil {
+		// Warn if config file has insecure permissions
+		warnInsecurePermissions(os.Stderr, path)
 
-	// Warn if config file has insecure permissions
-	if info, err := os.Stat(path); err == nil {
-		mode := info.Mode().Perm()
-		if mode&0077 != 0 {
-			fmt.Fprintf(os.Stderr, "Warning: config file %s has insecure permissions %04o\n", path, mode)
+		if data, err = migrate(path, data); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, err
 		}
-	}
-
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return nil, err
 	}
 
 	if cfg.Endpoint == "" {
 		cfg.Endpoint = DefaultEndpoint
 	}
+	cfg.fileEndpoint, cfg.fileOrg = cfg.Endpoint, cfg.Org
+
+	if cwd, err := os.Getwd(); err == nil {
+		if cfg.Project, err = FindProject(cwd); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Project != nil {
+		if cfg.Project.Endpoint != "" {
+			cfg.Endpoint = cfg.Project.Endpoint
+		}
+		if cfg.Project.Org != "" {
+			cfg.Org = cfg.Project.Org
+		}
+	}
+
+	if endpointOverride != "" {
+		cfg.Endpoint = endpointOverride
+	} else if env := os.Getenv(EndpointEnv); env != "" {
+		cfg.Endpoint = env
+	}
+
+	cred := cfg.Credentials[credentialKey(cfg.Endpoint)]
+	cfg.Token, cfg.User, cfg.TokenExpiresAt = cred.Token, cred.User, cred.ExpiresAt
 
+	if token := os.Getenv(TokenEnv); token != "" {
+		cfg.Token, cfg.User, cfg.TokenExpiresAt = token, nil, ""
+		cfg.envToken = token
+	}
+
+	loaded = cfg
 	return cfg, nil
 }
 
-// Save writes the config to disk
+// Save writes the config to disk, storing Token and User as the login for
+// Endpoint. An endpoint given with --endpoint, OKEN_ENDPOINT or the project
+// isn't saved as the default, nor is the project's org.
 func Save(cfg *Config) error {
+	// Fields added by a newer CLI would be lost
+	if cfg.Version > CurrentVersion {
+		return fmt.Errorf("config file version %d was written by a newer oken; upgrade oken to change it", cfg.Version)
+	}
+
 	path, err := Path()
 	if err != nil {
 		return err
@@ -82,7 +241,31 @@ func Save(cfg *Config) error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	file := *cfg
+	file.Version = CurrentVersion
+	file.Credentials = maps.Clone(cfg.Credentials)
+	if file.Credentials == nil {
+		file.Credentials = map[string]Credential{}
+	}
+	switch {
+	case cfg.envToken != "" && cfg.Token == cfg.envToken:
+		// Keep the saved login; OKEN_TOKEN is only for this process
+	case cfg.Token != "":
+		file.Credentials[credentialKey(cfg.Endpoint)] = Credential{Token: cfg.Token, User: cfg.User, ExpiresAt: cfg.TokenExpiresAt}
+	default:
+		delete(file.Credentials, credentialKey(cfg.Endpoint))
+	}
+
+	if file.fileEndpoint != "" {
+		file.Endpoint = file.fileEndpoint
+	}
+	if cfg.Project != nil && cfg.Project.Org != "" && cfg.Org == cfg.Project.Org {
+		file.Org = cfg.fileOrg
+	}
+	cred := file.Credentials[credentialKey(file.Endpoint)]
+	file.Token, file.User, file.TokenExpiresAt = cred.Token, cred.User, cred.ExpiresAt
+
+	data, err := json.MarshalIndent(&file, "", "  ")
 	if err != nil {
 		return err
 	}
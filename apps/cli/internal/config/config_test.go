@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,11 +16,15 @@ func setupTestHome(t *testing.T) (string, func()) {
 	tmpDir, err := os.MkdirTemp("", "oken-config-test")
 	require.NoError(t, err)
 
-	oldHome := os.Getenv("HOME")
+	oldHome, oldProfile, oldAppData := os.Getenv("HOME"), os.Getenv("USERPROFILE"), os.Getenv("APPDATA")
 	_ = os.Setenv("HOME", tmpDir)
+	_ = os.Setenv("USERPROFILE", tmpDir)
+	_ = os.Setenv("APPDATA", tmpDir)
 
 	return tmpDir, func() {
 		_ = os.Setenv("HOME", oldHome)
+		_ = os.Setenv("USERPROFILE", oldProfile)
+		_ = os.Setenv("APPDATA", oldAppData)
 		_ = os.RemoveAll(tmpDir)
 	}
 }
@@ -30,7 +35,7 @@ func TestPath(t *testing.T) {
 
 	path, err := Path()
 	require.NoError(t, err)
-	assert.Equal(t, filepath.Join(tmpDir, ".oken", "config.json"), path)
+	assert.Equal(t, filepath.Join(testConfigDir(tmpDir), "config.json"), path)
 }
 
 func TestLoadReturnsDefaultsWhenNoFile(t *testing.T) {
@@ -44,11 +49,20 @@ func TestLoadReturnsDefaultsWhenNoFile(t *testing.T) {
 	assert.Nil(t, cfg.User)
 }
 
+func TestLoaded(t *testing.T) {
+	_, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Same(t, cfg, Loaded())
+}
+
 func TestLoadParsesValidConfig(t *testing.T) {
 	tmpDir, cleanup := setupTestHome(t)
 	defer cleanup()
 
-	configDir := filepath.Join(tmpDir, ".oken")
+	configDir := testConfigDir(tmpDir)
 	require.NoError(t, os.MkdirAll(configDir, 0700))
 
 	configData := Config{
@@ -72,7 +86,7 @@ func TestLoadUsesDefaultEndpointWhenEmpty(t *testing.T) {
 	tmpDir, cleanup := setupTestHome(t)
 	defer cleanup()
 
-	configDir := filepath.Join(tmpDir, ".oken")
+	configDir := testConfigDir(tmpDir)
 	require.NoError(t, os.MkdirAll(configDir, 0700))
 
 	configData := map[string]string{"token": "test-token"}
@@ -90,7 +104,7 @@ func TestLoadReturnsErrorOnInvalidJSON(t *testing.T) {
 	tmpDir, cleanup := setupTestHome(t)
 	defer cleanup()
 
-	configDir := filepath.Join(tmpDir, ".oken")
+	configDir := testConfigDir(tmpDir)
 	require.NoError(t, os.MkdirAll(configDir, 0700))
 	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte("invalid json"), 0600))
 
@@ -112,12 +126,10 @@ func TestSaveCreatesDirectoryAndFile(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify file exists
-	configPath := filepath.Join(tmpDir, ".oken", "config.json")
+	configPath := filepath.Join(testConfigDir(tmpDir), "config.json")
 	info, err := os.Stat(configPath)
 	require.NoError(t, err)
-
-	// Verify permissions (0600)
-	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.False(t, info.IsDir())
 
 	// Verify content
 	data, err := os.ReadFile(configPath)
@@ -135,7 +147,7 @@ func TestSaveOverwritesExistingFile(t *testing.T) {
 	tmpDir, cleanup := setupTestHome(t)
 	defer cleanup()
 
-	configDir := filepath.Join(tmpDir, ".oken")
+	configDir := testConfigDir(tmpDir)
 	require.NoError(t, os.MkdirAll(configDir, 0700))
 	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"token":"old"}`), 0600))
 
@@ -151,3 +163,175 @@ func TestSaveOverwritesExistingFile(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "new-token", loaded.Token)
 }
+
+func TestSaveAndLoadOrg(t *testing.T) {
+	_, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	require.NoError(t, Save(&Config{Endpoint: DefaultEndpoint, Token: "test-token", Org: "acme"}))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "acme", cfg.Org)
+}
+
+func useEndpoint(t *testing.T, endpoint string) {
+	t.Helper()
+	SetEndpoint(endpoint)
+	t.Cleanup(func() { SetEndpoint("") })
+}
+
+func TestLoadUsesLoginForEndpoint(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+	t.Setenv(EndpointEnv, "")
+
+	configDir := testConfigDir(tmpDir)
+	require.NoError(t, os.MkdirAll(configDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{
+		"endpoint": "http://localhost:3000",
+		"token": "local-token",
+		"credentials": {
+			"http://localhost:3000": {"token": "local-token"},
+			"https://api.oken.dev": {"token": "prod-token", "user": {"email": "me@example.com"}}
+		}
+	}`), 0600))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "local-token", cfg.Token)
+	assert.Nil(t, cfg.User)
+
+	t.Setenv(EndpointEnv, "https://api.oken.dev/")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.oken.dev/", cfg.Endpoint)
+	assert.Equal(t, "prod-token", cfg.Token)
+	require.NotNil(t, cfg.User)
+	assert.Equal(t, "me@example.com", cfg.User.Email)
+
+	// --endpoint wins over OKEN_ENDPOINT
+	useEndpoint(t, "https://staging.oken.dev")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.oken.dev", cfg.Endpoint)
+	assert.Empty(t, cfg.Token)
+}
+
+func TestSaveKeepsLoginsForOtherEndpoints(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+	t.Setenv(EndpointEnv, "")
+
+	// A config from before per-endpoint logins
+	configDir := testConfigDir(tmpDir)
+	require.NoError(t, os.MkdirAll(configDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"endpoint":"http://localhost:3000","token":"local-token"}`), 0600))
+
+	useEndpoint(t, "https://api.oken.dev")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Token)
+
+	cfg.Token = "prod-token"
+	cfg.TokenExpiresAt = "2026-06-01T00:00:00Z"
+	require.NoError(t, Save(cfg))
+
+	data, err := os.ReadFile(filepath.Join(configDir, "config.json"))
+	require.NoError(t, err)
+	var saved Config
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, "http://localhost:3000", saved.Endpoint)
+	assert.Equal(t, "local-token", saved.Token)
+	assert.Equal(t, map[string]Credential{
+		"http://localhost:3000": {Token: "local-token"},
+		"https://api.oken.dev":  {Token: "prod-token", ExpiresAt: "2026-06-01T00:00:00Z"},
+	}, saved.Credentials)
+	assert.Empty(t, saved.TokenExpiresAt)
+
+	SetEndpoint("")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "local-token", cfg.Token)
+}
+
+func TestTokenFromEnv(t *testing.T) {
+	_, cleanup := setupTestHome(t)
+	defer cleanup()
+	t.Setenv(EndpointEnv, "")
+
+	require.NoError(t, Save(&Config{Endpoint: DefaultEndpoint, Token: "personal-token", User: &User{Email: "me@example.com"}}))
+
+	t.Setenv(TokenEnv, "oks_ci")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "oks_ci", cfg.Token)
+	assert.Nil(t, cfg.User)
+
+	// Saving other settings doesn't store the token from the environment
+	cfg.Org = "acme"
+	require.NoError(t, Save(cfg))
+
+	t.Setenv(TokenEnv, "")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "personal-token", cfg.Token)
+	assert.Equal(t, "acme", cfg.Org)
+}
+
+func TestTokenExpired(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.False(t, (&Config{}).TokenExpired(now))
+	assert.False(t, (&Config{TokenExpiresAt: "soon"}).TokenExpired(now))
+	assert.False(t, (&Config{TokenExpiresAt: "2026-05-02T00:00:00Z"}).TokenExpired(now))
+	assert.True(t, (&Config{TokenExpiresAt: "2026-05-01T12:00:00Z"}).TokenExpired(now))
+	assert.True(t, (&Config{TokenExpiresAt: "2026-04-30T00:00:00+02:00"}).TokenExpired(now))
+}
+
+func TestCacheDir(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	dir, err := CacheDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(testConfigDir(tmpDir), "cache"), dir)
+}
+
+func TestSigningKeyPath(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	path, err := SigningKeyPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(testConfigDir(tmpDir), "signing.key"), path)
+}
+
+func TestResolveUploadTimeout(t *testing.T) {
+	t.Setenv(UploadTimeoutEnv, "")
+
+	cfg := &Config{}
+	d, err := cfg.ResolveUploadTimeout(5 * time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, d)
+
+	cfg.UploadTimeout = "20m"
+	d, err = cfg.ResolveUploadTimeout(5 * time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Minute, d)
+
+	t.Setenv(UploadTimeoutEnv, "0")
+	d, err = cfg.ResolveUploadTimeout(5 * time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), d)
+
+	t.Setenv(UploadTimeoutEnv, "soon")
+	_, err = cfg.ResolveUploadTimeout(5 * time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), UploadTimeoutEnv)
+
+	t.Setenv(UploadTimeoutEnv, "")
+	cfg.UploadTimeout = "-1m"
+	_, err = cfg.ResolveUploadTimeout(5 * time.Minute)
+	require.Error(t, err)
+}
@@ -0,0 +1,32 @@
+//go:build !windows
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// baseDir returns ~/.oken, where config and cache files live
+func baseDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, configDir), nil
+}
+
+// warnInsecurePermissions warns if the config file is readable or writable
+// by anyone other than its owner
+func warnInsecurePermissions(w io.Writer, path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	mode := info.Mode().Perm()
+	if mode&0077 != 0 {
+		_, _ = fmt.Fprintf(w, "Warning: config file %s has insecure permissions %04o\n", path, mode)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfigDir(home string) string {
+	return filepath.Join(home, ".oken")
+}
+
+func TestSaveRestrictsPermissions(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	require.NoError(t, Save(&Config{Endpoint: DefaultEndpoint, Token: "test-token"}))
+
+	info, err := os.Stat(filepath.Join(testConfigDir(tmpDir), "config.json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestWarnInsecurePermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{}`), 0600))
+
+	var out bytes.Buffer
+	warnInsecurePermissions(&out, path)
+	assert.Empty(t, out.String())
+
+	require.NoError(t, os.Chmod(path, 0644))
+	warnInsecurePermissions(&out, path)
+	assert.Contains(t, out.String(), "insecure permissions 0644")
+}
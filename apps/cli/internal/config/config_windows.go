@@ -0,0 +1,33 @@
+//go:build windows
+
+package config
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// windowsConfigDir is the directory under %APPDATA% holding config and cache files
+const windowsConfigDir = "oken"
+
+// baseDir returns %APPDATA%\oken. A config left in %USERPROFILE%\.oken by
+// older versions is still used until it is moved.
+func baseDir() (string, error) {
+	if home, err := os.UserHomeDir(); err == nil {
+		legacy := filepath.Join(home, configDir)
+		if _, err := os.Stat(filepath.Join(legacy, configFile)); err == nil {
+			return legacy, nil
+		}
+	}
+
+	appData, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(appData, windowsConfigDir), nil
+}
+
+// warnInsecurePermissions does nothing on Windows, where access is governed
+// by ACLs rather than the mode bits Go reports
+func warnInsecurePermissions(w io.Writer, path string) {}
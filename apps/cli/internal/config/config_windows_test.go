@@ -0,0 +1,39 @@
+//go:build windows
+
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfigDir(home string) string {
+	return filepath.Join(home, "oken")
+}
+
+func TestPathUsesLegacyConfig(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	legacy := filepath.Join(tmpDir, ".oken")
+	require.NoError(t, os.MkdirAll(legacy, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "config.json"), []byte(`{}`), 0600))
+
+	path, err := Path()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(legacy, "config.json"), path)
+}
+
+func TestWarnInsecurePermissionsIsSkipped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{}`), 0644))
+
+	var out bytes.Buffer
+	warnInsecurePermissions(&out, path)
+	assert.Empty(t, out.String())
+}
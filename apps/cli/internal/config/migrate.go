@@ -0,0 +1,90 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// CurrentVersion is the config file layout written by this CLI
+const CurrentVersion = 1
+
+// migrations upgrade a config file one version at a time: migrations[i]
+// turns version i into version i+1. They work on the raw JSON object, so
+// fields this CLI doesn't know about are kept.
+var migrations = [CurrentVersion]func(raw map[string]json.RawMessage) error{
+	migrateCredentials,
+}
+
+// migrate upgrades config file data to CurrentVersion, writing the result
+// back to path. Files newer than CurrentVersion are returned unchanged.
+func migrate(path string, data []byte) ([]byte, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	version := 0
+	if v, ok := raw["version"]; ok {
+		if err := json.Unmarshal(v, &version); err != nil || version < 0 {
+			return nil, fmt.Errorf("invalid config version %s", v)
+		}
+	}
+	if version >= CurrentVersion {
+		return data, nil
+	}
+
+	for v := version; v < CurrentVersion; v++ {
+		if err := migrations[v](raw); err != nil {
+			return nil, fmt.Errorf("upgrade config from version %d: %w", v, err)
+		}
+	}
+	raw["version"] = json.RawMessage(strconv.Itoa(CurrentVersion))
+
+	migrated, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	// If the file can't be written, it is upgraded again on the next load
+	_ = os.WriteFile(path, migrated, 0600)
+	return migrated, nil
+}
+
+// migrateCredentials moves the single top-level login of version 0 into the
+// per-endpoint credentials of version 1
+func migrateCredentials(raw map[string]json.RawMessage) error {
+	if _, ok := raw["credentials"]; ok {
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	var legacy struct {
+		Endpoint       string `json:"endpoint"`
+		Token          string `json:"token"`
+		User           *User  `json:"user"`
+		TokenExpiresAt string `json:"tokenExpiresAt"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+	if legacy.Token == "" {
+		return nil
+	}
+
+	endpoint := legacy.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	creds, err := json.Marshal(map[string]Credential{
+		credentialKey(endpoint): {Token: legacy.Token, User: legacy.User, ExpiresAt: legacy.TokenExpiresAt},
+	})
+	if err != nil {
+		return err
+	}
+	raw["credentials"] = creds
+	return nil
+}
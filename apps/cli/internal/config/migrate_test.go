@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigratesLegacyFile(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+	t.Setenv(EndpointEnv, "")
+	t.Setenv(TokenEnv, "")
+
+	path := filepath.Join(tmpDir, ".oken", "config.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	legacy := `{"endpoint":"https://api.oken.dev/","token":"old-token","user":{"email":"a@example.com"},"theme":"dark"}`
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0600))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, cfg.Version)
+	assert.Equal(t, "old-token", cfg.Token)
+	assert.Equal(t, "old-token", cfg.Credentials["https://api.oken.dev"].Token)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.EqualValues(t, CurrentVersion, raw["version"])
+	assert.Equal(t, "dark", raw["theme"])
+	assert.Contains(t, raw, "credentials")
+}
+
+func TestLoadKeepsNewerFile(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	path := filepath.Join(tmpDir, ".oken", "config.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	newer := `{"version":99,"endpoint":"https://api.oken.dev","profiles":{}}`
+	require.NoError(t, os.WriteFile(path, []byte(newer), 0600))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 99, cfg.Version)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, newer, string(data))
+
+	err = Save(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "newer oken")
+}
+
+func TestLoadRejectsInvalidVersion(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	path := filepath.Join(tmpDir, ".oken", "config.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, []byte(`{"version":"two"}`), 0600))
+
+	_, err := Load()
+	require.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Project is the configuration checked into a project directory, which
+// overrides the config file for commands run anywhere inside the project.
+// It is read from .oken/config.json, or the [cli] table of oken.toml.
+type Project struct {
+	Endpoint string `json:"endpoint" toml:"endpoint"`
+	Org      string `json:"org" toml:"org"`
+
+	// Path is the file the settings came from
+	Path string `json:"-" toml:"-"`
+}
+
+// projectFile is an oken.toml, of which only the [cli] table is used here
+type projectFile struct {
+	CLI *Project `toml:"cli"`
+}
+
+// FindProject looks for project configuration in dir and its parents,
+// returning nil if there is none. The nearest directory with a
+// .oken/config.json or an oken.toml [cli] table wins; the user's own config
+// file is never treated as a project.
+func FindProject(dir string) (*Project, error) {
+	global, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		path := filepath.Join(dir, configDir, configFile)
+		if path != global {
+			project, err := readProjectJSON(path)
+			if err != nil || project != nil {
+				return project, err
+			}
+		}
+
+		project, err := readProjectTOML(filepath.Join(dir, "oken.toml"))
+		if err != nil || project != nil {
+			return project, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// readProjectJSON reads a .oken/config.json, returning nil if it doesn't exist
+func readProjectJSON(path string) (*Project, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	project := &Project{Path: path}
+	if err := json.Unmarshal(data, project); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return project, nil
+}
+
+// readProjectTOML reads the [cli] table of an oken.toml, returning nil if the
+// file or the table doesn't exist
+func readProjectTOML(path string) (*Project, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var file projectFile
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if file.CLI == nil {
+		return nil, nil
+	}
+	file.CLI.Path = path
+	return file.CLI, nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProject(t *testing.T) {
+	_, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	root := t.TempDir()
+	sub := filepath.Join(root, "agents", "summarizer")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".oken"), 0755))
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".oken", "config.json"), []byte(`{"endpoint":"https://oken.internal","token":"ignored"}`), 0644))
+
+	project, err := FindProject(sub)
+	require.NoError(t, err)
+	require.NotNil(t, project)
+	assert.Equal(t, "https://oken.internal", project.Endpoint)
+	assert.Equal(t, filepath.Join(root, ".oken", "config.json"), project.Path)
+
+	// An oken.toml without a [cli] table doesn't stop the search
+	require.NoError(t, os.WriteFile(filepath.Join(sub, "oken.toml"), []byte("name = \"Summarizer\"\n"), 0644))
+	project, err = FindProject(sub)
+	require.NoError(t, err)
+	assert.Equal(t, "https://oken.internal", project.Endpoint)
+
+	require.NoError(t, os.WriteFile(filepath.Join(sub, "oken.toml"), []byte("name = \"Summarizer\"\n\n[cli]\norg = \"acme\"\n"), 0644))
+	project, err = FindProject(sub)
+	require.NoError(t, err)
+	assert.Equal(t, &Project{Org: "acme", Path: filepath.Join(sub, "oken.toml")}, project)
+}
+
+func TestFindProjectIgnoresUserConfig(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	require.NoError(t, Save(&Config{Endpoint: "https://api.oken.dev"}))
+
+	project, err := FindProject(tmpDir)
+	require.NoError(t, err)
+	assert.Nil(t, project)
+}
+
+func TestLoadAppliesProject(t *testing.T) {
+	_, cleanup := setupTestHome(t)
+	defer cleanup()
+	t.Setenv(EndpointEnv, "")
+	t.Setenv(TokenEnv, "")
+
+	require.NoError(t, Save(&Config{
+		Endpoint: "https://api.oken.dev",
+		Org:      "personal",
+		Credentials: map[string]Credential{
+			"https://oken.internal": {Token: "internal-token"},
+		},
+	}))
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "oken.toml"), []byte("[cli]\nendpoint = \"https://oken.internal\"\norg = \"acme\"\n"), 0644))
+	t.Chdir(root)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://oken.internal", cfg.Endpoint)
+	assert.Equal(t, "acme", cfg.Org)
+	assert.Equal(t, "internal-token", cfg.Token)
+
+	t.Setenv(EndpointEnv, "https://staging.oken.dev")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.oken.dev", cfg.Endpoint)
+	t.Setenv(EndpointEnv, "")
+
+	// The project's settings aren't saved to the user's config
+	require.NoError(t, Save(cfg))
+	t.Chdir(t.TempDir())
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.oken.dev", cfg.Endpoint)
+	assert.Equal(t, "personal", cfg.Org)
+	assert.Nil(t, cfg.Project)
+}
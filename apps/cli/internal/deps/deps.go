@@ -0,0 +1,207 @@
+// Package deps reads the Python dependencies an agent declares, from a
+// lockfile, pyproject.toml or requirements.txt.
+package deps
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Lockfiles are checked in this order; the first one found wins over
+// pyproject.toml and requirements.txt
+var Lockfiles = []string{"uv.lock", "poetry.lock"}
+
+const (
+	// ProjectFile lists direct dependencies under [project], possibly unpinned
+	ProjectFile = "pyproject.toml"
+	// RequirementsFile lists direct dependencies, possibly unpinned
+	RequirementsFile = "requirements.txt"
+)
+
+// Dependency is a Python package the agent depends on
+type Dependency struct {
+	// Name is normalized as in PEP 503, e.g. "typing-extensions"
+	Name string
+	// Version is the exact version, or empty when not pinned
+	Version string
+	// Specifier is the version constraint as written, e.g. ">=2.0,<3"
+	Specifier string
+}
+
+// Pinned reports whether the dependency resolves to exactly one version
+func (d Dependency) Pinned() bool {
+	return d.Version != ""
+}
+
+// PURL returns the package URL identifying the dependency in an SBOM
+func (d Dependency) PURL() string {
+	if d.Version == "" {
+		return "pkg:pypi/" + d.Name
+	}
+	return "pkg:pypi/" + d.Name + "@" + d.Version
+}
+
+// Set is the dependencies read from one file in an agent directory
+type Set struct {
+	// File is the name of the file they were read from
+	File string
+	// Locked is true when File is a lockfile, so versions are exact
+	Locked       bool
+	Dependencies []Dependency
+}
+
+// Unpinned returns the dependencies that don't resolve to exactly one version
+func (s *Set) Unpinned() []Dependency {
+	var unpinned []Dependency
+	for _, d := range s.Dependencies {
+		if !d.Pinned() {
+			unpinned = append(unpinned, d)
+		}
+	}
+	return unpinned
+}
+
+// Load reads an agent's dependencies from the first lockfile found in dir,
+// pyproject.toml or requirements.txt, in that order, as the runner installs
+// them. It returns nil if dir has none of them.
+func Load(dir string) (*Set, error) {
+	for _, name := range Lockfiles {
+		path := filepath.Join(dir, name)
+		if !fileExists(path) {
+			continue
+		}
+		deps, err := parseLockfile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return &Set{File: name, Locked: true, Dependencies: deps}, nil
+	}
+
+	if path := filepath.Join(dir, ProjectFile); fileExists(path) {
+		deps, err := parseProject(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ProjectFile, err)
+		}
+		return &Set{File: ProjectFile, Dependencies: deps}, nil
+	}
+
+	file, err := os.Open(filepath.Join(dir, RequirementsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	deps, err := ParseRequirements(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", RequirementsFile, err)
+	}
+	return &Set{File: RequirementsFile, Dependencies: deps}, nil
+}
+
+// requirementPattern matches a name, optional extras and the rest of the line
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*(.*)$`)
+
+// ParseRequirements parses a requirements.txt. Comments, environment markers
+// and option lines such as -r or --index-url are skipped.
+func ParseRequirements(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		// Direct references such as "pkg @ https://..." have no version to read
+		if name, _, ok := strings.Cut(line, "@"); ok && !strings.ContainsAny(name, "<>=!~") {
+			deps = append(deps, Dependency{Name: Normalize(strings.TrimSpace(name))})
+			continue
+		}
+		if spec, _, ok := strings.Cut(line, ";"); ok {
+			line = strings.TrimSpace(spec)
+		}
+
+		m := requirementPattern.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("line %d: invalid requirement %q", n, line)
+		}
+		dep := Dependency{Name: Normalize(m[1]), Specifier: strings.ReplaceAll(m[3], " ", "")}
+		if version, ok := strings.CutPrefix(dep.Specifier, "==="); ok {
+			dep.Version = version
+		} else if version, ok := strings.CutPrefix(dep.Specifier, "=="); ok && !strings.ContainsAny(version, ",*") {
+			dep.Version = version
+		}
+		deps = append(deps, dep)
+	}
+	return deps, scanner.Err()
+}
+
+// lockfile is the part of uv.lock and poetry.lock that lists packages
+type lockfile struct {
+	Package []struct {
+		Name    string         `toml:"name"`
+		Version string         `toml:"version"`
+		Source  map[string]any `toml:"source"`
+	} `toml:"package"`
+}
+
+// parseLockfile reads the packages of a uv.lock or poetry.lock, skipping the
+// project itself
+func parseLockfile(path string) ([]Dependency, error) {
+	var lock lockfile
+	if _, err := toml.DecodeFile(path, &lock); err != nil {
+		return nil, err
+	}
+
+	deps := make([]Dependency, 0, len(lock.Package))
+	for _, p := range lock.Package {
+		// uv records the project being locked as an editable or virtual source
+		if _, ok := p.Source["editable"]; ok {
+			continue
+		}
+		if _, ok := p.Source["virtual"]; ok {
+			continue
+		}
+		deps = append(deps, Dependency{Name: Normalize(p.Name), Version: p.Version, Specifier: "==" + p.Version})
+	}
+	return deps, nil
+}
+
+// parseProject reads the [project] dependencies of a pyproject.toml, which
+// use the same syntax as requirements.txt lines
+func parseProject(path string) ([]Dependency, error) {
+	var project struct {
+		Project struct {
+			Dependencies []string `toml:"dependencies"`
+		} `toml:"project"`
+	}
+	if _, err := toml.DecodeFile(path, &project); err != nil {
+		return nil, err
+	}
+	return ParseRequirements(strings.NewReader(strings.Join(project.Project.Dependencies, "\n")))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+var separators = regexp.MustCompile(`[-_.]+`)
+
+// Normalize returns the PEP 503 normalized form of a package name
+func Normalize(name string) string {
+	return separators.ReplaceAllString(strings.ToLower(name), "-")
+}
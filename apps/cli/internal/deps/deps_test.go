@@ -0,0 +1,139 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequirements(t *testing.T) {
+	input := `# web
+requests==2.31.0
+Flask>=2.0,<3  # pinned later
+typing_extensions
+pydantic[email] == 2.5.3
+numpy==1.26.*
+uvicorn===0.24.0
+colorama==0.4.6 ; sys_platform == "win32"
+mylib @ https://example.com/mylib-1.0.tar.gz
+-r base.txt
+--index-url https://pypi.example.com/simple
+`
+	deps, err := ParseRequirements(strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Dependency{
+		{Name: "requests", Version: "2.31.0", Specifier: "==2.31.0"},
+		{Name: "flask", Specifier: ">=2.0,<3"},
+		{Name: "typing-extensions"},
+		{Name: "pydantic", Version: "2.5.3", Specifier: "==2.5.3"},
+		{Name: "numpy", Specifier: "==1.26.*"},
+		{Name: "uvicorn", Version: "0.24.0", Specifier: "===0.24.0"},
+		{Name: "colorama", Version: "0.4.6", Specifier: "==0.4.6"},
+		{Name: "mylib"},
+	}, deps)
+
+	_, err = ParseRequirements(strings.NewReader("requests==2.0\n!!!\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestDependencyPURL(t *testing.T) {
+	assert.Equal(t, "pkg:pypi/requests@2.31.0", Dependency{Name: "requests", Version: "2.31.0"}.PURL())
+	assert.Equal(t, "pkg:pypi/flask", Dependency{Name: "flask"}.PURL())
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "typing-extensions", Normalize("Typing_Extensions"))
+	assert.Equal(t, "zope-interface", Normalize("zope.interface"))
+	assert.Equal(t, "a-b", Normalize("a-_.b"))
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	set, err := Load(dir)
+	require.NoError(t, err)
+	assert.Nil(t, set)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "requirements.txt"), []byte("requests>=2\n"), 0644))
+	set, err = Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "requirements.txt", set.File)
+	assert.False(t, set.Locked)
+	assert.Equal(t, []Dependency{{Name: "requests", Specifier: ">=2"}}, set.Dependencies)
+
+	// A lockfile wins over requirements.txt
+	poetry := `[[package]]
+name = "requests"
+version = "2.31.0"
+
+[[package]]
+name = "Certifi"
+version = "2023.11.17"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "poetry.lock"), []byte(poetry), 0644))
+	set, err = Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "poetry.lock", set.File)
+	assert.True(t, set.Locked)
+	assert.Equal(t, []Dependency{
+		{Name: "requests", Version: "2.31.0", Specifier: "==2.31.0"},
+		{Name: "certifi", Version: "2023.11.17", Specifier: "==2023.11.17"},
+	}, set.Dependencies)
+}
+
+func TestLoadPyproject(t *testing.T) {
+	dir := t.TempDir()
+	pyproject := `[project]
+name = "my-agent"
+dependencies = ["httpx==0.27.0", "pydantic>=2"]
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pyproject.toml"), []byte(pyproject), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "requirements.txt"), []byte("requests\n"), 0644))
+
+	set, err := Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "pyproject.toml", set.File)
+	assert.False(t, set.Locked)
+	assert.Equal(t, []Dependency{
+		{Name: "httpx", Version: "0.27.0", Specifier: "==0.27.0"},
+		{Name: "pydantic", Specifier: ">=2"},
+	}, set.Dependencies)
+	assert.Equal(t, []Dependency{{Name: "pydantic", Specifier: ">=2"}}, set.Unpinned())
+}
+
+func TestLoadUVLockSkipsProject(t *testing.T) {
+	dir := t.TempDir()
+	uv := `version = 1
+
+[[package]]
+name = "my-agent"
+version = "0.1.0"
+source = { virtual = "." }
+
+[[package]]
+name = "httpx"
+version = "0.27.0"
+source = { registry = "https://pypi.org/simple" }
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "uv.lock"), []byte(uv), 0644))
+
+	set, err := Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "uv.lock", set.File)
+	assert.Equal(t, []Dependency{{Name: "httpx", Version: "0.27.0", Specifier: "==0.27.0"}}, set.Dependencies)
+}
+
+func TestLoadInvalidLockfile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "poetry.lock"), []byte("[[package"), 0644))
+
+	_, err := Load(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "poetry.lock")
+}
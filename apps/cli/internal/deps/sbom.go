@@ -0,0 +1,100 @@
+package deps
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SBOMFile is the name the SBOM is uploaded under
+const SBOMFile = "sbom.cdx.json"
+
+// SBOMMetadata describes the agent and tool an SBOM is generated for
+type SBOMMetadata struct {
+	Agent       string
+	ToolVersion string
+	Time        time.Time
+}
+
+type cdxBOM struct {
+	BOMFormat    string         `json:"bomFormat"`
+	SpecVersion  string         `json:"specVersion"`
+	Version      int            `json:"version"`
+	Metadata     cdxMetadata    `json:"metadata"`
+	Components   []cdxComponent `json:"components"`
+	Dependencies []cdxDependsOn `json:"dependencies,omitempty"`
+}
+
+type cdxMetadata struct {
+	Timestamp string        `json:"timestamp"`
+	Tools     cdxTools      `json:"tools"`
+	Component cdxComponent  `json:"component"`
+	Props     []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type    string `json:"type"`
+	BOMRef  string `json:"bom-ref,omitempty"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+type cdxDependsOn struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SBOM returns a CycloneDX 1.5 JSON document listing the dependencies as
+// libraries of the agent
+func (s *Set) SBOM(meta SBOMMetadata) ([]byte, error) {
+	agentRef := "agent:" + meta.Agent
+	bom := cdxBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cdxMetadata{
+			Timestamp: meta.Time.UTC().Format(time.RFC3339),
+			Tools: cdxTools{Components: []cdxComponent{
+				{Type: "application", Name: "oken-cli", Version: meta.ToolVersion},
+			}},
+			Component: cdxComponent{Type: "application", BOMRef: agentRef, Name: meta.Agent},
+			Props:     []cdxProperty{{Name: "oken:dependencies:source", Value: s.File}},
+		},
+		Components: make([]cdxComponent, 0, len(s.Dependencies)),
+	}
+
+	refs := make([]string, 0, len(s.Dependencies))
+	seen := make(map[string]bool)
+	for _, d := range s.Dependencies {
+		purl := d.PURL()
+		// A package listed twice with different markers is one component
+		if seen[purl] {
+			continue
+		}
+		seen[purl] = true
+		bom.Components = append(bom.Components, cdxComponent{
+			Type:    "library",
+			BOMRef:  purl,
+			Name:    d.Name,
+			Version: d.Version,
+			PURL:    purl,
+		})
+		refs = append(refs, purl)
+	}
+	// Lockfiles include transitive packages, so only requirements.txt
+	// tells which dependencies are direct
+	if !s.Locked {
+		bom.Dependencies = []cdxDependsOn{{Ref: agentRef, DependsOn: refs}}
+	}
+
+	return json.MarshalIndent(bom, "", "  ")
+}
@@ -0,0 +1,60 @@
+package deps
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSBOM(t *testing.T) {
+	set := &Set{
+		File: "requirements.txt",
+		Dependencies: []Dependency{
+			{Name: "requests", Version: "2.31.0"},
+			{Name: "flask", Specifier: ">=2"},
+			{Name: "requests", Version: "2.31.0"},
+		},
+	}
+
+	data, err := set.SBOM(SBOMMetadata{Agent: "my-agent", ToolVersion: "v1.2.3", Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)})
+	require.NoError(t, err)
+
+	var bom map[string]any
+	require.NoError(t, json.Unmarshal(data, &bom))
+	assert.Equal(t, "CycloneDX", bom["bomFormat"])
+	assert.Equal(t, "1.5", bom["specVersion"])
+
+	metadata := bom["metadata"].(map[string]any)
+	assert.Equal(t, "2024-01-02T03:04:05Z", metadata["timestamp"])
+	assert.Equal(t, "my-agent", metadata["component"].(map[string]any)["name"])
+
+	components := bom["components"].([]any)
+	require.Len(t, components, 2)
+	assert.Equal(t, map[string]any{
+		"type":    "library",
+		"bom-ref": "pkg:pypi/requests@2.31.0",
+		"name":    "requests",
+		"version": "2.31.0",
+		"purl":    "pkg:pypi/requests@2.31.0",
+	}, components[0])
+	assert.Equal(t, "pkg:pypi/flask", components[1].(map[string]any)["purl"])
+
+	dependencies := bom["dependencies"].([]any)
+	require.Len(t, dependencies, 1)
+	assert.Equal(t, []any{"pkg:pypi/requests@2.31.0", "pkg:pypi/flask"}, dependencies[0].(map[string]any)["dependsOn"])
+}
+
+func TestSBOMFromLockfileOmitsDirectDependencies(t *testing.T) {
+	set := &Set{File: "uv.lock", Locked: true, Dependencies: []Dependency{{Name: "httpx", Version: "0.27.0"}}}
+
+	data, err := set.SBOM(SBOMMetadata{Agent: "my-agent", Time: time.Now()})
+	require.NoError(t, err)
+
+	var bom map[string]any
+	require.NoError(t, json.Unmarshal(data, &bom))
+	assert.NotContains(t, bom, "dependencies")
+	assert.Len(t, bom["components"], 1)
+}
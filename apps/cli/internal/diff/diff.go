@@ -0,0 +1,156 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// maxCells bounds the size of the LCS table so huge files don't exhaust memory
+const maxCells = 4_000_000
+
+// ErrTooLarge is returned when the inputs are too large to diff line by line
+var ErrTooLarge = errors.New("file too large to diff")
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type edit struct {
+	kind opKind
+	line string
+}
+
+// Unified returns a unified diff between oldText and newText, or an empty
+// string when they are equal
+func Unified(oldName, newName, oldText, newText string) (string, error) {
+	if oldText == newText {
+		return "", nil
+	}
+
+	a, b := splitLines(oldText), splitLines(newText)
+	if (len(a)+1)*(len(b)+1) > maxCells {
+		return "", ErrTooLarge
+	}
+
+	edits := computeEdits(a, b)
+
+	// Line numbers (0-based) in old and new at each edit index
+	oldPos := make([]int, len(edits)+1)
+	newPos := make([]int, len(edits)+1)
+	for i, e := range edits {
+		oldPos[i+1], newPos[i+1] = oldPos[i], newPos[i]
+		if e.kind != opInsert {
+			oldPos[i+1]++
+		}
+		if e.kind != opDelete {
+			newPos[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	for i := 0; i < len(edits); {
+		if edits[i].kind == opEqual {
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		last := i
+		for j := i + 1; j < len(edits) && j <= last+2*contextLines; j++ {
+			if edits[j].kind != opEqual {
+				last = j
+			}
+		}
+
+		start := max(0, i-contextLines)
+		end := min(len(edits), last+contextLines+1)
+
+		oldStart, oldCount := oldPos[start]+1, oldPos[end]-oldPos[start]
+		newStart, newCount := newPos[start]+1, newPos[end]-newPos[start]
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, e := range edits[start:end] {
+			switch e.kind {
+			case opEqual:
+				sb.WriteString(" ")
+			case opDelete:
+				sb.WriteString("-")
+			case opInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(e.line)
+			sb.WriteString("\n")
+		}
+
+		i = end
+	}
+
+	return sb.String(), nil
+}
+
+// computeEdits builds the edit script from a to b using a longest common
+// subsequence table
+func computeEdits(a, b []string) []edit {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	edits := make([]edit, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, edit{opEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{opDelete, a[i]})
+			i++
+		default:
+			edits = append(edits, edit{opInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		edits = append(edits, edit{opDelete, a[i]})
+	}
+	for ; j < m; j++ {
+		edits = append(edits, edit{opInsert, b[j]})
+	}
+
+	return edits
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,46 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnified(t *testing.T) {
+	t.Run("equal", func(t *testing.T) {
+		out, err := Unified("a", "b", "same\n", "same\n")
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+
+	t.Run("changed line", func(t *testing.T) {
+		out, err := Unified("a/main.py", "b/main.py", "one\ntwo\nthree\n", "one\n2\nthree\n")
+		require.NoError(t, err)
+		assert.Equal(t, "--- a/main.py\n+++ b/main.py\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n", out)
+	})
+
+	t.Run("new file", func(t *testing.T) {
+		out, err := Unified("a/x", "b/x", "", "hello\nworld\n")
+		require.NoError(t, err)
+		assert.Contains(t, out, "@@ -0,0 +1,2 @@\n+hello\n+world\n")
+	})
+
+	t.Run("separate hunks", func(t *testing.T) {
+		var oldLines, newLines []string
+		for i := 0; i < 20; i++ {
+			line := strings.Repeat("x", i+1)
+			oldLines = append(oldLines, line)
+			if i == 1 || i == 18 {
+				line = "changed"
+			}
+			newLines = append(newLines, line)
+		}
+		out, err := Unified("a", "b", strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(out, "@@ -"))
+		assert.Contains(t, out, "@@ -1,5 +1,5 @@")
+		assert.Contains(t, out, "@@ -16,5 +16,5 @@")
+	})
+}
@@ -0,0 +1,162 @@
+// Package git fetches agent sources from git repositories and describes
+// local checkouts.
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Source is a git repository reference of the form <url>#<ref>:<dir>,
+// as accepted by docker build
+type Source struct {
+	URL string
+	// Ref is a branch, tag or commit; empty means the default branch
+	Ref string
+	// Dir is the subdirectory holding the agent, relative to the repository root
+	Dir string
+}
+
+// ParseSource parses <url>[#<ref>[:<dir>]]
+func ParseSource(spec string) (Source, error) {
+	url, fragment, _ := strings.Cut(spec, "#")
+	if url == "" {
+		return Source{}, fmt.Errorf("missing repository URL in %q", spec)
+	}
+	ref, dir, _ := strings.Cut(fragment, ":")
+	// git would read these as options, such as --upload-pack, which runs
+	// a command
+	if strings.HasPrefix(url, "-") {
+		return Source{}, fmt.Errorf("invalid repository URL %q", url)
+	}
+	if strings.HasPrefix(ref, "-") {
+		return Source{}, fmt.Errorf("invalid ref %q", ref)
+	}
+
+	src := Source{URL: url, Ref: ref}
+	if dir != "" {
+		dir = filepath.Clean(filepath.FromSlash(dir))
+		if !filepath.IsLocal(dir) {
+			return Source{}, fmt.Errorf("directory %q must be inside the repository", dir)
+		}
+		if dir != "." {
+			src.Dir = dir
+		}
+	}
+	return src, nil
+}
+
+// String formats the source as <url>#<ref>:<dir>, omitting empty parts
+func (s Source) String() string {
+	out := s.URL
+	if s.Ref != "" || s.Dir != "" {
+		out += "#" + s.Ref
+	}
+	if s.Dir != "" {
+		out += ":" + filepath.ToSlash(s.Dir)
+	}
+	return out
+}
+
+// Repository returns the URL without any credentials it carries, for
+// recording on deployments
+func (s Source) Repository() string {
+	u, err := url.Parse(s.URL)
+	if err != nil || u.User == nil {
+		return s.URL
+	}
+	u.User = nil
+	return u.String()
+}
+
+// Fetch checks out the source's ref into dest, which must be empty or not
+// exist, and returns the commit SHA. Only the one commit is fetched.
+func Fetch(ctx context.Context, src Source, dest string) (string, error) {
+	ref := src.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	steps := [][]string{
+		{"init", "--quiet", dest},
+		{"-C", dest, "fetch", "--quiet", "--depth", "1", "--", src.URL, ref},
+		{"-C", dest, "checkout", "--quiet", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		if _, err := run(ctx, args...); err != nil {
+			return "", err
+		}
+	}
+	return run(ctx, "-C", dest, "rev-parse", "HEAD")
+}
+
+// Info describes the checkout an agent is deployed from
+type Info struct {
+	// Repository is the origin remote without credentials, if there is one
+	Repository string
+	Commit     string
+	// Branch is empty when HEAD is detached
+	Branch string
+	// Dirty is true when the directory has uncommitted or untracked changes
+	Dirty bool
+}
+
+// Describe returns the commit checked out in dir and whether dir has changes
+// since. It returns nil if git isn't installed, dir isn't in a work tree, or
+// the repository has no commits yet.
+func Describe(ctx context.Context, dir string) (*Info, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, nil
+	}
+	if _, err := run(ctx, "-C", dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, nil
+	}
+	commit, err := run(ctx, "-C", dir, "rev-parse", "--verify", "--quiet", "HEAD")
+	if err != nil {
+		return nil, nil
+	}
+
+	info := &Info{Commit: commit}
+	if branch, err := run(ctx, "-C", dir, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		info.Branch = branch
+	}
+	if origin, err := run(ctx, "-C", dir, "remote", "get-url", "origin"); err == nil {
+		info.Repository = Source{URL: origin}.Repository()
+	}
+	// Only changes under dir count, so other agents in a monorepo don't
+	// mark this one dirty
+	status, err := run(ctx, "-C", dir, "status", "--porcelain", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	info.Dirty = status != ""
+	return info, nil
+}
+
+// run runs git and returns its trimmed stdout. Credential prompts are
+// disabled so a private repository fails instead of hanging.
+func run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// Name only the subcommand; URLs may carry credentials
+		sub := args[0]
+		if sub == "-C" {
+			sub = args[2]
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", sub, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", sub, err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
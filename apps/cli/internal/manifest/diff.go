@@ -0,0 +1,342 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+)
+
+// State is the platform's current state for the agents of a manifest
+type State struct {
+	// Agents holds existing agents by slug; missing agents are absent
+	Agents map[string]*api.Agent
+	// Secrets holds the names of each agent's secrets by agent slug
+	Secrets map[string][]string
+	// Schedules holds each agent's schedules by agent slug
+	Schedules map[string][]api.Schedule
+}
+
+// Action is what a change does
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Kind is the type of object a change applies to
+type Kind string
+
+const (
+	KindAgent    Kind = "agent"
+	KindScale    Kind = "scale"
+	KindSecret   Kind = "secret"
+	KindSchedule Kind = "schedule"
+)
+
+// Change is one step that converges the platform towards a manifest
+type Change struct {
+	Action Action
+	Kind   Kind
+	// Agent is the slug of the agent the change applies to
+	Agent string
+
+	// Path is the directory to deploy, for agent creates
+	Path string
+	// Name is the agent name, for agent creates
+	Name string
+	// Update is the change to the agent's name and metadata, for agent
+	// updates; Detail describes it
+	Update *api.UpdateAgentRequest
+	// Scale is the scale request, for scale updates; Detail describes it
+	Scale  *api.ScaleRequest
+	Detail string
+	// Secret is the secret name and SecretRef where its value comes from,
+	// for secret creates and deletes
+	Secret    string
+	SecretRef SecretRef
+	// Schedule is the desired schedule for creates; ScheduleID identifies
+	// the schedule for deletes
+	Schedule   Schedule
+	ScheduleID string
+}
+
+// String describes the change on one line, as in a plan
+func (c Change) String() string {
+	switch c.Kind {
+	case KindAgent:
+		switch c.Action {
+		case ActionCreate:
+			return fmt.Sprintf("agent %s (deploy from %s)", c.Agent, c.Path)
+		case ActionUpdate:
+			return fmt.Sprintf("agent %s: %s", c.Agent, c.Detail)
+		}
+		return fmt.Sprintf("agent %s", c.Agent)
+	case KindScale:
+		return fmt.Sprintf("scale %s: %s", c.Agent, c.Detail)
+	case KindSecret:
+		if c.Action == ActionCreate {
+			return fmt.Sprintf("secret %s/%s (from $%s)", c.Agent, c.Secret, c.SecretRef.FromEnv)
+		}
+		return fmt.Sprintf("secret %s/%s", c.Agent, c.Secret)
+	case KindSchedule:
+		return fmt.Sprintf("schedule %s %q", c.Agent, c.Schedule.Cron)
+	}
+	return fmt.Sprintf("%s %s", c.Kind, c.Agent)
+}
+
+// Diff returns the changes that bring state in line with m, grouped by agent
+// in manifest order. Agents are created before their secrets, scale and
+// schedules. Agents, secrets and schedules that aren't in the manifest are
+// deleted only with prune, after everything else.
+func Diff(m *Manifest, state *State, prune bool) []Change {
+	var changes []Change
+	for _, a := range m.Agents {
+		existing := state.Agents[a.Slug]
+		if existing == nil {
+			path := a.Path
+			if path == "" {
+				path = "."
+			}
+			name := a.Name
+			if name == "" {
+				name = a.Slug
+			}
+			changes = append(changes, Change{Action: ActionCreate, Kind: KindAgent, Agent: a.Slug, Name: name, Path: path})
+		} else if c, ok := diffAgent(a, existing); ok {
+			changes = append(changes, c)
+		}
+
+		changes = append(changes, diffSecrets(a, state.Secrets[a.Slug], prune)...)
+		if c, ok := diffScale(a, existing); ok {
+			changes = append(changes, c)
+		}
+		changes = append(changes, diffSchedules(a, state.Schedules[a.Slug], prune)...)
+	}
+
+	if prune {
+		managed := make(map[string]bool, len(m.Agents))
+		for _, a := range m.Agents {
+			managed[a.Slug] = true
+		}
+		var slugs []string
+		for slug := range state.Agents {
+			if !managed[slug] {
+				slugs = append(slugs, slug)
+			}
+		}
+		sort.Strings(slugs)
+		for _, slug := range slugs {
+			changes = append(changes, Change{Action: ActionDelete, Kind: KindAgent, Agent: slug})
+		}
+	}
+	return changes
+}
+
+// diffAgent compares the name and metadata set in the manifest with those of
+// an existing agent
+func diffAgent(a Agent, existing *api.Agent) (Change, bool) {
+	var req api.UpdateAgentRequest
+	var details []string
+	if a.Name != "" && a.Name != existing.Name {
+		name := a.Name
+		req.Name = &name
+		details = append(details, fmt.Sprintf("name %q → %q", existing.Name, name))
+	}
+	if a.Description != "" && a.Description != stringOrEmpty(existing.Description) {
+		description := a.Description
+		req.Description = &description
+		details = append(details, "description")
+	}
+	if a.Repository != "" && a.Repository != stringOrEmpty(existing.Repository) {
+		repository := a.Repository
+		req.Repository = &repository
+		details = append(details, fmt.Sprintf("repository %s → %s", stringValue(existing.Repository), repository))
+	}
+	if a.Owner != "" && a.Owner != stringOrEmpty(existing.Owner) {
+		owner := a.Owner
+		req.Owner = &owner
+		details = append(details, fmt.Sprintf("owner %s → %s", stringValue(existing.Owner), owner))
+	}
+
+	if len(details) == 0 {
+		return Change{}, false
+	}
+	return Change{Action: ActionUpdate, Kind: KindAgent, Agent: a.Slug, Update: &req, Detail: strings.Join(details, ", ")}, true
+}
+
+func diffSecrets(a Agent, existing []string, prune bool) []Change {
+	var changes []Change
+
+	names := make([]string, 0, len(a.Secrets))
+	for name := range a.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !slices.Contains(existing, name) {
+			changes = append(changes, Change{Action: ActionCreate, Kind: KindSecret, Agent: a.Slug, Secret: name, SecretRef: a.Secrets[name]})
+		}
+	}
+
+	if prune {
+		for _, name := range existing {
+			if _, ok := a.Secrets[name]; !ok {
+				changes = append(changes, Change{Action: ActionDelete, Kind: KindSecret, Agent: a.Slug, Secret: name})
+			}
+		}
+	}
+	return changes
+}
+
+// diffScale compares the desired scale with the agent's, which is nil for
+// agents that don't exist yet
+func diffScale(a Agent, existing *api.Agent) (Change, bool) {
+	if a.Scale == nil {
+		return Change{}, false
+	}
+
+	var req api.ScaleRequest
+	var details []string
+	if r := a.Scale.Replicas; r != nil {
+		var current *int
+		if existing != nil {
+			current = existing.Replicas
+		}
+		if current == nil || *current != *r {
+			req.Replicas = r
+			details = append(details, fmt.Sprintf("replicas %s → %d", intValue(current), *r))
+		}
+	}
+	if m := a.Scale.Memory; m != "" {
+		var current *string
+		if existing != nil {
+			current = existing.Memory
+		}
+		if current == nil || *current != m {
+			req.Memory = &m
+			details = append(details, fmt.Sprintf("memory %s → %s", stringValue(current), m))
+		}
+	}
+	if cpu := a.Scale.CPU; cpu != "" {
+		var current *string
+		if existing != nil {
+			current = existing.CPU
+		}
+		if current == nil || *current != cpu {
+			req.CPU = &cpu
+			details = append(details, fmt.Sprintf("cpu %s → %s", stringValue(current), cpu))
+		}
+	}
+
+	if len(details) == 0 {
+		return Change{}, false
+	}
+	return Change{Action: ActionUpdate, Kind: KindScale, Agent: a.Slug, Scale: &req, Detail: strings.Join(details, ", ")}, true
+}
+
+func diffSchedules(a Agent, existing []api.Schedule, prune bool) []Change {
+	var changes []Change
+
+	// Schedules have no name, so they match on cron expression and input
+	remaining := make(map[string][]api.Schedule, len(existing))
+	for _, s := range existing {
+		key := scheduleKey(s.Cron, s.Input)
+		remaining[key] = append(remaining[key], s)
+	}
+
+	for _, s := range a.Schedules {
+		key := scheduleKey(s.Cron, s.Input)
+		if matches := remaining[key]; len(matches) > 0 {
+			remaining[key] = matches[1:]
+			continue
+		}
+		changes = append(changes, Change{Action: ActionCreate, Kind: KindSchedule, Agent: a.Slug, Schedule: s})
+	}
+
+	if prune {
+		for _, s := range existing {
+			key := scheduleKey(s.Cron, s.Input)
+			if idx := slices.IndexFunc(remaining[key], func(r api.Schedule) bool { return r.ID == s.ID }); idx >= 0 {
+				changes = append(changes, Change{Action: ActionDelete, Kind: KindSchedule, Agent: a.Slug, Schedule: Schedule{Cron: s.Cron, Input: s.Input}, ScheduleID: s.ID})
+			}
+		}
+	}
+	return changes
+}
+
+// scheduleKey identifies a schedule by its cron expression and input. JSON
+// encoding sorts map keys, so equal inputs give equal keys.
+func scheduleKey(cron string, input map[string]any) string {
+	if len(input) == 0 {
+		return cron
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return cron
+	}
+	return cron + " " + string(data)
+}
+
+func intValue(v *int) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", *v)
+}
+
+func stringValue(v *string) string {
+	if v == nil || *v == "" {
+		return "-"
+	}
+	return *v
+}
+
+// FromState builds a manifest describing the given agents as they are on the
+// platform. Secrets reference environment variables of the same name, since
+// their values can't be read back. Paths are left empty.
+func FromState(agents []api.Agent, state *State) *Manifest {
+	m := &Manifest{Agents: make([]Agent, 0, len(agents))}
+	for _, agent := range agents {
+		a := Agent{
+			Slug:        agent.Slug,
+			Description: stringOrEmpty(agent.Description),
+			Repository:  stringOrEmpty(agent.Repository),
+			Owner:       stringOrEmpty(agent.Owner),
+		}
+		if agent.Name != agent.Slug {
+			a.Name = agent.Name
+		}
+
+		scale := Scale{Replicas: agent.Replicas, Memory: stringOrEmpty(agent.Memory), CPU: stringOrEmpty(agent.CPU)}
+		if scale.Replicas != nil || scale.Memory != "" || scale.CPU != "" {
+			a.Scale = &scale
+		}
+
+		for _, name := range state.Secrets[agent.Slug] {
+			if a.Secrets == nil {
+				a.Secrets = make(map[string]SecretRef)
+			}
+			a.Secrets[name] = SecretRef{FromEnv: name}
+		}
+
+		for _, s := range state.Schedules[agent.Slug] {
+			a.Schedules = append(a.Schedules, Schedule{Cron: s.Cron, Input: s.Input})
+		}
+
+		m.Agents = append(m.Agents, a)
+	}
+	return m
+}
+
+func stringOrEmpty(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
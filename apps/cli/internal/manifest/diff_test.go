@@ -0,0 +1,161 @@
+package manifest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestDiffCreatesMissingAgent(t *testing.T) {
+	m := &Manifest{Agents: []Agent{{
+		Slug:      "my-agent",
+		Scale:     &Scale{Replicas: ptr(2)},
+		Secrets:   map[string]SecretRef{"API_KEY": {FromEnv: "API_KEY"}},
+		Schedules: []Schedule{{Cron: "0 9 * * *"}},
+	}}}
+
+	changes := Diff(m, &State{}, false)
+	require.Len(t, changes, 4)
+
+	assert.Equal(t, ActionCreate, changes[0].Action)
+	assert.Equal(t, KindAgent, changes[0].Kind)
+	assert.Equal(t, ".", changes[0].Path)
+	assert.Equal(t, "my-agent", changes[0].Name)
+
+	assert.Equal(t, KindSecret, changes[1].Kind)
+	assert.Equal(t, "API_KEY", changes[1].Secret)
+
+	assert.Equal(t, KindScale, changes[2].Kind)
+	assert.Equal(t, 2, *changes[2].Scale.Replicas)
+	assert.Equal(t, "replicas - → 2", changes[2].Detail)
+
+	assert.Equal(t, KindSchedule, changes[3].Kind)
+	assert.Equal(t, "0 9 * * *", changes[3].Schedule.Cron)
+}
+
+func TestDiffNoChanges(t *testing.T) {
+	m := &Manifest{Agents: []Agent{{
+		Slug:      "my-agent",
+		Scale:     &Scale{Replicas: ptr(2), Memory: "1Gi"},
+		Secrets:   map[string]SecretRef{"API_KEY": {FromEnv: "API_KEY"}},
+		Schedules: []Schedule{{Cron: "0 9 * * *", Input: map[string]any{"n": 1}}},
+	}}}
+	state := &State{
+		Agents:    map[string]*api.Agent{"my-agent": {Slug: "my-agent", Replicas: ptr(2), Memory: ptr("1Gi")}},
+		Secrets:   map[string][]string{"my-agent": {"API_KEY"}},
+		Schedules: map[string][]api.Schedule{"my-agent": {{ID: "s1", Cron: "0 9 * * *", Input: map[string]any{"n": float64(1)}}}},
+	}
+
+	assert.Empty(t, Diff(m, state, true))
+}
+
+func TestDiffUpdatesAndPrunes(t *testing.T) {
+	m := &Manifest{Agents: []Agent{{
+		Slug:      "my-agent",
+		Scale:     &Scale{Replicas: ptr(3), Memory: "1Gi"},
+		Schedules: []Schedule{{Cron: "0 9 * * *"}},
+	}}}
+	state := &State{
+		Agents:    map[string]*api.Agent{"my-agent": {Slug: "my-agent", Replicas: ptr(1), Memory: ptr("1Gi")}},
+		Secrets:   map[string][]string{"my-agent": {"OLD_KEY"}},
+		Schedules: map[string][]api.Schedule{"my-agent": {{ID: "s1", Cron: "*/5 * * * *"}}},
+	}
+
+	changes := Diff(m, state, false)
+	require.Len(t, changes, 2)
+	assert.Equal(t, ActionUpdate, changes[0].Action)
+	assert.Equal(t, "replicas 1 → 3", changes[0].Detail)
+	assert.Nil(t, changes[0].Scale.Memory)
+	assert.Equal(t, ActionCreate, changes[1].Action)
+
+	changes = Diff(m, state, true)
+	require.Len(t, changes, 4)
+	assert.Equal(t, Change{Action: ActionDelete, Kind: KindSecret, Agent: "my-agent", Secret: "OLD_KEY"}, changes[0])
+	assert.Equal(t, ActionDelete, changes[3].Action)
+	assert.Equal(t, "s1", changes[3].ScheduleID)
+	assert.Equal(t, `schedule my-agent "*/5 * * * *"`, changes[3].String())
+}
+
+func TestDiffAgentFields(t *testing.T) {
+	m := &Manifest{Agents: []Agent{{
+		Slug:        "my-agent",
+		Name:        "Support Bot",
+		Description: "Answers support tickets",
+		Owner:       "#support",
+	}}}
+	state := &State{
+		Agents: map[string]*api.Agent{"my-agent": {Slug: "my-agent", Name: "my-agent", Owner: ptr("#support")}},
+	}
+
+	changes := Diff(m, state, false)
+	require.Len(t, changes, 1)
+	assert.Equal(t, ActionUpdate, changes[0].Action)
+	assert.Equal(t, KindAgent, changes[0].Kind)
+	assert.Equal(t, "Support Bot", *changes[0].Update.Name)
+	assert.Equal(t, "Answers support tickets", *changes[0].Update.Description)
+	assert.Nil(t, changes[0].Update.Owner)
+	assert.Nil(t, changes[0].Update.Repository)
+	assert.Equal(t, `agent my-agent: name "my-agent" → "Support Bot", description`, changes[0].String())
+
+	// Fields left out of the manifest aren't managed
+	m.Agents[0] = Agent{Slug: "my-agent"}
+	assert.Empty(t, Diff(m, state, false))
+}
+
+func TestDiffPrunesAgents(t *testing.T) {
+	m := &Manifest{Agents: []Agent{{Slug: "my-agent"}}}
+	state := &State{
+		Agents: map[string]*api.Agent{
+			"my-agent": {Slug: "my-agent", Name: "my-agent"},
+			"old-b":    {Slug: "old-b", Name: "old-b"},
+			"old-a":    {Slug: "old-a", Name: "old-a"},
+		},
+		Schedules: map[string][]api.Schedule{"old-a": {{ID: "s1", Cron: "0 9 * * *"}}},
+	}
+
+	assert.Empty(t, Diff(m, state, false))
+
+	changes := Diff(m, state, true)
+	require.Len(t, changes, 2)
+	assert.Equal(t, Change{Action: ActionDelete, Kind: KindAgent, Agent: "old-a"}, changes[0])
+	assert.Equal(t, Change{Action: ActionDelete, Kind: KindAgent, Agent: "old-b"}, changes[1])
+	assert.Equal(t, "agent old-a", changes[0].String())
+}
+
+func TestFromStateRoundTrips(t *testing.T) {
+	agents := []api.Agent{
+		{Slug: "my-agent", Name: "My Agent", Replicas: ptr(2), Memory: ptr("1Gi"), Owner: ptr("#support")},
+		{Slug: "plain", Name: "plain"},
+	}
+	state := &State{
+		Secrets:   map[string][]string{"my-agent": {"API_KEY"}},
+		Schedules: map[string][]api.Schedule{"my-agent": {{ID: "s1", Cron: "0 9 * * *", Input: map[string]any{"task": "report"}}}},
+	}
+
+	m := FromState(agents, state)
+	require.Len(t, m.Agents, 2)
+	assert.Equal(t, "My Agent", m.Agents[0].Name)
+	assert.Equal(t, "#support", m.Agents[0].Owner)
+	assert.Equal(t, SecretRef{FromEnv: "API_KEY"}, m.Agents[0].Secrets["API_KEY"])
+	assert.Empty(t, m.Agents[1].Name)
+	assert.Nil(t, m.Agents[1].Scale)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, m))
+	assert.Contains(t, buf.String(), "  - slug: plain\n")
+
+	parsed, err := Parse(&buf)
+	require.NoError(t, err)
+
+	// The exported manifest describes the platform exactly
+	state.Agents = map[string]*api.Agent{"my-agent": &agents[0], "plain": &agents[1]}
+	assert.Empty(t, Diff(parsed, state, true))
+}
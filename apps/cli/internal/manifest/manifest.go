@@ -0,0 +1,139 @@
+// Package manifest reads declarative oken.yaml manifests and compares them
+// with the state of the platform.
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+)
+
+// DefaultFile is the manifest read when no file is given
+const DefaultFile = "oken.yaml"
+
+// Manifest is the desired state of a set of agents
+type Manifest struct {
+	Agents []Agent `yaml:"agents"`
+}
+
+// Agent is the desired state of one agent
+type Agent struct {
+	Slug string `yaml:"slug"`
+	Name string `yaml:"name,omitempty"`
+	// Description, Repository and Owner are the agent's metadata, as with
+	// 'oken update'. Fields left out aren't changed.
+	Description string `yaml:"description,omitempty"`
+	Repository  string `yaml:"repository,omitempty"`
+	Owner       string `yaml:"owner,omitempty"`
+	// Path is the directory deployed when the agent doesn't exist yet
+	Path      string               `yaml:"path,omitempty"`
+	Scale     *Scale               `yaml:"scale,omitempty"`
+	Secrets   map[string]SecretRef `yaml:"secrets,omitempty"`
+	Schedules []Schedule           `yaml:"schedules,omitempty"`
+}
+
+// Scale is the desired replicas and resource limits of an agent. Unset
+// fields are left as they are.
+type Scale struct {
+	Replicas *int   `yaml:"replicas,omitempty"`
+	Memory   string `yaml:"memory,omitempty"`
+	CPU      string `yaml:"cpu,omitempty"`
+}
+
+// SecretRef says where a secret's value comes from. Values never appear in
+// the manifest itself.
+type SecretRef struct {
+	// FromEnv names the environment variable holding the value
+	FromEnv string `yaml:"fromEnv"`
+}
+
+// Schedule is a cron schedule that invokes an agent
+type Schedule struct {
+	Cron  string         `yaml:"cron"`
+	Input map[string]any `yaml:"input,omitempty"`
+}
+
+// Load reads and validates a manifest file. Relative agent paths are
+// resolved against the manifest's directory.
+func Load(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	m, err := Parse(f)
+	if err != nil {
+		return nil, err
+	}
+	for i, a := range m.Agents {
+		if a.Path != "" && !filepath.IsAbs(a.Path) {
+			m.Agents[i].Path = filepath.Join(filepath.Dir(path), a.Path)
+		}
+	}
+	return m, nil
+}
+
+// Parse reads and validates a manifest. Unknown fields are rejected so typos
+// don't silently leave state unmanaged.
+func Parse(r io.Reader) (*Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks that agents are uniquely named and their fields are well-formed
+func (m *Manifest) Validate() error {
+	seen := make(map[string]bool, len(m.Agents))
+	for i, a := range m.Agents {
+		if a.Slug == "" {
+			return fmt.Errorf("agents[%d]: slug is required", i)
+		}
+		if seen[a.Slug] {
+			return fmt.Errorf("agent %s: listed more than once", a.Slug)
+		}
+		seen[a.Slug] = true
+
+		metadata := api.AgentMetadata{Description: a.Description, Repository: a.Repository, Owner: a.Owner}
+		if err := metadata.Validate(); err != nil {
+			return fmt.Errorf("agent %s: %w", a.Slug, err)
+		}
+		if a.Scale != nil && a.Scale.Replicas != nil && *a.Scale.Replicas < 1 {
+			return fmt.Errorf("agent %s: replicas must be at least 1", a.Slug)
+		}
+		for name, ref := range a.Secrets {
+			if ref.FromEnv == "" {
+				return fmt.Errorf("agent %s: secret %s: fromEnv is required", a.Slug, name)
+			}
+		}
+		for j, s := range a.Schedules {
+			if s.Cron == "" {
+				return fmt.Errorf("agent %s: schedules[%d]: cron is required", a.Slug, j)
+			}
+		}
+	}
+	return nil
+}
+
+// Write encodes a manifest as YAML
+func Write(w io.Writer, m *Manifest) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return err
+	}
+	return enc.Close()
+}
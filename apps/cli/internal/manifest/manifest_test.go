@@ -0,0 +1,66 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	m, err := Parse(strings.NewReader(`
+agents:
+  - slug: my-agent
+    name: My Agent
+    path: ./agents/my-agent
+    scale:
+      replicas: 2
+      memory: 1Gi
+    secrets:
+      OPENAI_API_KEY:
+        fromEnv: OPENAI_KEY
+    schedules:
+      - cron: "0 9 * * *"
+        input:
+          task: report
+`))
+	require.NoError(t, err)
+	require.Len(t, m.Agents, 1)
+
+	a := m.Agents[0]
+	assert.Equal(t, "my-agent", a.Slug)
+	assert.Equal(t, "./agents/my-agent", a.Path)
+	require.NotNil(t, a.Scale)
+	require.NotNil(t, a.Scale.Replicas)
+	assert.Equal(t, 2, *a.Scale.Replicas)
+	assert.Equal(t, "1Gi", a.Scale.Memory)
+	assert.Equal(t, SecretRef{FromEnv: "OPENAI_KEY"}, a.Secrets["OPENAI_API_KEY"])
+	require.Len(t, a.Schedules, 1)
+	assert.Equal(t, "0 9 * * *", a.Schedules[0].Cron)
+	assert.Equal(t, map[string]any{"task": "report"}, a.Schedules[0].Input)
+}
+
+func TestParseEmpty(t *testing.T) {
+	m, err := Parse(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, m.Agents)
+}
+
+func TestParseRejectsInvalidManifests(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":         "agents:\n  - slug: a\n    replicas: 2\n",
+		"missing slug":          "agents:\n  - name: A\n",
+		"duplicate slug":        "agents:\n  - slug: a\n  - slug: a\n",
+		"zero replicas":         "agents:\n  - slug: a\n    scale:\n      replicas: 0\n",
+		"secret without env":    "agents:\n  - slug: a\n    secrets:\n      KEY: {}\n",
+		"schedule without cron": "agents:\n  - slug: a\n    schedules:\n      - input: {}\n",
+		"invalid repository":    "agents:\n  - slug: a\n    repository: git@github.com:acme/a.git\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(input))
+			assert.Error(t, err)
+		})
+	}
+}
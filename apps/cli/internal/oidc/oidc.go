@@ -0,0 +1,248 @@
+// Package oidc signs in with an OpenID Connect provider, using the
+// authorization code flow with PKCE and a redirect to a localhost callback
+// server.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// callbackPath is where the provider redirects the browser after login
+const callbackPath = "/callback"
+
+// maxResponseSize caps discovery and token responses
+const maxResponseSize = 1 << 20
+
+// httpClient talks to the provider's discovery and token endpoints
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Config identifies the provider and the client registered with it
+type Config struct {
+	Issuer   string
+	ClientID string
+	// Scopes default to openid, email and profile; openid is always requested
+	Scopes []string
+}
+
+// Result is the outcome of a successful login
+type Result struct {
+	IDToken string
+	// Nonce was sent with the request and must match the one in IDToken
+	Nonce string
+}
+
+// endpoints are the parts of the provider's discovery document the flow uses
+type endpoints struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// callback is what the provider's redirect delivered
+type callback struct {
+	code string
+	err  error
+}
+
+// Login runs the flow: it starts a callback server on 127.0.0.1, calls open
+// with the URL to sign in at, and waits for the provider to redirect back
+// until ctx is done. The code it receives is exchanged for an ID token.
+func Login(ctx context.Context, cfg Config, open func(authURL string) error) (*Result, error) {
+	ep, err := discover(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("start callback server: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s%s", listener.Addr(), callbackPath)
+
+	state, nonce, verifier := randomString(), randomString(), randomString()
+	challenge := sha256.Sum256([]byte(verifier))
+
+	authURL, err := url.Parse(ep.AuthorizationEndpoint)
+	if err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", cfg.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", strings.Join(scopes(cfg.Scopes), " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	callbacks := make(chan callback, 1)
+	server := &http.Server{
+		Handler:           callbackHandler(state, callbacks),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	if err := open(authURL.String()); err != nil {
+		return nil, err
+	}
+
+	var cb callback
+	select {
+	case cb = <-callbacks:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for login: %w", ctx.Err())
+	}
+	if cb.err != nil {
+		return nil, cb.err
+	}
+
+	idToken, err := exchange(ctx, ep.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {cb.code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {cfg.ClientID},
+		"code_verifier": {verifier},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Result{IDToken: idToken, Nonce: nonce}, nil
+}
+
+// scopes adds openid to the requested scopes if missing
+func scopes(requested []string) []string {
+	if len(requested) == 0 {
+		return []string{"openid", "email", "profile"}
+	}
+	if slices.Contains(requested, "openid") {
+		return requested
+	}
+	return append([]string{"openid"}, requested...)
+}
+
+// callbackHandler reports the first redirect from the provider with a
+// matching state and tells the user to go back to the terminal
+func callbackHandler(state string, callbacks chan<- callback) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			http.Error(w, "Invalid login state. Start again from the terminal.", http.StatusBadRequest)
+			return
+		}
+
+		var cb callback
+		switch {
+		case query.Get("error") != "":
+			msg := query.Get("error")
+			if desc := query.Get("error_description"); desc != "" {
+				msg = fmt.Sprintf("%s: %s", msg, desc)
+			}
+			cb.err = fmt.Errorf("login failed: %s", msg)
+		case query.Get("code") == "":
+			cb.err = errors.New("login failed: no authorization code in callback")
+		default:
+			cb.code = query.Get("code")
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if cb.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, "<p>Login failed. Check your terminal for details.</p>")
+		} else {
+			_, _ = io.WriteString(w, "<p>Logged in. You can close this tab and return to your terminal.</p>")
+		}
+
+		select {
+		case callbacks <- cb:
+		default:
+			// A callback was already received
+		}
+	})
+	return mux
+}
+
+// discover fetches the provider's endpoints from its discovery document
+func discover(ctx context.Context, issuer string) (*endpoints, error) {
+	docURL := strings.TrimRight(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, docURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("discover %s: %w", issuer, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discover %s: status %d", issuer, resp.StatusCode)
+	}
+
+	var ep endpoints
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&ep); err != nil {
+		return nil, fmt.Errorf("discover %s: %w", issuer, err)
+	}
+	if ep.AuthorizationEndpoint == "" || ep.TokenEndpoint == "" {
+		return nil, fmt.Errorf("discover %s: missing authorization or token endpoint", issuer)
+	}
+	return &ep, nil
+}
+
+// exchange trades an authorization code for an ID token
+func exchange(ctx context.Context, tokenEndpoint string, form url.Values) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("exchange code: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("exchange code: status %d", resp.StatusCode)
+	}
+	if body.Error != "" {
+		if body.ErrorDescription != "" {
+			return "", fmt.Errorf("exchange code: %s: %s", body.Error, body.ErrorDescription)
+		}
+		return "", fmt.Errorf("exchange code: %s", body.Error)
+	}
+	if body.IDToken == "" {
+		return "", fmt.Errorf("exchange code: no ID token in response (status %d)", resp.StatusCode)
+	}
+	return body.IDToken, nil
+}
+
+// randomString returns a random URL-safe value for state, nonce and the PKCE
+// verifier
+func randomString() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
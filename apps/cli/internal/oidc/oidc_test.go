@@ -0,0 +1,132 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider is an OpenID provider that approves every login
+type fakeProvider struct {
+	t         *testing.T
+	server    *httptest.Server
+	challenge string
+	authQuery url.Values
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	p := &fakeProvider{t: t}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		p.authQuery = r.URL.Query()
+		p.challenge = p.authQuery.Get("code_challenge")
+		redirect := p.authQuery.Get("redirect_uri") + "?code=the-code&state=" + url.QueryEscape(p.authQuery.Get("state"))
+		http.Redirect(w, r, redirect, http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "the-code", r.PostForm.Get("code"))
+		assert.Equal(t, "cli", r.PostForm.Get("client_id"))
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		assert.Equal(t, p.challenge, base64.RawURLEncoding.EncodeToString(sum[:]))
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": "the-id-token", "token_type": "Bearer"})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// browse follows the sign-in URL like a browser would
+func browse(authURL string) error {
+	resp, err := http.Get(authURL)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestLogin(t *testing.T) {
+	provider := newFakeProvider(t)
+
+	result, err := Login(testContext(t), Config{Issuer: provider.server.URL, ClientID: "cli", Scopes: []string{"email"}}, browse)
+	require.NoError(t, err)
+	assert.Equal(t, "the-id-token", result.IDToken)
+
+	assert.Equal(t, "code", provider.authQuery.Get("response_type"))
+	assert.Equal(t, "openid email", provider.authQuery.Get("scope"))
+	assert.Equal(t, "S256", provider.authQuery.Get("code_challenge_method"))
+	assert.Equal(t, result.Nonce, provider.authQuery.Get("nonce"))
+	assert.Regexp(t, `^http://127\.0\.0\.1:\d+/callback$`, provider.authQuery.Get("redirect_uri"))
+}
+
+func TestLoginRejectsWrongState(t *testing.T) {
+	provider := newFakeProvider(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var status int
+	_, err := Login(ctx, Config{Issuer: provider.server.URL, ClientID: "cli"}, func(authURL string) error {
+		u, _ := url.Parse(authURL)
+		resp, err := http.Get(u.Query().Get("redirect_uri") + "?code=stolen&state=wrong")
+		if err != nil {
+			return err
+		}
+		status = resp.StatusCode
+		return resp.Body.Close()
+	})
+	assert.Equal(t, http.StatusBadRequest, status)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLoginProviderError(t *testing.T) {
+	provider := newFakeProvider(t)
+
+	_, err := Login(testContext(t), Config{Issuer: provider.server.URL, ClientID: "cli"}, func(authURL string) error {
+		u, _ := url.Parse(authURL)
+		q := u.Query()
+		resp, err := http.Get(q.Get("redirect_uri") + "?error=access_denied&error_description=Not+in+group&state=" + url.QueryEscape(q.Get("state")))
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	})
+	require.Error(t, err)
+	assert.EqualError(t, err, "login failed: access_denied: Not in group")
+}
+
+func TestDiscoverMissingEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"issuer": "x"}`))
+	}))
+	defer server.Close()
+
+	_, err := discover(testContext(t), server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing authorization or token endpoint")
+}
@@ -0,0 +1,260 @@
+// Package outputs finds files in agent invocation outputs and saves them to
+// disk.
+package outputs
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileType marks an output object as a file: {"type": "file", "data": "<base64>",
+// "name": "chart.png", "contentType": "image/png"}. Name and contentType are
+// optional.
+const FileType = "file"
+
+// defaultName is used for files whose name can't be derived from the output
+const defaultName = "output"
+
+// preferredExtensions picks the usual extension for content types that have
+// several
+var preferredExtensions = map[string]string{
+	"audio/mpeg": ".mp3",
+	"image/jpeg": ".jpg",
+	"text/plain": ".txt",
+}
+
+// unsafeChars are replaced in file names derived from output keys
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// File is a file saved from an output
+type File struct {
+	// Key is where the file was in the output, e.g. "charts[0]"
+	Key         string `json:"key"`
+	Path        string `json:"path"`
+	ContentType string `json:"contentType,omitempty"`
+	Size        int    `json:"size"`
+}
+
+// reference replaces a saved file in the output
+func (f File) reference() map[string]any {
+	ref := map[string]any{"type": FileType, "path": f.Path, "size": f.Size}
+	if f.ContentType != "" {
+		ref["contentType"] = f.ContentType
+	}
+	return ref
+}
+
+// Count returns how many files an output holds
+func Count(output map[string]any) int {
+	n := 0
+	walk(output, "", func(key string, value any) any {
+		if _, ok := decode(key, value); ok {
+			n++
+		}
+		return nil
+	})
+	return n
+}
+
+// Save writes the files in an output to dir, creating it if needed, and
+// replaces each in the output with a reference to the saved file. Existing
+// files are never overwritten; a number is added to the name instead.
+func Save(output map[string]any, dir string) ([]File, error) {
+	var saved []File
+	var saveErr error
+	replaced := walk(output, "", func(key string, value any) any {
+		if saveErr != nil {
+			return nil
+		}
+		f, ok := decode(key, value)
+		if !ok {
+			return nil
+		}
+		if f.err != nil {
+			saveErr = fmt.Errorf("%s: %w", key, f.err)
+			return nil
+		}
+		if len(saved) == 0 {
+			if saveErr = os.MkdirAll(dir, 0755); saveErr != nil {
+				return nil
+			}
+		}
+
+		path, err := create(dir, f.name, f.data)
+		if err != nil {
+			saveErr = err
+			return nil
+		}
+		file := File{Key: key, Path: path, ContentType: f.contentType, Size: len(f.data)}
+		saved = append(saved, file)
+		return file.reference()
+	})
+	// The whole output may be one file
+	if ref, ok := replaced.(map[string]any); ok {
+		clear(output)
+		maps.Copy(output, ref)
+	}
+	return saved, saveErr
+}
+
+// walk visits every value in an output in a stable order, replacing a value
+// with what visit returns unless that is nil
+func walk(value any, key string, visit func(key string, value any) any) any {
+	if replaced := visit(key, value); replaced != nil {
+		return replaced
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := k
+			if key != "" {
+				child = key + "." + k
+			}
+			if replaced := walk(v[k], child, visit); replaced != nil {
+				v[k] = replaced
+			}
+		}
+	case []any:
+		for i := range v {
+			if replaced := walk(v[i], fmt.Sprintf("%s[%d]", key, i), visit); replaced != nil {
+				v[i] = replaced
+			}
+		}
+	}
+	return nil
+}
+
+// decodedFile is a file value found in an output
+type decodedFile struct {
+	name        string
+	contentType string
+	data        []byte
+	// err is set when the value is marked as a file but can't be decoded
+	err error
+}
+
+// decode recognizes file objects and base64 data URLs
+func decode(key string, value any) (decodedFile, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		if v["type"] != FileType {
+			return decodedFile{}, false
+		}
+		encoded, ok := v["data"].(string)
+		if !ok {
+			// A reference to a file that was already saved, or a URL
+			return decodedFile{}, false
+		}
+		f := decodedFile{contentType: stringField(v, "contentType")}
+		f.data, f.err = base64.StdEncoding.DecodeString(encoded)
+		f.name = fileName(stringField(v, "name"), key, f.contentType)
+		if f.contentType == "" {
+			f.contentType = mime.TypeByExtension(filepath.Ext(f.name))
+		}
+		return f, true
+	case string:
+		contentType, encoded, ok := parseDataURL(v)
+		if !ok {
+			return decodedFile{}, false
+		}
+		f := decodedFile{contentType: contentType}
+		f.data, f.err = base64.StdEncoding.DecodeString(encoded)
+		f.name = fileName("", key, contentType)
+		return f, true
+	}
+	return decodedFile{}, false
+}
+
+// parseDataURL splits a base64 data URL such as data:image/png;base64,iVBOR...
+func parseDataURL(s string) (contentType, encoded string, ok bool) {
+	rest, found := strings.CutPrefix(s, "data:")
+	if !found {
+		return "", "", false
+	}
+	meta, encoded, found := strings.Cut(rest, ",")
+	if !found {
+		return "", "", false
+	}
+	contentType, found = strings.CutSuffix(meta, ";base64")
+	if !found {
+		return "", "", false
+	}
+	return contentType, encoded, true
+}
+
+// stringField returns a string field of an object, or "" if it isn't one
+func stringField(v map[string]any, name string) string {
+	s, _ := v[name].(string)
+	return s
+}
+
+// fileName picks a safe file name: the given name without any directories,
+// or else one made from the output key with an extension for the content type
+func fileName(name, key, contentType string) string {
+	if name = filepath.Base(filepath.FromSlash(name)); name != "." && name != string(filepath.Separator) && name != ".." {
+		return name
+	}
+
+	name = strings.Trim(unsafeChars.ReplaceAllString(key, "-"), "-.")
+	if name == "" {
+		name = defaultName
+	}
+	return name + extension(contentType)
+}
+
+// extension returns the file extension for a content type, or "" if it is
+// unknown
+func extension(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	if ext, ok := preferredExtensions[mediaType]; ok {
+		return ext
+	}
+	exts, err := mime.ExtensionsByType(mediaType)
+	if err != nil || len(exts) == 0 {
+		return ""
+	}
+	sort.Strings(exts)
+	return exts[0]
+}
+
+// create writes data to a new file in dir, adding -1, -2, ... before the
+// extension if the name is taken, and returns its path
+func create(dir, name string, data []byte) (string, error) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for n := 0; ; n++ {
+		candidate := name
+		if n > 0 {
+			candidate = base + "-" + strconv.Itoa(n) + ext
+		}
+		path := filepath.Join(dir, candidate)
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := file.Write(data); err != nil {
+			_ = file.Close()
+			return "", err
+		}
+		return path, file.Close()
+	}
+}
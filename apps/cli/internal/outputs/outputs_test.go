@@ -0,0 +1,91 @@
+package outputs
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func TestSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	output := map[string]any{
+		"summary": "two charts and a table",
+		"charts": []any{
+			map[string]any{"type": "file", "name": "chart.png", "contentType": "image/png", "data": encode("png-1")},
+			map[string]any{"type": "file", "name": "../chart.png", "data": encode("png-2")},
+		},
+		"table": "data:text/csv;base64," + encode("a,b\n1,2\n"),
+		"link":  map[string]any{"type": "file", "url": "https://example.com/report.pdf"},
+	}
+
+	files, err := Save(output, dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []File{
+		{Key: "charts[0]", Path: filepath.Join(dir, "chart.png"), ContentType: "image/png", Size: 5},
+		{Key: "charts[1]", Path: filepath.Join(dir, "chart-1.png"), ContentType: "image/png", Size: 5},
+		{Key: "table", Path: filepath.Join(dir, "table.csv"), ContentType: "text/csv", Size: 8},
+	}, files)
+
+	data, err := os.ReadFile(filepath.Join(dir, "chart-1.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png-2", string(data))
+
+	assert.Equal(t, "two charts and a table", output["summary"])
+	assert.Equal(t, map[string]any{"type": "file", "path": filepath.Join(dir, "table.csv"), "size": 8, "contentType": "text/csv"}, output["table"])
+	assert.Equal(t, map[string]any{"type": "file", "url": "https://example.com/report.pdf"}, output["link"])
+	assert.Equal(t, 0, Count(output))
+}
+
+func TestSaveKeepsExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "speech.mp3"), []byte("old"), 0644))
+
+	output := map[string]any{"speech": "data:audio/mpeg;base64," + encode("new")}
+	files, err := Save(output, dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, filepath.Join(dir, "speech-1.mp3"), files[0].Path)
+
+	data, err := os.ReadFile(filepath.Join(dir, "speech.mp3"))
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(data))
+}
+
+func TestSaveWholeOutput(t *testing.T) {
+	dir := t.TempDir()
+	output := map[string]any{"type": "file", "contentType": "image/jpeg", "data": encode("jpg")}
+
+	files, err := Save(output, dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, filepath.Join(dir, "output.jpg"), files[0].Path)
+	assert.Equal(t, filepath.Join(dir, "output.jpg"), output["path"])
+	assert.NotContains(t, output, "data")
+}
+
+func TestSaveInvalidData(t *testing.T) {
+	output := map[string]any{"image": map[string]any{"type": "file", "data": "not base64!"}}
+
+	_, err := Save(output, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "image:")
+}
+
+func TestCount(t *testing.T) {
+	output := map[string]any{
+		"text":   "data:not a data url",
+		"image":  "data:image/png;base64," + encode("png"),
+		"nested": map[string]any{"files": []any{map[string]any{"type": "file", "data": encode("x")}}},
+	}
+	assert.Equal(t, 2, Count(output))
+	assert.Equal(t, 0, Count(map[string]any{"answer": 42}))
+}
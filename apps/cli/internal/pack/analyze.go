@@ -0,0 +1,204 @@
+package pack
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// largestFiles is how many of the biggest files an analysis lists
+const largestFiles = 10
+
+// suggestMinSize is the size from which an exclusion is worth suggesting
+const suggestMinSize = 1 << 20
+
+// heavyDirs are directory names that usually hold data rather than code
+var heavyDirs = map[string]string{
+	"data":        "data files",
+	"datasets":    "data files",
+	"models":      "model weights",
+	"checkpoints": "model checkpoints",
+	"logs":        "log files",
+	"coverage":    "test coverage report",
+	"tmp":         "temporary files",
+}
+
+// heavyExts are file extensions that are rarely needed at runtime
+var heavyExts = map[string]string{
+	".pt":          "model weights",
+	".pth":         "model weights",
+	".ckpt":        "model checkpoint",
+	".safetensors": "model weights",
+	".onnx":        "model weights",
+	".h5":          "model weights",
+	".zip":         "archive",
+	".tar":         "archive",
+	".gz":          "archive",
+	".tgz":         "archive",
+	".csv":         "data file",
+	".parquet":     "data file",
+	".sqlite":      "database",
+	".db":          "database",
+	".log":         "log file",
+	".mp4":         "video",
+	".mov":         "video",
+}
+
+// SizeEntry is the size of a file or directory in a package
+type SizeEntry struct {
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	Compressed int64  `json:"compressed"`
+	Files      int    `json:"files"`
+}
+
+// Suggestion is a path that is large and likely safe to exclude
+type Suggestion struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Reason string `json:"reason"`
+}
+
+// Analysis breaks down what a package contains
+type Analysis struct {
+	Entries     []SizeEntry  `json:"entries"`
+	Largest     []SizeEntry  `json:"largest"`
+	Suggestions []Suggestion `json:"suggestions"`
+	Files       int          `json:"files"`
+	Size        int64        `json:"size"`
+	Compressed  int64        `json:"compressed"`
+}
+
+// Analyze reports the uncompressed and compressed size of each top-level file
+// and directory that would be packaged, the largest files, and exclusions
+// worth considering. Compressed sizes are estimated per file.
+func Analyze(dir string, opts Options) (*Analysis, error) {
+	totals := make(map[string]*SizeEntry)
+	heavy := make(map[string]*Suggestion)
+	files := []SizeEntry{}
+	analysis := &Analysis{Entries: []SizeEntry{}, Suggestions: []Suggestion{}}
+
+	err := Walk(dir, opts, func(filePath, relPath string, info os.FileInfo) error {
+		compressed, err := compressedSize(filePath, opts.Compression, opts.Level)
+		if err != nil {
+			return err
+		}
+
+		slashPath := filepath.ToSlash(relPath)
+		top, _, isNested := strings.Cut(slashPath, "/")
+		if isNested {
+			top += "/"
+		}
+
+		entry, ok := totals[top]
+		if !ok {
+			entry = &SizeEntry{Path: top}
+			totals[top] = entry
+		}
+		entry.Size += info.Size()
+		entry.Compressed += compressed
+		entry.Files++
+
+		files = append(files, SizeEntry{Path: slashPath, Size: info.Size(), Compressed: compressed, Files: 1})
+		addHeavy(heavy, slashPath, info.Size())
+
+		analysis.Size += info.Size()
+		analysis.Compressed += compressed
+		analysis.Files++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range totals {
+		analysis.Entries = append(analysis.Entries, *entry)
+	}
+	sortBySize(analysis.Entries)
+
+	sortBySize(files)
+	analysis.Largest = files[:min(len(files), largestFiles)]
+
+	for p, s := range heavy {
+		if s.Size < suggestMinSize || coveredBy(heavy, p) {
+			continue
+		}
+		analysis.Suggestions = append(analysis.Suggestions, *s)
+	}
+	sort.Slice(analysis.Suggestions, func(i, j int) bool {
+		return analysis.Suggestions[i].Size > analysis.Suggestions[j].Size
+	})
+
+	return analysis, nil
+}
+
+// addHeavy adds a file's size to every heavy directory above it, and to the
+// file itself if its extension is rarely needed at runtime
+func addHeavy(heavy map[string]*Suggestion, slashPath string, size int64) {
+	dirs := strings.Split(slashPath, "/")
+	for i := range len(dirs) - 1 {
+		if reason, ok := heavyDirs[strings.ToLower(dirs[i])]; ok {
+			p := strings.Join(dirs[:i+1], "/") + "/"
+			if heavy[p] == nil {
+				heavy[p] = &Suggestion{Path: p, Reason: reason}
+			}
+			heavy[p].Size += size
+		}
+	}
+	if reason, ok := heavyExts[strings.ToLower(path.Ext(slashPath))]; ok {
+		heavy[slashPath] = &Suggestion{Path: slashPath, Size: size, Reason: reason}
+	}
+}
+
+// coveredBy reports whether a directory above p is already suggested
+func coveredBy(heavy map[string]*Suggestion, p string) bool {
+	for dir, s := range heavy {
+		if dir != p && strings.HasSuffix(dir, "/") && strings.HasPrefix(p, dir) && s.Size >= suggestMinSize {
+			return true
+		}
+	}
+	return false
+}
+
+func sortBySize(entries []SizeEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Size != entries[j].Size {
+			return entries[i].Size > entries[j].Size
+		}
+		return entries[i].Path < entries[j].Path
+	})
+}
+
+// compressedSize returns the size of a file compressed with the given codec
+// and level
+func compressedSize(name string, compression Compression, level int) (int64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var counter countingWriter
+	gw, err := newCompressor(&counter, compression, level)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(gw, f); err != nil {
+		return 0, err
+	}
+	if err := gw.Close(); err != nil {
+		return 0, err
+	}
+	return int64(counter), nil
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter int64
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	*c += countingWriter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,67 @@
+package pack
+
+import (
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"main.py":         "12345",
+		"data/a.csv":      strings.Repeat("x", 100),
+		"data/nested/b":   strings.Repeat("x", 50),
+		"src/app.py":      strings.Repeat("x", 20),
+		"__pycache__/a.c": strings.Repeat("x", 1000),
+	})
+
+	analysis, err := Analyze(tmpDir, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, analysis.Files)
+	assert.Equal(t, int64(175), analysis.Size)
+
+	require.Len(t, analysis.Entries, 3)
+	assert.Equal(t, "data/", analysis.Entries[0].Path)
+	assert.Equal(t, int64(150), analysis.Entries[0].Size)
+	assert.Equal(t, 2, analysis.Entries[0].Files)
+	assert.Positive(t, analysis.Entries[0].Compressed)
+	assert.Equal(t, "src/", analysis.Entries[1].Path)
+	assert.Equal(t, "main.py", analysis.Entries[2].Path)
+
+	require.Len(t, analysis.Largest, 4)
+	assert.Equal(t, "data/a.csv", analysis.Largest[0].Path)
+
+	// Nothing is large enough to suggest
+	assert.Empty(t, analysis.Suggestions)
+}
+
+func TestAnalyzeSuggestions(t *testing.T) {
+	big := make([]byte, 2<<20)
+	_, _ = rand.Read(big)
+
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"main.py":             "print('hi')",
+		"data/raw/dump.csv":   string(big),
+		"weights/model.pt":    string(big),
+		"src/small.csv":       "a,b",
+		"assets/logo-big.png": string(big),
+	})
+
+	analysis, err := Analyze(tmpDir, Options{})
+	require.NoError(t, err)
+
+	var paths []string
+	for _, s := range analysis.Suggestions {
+		paths = append(paths, s.Path)
+	}
+	assert.ElementsMatch(t, []string{"data/", "weights/model.pt"}, paths)
+
+	// Random data doesn't compress
+	assert.Greater(t, analysis.Compressed, int64(6<<20))
+}
@@ -0,0 +1,213 @@
+package pack
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignorePattern is one line of a .gitignore or .dockerignore file
+type ignorePattern struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// Ignore matches paths against the patterns of an ignore file. Later patterns
+// take precedence and a leading "!" re-includes a path.
+type Ignore struct {
+	patterns []ignorePattern
+}
+
+// ParseIgnore parses ignore file patterns. When anchored is true every pattern
+// is relative to the root, as in .dockerignore; otherwise patterns without a
+// slash match at any depth, as in .gitignore.
+func ParseIgnore(r io.Reader, anchored bool) (*Ignore, error) {
+	ig := &Ignore{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+
+		// A slash anywhere but the end anchors the pattern to the root
+		rooted := anchored || strings.Contains(line, "/")
+		line = strings.TrimPrefix(path.Clean("/"+line), "/")
+		if line == "" {
+			continue
+		}
+		p.segments = strings.Split(line, "/")
+		if !rooted {
+			p.segments = append([]string{"**"}, p.segments...)
+		}
+
+		ig.patterns = append(ig.patterns, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ig, nil
+}
+
+// loadIgnore parses the ignore file at name, returning nil if it doesn't exist
+func loadIgnore(name string, anchored bool) (*Ignore, error) {
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return ParseIgnore(f, anchored)
+}
+
+// Match reports whether relPath is ignored. relPath uses the OS separator and
+// is relative to the directory holding the ignore file.
+func (ig *Ignore) Match(relPath string, isDir bool) bool {
+	ignored, _ := ig.match(relPath, isDir)
+	return ignored
+}
+
+// match is like Match but also reports whether any pattern applied, so a
+// deeper ignore file can override a decision made higher up
+func (ig *Ignore) match(relPath string, isDir bool) (ignored, matched bool) {
+	if ig == nil {
+		return false, false
+	}
+	segments := strings.Split(filepath.ToSlash(relPath), "/")
+
+	for _, p := range ig.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if matchSegments(p.segments, segments) {
+			ignored, matched = !p.negate, true
+		}
+	}
+	return ignored, matched
+}
+
+// gitignoreScope is a .gitignore file and the directory it applies to
+type gitignoreScope struct {
+	dir    string
+	ignore *Ignore
+}
+
+// gitignoreChain holds the .gitignore files that apply during a walk, from
+// the repository root down to the directory being visited
+type gitignoreChain struct {
+	scopes []gitignoreScope
+}
+
+// newGitignoreChain loads the .gitignore files of dir's ancestors up to the
+// enclosing git repository root, so a project inside a monorepo honors the
+// rules of its parents
+func newGitignoreChain(dir string) (*gitignoreChain, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the enclosing repository root
+	root := ""
+	for d := abs; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			root = d
+			break
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	chain := &gitignoreChain{}
+	if root == "" || root == abs {
+		return chain, nil
+	}
+
+	// Collect parents up to the root; dir itself is loaded by the walk
+	var parents []string
+	for d := filepath.Dir(abs); ; d = filepath.Dir(d) {
+		parents = append(parents, d)
+		if d == root {
+			break
+		}
+	}
+	for i := len(parents) - 1; i >= 0; i-- {
+		if err := chain.load(parents[i]); err != nil {
+			return nil, err
+		}
+	}
+	return chain, nil
+}
+
+// load adds the .gitignore file in dir, if there is one
+func (c *gitignoreChain) load(dir string) error {
+	ig, err := loadIgnore(filepath.Join(dir, ".gitignore"), false)
+	if err != nil || ig == nil {
+		return err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	c.scopes = append(c.scopes, gitignoreScope{dir: abs, ignore: ig})
+	return nil
+}
+
+// Match reports whether the absolute path is ignored by the chain
+func (c *gitignoreChain) Match(path string, isDir bool) bool {
+	ignored := false
+	for _, s := range c.scopes {
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil || !filepath.IsLocal(rel) {
+			continue
+		}
+		if ig, matched := s.ignore.match(rel, isDir); matched {
+			ignored = ig
+		}
+	}
+	return ignored
+}
+
+// matchSegments matches path segments against pattern segments, where "**"
+// matches any number of segments
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			pattern = pattern[1:]
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range segments {
+				if matchSegments(pattern, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
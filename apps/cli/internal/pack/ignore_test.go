@@ -0,0 +1,128 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreMatch(t *testing.T) {
+	patterns := `
+# build output
+*.log
+build/
+/data
+docs/**/*.md
+!keep.log
+`
+
+	tests := []struct {
+		name     string
+		anchored bool
+		path     string
+		isDir    bool
+		want     bool
+	}{
+		{"glob at root", false, "debug.log", false, true},
+		{"glob nested", false, "sub/debug.log", false, true},
+		{"negation", false, "keep.log", false, false},
+		{"dir only matches dir", false, "build", true, true},
+		{"dir only skips file", false, "build", false, false},
+		{"dir only nested", false, "src/build", true, true},
+		{"leading slash anchors", false, "data", true, true},
+		{"leading slash not nested", false, "src/data", true, false},
+		{"double star", false, "docs/a/b/readme.md", false, true},
+		{"double star zero dirs", false, "docs/readme.md", false, true},
+		{"no match", false, "main.py", false, false},
+		{"anchored glob at root", true, "debug.log", false, true},
+		{"anchored glob not nested", true, "sub/debug.log", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ig, err := ParseIgnore(strings.NewReader(patterns), tt.anchored)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ig.Match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestIgnoreNil(t *testing.T) {
+	var ig *Ignore
+	assert.False(t, ig.Match("main.py", false))
+}
+
+func walkPaths(t *testing.T, dir string, opts Options) []string {
+	t.Helper()
+	var paths []string
+	err := Walk(dir, opts, func(path, relPath string, info os.FileInfo) error {
+		paths = append(paths, filepath.ToSlash(relPath))
+		return nil
+	})
+	require.NoError(t, err)
+	return paths
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestWalkRespectsGitignore(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		".gitignore":          "*.log\ndist/\n",
+		"main.py":             "main",
+		"debug.log":           "log",
+		"dist/bundle.js":      "js",
+		"src/.gitignore":      "fixtures.json\n!important.log\n",
+		"src/app.py":          "app",
+		"src/fixtures.json":   "{}",
+		"src/important.log":   "keep",
+		"other/fixtures.json": "{}",
+	})
+
+	assert.ElementsMatch(t, []string{"main.py", "src/app.py", "src/important.log", "other/fixtures.json"}, walkPaths(t, tmpDir, Options{}))
+}
+
+func TestWalkGitignoreDisabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		".gitignore": "*.log\n",
+		"main.py":    "main",
+		"debug.log":  "log",
+	})
+
+	assert.ElementsMatch(t, []string{"main.py", "debug.log"}, walkPaths(t, tmpDir, Options{NoGitignore: true}))
+}
+
+func TestWalkRespectsParentGitignore(t *testing.T) {
+	repo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0755))
+	writeFiles(t, repo, map[string]string{
+		".gitignore":               "*.csv\n/agents/my-agent/out/\n",
+		"agents/my-agent/main.py":  "main",
+		"agents/my-agent/data.csv": "1,2",
+		"agents/my-agent/out/x":    "x",
+	})
+
+	assert.Equal(t, []string{"main.py"}, walkPaths(t, filepath.Join(repo, "agents", "my-agent"), Options{}))
+}
+
+func TestWalkIgnoresParentGitignoreOutsideRepository(t *testing.T) {
+	parent := t.TempDir()
+	writeFiles(t, parent, map[string]string{
+		".gitignore":      "*.py\n",
+		"project/main.py": "main",
+	})
+
+	assert.Equal(t, []string{"main.py"}, walkPaths(t, filepath.Join(parent, "project"), Options{}))
+}
@@ -2,13 +2,19 @@ package pack
 
 import (
 	"archive/tar"
+	"bufio"
 	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 var excludeDirs = map[string]bool{
@@ -20,19 +26,155 @@ var excludeDirs = map[string]bool{
 	".pytest_cache": true,
 }
 
+// keepHidden lists dotfiles that are packaged despite the hidden file rule,
+// such as runtime version pins
+var keepHidden = map[string]bool{
+	".python-version": true,
+	".nvmrc":          true,
+	".node-version":   true,
+}
+
 var excludeFiles = map[string]bool{
 	".env":       true,
 	".env.local": true,
 	".DS_Store":  true,
 }
 
-// CreateTarball creates a gzipped tar archive of the given directory
-func CreateTarball(dir string) (io.Reader, error) {
-	var buf bytes.Buffer
-	gw := gzip.NewWriter(&buf)
-	tw := tar.NewWriter(gw)
+// alwaysExclude is skipped even when .dockerignore replaces the default rules,
+// so repository metadata and local secrets never leave the machine
+var alwaysExclude = map[string]bool{
+	".git":       true,
+	".env":       true,
+	".env.local": true,
+}
+
+// Options controls which files are packaged
+type Options struct {
+	// Docker packages a Docker build context: the default exclusion rules
+	// are replaced by the project's .dockerignore
+	Docker bool
+	// NoGitignore disables .gitignore handling, which otherwise excludes
+	// files ignored by the project's .gitignore files and those of its
+	// parents within the same repository
+	NoGitignore bool
+	// IncludeHidden packages dotfiles, which are skipped by default
+	IncludeHidden bool
+	// Include lists patterns, relative to the project root, of dotfiles to
+	// package despite the hidden file rule
+	Include []string
+	// Exclude lists patterns, relative to the project root, of files and
+	// directories to leave out
+	Exclude []string
+	// Compression is the codec the package is compressed with; empty
+	// means Gzip
+	Compression Compression
+	// Level is the compression level, from 1 (fastest) to 9 (smallest).
+	// Zero uses the codec's default level.
+	Level int
+}
+
+// Compression is a codec packages can be compressed with
+type Compression string
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+const (
+	Gzip Compression = "gzip"
+	// Zstd packages and uploads large agents faster than gzip, at a
+	// similar size
+	Zstd Compression = "zstd"
+)
+
+// Compressions are the supported codecs
+var Compressions = []Compression{Gzip, Zstd}
+
+// ParseCompression parses a codec name, as listed in Compressions
+func ParseCompression(s string) (Compression, error) {
+	if c := Compression(s); slices.Contains(Compressions, c) {
+		return c, nil
+	}
+	return "", fmt.Errorf("invalid compression %q: must be gzip or zstd", s)
+}
+
+// Extension returns the file extension of a package compressed with c
+func (c Compression) Extension() string {
+	if c == Zstd {
+		return ".tar.zst"
+	}
+	return ".tar.gz"
+}
+
+// zstdMagic starts every zstd frame
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// newCompressor returns a writer compressing to w with the given codec and
+// level, as described in Options
+func newCompressor(w io.Writer, c Compression, level int) (io.WriteCloser, error) {
+	if level < 0 || level > 9 {
+		return nil, fmt.Errorf("invalid compression level %d: must be 1 to 9", level)
+	}
+	switch c {
+	case "", Gzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
+	case Zstd:
+		zstdLevel := zstd.SpeedDefault
+		if level != 0 {
+			zstdLevel = zstd.EncoderLevelFromZstd(level)
+		}
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstdLevel))
+	}
+	return nil, fmt.Errorf("invalid compression %q: must be gzip or zstd", c)
+}
+
+// newDecompressor returns a reader decompressing r, detecting gzip or zstd
+// from the first bytes
+func newDecompressor(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(zstdMagic)); bytes.Equal(magic, zstdMagic) {
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	}
+	return gzip.NewReader(br)
+}
+
+// Walk calls fn for every regular file under dir that would be packaged,
+// applying the exclusion rules. relPath is relative to dir.
+func Walk(dir string, opts Options, fn func(path, relPath string, info os.FileInfo) error) error {
+	var dockerIgnore *Ignore
+	if opts.Docker {
+		var err error
+		if dockerIgnore, err = loadIgnore(filepath.Join(dir, ".dockerignore"), true); err != nil {
+			return err
+		}
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	include, err := ParseIgnore(strings.NewReader(strings.Join(opts.Include, "\n")), true)
+	if err != nil {
+		return err
+	}
+
+	exclude, err := ParseIgnore(strings.NewReader(strings.Join(opts.Exclude, "\n")), true)
+	if err != nil {
+		return err
+	}
+
+	var gitignore *gitignoreChain
+	if !opts.Docker && !opts.NoGitignore {
+		if gitignore, err = newGitignoreChain(absDir); err != nil {
+			return err
+		}
+	}
+
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -50,15 +192,51 @@ func CreateTarball(dir string) (io.Reader, error) {
 
 		// Skip root directory
 		if relPath == "." {
+			if gitignore != nil {
+				return gitignore.load(absDir)
+			}
+			return nil
+		}
+
+		// Skip symlinks
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+
+		if exclude.Match(relPath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		// Check exclusions
 		baseName := filepath.Base(path)
+		if opts.Docker {
+			// The Dockerfile is always part of the build context
+			excluded := alwaysExclude[baseName] || (relPath != "Dockerfile" && dockerIgnore.Match(relPath, info.IsDir()))
+			if info.IsDir() {
+				if excluded {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if excluded {
+				return nil
+			}
+			return fn(path, relPath, info)
+		}
+
+		// Check exclusions
 		if info.IsDir() {
 			if excludeDirs[baseName] {
 				return filepath.SkipDir
 			}
+			if gitignore != nil {
+				if gitignore.Match(filepath.Join(absDir, relPath), true) {
+					return filepath.SkipDir
+				}
+				return gitignore.load(filepath.Join(absDir, relPath))
+			}
 			return nil
 		}
 
@@ -67,15 +245,47 @@ func CreateTarball(dir string) (io.Reader, error) {
 		}
 
 		// Skip hidden files (except specific ones we might want)
-		if strings.HasPrefix(baseName, ".") && baseName != ".python-version" {
+		if strings.HasPrefix(baseName, ".") && !keepHidden[baseName] && !opts.IncludeHidden && !include.Match(relPath, false) {
 			return nil
 		}
 
-		// Skip symlinks
-		if info.Mode()&os.ModeSymlink != 0 {
+		if gitignore != nil && gitignore.Match(filepath.Join(absDir, relPath), false) {
 			return nil
 		}
 
+		return fn(path, relPath, info)
+	})
+}
+
+// Tarball is a packaged project. Reading it yields the compressed archive.
+type Tarball struct {
+	io.Reader
+	// Compression is the codec the archive is compressed with
+	Compression Compression
+	// SHA256 is the hex-encoded digest of the archive
+	SHA256 string
+	// Size is the archive size in bytes
+	Size int64
+}
+
+// CreateTarball creates a tar archive of the given directory, compressed as
+// set in opts
+func CreateTarball(dir string, opts Options) (*Tarball, error) {
+	compression := opts.Compression
+	if compression == "" {
+		compression = Gzip
+	}
+
+	// Hash the archive while it is written
+	var buf bytes.Buffer
+	hash := sha256.New()
+	gw, err := newCompressor(io.MultiWriter(&buf, hash), compression, opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	tw := tar.NewWriter(gw)
+
+	err = Walk(dir, opts, func(path, relPath string, info os.FileInfo) error {
 		// Create tar header
 		header, err := tar.FileInfoHeader(info, "")
 		if err != nil {
@@ -109,5 +319,194 @@ func CreateTarball(dir string) (io.Reader, error) {
 		return nil, err
 	}
 
+	return &Tarball{
+		Reader:      &buf,
+		Compression: compression,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		Size:        int64(buf.Len()),
+	}, nil
+}
+
+// LoadTarball reads a gzip or zstd compressed tar archive built elsewhere,
+// such as by a CI pipeline, checking that it is readable and that no entry
+// escapes the archive root
+func LoadTarball(r io.Reader) (*Tarball, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	gr, err := newDecompressor(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("not a gzip or zstd compressed tar archive: %w", err)
+	}
+	defer func() { _ = gr.Close() }()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive: %w", err)
+		}
+		if !filepath.IsLocal(filepath.FromSlash(header.Name)) {
+			return nil, fmt.Errorf("path escapes root directory: %s", header.Name)
+		}
+	}
+
+	compression := Gzip
+	if bytes.HasPrefix(data, zstdMagic) {
+		compression = Zstd
+	}
+	sum := sha256.Sum256(data)
+	return &Tarball{
+		Reader:      bytes.NewReader(data),
+		Compression: compression,
+		SHA256:      hex.EncodeToString(sum[:]),
+		Size:        int64(len(data)),
+	}, nil
+}
+
+// ArchivePath creates a gzipped tar archive of a single file or directory,
+// stored under name. Unlike CreateTarball, no exclusion rules are applied;
+// symlinks and other special files are skipped.
+func ArchivePath(src, name string) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(name, relPath))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, file)
+		_ = file.Close()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
 	return &buf, nil
 }
+
+// ExtractTarball extracts a gzip or zstd compressed tar archive into dir,
+// creating it if needed.
+// Entries that would escape dir, and anything other than regular files and
+// directories, are rejected.
+func ExtractTarball(r io.Reader, dir string) error {
+	gr, err := newDecompressor(r)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = gr.Close() }()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("path escapes root directory: %s", header.Name)
+		}
+		target := filepath.Join(dir, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, header.FileInfo().Mode().Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, tr)
+			_ = file.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported entry type in archive: %s", header.Name)
+		}
+	}
+}
+
+// ReadTarball reads the regular files of a gzip or zstd compressed tar
+// archive into memory, keyed by their slash-separated path
+func ReadTarball(r io.Reader) (map[string][]byte, error) {
+	gr, err := newDecompressor(r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = gr.Close() }()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.ToSlash(header.Name)] = data
+	}
+}
@@ -2,10 +2,14 @@ package pack
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,7 +50,7 @@ func TestCreateTarballBasic(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.py"), []byte("print('hello')"), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "requirements.txt"), []byte("requests"), 0644))
 
-	reader, err := CreateTarball(tmpDir)
+	reader, err := CreateTarball(tmpDir, Options{})
 	require.NoError(t, err)
 
 	files := extractTarball(t, reader)
@@ -71,7 +75,7 @@ func TestCreateTarballExcludesDirectories(t *testing.T) {
 	// Create an included file
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.py"), []byte("included"), 0644))
 
-	reader, err := CreateTarball(tmpDir)
+	reader, err := CreateTarball(tmpDir, Options{})
 	require.NoError(t, err)
 
 	files := extractTarball(t, reader)
@@ -99,7 +103,7 @@ func TestCreateTarballExcludesFiles(t *testing.T) {
 	// Create an included file
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.py"), []byte("included"), 0644))
 
-	reader, err := CreateTarball(tmpDir)
+	reader, err := CreateTarball(tmpDir, Options{})
 	require.NoError(t, err)
 
 	files := extractTarball(t, reader)
@@ -118,7 +122,7 @@ func TestCreateTarballIncludesPythonVersion(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".python-version"), []byte("3.11"), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.py"), []byte("code"), 0644))
 
-	reader, err := CreateTarball(tmpDir)
+	reader, err := CreateTarball(tmpDir, Options{})
 	require.NoError(t, err)
 
 	files := extractTarball(t, reader)
@@ -141,7 +145,7 @@ func TestCreateTarballExcludesHiddenFiles(t *testing.T) {
 	// Regular file
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.py"), []byte("code"), 0644))
 
-	reader, err := CreateTarball(tmpDir)
+	reader, err := CreateTarball(tmpDir, Options{})
 	require.NoError(t, err)
 
 	files := extractTarball(t, reader)
@@ -165,7 +169,7 @@ func TestCreateTarballSkipsSymlinks(t *testing.T) {
 		t.Skip("symlinks not supported on this platform")
 	}
 
-	reader, err := CreateTarball(tmpDir)
+	reader, err := CreateTarball(tmpDir, Options{})
 	require.NoError(t, err)
 
 	files := extractTarball(t, reader)
@@ -184,7 +188,7 @@ func TestCreateTarballWithSubdirectories(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "src", "app.py"), []byte("app"), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "src", "utils", "helper.py"), []byte("helper"), 0644))
 
-	reader, err := CreateTarball(tmpDir)
+	reader, err := CreateTarball(tmpDir, Options{})
 	require.NoError(t, err)
 
 	files := extractTarball(t, reader)
@@ -198,7 +202,7 @@ func TestCreateTarballEmptyDirectory(t *testing.T) {
 	require.NoError(t, err)
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
-	reader, err := CreateTarball(tmpDir)
+	reader, err := CreateTarball(tmpDir, Options{})
 	require.NoError(t, err)
 
 	files := extractTarball(t, reader)
@@ -206,6 +210,313 @@ func TestCreateTarballEmptyDirectory(t *testing.T) {
 }
 
 func TestCreateTarballNonExistentDirectory(t *testing.T) {
-	_, err := CreateTarball("/nonexistent/path")
+	_, err := CreateTarball("/nonexistent/path", Options{})
 	assert.Error(t, err)
 }
+
+func TestExtractTarballRoundTrip(t *testing.T) {
+	srcDir, err := os.MkdirTemp("", "pack-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(srcDir) }()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "main.py"), []byte("main"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "src", "app.py"), []byte("app"), 0644))
+
+	reader, err := CreateTarball(srcDir, Options{})
+	require.NoError(t, err)
+
+	dstDir, err := os.MkdirTemp("", "pack-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dstDir) }()
+
+	target := filepath.Join(dstDir, "out")
+	require.NoError(t, ExtractTarball(reader, target))
+
+	data, err := os.ReadFile(filepath.Join(target, "main.py"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("main"), data)
+
+	data, err = os.ReadFile(filepath.Join(target, "src", "app.py"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("app"), data)
+}
+
+func TestExtractTarballRejectsPathTraversal(t *testing.T) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil.txt", Mode: 0644, Size: 4, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("evil"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	dstDir, err := os.MkdirTemp("", "pack-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dstDir) }()
+
+	err = ExtractTarball(&buf, filepath.Join(dstDir, "out"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "escapes")
+
+	_, err = os.Stat(filepath.Join(dstDir, "evil.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWalkAppliesExclusions(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pack-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "__pycache__"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "__pycache__", "main.pyc"), []byte("x"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".env"), []byte("SECRET=1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.py"), []byte("main"), 0644))
+
+	var paths []string
+	err = Walk(tmpDir, Options{}, func(path, relPath string, info os.FileInfo) error {
+		paths = append(paths, relPath)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.py"}, paths)
+}
+
+func TestReadTarball(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pack-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.py"), []byte("main"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "src", "app.py"), []byte("app"), 0644))
+
+	reader, err := CreateTarball(tmpDir, Options{})
+	require.NoError(t, err)
+
+	files, err := ReadTarball(reader)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, []byte("main"), files["main.py"])
+	assert.Equal(t, []byte("app"), files["src/app.py"])
+}
+
+func TestWalkKeepsRuntimeVersionFiles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pack-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	for _, name := range []string{".nvmrc", ".node-version", ".python-version", ".npmrc", "package.json"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte("x"), 0644))
+	}
+
+	var paths []string
+	err = Walk(tmpDir, Options{}, func(path, relPath string, info os.FileInfo) error {
+		paths = append(paths, relPath)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{".nvmrc", ".node-version", ".python-version", "package.json"}, paths)
+}
+
+func TestWalkDockerUsesDockerignore(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pack-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".git", "HEAD"), []byte("ref"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "node_modules", "dep"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "node_modules", "dep", "index.js"), []byte("x"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "data"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "data", "dump.csv"), []byte("x"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".env"), []byte("SECRET=1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".streamlit"), []byte("x"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Dockerfile"), []byte("FROM python"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".dockerignore"), []byte("data/\nDockerfile\n"), 0644))
+
+	var paths []string
+	err = Walk(tmpDir, Options{Docker: true}, func(path, relPath string, info os.FileInfo) error {
+		paths = append(paths, filepath.ToSlash(relPath))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{".dockerignore", ".streamlit", "Dockerfile", "node_modules/dep/index.js"}, paths)
+}
+
+func TestWalkIncludeHidden(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"main.py":            "main",
+		".streamlit/.config": "x",
+		"data/.keep":         "",
+		".npmrc":             "token",
+		".env":               "SECRET=1",
+		".git/HEAD":          "ref",
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		paths := walkPaths(t, tmpDir, Options{Include: []string{".streamlit/**", "**/.keep"}})
+		assert.ElementsMatch(t, []string{"main.py", ".streamlit/.config", "data/.keep"}, paths)
+	})
+
+	t.Run("all hidden files", func(t *testing.T) {
+		paths := walkPaths(t, tmpDir, Options{IncludeHidden: true})
+		assert.ElementsMatch(t, []string{"main.py", ".streamlit/.config", "data/.keep", ".npmrc"}, paths)
+	})
+}
+
+func TestCreateTarballLevel(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{"main.py": strings.Repeat("print('hello')\n", 1000)})
+
+	fast, err := CreateTarball(tmpDir, Options{Level: gzip.BestSpeed})
+	require.NoError(t, err)
+	files, err := ReadTarball(fast)
+	require.NoError(t, err)
+	assert.Len(t, files["main.py"], 15000)
+
+	_, err = CreateTarball(tmpDir, Options{Level: 42})
+	assert.Error(t, err)
+}
+
+func TestCreateTarballZstd(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{"main.py": strings.Repeat("print('hello')\n", 1000)})
+
+	tarball, err := CreateTarball(tmpDir, Options{Compression: Zstd, Level: 3})
+	require.NoError(t, err)
+	assert.Equal(t, Zstd, tarball.Compression)
+
+	data, err := io.ReadAll(tarball)
+	require.NoError(t, err)
+	assert.Equal(t, zstdMagic, data[:4])
+
+	loaded, err := LoadTarball(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, Zstd, loaded.Compression)
+	files, err := ReadTarball(loaded)
+	require.NoError(t, err)
+	assert.Len(t, files["main.py"], 15000)
+
+	dest := t.TempDir()
+	require.NoError(t, ExtractTarball(bytes.NewReader(data), dest))
+	assert.FileExists(t, filepath.Join(dest, "main.py"))
+
+	_, err = CreateTarball(tmpDir, Options{Compression: "brotli"})
+	assert.Error(t, err)
+}
+
+func TestParseCompression(t *testing.T) {
+	c, err := ParseCompression("zstd")
+	require.NoError(t, err)
+	assert.Equal(t, Zstd, c)
+	assert.Equal(t, ".tar.zst", c.Extension())
+	assert.Equal(t, ".tar.gz", Gzip.Extension())
+
+	_, err = ParseCompression("xz")
+	assert.Error(t, err)
+}
+
+func TestCreateTarballChecksum(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{"main.py": "print('hello')"})
+
+	tarball, err := CreateTarball(tmpDir, Options{})
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(tarball)
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+	assert.Equal(t, hex.EncodeToString(sum[:]), tarball.SHA256)
+	assert.Equal(t, int64(len(data)), tarball.Size)
+}
+
+func TestLoadTarball(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{"main.py": "print('hello')"})
+
+	created, err := CreateTarball(tmpDir, Options{})
+	require.NoError(t, err)
+	data, err := io.ReadAll(created)
+	require.NoError(t, err)
+
+	tarball, err := LoadTarball(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, created.SHA256, tarball.SHA256)
+	assert.Equal(t, created.Size, tarball.Size)
+	assert.Equal(t, Gzip, tarball.Compression)
+
+	files, err := ReadTarball(tarball)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("print('hello')"), files["main.py"])
+}
+
+func TestLoadTarballRejectsInvalidArchives(t *testing.T) {
+	_, err := LoadTarball(strings.NewReader("not an archive"))
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil.txt", Mode: 0644, Typeflag: tar.TypeReg}))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	_, err = LoadTarball(&buf)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "escapes")
+}
+
+func TestWalkExclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"main.py":        "main",
+		"agent.tar.gz":   "old",
+		"fixtures/a.csv": "1,2",
+		"src/fixtures":   "keep",
+	})
+
+	paths := walkPaths(t, tmpDir, Options{Exclude: []string{"agent.tar.gz", "fixtures/"}})
+	assert.ElementsMatch(t, []string{"main.py", "src/fixtures"}, paths)
+}
+
+func TestArchivePathFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pack-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	src := filepath.Join(tmpDir, "output.json")
+	require.NoError(t, os.WriteFile(src, []byte(`{"ok":true}`), 0644))
+
+	buf, err := ArchivePath(src, "result.json")
+	require.NoError(t, err)
+
+	files := extractTarball(t, buf)
+	assert.Equal(t, map[string][]byte{"result.json": []byte(`{"ok":true}`)}, files)
+}
+
+func TestArchivePathDirectoryKeepsHiddenFiles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "pack-test")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "fixtures", "nested"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "fixtures", ".env"), []byte("A=1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "fixtures", "nested", "a.txt"), []byte("a"), 0644))
+
+	buf, err := ArchivePath(filepath.Join(tmpDir, "fixtures"), "fixtures")
+	require.NoError(t, err)
+
+	dstDir := filepath.Join(tmpDir, "out")
+	require.NoError(t, ExtractTarball(buf, dstDir))
+
+	data, err := os.ReadFile(filepath.Join(dstDir, "fixtures", ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("A=1"), data)
+
+	data, err = os.ReadFile(filepath.Join(dstDir, "fixtures", "nested", "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("a"), data)
+}
@@ -0,0 +1,61 @@
+package pack
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+)
+
+// maxScanSize skips files too large to plausibly hold hand-written credentials
+const maxScanSize = 1 << 20
+
+// secretRule is a pattern for a kind of credential
+type secretRule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+var secretRules = []secretRule{
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`)},
+	{"API secret key", regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`)},
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36}\b`)},
+}
+
+// SecretFinding is a likely credential found in a packaged file
+type SecretFinding struct {
+	Path string
+	Line int
+	Rule string
+}
+
+// ScanSecrets scans the files that would be packaged for likely credentials.
+// Binary files and files over 1 MiB are skipped.
+func ScanSecrets(dir string, opts Options) ([]SecretFinding, error) {
+	var findings []SecretFinding
+	err := Walk(dir, opts, func(path, relPath string, info os.FileInfo) error {
+		if info.Size() > maxScanSize {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(data, 0) >= 0 {
+			return nil
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), maxScanSize)
+		for line := 1; scanner.Scan(); line++ {
+			for _, rule := range secretRules {
+				if rule.pattern.Match(scanner.Bytes()) {
+					findings = append(findings, SecretFinding{Path: relPath, Line: line, Rule: rule.name})
+				}
+			}
+		}
+		return scanner.Err()
+	})
+	return findings, err
+}
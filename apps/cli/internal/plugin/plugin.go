@@ -0,0 +1,177 @@
+// Package plugin finds oken-<name> executables on PATH, which extend the CLI
+// with external subcommands the way git and kubectl plugins do.
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Prefix starts the file name of every plugin executable
+const Prefix = "oken-"
+
+// Environment variables set for plugins
+const (
+	// ConfigEnv is the path of the user's config file
+	ConfigEnv = "OKEN_CONFIG"
+	// EndpointEnv is the platform URL the command would use
+	EndpointEnv = "OKEN_ENDPOINT"
+	// TokenEnv is the token for EndpointEnv, if logged in
+	TokenEnv = "OKEN_TOKEN"
+	// OrgEnv is the selected organization, if any
+	OrgEnv = "OKEN_ORG"
+	// VersionEnv is the version of the oken CLI that ran the plugin
+	VersionEnv = "OKEN_CLI_VERSION"
+	// ExecutableEnv is the path of the oken CLI, for plugins that call it
+	ExecutableEnv = "OKEN_EXECUTABLE"
+)
+
+// Plugin is an executable that provides the subcommand Name
+type Plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Shadowed lists executables with the same name later on PATH, which are
+	// never run
+	Shadowed []string `json:"shadowed,omitempty"`
+}
+
+// Context is what a plugin is told about the CLI that runs it
+type Context struct {
+	ConfigPath string
+	Endpoint   string
+	Token      string
+	Org        string
+	Version    string
+	Executable string
+}
+
+// Environ returns base with the context's variables added, replacing any
+// that were already set
+func (c Context) Environ(base []string) []string {
+	vars := map[string]string{
+		ConfigEnv:     c.ConfigPath,
+		EndpointEnv:   c.Endpoint,
+		TokenEnv:      c.Token,
+		OrgEnv:        c.Org,
+		VersionEnv:    c.Version,
+		ExecutableEnv: c.Executable,
+	}
+
+	env := make([]string, 0, len(base)+len(vars))
+	for _, kv := range base {
+		name, _, _ := strings.Cut(kv, "=")
+		if _, ok := vars[name]; !ok {
+			env = append(env, kv)
+		}
+	}
+	for _, name := range []string{ConfigEnv, EndpointEnv, TokenEnv, OrgEnv, VersionEnv, ExecutableEnv} {
+		if vars[name] != "" {
+			env = append(env, name+"="+vars[name])
+		}
+	}
+	return env
+}
+
+// ValidName reports whether name can be a plugin subcommand: it must not be
+// a flag or contain a path
+func ValidName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, "-") && !strings.ContainsAny(name, `/\.`)
+}
+
+// Find returns the first plugin named name in the PATH directories, or false
+// if there is none
+func Find(name string) (Plugin, bool) {
+	if !ValidName(name) {
+		return Plugin{}, false
+	}
+	for _, p := range List() {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Plugin{}, false
+}
+
+// List returns the plugins in the PATH directories, sorted by name
+func List() []Plugin {
+	byName := map[string]*Plugin{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			if p, seen := byName[name]; seen {
+				if p.Path != path {
+					p.Shadowed = append(p.Shadowed, path)
+				}
+				continue
+			}
+			byName[name] = &Plugin{Name: name, Path: path}
+		}
+	}
+
+	plugins := make([]Plugin, 0, len(byName))
+	for _, p := range byName {
+		plugins = append(plugins, *p)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// pluginName returns the subcommand a file provides, or false if it isn't a
+// plugin
+func pluginName(file string) (string, bool) {
+	name, ok := strings.CutPrefix(file, Prefix)
+	if !ok {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !hasExecutableExt(ext) {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name, ValidName(name)
+}
+
+// isExecutable reports whether path is a file that can be run
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode().Perm()&0111 != 0
+}
+
+// hasExecutableExt reports whether ext is listed in PATHEXT
+func hasExecutableExt(ext string) bool {
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = ".com;.exe;.bat;.cmd"
+	}
+	for _, e := range filepath.SplitList(pathext) {
+		if e != "" && strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
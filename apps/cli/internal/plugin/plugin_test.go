@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeExecutable(t *testing.T, dir, name string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), mode))
+	return path
+}
+
+func TestList(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses file modes to mark executables")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	hello := writeExecutable(t, first, "oken-hello", 0755)
+	shadowed := writeExecutable(t, second, "oken-hello", 0755)
+	deploy := writeExecutable(t, second, "oken-deploy-preview", 0755)
+	writeExecutable(t, second, "oken-notes", 0644)
+	writeExecutable(t, second, "oken-script.sh", 0755)
+	writeExecutable(t, second, "kubectl-hello", 0755)
+	t.Setenv("PATH", first+string(filepath.ListSeparator)+second)
+
+	assert.Equal(t, []Plugin{
+		{Name: "deploy-preview", Path: deploy},
+		{Name: "hello", Path: hello, Shadowed: []string{shadowed}},
+	}, List())
+
+	p, ok := Find("hello")
+	require.True(t, ok)
+	assert.Equal(t, hello, p.Path)
+
+	_, ok = Find("notes")
+	assert.False(t, ok)
+	_, ok = Find("../hello")
+	assert.False(t, ok)
+}
+
+func TestValidName(t *testing.T) {
+	assert.True(t, ValidName("hello"))
+	assert.True(t, ValidName("deploy-preview"))
+	assert.False(t, ValidName(""))
+	assert.False(t, ValidName("--help"))
+	assert.False(t, ValidName("../hello"))
+	assert.False(t, ValidName("script.sh"))
+}
+
+func TestEnviron(t *testing.T) {
+	env := Context{
+		ConfigPath: "/home/me/.oken/config.json",
+		Endpoint:   "https://api.oken.dev",
+		Token:      "ok_123",
+		Version:    "1.2.3",
+	}.Environ([]string{"PATH=/usr/bin", "OKEN_TOKEN=stale", "OKEN_ORG=acme"})
+
+	assert.Equal(t, []string{
+		"PATH=/usr/bin",
+		"OKEN_CONFIG=/home/me/.oken/config.json",
+		"OKEN_ENDPOINT=https://api.oken.dev",
+		"OKEN_TOKEN=ok_123",
+		"OKEN_CLI_VERSION=1.2.3",
+	}, env)
+}
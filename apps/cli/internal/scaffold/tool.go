@@ -0,0 +1,253 @@
+// Package scaffold generates code in agent projects, such as tool modules,
+// and wires it into the agent's entrypoint.
+package scaffold
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/neult/oken/apps/cli/internal/deps"
+)
+
+// ToolsDir is the package tool modules are generated in
+const ToolsDir = "tools"
+
+// Framework is the agent framework a tool is written for
+type Framework string
+
+const (
+	// FrameworkNone is a plain function, registered by hand
+	FrameworkNone      Framework = "none"
+	FrameworkLangChain Framework = "langchain"
+	FrameworkOpenAI    Framework = "openai-agents"
+	FrameworkCrewAI    Framework = "crewai"
+)
+
+// Frameworks are the frameworks tools can be generated for
+var Frameworks = []Framework{FrameworkLangChain, FrameworkOpenAI, FrameworkCrewAI, FrameworkNone}
+
+// frameworkPackages identify a framework by a dependency. They are checked
+// in order, since lockfiles also list indirect dependencies and older CrewAI
+// releases depend on LangChain.
+var frameworkPackages = []struct {
+	pkg       string
+	framework Framework
+}{
+	{"openai-agents", FrameworkOpenAI},
+	{"crewai", FrameworkCrewAI},
+	{"langchain-core", FrameworkLangChain},
+	{"langchain", FrameworkLangChain},
+	{"langgraph", FrameworkLangChain},
+}
+
+// frameworkTool is how each framework turns a function into a tool
+var frameworkTool = map[Framework]struct {
+	// pkg is the package providing the decorator
+	pkg       string
+	imports   string
+	decorator string
+}{
+	FrameworkLangChain: {"langchain-core", "from langchain_core.tools import tool", "@tool"},
+	FrameworkOpenAI:    {"openai-agents", "from agents import function_tool", "@function_tool"},
+	FrameworkCrewAI:    {"crewai", "from crewai.tools import tool", "@tool"},
+}
+
+// ParseFramework parses a framework name, as listed in Frameworks
+func ParseFramework(s string) (Framework, error) {
+	if f := Framework(s); slices.Contains(Frameworks, f) {
+		return f, nil
+	}
+	names := make([]string, len(Frameworks))
+	for i, f := range Frameworks {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("invalid framework %q: must be one of %s", s, strings.Join(names, ", "))
+}
+
+// DetectFramework returns the framework an agent depends on, or
+// FrameworkNone if it uses none that tools can be generated for
+func DetectFramework(set *deps.Set) Framework {
+	if set == nil {
+		return FrameworkNone
+	}
+	for _, fp := range frameworkPackages {
+		for _, d := range set.Dependencies {
+			if d.Name == fp.pkg {
+				return fp.framework
+			}
+		}
+	}
+	return FrameworkNone
+}
+
+// Requirement returns the package a framework's tools import, or "" if the
+// framework needs none
+func (f Framework) Requirement() string {
+	return frameworkTool[f].pkg
+}
+
+var toolNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*([-_][a-z0-9]+)*$`)
+
+// Tool is a tool module to generate
+type Tool struct {
+	// Function is the Python name of the tool function, e.g. fetch_weather
+	Function  string
+	Framework Framework
+}
+
+// NewTool returns the tool for a name such as fetch-weather
+func NewTool(name string, framework Framework) (Tool, error) {
+	if !toolNamePattern.MatchString(name) {
+		return Tool{}, fmt.Errorf("invalid tool name %q: use lowercase letters, numbers, hyphens and underscores, starting with a letter", name)
+	}
+	return Tool{Function: strings.ReplaceAll(name, "-", "_"), Framework: framework}, nil
+}
+
+// Path returns the path of the tool module, relative to the agent directory
+func (t Tool) Path() string {
+	return ToolsDir + "/" + t.Function + ".py"
+}
+
+// Import returns the statement that imports the tool in the entrypoint
+func (t Tool) Import() string {
+	return fmt.Sprintf("from %s.%s import %s", ToolsDir, t.Function, t.Function)
+}
+
+// Source returns the contents of the tool module
+func (t Tool) Source() string {
+	var b strings.Builder
+	fw, ok := frameworkTool[t.Framework]
+	if ok {
+		b.WriteString(fw.imports + "\n\n\n")
+		b.WriteString(fw.decorator + "\n")
+	}
+	fmt.Fprintf(&b, `def %s(query: str) -> str:
+    """Describe what %s does and when to use it.
+
+    The model reads this docstring, and the argument names and types, to
+    decide when to call the tool and with what.
+    """
+    raise NotImplementedError("%s is not implemented yet")
+`, t.Function, t.Function, t.Function)
+	return b.String()
+}
+
+var (
+	// importLine matches a top-level import statement
+	importLine = regexp.MustCompile(`^(import|from)\s+\S`)
+	// inlineToolsList matches a one-line tools list, as a variable or a
+	// keyword argument: tools = [search] or Agent(tools=[search])
+	inlineToolsList = regexp.MustCompile(`(?i)\btools\s*=\s*\[([^\]]*)\]`)
+	// openToolsList matches the first line of a tools list spanning lines
+	openToolsList = regexp.MustCompile(`(?i)\btools\s*=\s*\[\s*$`)
+)
+
+// Register imports the tool in an entrypoint's source and adds it to the
+// first tools list found. listed reports whether such a list was found;
+// otherwise only the import is added. Source that already imports the tool
+// is returned unchanged, as registered.
+func (t Tool) Register(source string) (result string, listed bool) {
+	lines := strings.Split(source, "\n")
+	if slices.Contains(lines, t.Import()) {
+		return source, true
+	}
+
+	lines, listed = t.addToList(lines)
+
+	// After the last import, or the module docstring without one; a
+	// parenthesized import ends at its closing parenthesis
+	at := docstringEnd(lines)
+	for i := 0; i < len(lines); i++ {
+		if !importLine.MatchString(lines[i]) {
+			continue
+		}
+		if strings.HasSuffix(strings.TrimSpace(lines[i]), "(") {
+			for i < len(lines)-1 && !strings.HasPrefix(strings.TrimSpace(lines[i]), ")") {
+				i++
+			}
+		}
+		at = i + 1
+	}
+	lines = slices.Insert(lines, at, t.Import())
+
+	return strings.Join(lines, "\n"), listed
+}
+
+// addToList appends the tool to the first tools list in lines
+func (t Tool) addToList(lines []string) ([]string, bool) {
+	for i, line := range lines {
+		if m := inlineToolsList.FindStringSubmatchIndex(line); m != nil {
+			items := strings.TrimSpace(line[m[2]:m[3]])
+			insert := t.Function
+			if items != "" {
+				insert = ", " + t.Function
+				if strings.HasSuffix(items, ",") {
+					insert = " " + t.Function
+				}
+			}
+			// After the last item, before any whitespace before the bracket
+			end := m[2] + len(strings.TrimRight(line[m[2]:m[3]], " "))
+			lines[i] = line[:end] + insert + line[end:]
+			return lines, true
+		}
+		if openToolsList.MatchString(line) {
+			indent := leadingSpace(line) + "    "
+			for j := i + 1; j < len(lines); j++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[j]), "]") {
+					if j > i+1 {
+						indent = leadingSpace(lines[j-1])
+						// The last item may lack a trailing comma
+						if prev := strings.TrimRight(lines[j-1], " "); !strings.HasSuffix(prev, ",") && !strings.HasSuffix(prev, "[") {
+							lines[j-1] = prev + ","
+						}
+					}
+					return slices.Insert(lines, j, indent+t.Function+","), true
+				}
+			}
+		}
+	}
+	return lines, false
+}
+
+// docstringEnd returns the index of the first line after a module
+// docstring, or 0 if lines don't start with one
+func docstringEnd(lines []string) int {
+	first := strings.TrimSpace(lines[0])
+	for _, quote := range []string{`"""`, `'''`} {
+		if !strings.HasPrefix(first, quote) {
+			continue
+		}
+		if len(first) > len(quote) && strings.HasSuffix(first, quote) {
+			return 1
+		}
+		for i := 1; i < len(lines); i++ {
+			if strings.Contains(lines[i], quote) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+func leadingSpace(s string) string {
+	return s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+}
+
+// AddRequirement appends a package to a requirements.txt's contents,
+// unless it is already listed
+func AddRequirement(requirements, pkg string) string {
+	listed, err := deps.ParseRequirements(strings.NewReader(requirements))
+	if err == nil {
+		for _, d := range listed {
+			if d.Name == deps.Normalize(pkg) {
+				return requirements
+			}
+		}
+	}
+	if requirements != "" && !strings.HasSuffix(requirements, "\n") {
+		requirements += "\n"
+	}
+	return requirements + pkg + "\n"
+}
@@ -0,0 +1,127 @@
+package scaffold
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neult/oken/apps/cli/internal/deps"
+)
+
+func TestDetectFramework(t *testing.T) {
+	tests := []struct {
+		name string
+		deps []string
+		want Framework
+	}{
+		{"langgraph", []string{"langgraph", "httpx"}, FrameworkLangChain},
+		{"openai agents", []string{"openai", "openai-agents"}, FrameworkOpenAI},
+		{"crewai depending on langchain", []string{"langchain", "crewai"}, FrameworkCrewAI},
+		{"plain", []string{"httpx"}, FrameworkNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := &deps.Set{}
+			for _, name := range tt.deps {
+				set.Dependencies = append(set.Dependencies, deps.Dependency{Name: name})
+			}
+			assert.Equal(t, tt.want, DetectFramework(set))
+		})
+	}
+
+	assert.Equal(t, FrameworkNone, DetectFramework(nil))
+}
+
+func TestParseFramework(t *testing.T) {
+	f, err := ParseFramework("openai-agents")
+	require.NoError(t, err)
+	assert.Equal(t, FrameworkOpenAI, f)
+
+	_, err = ParseFramework("autogen")
+	assert.Error(t, err)
+}
+
+func TestNewTool(t *testing.T) {
+	tool, err := NewTool("fetch-weather", FrameworkLangChain)
+	require.NoError(t, err)
+	assert.Equal(t, "fetch_weather", tool.Function)
+	assert.Equal(t, "tools/fetch_weather.py", tool.Path())
+	assert.Equal(t, "from tools.fetch_weather import fetch_weather", tool.Import())
+
+	for _, name := range []string{"", "Fetch", "2fetch", "fetch weather", "fetch--weather", "../x"} {
+		_, err := NewTool(name, FrameworkNone)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestToolSource(t *testing.T) {
+	tool := Tool{Function: "fetch_weather", Framework: FrameworkLangChain}
+	source := tool.Source()
+	assert.Contains(t, source, "from langchain_core.tools import tool\n\n\n@tool\ndef fetch_weather(query: str) -> str:\n")
+
+	tool.Framework = FrameworkNone
+	assert.True(t, strings.HasPrefix(tool.Source(), "def fetch_weather(query: str) -> str:\n"))
+}
+
+func TestRegister(t *testing.T) {
+	tool := Tool{Function: "fetch_weather", Framework: FrameworkOpenAI}
+
+	tests := []struct {
+		name       string
+		source     string
+		want       string
+		wantListed bool
+	}{
+		{
+			name:       "inline keyword argument",
+			source:     "from agents import Agent\n\nagent = Agent(name=\"a\", tools=[search])\n",
+			want:       "from agents import Agent\nfrom tools.fetch_weather import fetch_weather\n\nagent = Agent(name=\"a\", tools=[search, fetch_weather])\n",
+			wantListed: true,
+		},
+		{
+			name:       "empty list",
+			source:     "import os\nTOOLS = []\n",
+			want:       "import os\nfrom tools.fetch_weather import fetch_weather\nTOOLS = [fetch_weather]\n",
+			wantListed: true,
+		},
+		{
+			name:       "multi-line list",
+			source:     "from agents import (\n    Agent,\n    Runner,\n)\n\ntools = [\n    search,\n    lookup\n]\n",
+			want:       "from agents import (\n    Agent,\n    Runner,\n)\nfrom tools.fetch_weather import fetch_weather\n\ntools = [\n    search,\n    lookup,\n    fetch_weather,\n]\n",
+			wantListed: true,
+		},
+		{
+			name:   "no list",
+			source: "\"\"\"Agent.\"\"\"\n\ndef handler(input):\n    return {}\n",
+			want:   "\"\"\"Agent.\"\"\"\nfrom tools.fetch_weather import fetch_weather\n\ndef handler(input):\n    return {}\n",
+		},
+		{
+			name:   "no imports",
+			source: "def handler(input):\n    return {}\n",
+			want:   "from tools.fetch_weather import fetch_weather\ndef handler(input):\n    return {}\n",
+		},
+		{
+			name:       "already imported",
+			source:     "from tools.fetch_weather import fetch_weather\ntools = [fetch_weather]\n",
+			want:       "from tools.fetch_weather import fetch_weather\ntools = [fetch_weather]\n",
+			wantListed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, listed := tool.Register(tt.source)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantListed, listed)
+		})
+	}
+}
+
+func TestAddRequirement(t *testing.T) {
+	assert.Equal(t, "httpx==0.27.0\nlangchain-core\n", AddRequirement("httpx==0.27.0", "langchain-core"))
+	assert.Equal(t, "langchain_core>=0.3\n", AddRequirement("langchain_core>=0.3\n", "langchain-core"))
+	assert.Equal(t, "crewai\n", AddRequirement("", "crewai"))
+}
@@ -0,0 +1,157 @@
+// Package sign signs and verifies package digests with ed25519 keys.
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Algorithm names the signature scheme recorded with signatures
+const Algorithm = "ed25519"
+
+// ErrInvalidSignature is returned when a signature doesn't match the digest
+var ErrInvalidSignature = errors.New("signature does not match")
+
+// GenerateKey creates a key pair and writes the private key to path as PEM,
+// readable only by the current user. It fails if path exists.
+func GenerateKey(path string) (ed25519.PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return key, file.Close()
+}
+
+// LoadPrivateKey reads a PEM-encoded ed25519 private key
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ed, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an %s key", path, Algorithm)
+	}
+	return ed, nil
+}
+
+// LoadPublicKey reads a PEM-encoded ed25519 public key, or derives it from a
+// private key file
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	if block.Type == "PRIVATE KEY" {
+		key, err := LoadPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return key.Public().(ed25519.PublicKey), nil
+	}
+	key, err := ParsePublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// ParsePublicKey parses a PEM-encoded ed25519 public key
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("not a PEM public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ed, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("not an %s key", Algorithm)
+	}
+	return ed, nil
+}
+
+// EncodePublicKey returns the public key as PEM, for sharing with verifiers
+func EncodePublicKey(pub ed25519.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// Fingerprint identifies a public key by the first bytes of its SHA-256 hash
+func Fingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return "SHA256:" + hex.EncodeToString(sum[:8])
+}
+
+// Sign signs a hex SHA-256 package digest, returning a base64 signature
+func Sign(key ed25519.PrivateKey, digest string) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(key, message(digest)))
+}
+
+// Verify checks a base64 signature of a hex SHA-256 package digest
+func Verify(pub ed25519.PublicKey, digest, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("malformed signature: %w", err)
+	}
+	if !ed25519.Verify(pub, message(digest), sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// message is what gets signed, so a signature can't be replayed for
+// anything other than a package digest
+func message(digest string) []byte {
+	return []byte("oken-package-sha256:" + digest)
+}
+
+func readPEM(path, blockType string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s does not hold a PEM %s", path, blockType)
+	}
+	return block, nil
+}
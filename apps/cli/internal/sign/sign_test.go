@@ -0,0 +1,72 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const digest = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+func TestGenerateAndLoadKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "signing.key")
+
+	key, err := GenerateKey(path)
+	require.NoError(t, err)
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	loaded, err := LoadPrivateKey(path)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(loaded))
+
+	_, err = GenerateKey(path)
+	assert.Error(t, err, "existing keys must not be overwritten")
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "signing.key")
+	key, err := GenerateKey(keyPath)
+	require.NoError(t, err)
+
+	fromPrivate, err := LoadPublicKey(keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, key.Public(), fromPrivate)
+
+	encoded, err := EncodePublicKey(fromPrivate)
+	require.NoError(t, err)
+	pubPath := filepath.Join(dir, "signing.pub")
+	require.NoError(t, os.WriteFile(pubPath, []byte(encoded), 0644))
+
+	fromPublic, err := LoadPublicKey(pubPath)
+	require.NoError(t, err)
+	assert.Equal(t, fromPrivate, fromPublic)
+	assert.Equal(t, Fingerprint(fromPrivate), Fingerprint(fromPublic))
+
+	require.NoError(t, os.WriteFile(pubPath, []byte("not pem"), 0644))
+	_, err = LoadPublicKey(pubPath)
+	assert.Error(t, err)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	key, err := GenerateKey(filepath.Join(t.TempDir(), "signing.key"))
+	require.NoError(t, err)
+	pub := key.Public().(ed25519.PublicKey)
+
+	signature := Sign(key, digest)
+	require.NoError(t, Verify(pub, digest, signature))
+
+	tampered := "0" + digest[1:]
+	assert.ErrorIs(t, Verify(pub, tampered, signature), ErrInvalidSignature)
+	assert.Error(t, Verify(pub, digest, "not base64!"))
+}
@@ -0,0 +1,162 @@
+// Package telemetry reports anonymous usage of the CLI when the user has
+// opted in: which command ran, how long it took and what kind of error it
+// ended with. Arguments, flags, inputs and outputs are never sent.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+const (
+	// Env set to 0, false or off disables telemetry even if it was turned on
+	Env = "OKEN_TELEMETRY"
+	// DoNotTrackEnv is the cross-tool opt-out (https://consoledonottrack.com)
+	DoNotTrackEnv = "DO_NOT_TRACK"
+	// URLEnv sends events somewhere other than the platform's endpoint
+	URLEnv = "OKEN_TELEMETRY_URL"
+
+	// Path is where events are sent on the platform
+	Path = "/api/telemetry"
+)
+
+// sendTimeout bounds how long a command can be delayed by reporting it
+const sendTimeout = 2 * time.Second
+
+// Error categories
+const (
+	ErrorUnauthorized = "unauthorized"
+	ErrorClient       = "api_client"
+	ErrorServer       = "api_server"
+	ErrorNetwork      = "network"
+	ErrorCanceled     = "canceled"
+	ErrorNoInput      = "no_input"
+	ErrorExitStatus   = "exit_status"
+	ErrorOther        = "other"
+)
+
+// Event is everything reported about one command
+type Event struct {
+	// ID is a random identifier for the installation, not tied to the user
+	ID         string `json:"id"`
+	Command    string `json:"command"`
+	DurationMs int64  `json:"durationMs"`
+	// Error is the category of error the command failed with, or "" on
+	// success
+	Error   string `json:"error,omitempty"`
+	Version string `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	Time    string `json:"time"`
+}
+
+// NewEvent describes a finished command
+func NewEvent(id, command string, duration time.Duration, errorCategory, version string) Event {
+	return Event{
+		ID:         id,
+		Command:    command,
+		DurationMs: duration.Milliseconds(),
+		Error:      errorCategory,
+		Version:    version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Time:       time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// NewID returns a random installation ID
+func NewID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// DisabledByEnv returns the environment variable that turns telemetry off,
+// or "" if none does
+func DisabledByEnv() string {
+	switch strings.ToLower(os.Getenv(Env)) {
+	case "0", "false", "off":
+		return Env
+	}
+	if v := os.Getenv(DoNotTrackEnv); v != "" && v != "0" && !strings.EqualFold(v, "false") {
+		return DoNotTrackEnv
+	}
+	return ""
+}
+
+// URL returns where events are sent for the platform at endpoint
+func URL(endpoint string) string {
+	if u := os.Getenv(URLEnv); u != "" {
+		return u
+	}
+	return strings.TrimRight(endpoint, "/") + Path
+}
+
+// Category sorts an error into a coarse category, so that nothing from the
+// error message is sent
+func Category(err error) string {
+	if err == nil {
+		return ""
+	}
+	var apiErr *api.APIError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, api.ErrUnauthorized):
+		return ErrorUnauthorized
+	case errors.As(err, &apiErr) && apiErr.StatusCode >= 500:
+		return ErrorServer
+	case errors.As(err, &apiErr):
+		return ErrorClient
+	case errors.Is(err, context.Canceled):
+		return ErrorCanceled
+	case errors.As(err, &netErr):
+		return ErrorNetwork
+	case errors.Is(err, ui.ErrNoInput):
+		return ErrorNoInput
+	}
+	return ErrorOther
+}
+
+// Send reports an event. It gives up after a short timeout, since telemetry
+// must never get in the way of the command.
+func Send(url string, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", api.UserAgent(event.Version))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry rejected: %s", resp.Status)
+	}
+	return nil
+}
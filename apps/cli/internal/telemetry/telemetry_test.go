@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+	"github.com/neult/oken/apps/cli/internal/ui"
+)
+
+func TestCategory(t *testing.T) {
+	assert.Equal(t, "", Category(nil))
+	assert.Equal(t, ErrorUnauthorized, Category(&api.APIError{StatusCode: http.StatusUnauthorized}))
+	assert.Equal(t, ErrorClient, Category(fmt.Errorf("deploy: %w", &api.APIError{StatusCode: http.StatusNotFound, Message: "agent my-secret-agent not found"})))
+	assert.Equal(t, ErrorServer, Category(&api.APIError{StatusCode: http.StatusBadGateway}))
+	assert.Equal(t, ErrorCanceled, Category(context.Canceled))
+	assert.Equal(t, ErrorNetwork, Category(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+	assert.Equal(t, ErrorNoInput, Category(ui.ErrNoInput))
+	assert.Equal(t, ErrorOther, Category(errors.New("invalid flags")))
+}
+
+func TestDisabledByEnv(t *testing.T) {
+	t.Setenv(Env, "")
+	t.Setenv(DoNotTrackEnv, "")
+	assert.Equal(t, "", DisabledByEnv())
+
+	t.Setenv(DoNotTrackEnv, "1")
+	assert.Equal(t, DoNotTrackEnv, DisabledByEnv())
+
+	t.Setenv(Env, "off")
+	assert.Equal(t, Env, DisabledByEnv())
+}
+
+func TestURL(t *testing.T) {
+	t.Setenv(URLEnv, "")
+	assert.Equal(t, "https://api.oken.dev/api/telemetry", URL("https://api.oken.dev/"))
+
+	t.Setenv(URLEnv, "https://telemetry.example.com/events")
+	assert.Equal(t, "https://telemetry.example.com/events", URL("https://api.oken.dev"))
+}
+
+func TestSend(t *testing.T) {
+	var got Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Empty(t, r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event := NewEvent("abc123", "oken secrets rotate", 1500*time.Millisecond, ErrorClient, "1.2.3")
+	require.NoError(t, Send(server.URL, event))
+	assert.Equal(t, "oken secrets rotate", got.Command)
+	assert.Equal(t, int64(1500), got.DurationMs)
+	assert.Equal(t, ErrorClient, got.Error)
+	assert.Equal(t, "abc123", got.ID)
+}
+
+func TestSendRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	assert.Error(t, Send(server.URL, NewEvent("abc123", "oken list", time.Second, "", "dev")))
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ProgressMode controls how progress and status messages are reported
+type ProgressMode string
+
+const (
+	ProgressText ProgressMode = "text"
+	// ProgressJSON writes newline-delimited JSON events to stderr, for
+	// programs that wrap the CLI
+	ProgressJSON ProgressMode = "json"
+)
+
+var progressMode = ProgressText
+
+// events is where --progress json events are written; replaced in tests
+var events io.Writer = os.Stderr
+
+// Event is one line of --progress json output. Progress events mark a phase
+// of a long-running command; message events carry status messages.
+type Event struct {
+	Type    string `json:"type"`
+	Time    string `json:"time"`
+	Phase   string `json:"phase,omitempty"`
+	Percent *int   `json:"percent,omitempty"`
+	Level   string `json:"level,omitempty"`
+	Message string `json:"message"`
+}
+
+// SetProgress sets how progress is reported
+func SetProgress(m ProgressMode) {
+	progressMode = m
+}
+
+// IsProgressJSON reports whether progress is reported as JSON events
+func IsProgressJSON() bool {
+	return progressMode == ProgressJSON
+}
+
+// Progress reports that a command entered a phase, such as "upload" or
+// "build". percent is the phase's completion from 0 to 100, or -1 if
+// unknown. In text mode it prints like Info.
+func Progress(phase string, percent int, format string, a ...any) {
+	if !IsProgressJSON() {
+		Info(format, a...)
+		return
+	}
+	e := Event{Type: "progress", Phase: phase, Message: fmt.Sprintf(format, a...)}
+	if percent >= 0 {
+		e.Percent = &percent
+	}
+	writeEvent(e)
+}
+
+// emitMessage writes a status message as a JSON event when --progress json is active
+func emitMessage(level, format string, a ...any) bool {
+	if !IsProgressJSON() {
+		return false
+	}
+	writeEvent(Event{Type: "message", Level: level, Message: fmt.Sprintf(format, a...)})
+	return true
+}
+
+func writeEvent(e Event) {
+	e.Time = time.Now().UTC().Format(time.RFC3339)
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintln(events, string(data))
+}
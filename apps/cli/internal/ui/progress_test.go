@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressJSON(t *testing.T) {
+	var out bytes.Buffer
+	oldEvents := events
+	events = &out
+	SetProgress(ProgressJSON)
+	defer func() {
+		events = oldEvents
+		SetProgress(ProgressText)
+	}()
+
+	Progress("upload", 42, "Uploaded %d parts", 3)
+	Progress("build", -1, "Building")
+	Warning("Disk almost %s", "full")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+
+	var upload, build, warning Event
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &upload))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &build))
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &warning))
+
+	assert.Equal(t, "progress", upload.Type)
+	assert.Equal(t, "upload", upload.Phase)
+	require.NotNil(t, upload.Percent)
+	assert.Equal(t, 42, *upload.Percent)
+	assert.Equal(t, "Uploaded 3 parts", upload.Message)
+	assert.NotEmpty(t, upload.Time)
+
+	assert.Nil(t, build.Percent)
+	assert.NotContains(t, lines[1], "percent")
+
+	assert.Equal(t, "message", warning.Type)
+	assert.Equal(t, "warning", warning.Level)
+	assert.Equal(t, "Disk almost full", warning.Message)
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"golang.org/x/term"
+)
+
+// ErrNoInput is returned instead of prompting when --no-input is set or
+// stdin is not a terminal, so scripts fail fast rather than hang
+var ErrNoInput = errors.New("input required, but prompts are disabled (--no-input) or stdin is not a terminal")
+
+var noInput bool
+
+// stdin, stdinIsTerminal and readSecret are replaced in tests
+var (
+	stdin           io.Reader = os.Stdin
+	stdinIsTerminal           = func() bool {
+		fd := os.Stdin.Fd()
+		return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+	}
+	readSecret = func() ([]byte, error) {
+		return term.ReadPassword(int(os.Stdin.Fd()))
+	}
+)
+
+// SetNoInput disables all interactive prompts
+func SetNoInput(v bool) {
+	noInput = v
+}
+
+// CanPrompt reports whether the user can be asked for input
+func CanPrompt() bool {
+	return !noInput && stdinIsTerminal()
+}
+
+// Confirm asks a yes/no question that defaults to no. It returns ErrNoInput
+// without asking if prompts are not possible.
+func Confirm(question string) (bool, error) {
+	if !CanPrompt() {
+		return false, ErrNoInput
+	}
+
+	_, _ = fmt.Fprintf(messages(), "%s [y/N] ", question)
+	response, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
+
+// ConfirmTyped asks the user to type expected to go ahead, for actions that
+// are hard to undo. It returns ErrNoInput without asking if prompts are not
+// possible.
+func ConfirmTyped(question, expected string) (bool, error) {
+	if !CanPrompt() {
+		return false, ErrNoInput
+	}
+
+	_, _ = fmt.Fprintf(messages(), "%s Type '%s' to confirm: ", question, expected)
+	response, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	return strings.TrimSpace(response) == expected, nil
+}
+
+// PromptSecret asks for a value without echoing it, such as a password. It
+// returns ErrNoInput without asking if prompts are not possible.
+func PromptSecret(question string) (string, error) {
+	if !CanPrompt() {
+		return "", ErrNoInput
+	}
+
+	_, _ = fmt.Fprintf(messages(), "%s ", question)
+	value, err := readSecret()
+	// The newline typed by the user isn't echoed either
+	_, _ = fmt.Fprintln(messages())
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
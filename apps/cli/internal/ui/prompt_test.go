@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeStdin(t *testing.T, input string, terminal bool) {
+	t.Helper()
+	oldStdin, oldIsTerminal := stdin, stdinIsTerminal
+	stdin = strings.NewReader(input)
+	stdinIsTerminal = func() bool { return terminal }
+	t.Cleanup(func() {
+		stdin, stdinIsTerminal = oldStdin, oldIsTerminal
+		noInput = false
+	})
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		fakeStdin(t, tt.input, true)
+		ok, err := Confirm("Continue?")
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, ok, "input %q", tt.input)
+	}
+}
+
+func TestConfirmWithoutTerminal(t *testing.T) {
+	fakeStdin(t, "y\n", false)
+	ok, err := Confirm("Continue?")
+	assert.ErrorIs(t, err, ErrNoInput)
+	assert.False(t, ok)
+}
+
+func TestConfirmWithNoInput(t *testing.T) {
+	fakeStdin(t, "y\n", true)
+	SetNoInput(true)
+	assert.False(t, CanPrompt())
+	ok, err := Confirm("Continue?")
+	assert.ErrorIs(t, err, ErrNoInput)
+	assert.False(t, ok)
+}
+
+func TestConfirmTyped(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"test-*\n", true},
+		{"  test-*  \n", true},
+		{"test-*", true},
+		{"y\n", false},
+		{"TEST-*\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		fakeStdin(t, tt.input, true)
+		ok, err := ConfirmTyped("Delete 3 agents?", "test-*")
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, ok, "input %q", tt.input)
+	}
+}
+
+func TestConfirmTypedWithoutTerminal(t *testing.T) {
+	fakeStdin(t, "test-*\n", false)
+	ok, err := ConfirmTyped("Delete 3 agents?", "test-*")
+	assert.ErrorIs(t, err, ErrNoInput)
+	assert.False(t, ok)
+}
+
+func TestPromptSecret(t *testing.T) {
+	fakeStdin(t, "", true)
+	oldReadSecret := readSecret
+	readSecret = func() ([]byte, error) { return []byte("s3cret"), nil }
+	t.Cleanup(func() { readSecret = oldReadSecret })
+
+	value, err := PromptSecret("Password:")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	SetNoInput(true)
+	_, err = PromptSecret("Password:")
+	assert.ErrorIs(t, err, ErrNoInput)
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+)
+
+// Trace prints an invocation trace to stderr as a tree of spans, each with
+// its kind, duration and share of the whole invocation
+func Trace(t *api.Trace) error {
+	return writeTrace(os.Stderr, t)
+}
+
+func writeTrace(out io.Writer, t *api.Trace) error {
+	if _, err := fmt.Fprintf(out, "%s %s\n", bold("Trace"), formatMs(t.DurationMs)); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	writeSpans(w, t.Spans, "", t.DurationMs)
+	return w.Flush()
+}
+
+// writeSpans writes one row per span, indenting children under their parent
+func writeSpans(w io.Writer, spans []api.TraceSpan, indent string, total float64) {
+	for i, span := range spans {
+		branch, childIndent := "├─ ", "│  "
+		if i == len(spans)-1 {
+			branch, childIndent = "└─ ", "   "
+		}
+		share := ""
+		if total > 0 {
+			share = fmt.Sprintf("%.1f%%", span.DurationMs*100/total)
+		}
+		_, _ = fmt.Fprintf(w, "%s%s%s\t%s\t%s\t%s", faint(indent), faint(branch), span.Name, faint(span.Kind), formatMs(span.DurationMs), share)
+		if span.Error != "" {
+			_, _ = fmt.Fprintf(w, "\t%s %s", red("✗"), span.Error)
+		}
+		_, _ = fmt.Fprintln(w)
+		writeSpans(w, span.Children, indent+childIndent, total)
+	}
+}
+
+// formatMs formats a duration in milliseconds, e.g. 120ms or 1.24s
+func formatMs(ms float64) string {
+	d := time.Duration(ms * float64(time.Millisecond))
+	if d >= time.Second {
+		return d.Round(10 * time.Millisecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
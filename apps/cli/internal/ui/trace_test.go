@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+)
+
+func TestWriteTrace(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	trace := &api.Trace{
+		DurationMs: 1240,
+		Spans: []api.TraceSpan{
+			{Name: "plan", Kind: api.SpanLLM, DurationMs: 310},
+			{Name: "search", Kind: api.SpanTool, DurationMs: 620, Children: []api.TraceSpan{
+				{Name: "GET /docs", Kind: api.SpanStep, DurationMs: 600},
+			}},
+			{Name: "answer", Kind: api.SpanLLM, DurationMs: 300, Error: "rate limited"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeTrace(&buf, trace))
+	assert.Equal(t, `Trace 1.24s
+├─ plan          llm   310ms  25.0%
+├─ search        tool  620ms  50.0%
+│  └─ GET /docs  step  600ms  48.4%
+└─ answer        llm   300ms  24.2%  ✗ rate limited
+`, buf.String())
+}
+
+func TestFormatMs(t *testing.T) {
+	assert.Equal(t, "0s", formatMs(0))
+	assert.Equal(t, "12ms", formatMs(12.4))
+	assert.Equal(t, "999ms", formatMs(999))
+	assert.Equal(t, "2.5s", formatMs(2500))
+}
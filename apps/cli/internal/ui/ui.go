@@ -1,9 +1,19 @@
 package ui
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
 
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"gopkg.in/yaml.v3"
+
+	"github.com/neult/oken/apps/cli/internal/api"
 )
 
 var (
@@ -12,26 +22,139 @@ var (
 	yellow = color.New(color.FgYellow).SprintFunc()
 	cyan   = color.New(color.FgCyan).SprintFunc()
 	bold   = color.New(color.Bold).SprintFunc()
+	faint  = color.New(color.FgHiBlack).SprintFunc()
+	white  = color.New(color.FgWhite).SprintFunc()
+)
+
+// Format controls how status messages are rendered
+type Format string
+
+const (
+	FormatText   Format = "text"
+	FormatGitHub Format = "github"
+	FormatJSON   Format = "json"
+	FormatCSV    Format = "csv"
+	FormatTSV    Format = "tsv"
+	FormatYAML   Format = "yaml"
 )
 
+var outputFormat = FormatText
+
+// outputTemplate renders results when --format is set
+var outputTemplate *template.Template
+
+// templateFuncs are available in --format templates in addition to the builtins
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// SetFormat sets the output format for status messages
+func SetFormat(f Format) {
+	outputFormat = f
+}
+
+// IsStructured reports whether command results should be printed as JSON or YAML
+func IsStructured() bool {
+	return outputFormat == FormatJSON || outputFormat == FormatYAML
+}
+
+// SetTemplate parses the Go template used to render results, as with
+// --format '{{.Slug}} {{.Status}}'
+func SetTemplate(text string) error {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return err
+	}
+	outputTemplate = tmpl
+	return nil
+}
+
+// IsTemplate reports whether results should be rendered with a --format template
+func IsTemplate() bool {
+	return outputTemplate != nil
+}
+
+// Template renders v with the --format template, followed by a newline
+func Template(v any) error {
+	return renderTemplate(os.Stdout, v)
+}
+
+func renderTemplate(out io.Writer, v any) error {
+	if err := outputTemplate.Execute(out, v); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(out)
+	return err
+}
+
+// IsTabular reports whether list results should be printed as CSV or TSV rows
+func IsTabular() bool {
+	return outputFormat == FormatCSV || outputFormat == FormatTSV
+}
+
+// stdoutReserved is set by commands that stream data to stdout
+var stdoutReserved bool
+
+// ReserveStdout sends status messages to stderr, for commands that write
+// data such as JSON lines to stdout in every output format
+func ReserveStdout() {
+	stdoutReserved = true
+}
+
+// messages returns where status messages go; stderr in machine-readable
+// modes keeps stdout parseable
+func messages() io.Writer {
+	if stdoutReserved || IsStructured() || IsTabular() || IsTemplate() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
 // Success prints a success message with a green checkmark
 func Success(format string, a ...any) {
-	fmt.Printf("%s %s\n", green("✓"), fmt.Sprintf(format, a...))
+	if emitMessage("success", format, a...) {
+		return
+	}
+	if annotate("notice", format, a...) {
+		return
+	}
+	_, _ = fmt.Fprintf(messages(), "%s %s\n", green("✓"), fmt.Sprintf(format, a...))
 }
 
 // Error prints an error message with a red X
 func Error(format string, a ...any) {
-	fmt.Printf("%s %s\n", red("✗"), fmt.Sprintf(format, a...))
+	if emitMessage("error", format, a...) {
+		return
+	}
+	if annotate("error", format, a...) {
+		return
+	}
+	_, _ = fmt.Fprintf(messages(), "%s %s\n", red("✗"), fmt.Sprintf(format, a...))
 }
 
 // Warning prints a warning message with a yellow exclamation
 func Warning(format string, a ...any) {
-	fmt.Printf("%s %s\n", yellow("!"), fmt.Sprintf(format, a...))
+	if emitMessage("warning", format, a...) {
+		return
+	}
+	if annotate("warning", format, a...) {
+		return
+	}
+	_, _ = fmt.Fprintf(messages(), "%s %s\n", yellow("!"), fmt.Sprintf(format, a...))
 }
 
 // Info prints an info message with a cyan arrow
 func Info(format string, a ...any) {
-	fmt.Printf("%s %s\n", cyan("→"), fmt.Sprintf(format, a...))
+	if emitMessage("info", format, a...) {
+		return
+	}
+	_, _ = fmt.Fprintf(messages(), "%s %s\n", cyan("→"), fmt.Sprintf(format, a...))
 }
 
 // Bold returns bold text
@@ -43,3 +166,152 @@ func Bold(s string) string {
 func Cyan(s string) string {
 	return cyan(s)
 }
+
+// Green returns green text
+func Green(s string) string {
+	return green(s)
+}
+
+// Red returns red text
+func Red(s string) string {
+	return red(s)
+}
+
+// Yellow returns yellow text
+func Yellow(s string) string {
+	return yellow(s)
+}
+
+// AgentStatus returns an agent status colored by state. Every status gets a
+// color escape of the same width, so tabwriter columns stay aligned.
+func AgentStatus(s api.AgentStatus) string {
+	switch s {
+	case api.AgentRunning:
+		return green(string(s))
+	case api.AgentDeploying:
+		return yellow(string(s))
+	case api.AgentFailed:
+		return red(string(s))
+	case api.AgentStopped:
+		return faint(string(s))
+	default:
+		return white(string(s))
+	}
+}
+
+// Result prints v to stdout as YAML in YAML mode and as JSON otherwise
+func Result(v any) error {
+	if outputFormat == FormatYAML {
+		return writeYAML(os.Stdout, v)
+	}
+	return JSON(v)
+}
+
+// writeYAML renders v as YAML. v is encoded as JSON first so YAML output
+// has the same field names, ordering and omitted fields as JSON output.
+func writeYAML(out io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, so it decodes into a node tree that keeps key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle clears the flow and quoting styles taken from the JSON input,
+// leaving the encoder to pick plain block style where possible
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// JSON prints v as indented JSON to stdout
+func JSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// Table prints a header and rows to stdout as CSV, or TSV in TSV mode.
+// Fields containing separators, quotes or newlines are quoted.
+func Table(header []string, rows [][]string) error {
+	return writeTable(os.Stdout, header, rows)
+}
+
+func writeTable(out io.Writer, header []string, rows [][]string) error {
+	w := csv.NewWriter(out)
+	if outputFormat == FormatTSV {
+		w.Comma = '\t'
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+// Bytes formats a byte count using binary units (e.g. 512.0 MiB)
+func Bytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// IsTerminal reports whether stdout is an interactive terminal
+func IsTerminal() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// ClearScreen clears the terminal and moves the cursor to the top-left corner
+func ClearScreen() {
+	fmt.Print("\033[H\033[2J")
+}
+
+// annotate prints a GitHub Actions workflow command when the GitHub format is active
+func annotate(level, msgFormat string, a ...any) bool {
+	if outputFormat != FormatGitHub {
+		return false
+	}
+	fmt.Println(annotation(level, fmt.Sprintf(msgFormat, a...)))
+	return true
+}
+
+// annotation formats a GitHub Actions workflow command (e.g. ::error::message)
+func annotation(level, msg string) string {
+	return fmt.Sprintf("::%s::%s", level, escapeAnnotation(msg))
+}
+
+// escapeAnnotation escapes characters GitHub treats specially in command data
+func escapeAnnotation(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	s = strings.ReplaceAll(s, "\n", "%0A")
+	return s
+}
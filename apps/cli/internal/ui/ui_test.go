@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neult/oken/apps/cli/internal/api"
+)
+
+func TestAnnotation(t *testing.T) {
+	tests := []struct {
+		name  string
+		level string
+		msg   string
+		want  string
+	}{
+		{"error", "error", "Failed to deploy agent", "::error::Failed to deploy agent"},
+		{"notice", "notice", "Agent deployed successfully!", "::notice::Agent deployed successfully!"},
+		{"escapes percent", "error", "100% broken", "::error::100%25 broken"},
+		{"escapes newlines", "warning", "line one\r\nline two", "::warning::line one%0D%0Aline two"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, annotation(tt.level, tt.msg))
+		})
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{512 * 1024 * 1024, "512.0 MiB"},
+		{3 * 1024 * 1024 * 1024 / 2, "1.5 GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, Bytes(tt.n))
+		})
+	}
+}
+
+func TestAgentStatus(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	statuses := []api.AgentStatus{api.AgentRunning, api.AgentDeploying, api.AgentFailed, api.AgentStopped, "sleeping"}
+	width := len(AgentStatus(statuses[0])) - len(statuses[0])
+	for _, s := range statuses {
+		rendered := AgentStatus(s)
+		assert.Contains(t, rendered, string(s))
+		assert.Equal(t, width, len(rendered)-len(s), "color escape width for %s", s)
+	}
+	assert.NotEqual(t, AgentStatus(api.AgentRunning), AgentStatus(api.AgentFailed))
+}
+
+func TestWriteTable(t *testing.T) {
+	defer SetFormat(FormatText)
+
+	header := []string{"name", "url"}
+	rows := [][]string{
+		{"plain", "https://example.com"},
+		{"has, comma", "tab\there"},
+		{`has "quotes"`, "line\nbreak"},
+	}
+
+	SetFormat(FormatCSV)
+	var csvOut bytes.Buffer
+	require.NoError(t, writeTable(&csvOut, header, rows))
+	assert.Equal(t, "name,url\nplain,https://example.com\n\"has, comma\",tab\there\n\"has \"\"quotes\"\"\",\"line\nbreak\"\n", csvOut.String())
+
+	SetFormat(FormatTSV)
+	var tsvOut bytes.Buffer
+	require.NoError(t, writeTable(&tsvOut, header, rows))
+	assert.Equal(t, "name\turl\nplain\thttps://example.com\nhas, comma\t\"tab\there\"\n\"has \"\"quotes\"\"\"\t\"line\nbreak\"\n", tsvOut.String())
+}
+
+func TestRenderTemplate(t *testing.T) {
+	defer func() { outputTemplate = nil }()
+
+	endpoint := "https://my-agent.oken.run"
+	agent := api.Agent{Slug: "my-agent", Status: api.AgentRunning, Endpoint: &endpoint}
+
+	require.NoError(t, SetTemplate("{{.Slug}} {{.Status}} {{.Endpoint}}"))
+	assert.True(t, IsTemplate())
+	var out bytes.Buffer
+	require.NoError(t, renderTemplate(&out, agent))
+	assert.Equal(t, "my-agent running https://my-agent.oken.run\n", out.String())
+
+	require.NoError(t, SetTemplate(`{{json .Slug}} {{upper (print .Status)}}`))
+	out.Reset()
+	require.NoError(t, renderTemplate(&out, agent))
+	assert.Equal(t, "\"my-agent\" RUNNING\n", out.String())
+
+	require.NoError(t, SetTemplate("{{.Nope}}"))
+	assert.Error(t, renderTemplate(&out, agent))
+
+	assert.Error(t, SetTemplate("{{.Slug"))
+}
+
+func TestWriteYAML(t *testing.T) {
+	endpoint := "https://my-agent.oken.run"
+	agents := []api.Agent{{ID: "123", Name: "My Agent", Slug: "my-agent", Status: api.AgentRunning, Endpoint: &endpoint}}
+
+	var out bytes.Buffer
+	require.NoError(t, writeYAML(&out, map[string]any{"agents": agents, "empty": []string{}}))
+	assert.Equal(t, `agents:
+  - id: "123"
+    name: My Agent
+    slug: my-agent
+    status: running
+    environment: null
+    endpoint: https://my-agent.oken.run
+    runtime: null
+    build: null
+    pythonVersion: null
+    nodeVersion: null
+    entrypoint: null
+    replicas: null
+    memory: null
+    cpu: null
+    createdAt: ""
+    updatedAt: ""
+empty: []
+`, out.String())
+}
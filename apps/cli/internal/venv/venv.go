@@ -0,0 +1,170 @@
+// Package venv plans the commands that create a Python virtualenv for an
+// agent and install its dependencies, with uv when available.
+package venv
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/neult/oken/apps/cli/internal/deps"
+)
+
+const (
+	// Dir is where the virtualenv is created, relative to the agent
+	// directory; packaging already leaves it out
+	Dir = ".venv"
+	// VersionFile pins the Python version for pyenv, uv and editors
+	VersionFile = ".python-version"
+	// DefaultPython is the version the platform runs agents with when
+	// oken.toml doesn't set python_version
+	DefaultPython = "3.12"
+)
+
+// ReadVersionFile returns the version in a .python-version file, or "" if
+// there is none
+func ReadVersionFile(dir string) (string, error) {
+	file, err := os.Open(filepath.Join(dir, VersionFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line, nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// Version returns the Python version a virtualenv was created with, from
+// its pyvenv.cfg
+func Version(venvDir string) (string, error) {
+	file, err := os.Open(filepath.Join(venvDir, "pyvenv.cfg"))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	var version string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		// venv writes version, uv writes version_info
+		switch strings.TrimSpace(key) {
+		case "version", "version_info":
+			version = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if version == "" {
+		return "", fmt.Errorf("no Python version in %s", filepath.Join(venvDir, "pyvenv.cfg"))
+	}
+	return version, nil
+}
+
+// Matches reports whether version satisfies want, which may leave out the
+// patch or minor version: 3.12.4 matches 3.12
+func Matches(version, want string) bool {
+	return version == want || strings.HasPrefix(version, want+".")
+}
+
+// Options describe the agent directory and the tools on PATH
+type Options struct {
+	// Python is the version to create the virtualenv with
+	Python string
+	// DepsFile is the file dependencies are read from, as in deps.Set, or
+	// "" when there is none
+	DepsFile string
+	// UV and Poetry report whether uv and poetry are installed
+	UV     bool
+	Poetry bool
+	// Exists is true when the virtualenv already exists and can be reused
+	Exists bool
+	// Windows selects the Windows launcher and virtualenv layout
+	Windows bool
+}
+
+// Step is a command to run in the agent directory
+type Step struct {
+	Args []string
+	// Env is added to the environment of the command
+	Env []string
+}
+
+// String returns the command line
+func (s Step) String() string {
+	return strings.Join(s.Args, " ")
+}
+
+// Plan returns the commands that create the virtualenv in Dir and install
+// the agent's dependencies into it
+func Plan(opts Options) ([]Step, error) {
+	switch opts.DepsFile {
+	case "uv.lock", deps.ProjectFile:
+		if !opts.UV {
+			return nil, fmt.Errorf("%s needs uv to install; see https://docs.astral.sh/uv/getting-started/installation/", opts.DepsFile)
+		}
+		// Creates the virtualenv, or recreates it for another Python
+		return []Step{{Args: []string{"uv", "sync", "--python", opts.Python}}}, nil
+	case "poetry.lock":
+		if !opts.Poetry {
+			return nil, fmt.Errorf("poetry.lock needs poetry to install; see https://python-poetry.org/docs/#installation")
+		}
+		inProject := []string{"POETRY_VIRTUALENVS_IN_PROJECT=true"}
+		return []Step{
+			{Args: []string{"poetry", "env", "use", opts.Python}, Env: inProject},
+			{Args: []string{"poetry", "install", "--no-root"}, Env: inProject},
+		}, nil
+	}
+
+	var steps []Step
+	if !opts.Exists {
+		switch {
+		case opts.UV:
+			steps = append(steps, Step{Args: []string{"uv", "venv", "--python", opts.Python, Dir}})
+		case opts.Windows:
+			steps = append(steps, Step{Args: []string{"py", "-" + minorVersion(opts.Python), "-m", "venv", Dir}})
+		default:
+			steps = append(steps, Step{Args: []string{"python" + minorVersion(opts.Python), "-m", "venv", Dir}})
+		}
+	}
+	if opts.DepsFile == deps.RequirementsFile {
+		if opts.UV {
+			steps = append(steps, Step{Args: []string{"uv", "pip", "install", "--python", Dir, "-r", deps.RequirementsFile}})
+		} else {
+			steps = append(steps, Step{Args: []string{Python(opts.Windows), "-m", "pip", "install", "-r", deps.RequirementsFile}})
+		}
+	}
+	return steps, nil
+}
+
+// minorVersion drops the patch version, since interpreters on PATH are
+// named after the minor one, like python3.12
+func minorVersion(version string) string {
+	if parts := strings.SplitN(version, ".", 3); len(parts) == 3 {
+		return parts[0] + "." + parts[1]
+	}
+	return version
+}
+
+// Python returns the path of the virtualenv's interpreter
+func Python(windows bool) string {
+	if windows {
+		return Dir + `\Scripts\python.exe`
+	}
+	return Dir + "/bin/python"
+}
@@ -0,0 +1,114 @@
+package venv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadVersionFile(t *testing.T) {
+	dir := t.TempDir()
+
+	version, err := ReadVersionFile(dir)
+	require.NoError(t, err)
+	assert.Empty(t, version)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, VersionFile), []byte("# pinned\n3.11.9\n"), 0644))
+	version, err = ReadVersionFile(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "3.11.9", version)
+}
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  string
+		want string
+	}{
+		{"venv", "home = /usr/bin\ninclude-system-site-packages = false\nversion = 3.12.4\n", "3.12.4"},
+		{"uv", "home = /opt/python/bin\nimplementation = CPython\nuv = 0.4.0\nversion_info = 3.11.9\n", "3.11.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "pyvenv.cfg"), []byte(tt.cfg), 0644))
+			version, err := Version(dir)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, version)
+		})
+	}
+
+	_, err := Version(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestMatches(t *testing.T) {
+	assert.True(t, Matches("3.12.4", "3.12"))
+	assert.True(t, Matches("3.12.4", "3"))
+	assert.True(t, Matches("3.12", "3.12"))
+	assert.False(t, Matches("3.11.9", "3.12"))
+	assert.False(t, Matches("3.120.1", "3.12"))
+}
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "uv lockfile",
+			opts: Options{Python: "3.12", DepsFile: "uv.lock", UV: true},
+			want: []string{"uv sync --python 3.12"},
+		},
+		{
+			name: "requirements with uv",
+			opts: Options{Python: "3.12", DepsFile: "requirements.txt", UV: true},
+			want: []string{"uv venv --python 3.12 .venv", "uv pip install --python .venv -r requirements.txt"},
+		},
+		{
+			name: "requirements with venv",
+			opts: Options{Python: "3.11.9", DepsFile: "requirements.txt"},
+			want: []string{"python3.11 -m venv .venv", ".venv/bin/python -m pip install -r requirements.txt"},
+		},
+		{
+			name: "existing virtualenv on Windows",
+			opts: Options{Python: "3.11", DepsFile: "requirements.txt", Exists: true, Windows: true},
+			want: []string{`.venv\Scripts\python.exe -m pip install -r requirements.txt`},
+		},
+		{
+			name: "no dependencies on Windows",
+			opts: Options{Python: "3.12", Windows: true},
+			want: []string{"py -3.12 -m venv .venv"},
+		},
+		{
+			name: "poetry",
+			opts: Options{Python: "3.12", DepsFile: "poetry.lock", Poetry: true},
+			want: []string{"poetry env use 3.12", "poetry install --no-root"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := Plan(tt.opts)
+			require.NoError(t, err)
+			var got []string
+			for _, s := range steps {
+				got = append(got, s.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPlanMissingTool(t *testing.T) {
+	_, err := Plan(Options{Python: "3.12", DepsFile: "pyproject.toml"})
+	assert.ErrorContains(t, err, "needs uv")
+
+	_, err = Plan(Options{Python: "3.12", DepsFile: "poetry.lock", UV: true})
+	assert.ErrorContains(t, err, "needs poetry")
+}
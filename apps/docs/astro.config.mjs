@@ -24,14 +24,57 @@ export default defineConfig({
 						{ label: 'Overview', slug: 'cli/overview' },
 						{ label: 'oken login', slug: 'cli/login' },
 						{ label: 'oken init', slug: 'cli/init' },
+						{ label: 'oken add', slug: 'cli/add' },
+						{ label: 'oken setup', slug: 'cli/setup' },
 						{ label: 'oken deploy', slug: 'cli/deploy' },
+						{ label: 'oken pack', slug: 'cli/pack' },
+						{ label: 'oken deployments', slug: 'cli/deployments' },
+						{ label: 'oken promote', slug: 'cli/promote' },
+						{ label: 'oken plan', slug: 'cli/plan' },
+						{ label: 'oken apply', slug: 'cli/apply' },
+						{ label: 'oken export', slug: 'cli/export' },
 						{ label: 'oken list', slug: 'cli/list' },
+						{ label: 'oken search', slug: 'cli/search' },
 						{ label: 'oken status', slug: 'cli/status' },
+						{ label: 'oken inspect', slug: 'cli/inspect' },
+						{ label: 'oken label', slug: 'cli/label' },
+						{ label: 'oken health', slug: 'cli/health' },
+						{ label: 'oken warm', slug: 'cli/warm' },
+						{ label: 'oken open', slug: 'cli/open' },
+						{ label: 'oken pull', slug: 'cli/pull' },
+						{ label: 'oken verify', slug: 'cli/verify' },
+						{ label: 'oken sbom', slug: 'cli/sbom' },
+						{ label: 'oken diff', slug: 'cli/diff' },
+						{ label: 'oken scale', slug: 'cli/scale' },
+						{ label: 'oken update', slug: 'cli/update' },
+						{ label: 'oken metrics', slug: 'cli/metrics' },
+						{ label: 'oken usage', slug: 'cli/usage' },
+						{ label: 'oken audit', slug: 'cli/audit' },
+						{ label: 'oken top', slug: 'cli/top' },
+						{ label: 'oken schedule', slug: 'cli/schedule' },
+						{ label: 'oken webhooks', slug: 'cli/webhooks' },
+						{ label: 'oken domains', slug: 'cli/domains' },
+						{ label: 'oken logdrains', slug: 'cli/logdrains' },
 						{ label: 'oken invoke', slug: 'cli/invoke' },
+						{ label: 'oken chat', slug: 'cli/chat' },
+						{ label: 'oken curl', slug: 'cli/curl' },
 						{ label: 'oken logs', slug: 'cli/logs' },
+						{ label: 'oken exec', slug: 'cli/exec' },
+						{ label: 'oken shell', slug: 'cli/shell' },
+						{ label: 'oken cp', slug: 'cli/cp' },
+						{ label: 'oken port-forward', slug: 'cli/port-forward' },
+						{ label: 'oken debug', slug: 'cli/debug' },
 						{ label: 'oken stop', slug: 'cli/stop' },
 						{ label: 'oken delete', slug: 'cli/delete' },
 						{ label: 'oken secrets', slug: 'cli/secrets' },
+						{ label: 'oken env', slug: 'cli/env' },
+						{ label: 'oken tokens', slug: 'cli/tokens' },
+						{ label: 'oken org', slug: 'cli/org' },
+						{ label: 'oken plugin', slug: 'cli/plugin' },
+						{ label: 'oken telemetry', slug: 'cli/telemetry' },
+						{ label: 'oken local', slug: 'cli/local' },
+						{ label: 'oken alias', slug: 'cli/alias' },
+						{ label: 'oken share', slug: 'cli/share' },
 					],
 				},
 				{